@@ -0,0 +1,81 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"sort"
+
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/registrycache"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var pruneAll bool
+
+// statsCacheCmd represents the cache stats command
+var statsCacheCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show the usage of the registry cache shared by all profiles.",
+	Long:  "Show the usage of the pull-through registry cache of Docker Hub and k8s.gcr.io, enabled with 'minikube config set registry-cache true'.",
+	Run: func(cmd *cobra.Command, args []string) {
+		st, err := registrycache.GetStats()
+		if err != nil {
+			exit.Error(reason.HostRegistryCache, "Failed to get the registry cache stats", err)
+		}
+		if !registrycache.Enabled() {
+			out.Step(style.Tip, "The registry cache is disabled, enable it with: minikube config set {{.key}} true", out.V{"key": registrycache.EnabledKey})
+		}
+		if !st.Running {
+			out.Step(style.Empty, "The registry cache isn't running, it starts with the next 'minikube start'")
+			return
+		}
+		out.Step(style.Caching, "Registry cache: {{.size}} of {{.limit}}, {{.blobs}} blobs of {{.repositories}} repositories",
+			out.V{"size": units.BytesSize(float64(st.Size)), "limit": units.BytesSize(float64(st.Limit)), "blobs": st.Blobs, "repositories": st.Repositories})
+		hosts := []string{}
+		for h := range st.Addresses {
+			hosts = append(hosts, h)
+		}
+		sort.Strings(hosts)
+		for _, h := range hosts {
+			out.Infof("Mirror of {{.registry}} at {{.address}}", out.V{"registry": h, "address": st.Addresses[h]})
+		}
+	},
+}
+
+// pruneCacheCmd represents the cache prune command
+var pruneCacheCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Evict blobs from the registry cache shared by all profiles.",
+	Long:  "Evict the least recently stored blobs from the registry cache until it fits in its size limit, set with 'minikube config set registry-cache-size', or all of them with --all.",
+	Run: func(cmd *cobra.Command, args []string) {
+		freed, err := registrycache.Prune(pruneAll)
+		if err != nil {
+			exit.Error(reason.HostRegistryCache, "Failed to prune the registry cache", err)
+		}
+		out.Step(style.Deleted, "Freed {{.size}} from the registry cache", out.V{"size": units.BytesSize(float64(freed))})
+	},
+}
+
+func init() {
+	pruneCacheCmd.Flags().BoolVar(&pruneAll, "all", false, "Evict all blobs from the registry cache")
+	cacheCmd.AddCommand(statsCacheCmd)
+	cacheCmd.AddCommand(pruneCacheCmd)
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var verifyRepair bool
+
+// verifyCacheCmd represents the cache verify command
+var verifyCacheCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify the digests of cached images, preloads and binaries.",
+	Long:  "Verify the cached images with their digests, and the preloads and Kubernetes binaries with their checksums, and download the corrupted ones again.",
+	Run: func(cmd *cobra.Command, args []string) {
+		results, err := machine.VerifyCache(verifyRepair)
+		if err != nil {
+			exit.Error(reason.HostCacheVerify, "Failed to verify the cache", err)
+		}
+		verified, unverified, failed := 0, 0, 0
+		for _, r := range results {
+			switch {
+			case r.Unverified:
+				unverified++
+				out.Step(style.Empty, "{{.kind}} {{.name}} has no checksum to verify it with", out.V{"kind": r.Kind, "name": r.Name})
+			case r.Err == nil:
+				verified++
+			case r.Repaired:
+				out.Step(style.Check, "{{.kind}} {{.name}} was corrupted and downloaded again: {{.error}}", out.V{"kind": r.Kind, "name": r.Name, "error": r.Err})
+			case r.RepairErr != nil:
+				failed++
+				out.WarningT("{{.kind}} {{.name}} is corrupted and downloading it again failed: {{.error}}", out.V{"kind": r.Kind, "name": r.Name, "error": r.RepairErr})
+			default:
+				failed++
+				out.WarningT("{{.kind}} {{.name}} is corrupted: {{.error}}", out.V{"kind": r.Kind, "name": r.Name, "error": r.Err})
+			}
+		}
+		out.Step(style.Verifying, "Verified {{.verified}} of {{.total}} cached artifacts", out.V{"verified": verified, "total": len(results)})
+		if unverified > 0 {
+			out.Step(style.Tip, "{{.count}} cached artifacts have no checksum, verify them while online or delete them to download them again", out.V{"count": unverified})
+		}
+		if failed > 0 {
+			exit.Message(reason.HostCacheVerify, "{{.count}} cached artifacts are corrupted", out.V{"count": failed})
+		}
+	},
+}
+
+func init() {
+	verifyCacheCmd.Flags().BoolVar(&verifyRepair, "repair", true, "Download the corrupted cached artifacts again")
+	cacheCmd.AddCommand(verifyCacheCmd)
+}
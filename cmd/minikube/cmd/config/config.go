@@ -22,8 +22,11 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/download"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/registrycache"
 )
 
 // Bootstrapper is the name for bootstrapper
@@ -175,6 +178,72 @@ var settings = []Setting{
 		name: "native-ssh",
 		set:  SetBool,
 	},
+	{
+		name: config.Rootless,
+		set:  SetBool,
+	},
+	{
+		name: registrycache.EnabledKey,
+		set:  SetBool,
+	},
+	{
+		name:        registrycache.SizeKey,
+		set:         SetString,
+		validations: []setFn{IsValidDiskSize},
+	},
+	{
+		name:        registrycache.PortKey,
+		set:         SetInt,
+		validations: []setFn{IsPositive},
+	},
+	{
+		name:        machine.CacheMaxSizeKey,
+		set:         SetString,
+		validations: []setFn{IsValidDiskSize},
+	},
+	{
+		name:        machine.CacheMaxAgeKey,
+		set:         SetString,
+		validations: []setFn{IsValidDuration},
+	},
+	{
+		name: download.PreloadMirrorKey,
+		set:  SetString,
+	},
+	{
+		name: download.ArtifactMirrorKey,
+		set:  SetString,
+	},
+	{
+		name: download.ArtifactMirrorTokenKey,
+		set:  SetString,
+	},
+	{
+		name: download.ArtifactMirrorNetrcKey,
+		set:  SetBool,
+	},
+	{
+		name: download.ISOMirrorKey,
+		set:  SetString,
+	},
+	{
+		name: download.BinaryMirrorKey,
+		set:  SetString,
+	},
+	{
+		name: download.DriverMirrorKey,
+		set:  SetString,
+	},
+	{
+		name:        download.DownloadRateLimitKey,
+		set:         SetString,
+		validations: []setFn{IsValidDiskSize},
+	},
+	{
+		name:        download.DownloadConnectionsKey,
+		set:         SetInt,
+		validations: []setFn{IsPositive},
+	},
 }
 
 // ConfigCmd represents the config command
@@ -43,6 +43,12 @@ import (
 var output string
 var isLight bool
 
+// profileJSON is a profile along with the resources consumed by its running nodes, keyed by machine name
+type profileJSON struct {
+	*config.Profile
+	Resources map[string]*machine.ResourceUsage `json:",omitempty"`
+}
+
 var profileListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "Lists all minikube profiles.",
@@ -192,7 +198,7 @@ func printProfilesJSON() {
 
 	var body = map[string]interface{}{}
 	if err == nil || config.IsNotExist(err) {
-		body["valid"] = profilesOrDefault(validProfiles)
+		body["valid"] = profilesWithUsage(profilesOrDefault(validProfiles))
 		body["invalid"] = profilesOrDefault(invalidProfiles)
 		jsonString, _ := json.Marshal(body)
 		out.String(string(jsonString))
@@ -204,6 +210,49 @@ func printProfilesJSON() {
 	}
 }
 
+// profilesWithUsage adds the resources consumed by each running node, unless --light was passed
+func profilesWithUsage(profiles []*config.Profile) []profileJSON {
+	ps := []profileJSON{}
+	for _, p := range profiles {
+		ps = append(ps, profileJSON{Profile: p})
+	}
+	if isLight {
+		return ps
+	}
+
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		klog.Errorf("failed to get machine api client %v", err)
+		return ps
+	}
+	defer api.Close()
+
+	for i, p := range ps {
+		for _, n := range p.Config.Nodes {
+			machineName := config.MachineName(*p.Config, n)
+			hs, err := machine.Status(api, machineName)
+			if err != nil || hs != state.Running.String() {
+				continue
+			}
+			h, err := machine.LoadHost(api, machineName)
+			if err != nil {
+				klog.Warningf("error loading host %s: %v", machineName, err)
+				continue
+			}
+			u, err := machine.Usage(h, p.Config.KubernetesConfig.ContainerRuntime)
+			if err != nil {
+				klog.Warningf("error getting resource usage of %s: %v", machineName, err)
+				continue
+			}
+			if ps[i].Resources == nil {
+				ps[i].Resources = map[string]*machine.ResourceUsage{}
+			}
+			ps[i].Resources[machineName] = u
+		}
+	}
+	return ps
+}
+
 func profilesOrDefault(profiles []*config.Profile) []*config.Profile {
 	if profiles != nil {
 		return profiles
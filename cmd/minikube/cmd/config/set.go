@@ -17,20 +17,33 @@ limitations under the License.
 package config
 
 import (
+	"strconv"
+
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/util"
 )
 
+// resizeSettings are the settings which resize an existing cluster when set with --profile
+var resizeSettings = map[string]bool{
+	"cpus":   true,
+	"memory": true,
+}
+
 var configSetCmd = &cobra.Command{
 	Use:   "set PROPERTY_NAME PROPERTY_VALUE",
 	Short: "Sets an individual value in a minikube config file",
 	Long: `Sets the PROPERTY_NAME config value to PROPERTY_VALUE
-	These values can be overwritten by flags or environment variables at runtime.`,
+	These values can be overwritten by flags or environment variables at runtime.
+	When --profile is passed, cpus and memory resize that existing cluster instead: live for the docker driver, or the next time the machine starts for kvm2.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) < 2 {
 			exit.Message(reason.Usage, "not enough arguments ({{.ArgCount}}).\nusage: minikube config set PROPERTY_NAME PROPERTY_VALUE", out.V{"ArgCount": len(args)})
@@ -38,6 +51,12 @@ var configSetCmd = &cobra.Command{
 		if len(args) > 2 {
 			exit.Message(reason.Usage, "toom any arguments ({{.ArgCount}}).\nusage: minikube config set PROPERTY_NAME PROPERTY_VALUE", out.V{"ArgCount": len(args)})
 		}
+		if cmd.Flags().Changed(config.ProfileName) && resizeSettings[args[0]] {
+			if err := resizeProfile(viper.GetString(config.ProfileName), args[0], args[1]); err != nil {
+				exit.Error(reason.GuestResize, "Resize failed", err)
+			}
+			return
+		}
 		err := Set(args[0], args[1])
 		if err != nil {
 			exit.Error(reason.InternalConfigSet, "Set failed", err)
@@ -80,3 +99,39 @@ func Set(name string, value string) error {
 	// Write the value
 	return config.WriteConfig(localpath.ConfigFile(), cc)
 }
+
+// resizeProfile applies a cpus or memory setting to an existing cluster, rather than to the defaults for new clusters
+func resizeProfile(profile string, name string, value string) error {
+	s, err := findSetting(name)
+	if err != nil {
+		return errors.Wrapf(err, "find settings for %q value of %q", name, value)
+	}
+	if err := run(name, value, s.validations); err != nil {
+		return errors.Wrapf(err, "run validations for %q with value of %q", name, value)
+	}
+
+	cc, err := config.Load(profile)
+	if err != nil {
+		return errors.Wrapf(err, "load profile %q", profile)
+	}
+	switch name {
+	case "cpus":
+		cc.CPUs, err = strconv.Atoi(value)
+	case "memory":
+		cc.Memory, err = util.CalculateSizeInMB(value)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "parse %q value of %q", name, value)
+	}
+
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		return errors.Wrap(err, "api client")
+	}
+	defer api.Close()
+
+	if err := machine.ResizeCluster(api, cc, cluster.KubeletReconfigurer(api, viper.GetString(Bootstrapper))); err != nil {
+		return err
+	}
+	return config.SaveProfile(profile, cc)
+}
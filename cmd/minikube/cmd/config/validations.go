@@ -23,6 +23,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	units "github.com/docker/go-units"
 	"k8s.io/minikube/pkg/minikube/cruntime"
@@ -108,6 +109,18 @@ func IsPositive(name string, val string) error {
 	return nil
 }
 
+// IsValidDuration checks if a string parses as a positive duration, such as "720h"
+func IsValidDuration(name string, val string) error {
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		return fmt.Errorf("%s:%v", name, err)
+	}
+	if d <= 0 {
+		return fmt.Errorf("%s must be > 0", name)
+	}
+	return nil
+}
+
 // IsValidCIDR checks if a string parses as a CIDR
 func IsValidCIDR(name string, cidr string) error {
 	_, _, err := net.ParseCIDR(cidr)
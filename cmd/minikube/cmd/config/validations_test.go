@@ -55,6 +55,29 @@ func TestDriver(t *testing.T) {
 
 }
 
+func TestValidDuration(t *testing.T) {
+	var tests = []validationTest{
+		{
+			value:     "720h",
+			shouldErr: false,
+		},
+		{
+			value:     "30m",
+			shouldErr: false,
+		},
+		{
+			value:     "0s",
+			shouldErr: true,
+		},
+		{
+			value:     "30d",
+			shouldErr: true,
+		},
+	}
+
+	runValidations(t, tests, "cache-max-age", IsValidDuration)
+}
+
 func TestValidCIDR(t *testing.T) {
 	var tests = []validationTest{
 		{
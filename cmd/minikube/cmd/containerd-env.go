@@ -0,0 +1,289 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/docker/machine/libmachine/ssh"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/shell"
+)
+
+var containerdEnvTmpl = fmt.Sprintf(
+	"{{ .Prefix }}%s{{ .Delimiter }}{{ .ContainerdAddress }}{{ .Suffix }}"+
+		"{{ .Prefix }}%s{{ .Delimiter }}{{ .ContainerdNamespace }}{{ .Suffix }}"+
+		"{{ .Prefix }}%s{{ .Delimiter }}{{ .BuildkitHost }}{{ .Suffix }}"+
+		"{{ if .ExistingContainerdAddress }}"+
+		"{{ .Prefix }}%s{{ .Delimiter }}{{ .ExistingContainerdAddress }}{{ .Suffix }}"+
+		"{{ end }}"+
+		"{{ if .ExistingContainerdNamespace }}"+
+		"{{ .Prefix }}%s{{ .Delimiter }}{{ .ExistingContainerdNamespace }}{{ .Suffix }}"+
+		"{{ end }}"+
+		"{{ if .ExistingBuildkitHost }}"+
+		"{{ .Prefix }}%s{{ .Delimiter }}{{ .ExistingBuildkitHost }}{{ .Suffix }}"+
+		"{{ end }}"+
+		"{{ .Prefix }}%s{{ .Delimiter }}{{ .MinikubeContainerdProfile }}{{ .Suffix }}"+
+		"{{ .UsageHint }}",
+	constants.ContainerdAddressEnv,
+	constants.ContainerdNamespaceEnv,
+	constants.BuildkitHostEnv,
+	constants.ExistingContainerdAddressEnv,
+	constants.ExistingContainerdNamespaceEnv,
+	constants.ExistingBuildkitHostEnv,
+	constants.MinikubeActiveContainerdEnv)
+
+// ContainerdShellConfig represents the shell config for containerd
+type ContainerdShellConfig struct {
+	shell.Config
+	ContainerdAddress         string
+	ContainerdNamespace       string
+	BuildkitHost              string
+	MinikubeContainerdProfile string
+
+	ExistingContainerdAddress   string
+	ExistingContainerdNamespace string
+	ExistingBuildkitHost        string
+}
+
+var containerdUnset bool
+
+// containerdShellCfgSet generates context variables for "containerd-env"
+func containerdShellCfgSet(ec ContainerdEnvConfig, envMap map[string]string) *ContainerdShellConfig {
+	profile := ec.profile
+	const usgPlz = "To point your shell to minikube's containerd and buildkit, run:"
+	usgCmd := fmt.Sprintf("minikube -p %s containerd-env", profile)
+	s := &ContainerdShellConfig{
+		Config: *shell.CfgSet(ec.EnvConfig, usgPlz, usgCmd),
+	}
+	s.ContainerdAddress = envMap[constants.ContainerdAddressEnv]
+	s.ContainerdNamespace = envMap[constants.ContainerdNamespaceEnv]
+	s.BuildkitHost = envMap[constants.BuildkitHostEnv]
+
+	s.ExistingContainerdAddress = envMap[constants.ExistingContainerdAddressEnv]
+	s.ExistingContainerdNamespace = envMap[constants.ExistingContainerdNamespaceEnv]
+	s.ExistingBuildkitHost = envMap[constants.ExistingBuildkitHostEnv]
+
+	s.MinikubeContainerdProfile = envMap[constants.MinikubeActiveContainerdEnv]
+
+	return s
+}
+
+// containerdSocket returns the host path of the forwarded containerd socket
+func containerdSocket(profile string) string {
+	return filepath.Join(localpath.Profile(profile), "containerd.sock")
+}
+
+// buildkitSocket returns the host path of the forwarded buildkitd socket
+func buildkitSocket(profile string) string {
+	return filepath.Join(localpath.Profile(profile), "buildkitd.sock")
+}
+
+// containerdTunnelControl returns the path of the ssh control socket of the tunnel
+func containerdTunnelControl(profile string) string {
+	return filepath.Join(localpath.Profile(profile), "containerd-env.ctl")
+}
+
+// containerdTunnelArgs returns the ssh arguments forwarding the containerd and buildkitd sockets to the host
+func containerdTunnelArgs(profile string, client *ssh.ExternalClient) []string {
+	// ssh uses the first value given for an option, so ours go before the base args
+	args := []string{
+		"-f", "-N", "-M",
+		"-S", containerdTunnelControl(profile),
+		"-o", "ExitOnForwardFailure=yes",
+		"-o", "StreamLocalBindUnlink=yes",
+		"-L", fmt.Sprintf("%s:%s", containerdSocket(profile), cruntime.ContainerdSocket),
+		"-L", fmt.Sprintf("%s:%s", buildkitSocket(profile), cruntime.BuildkitSocket),
+	}
+	return append(args, client.BaseArgs...)
+}
+
+// containerdTunnelRunning checks if the tunnel of the profile is up
+func containerdTunnelRunning(profile string, client *ssh.ExternalClient) bool {
+	cmd := exec.Command(client.BinaryPath, "-S", containerdTunnelControl(profile), "-O", "check", "minikube")
+	return cmd.Run() == nil
+}
+
+// startContainerdTunnel forwards the containerd and buildkitd sockets of the node, unless already forwarded
+func startContainerdTunnel(profile string, client *ssh.ExternalClient) error {
+	if containerdTunnelRunning(profile, client) {
+		klog.Infof("reusing the containerd tunnel of %s", profile)
+		return nil
+	}
+	// ssh forks into the background once the forwards are set up, so the output is not waited upon
+	cmd := exec.Command(client.BinaryPath, containerdTunnelArgs(profile, client)...)
+	klog.Infof("starting containerd tunnel: %s", cmd.Args)
+	if err := cmd.Run(); err != nil {
+		return errors.Wrap(err, "ssh tunnel")
+	}
+	return nil
+}
+
+// stopContainerdTunnel stops the tunnel of the profile, if there is one, and removes its forwarded sockets
+func stopContainerdTunnel(profile string) {
+	ctl := containerdTunnelControl(profile)
+	if _, err := os.Stat(ctl); err != nil {
+		return
+	}
+	sshBinaryPath, err := exec.LookPath("ssh")
+	if err != nil {
+		klog.Warningf("unable to stop containerd tunnel: %v", err)
+		return
+	}
+	if err := exec.Command(sshBinaryPath, "-S", ctl, "-O", "exit", "minikube").Run(); err != nil {
+		klog.Infof("stopping containerd tunnel: %v", err)
+	}
+	for _, sock := range []string{containerdSocket(profile), buildkitSocket(profile)} {
+		if err := os.Remove(sock); err != nil && !os.IsNotExist(err) {
+			klog.Warningf("unable to remove %s: %v", sock, err)
+		}
+	}
+}
+
+// containerdEnvCmd represents the containerd-env command
+var containerdEnvCmd = &cobra.Command{
+	Use:   "containerd-env",
+	Short: "Configure environment to use minikube's containerd and buildkit",
+	Long: `Sets up containerd and buildkit env variables, for clients such as nerdctl and buildctl.
+
+The containerd and buildkitd sockets of the node are forwarded over ssh to the minikube profile directory,
+so that images built with 'nerdctl build' or 'buildctl' land straight in the k8s.io namespace used by Kubernetes.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		sh := shell.EnvConfig{
+			Shell: shell.ForceShell,
+		}
+
+		if containerdUnset {
+			if profile := os.Getenv(constants.MinikubeActiveContainerdEnv); profile != "" {
+				stopContainerdTunnel(profile)
+			}
+			if err := containerdUnsetScript(ContainerdEnvConfig{EnvConfig: sh}, os.Stdout); err != nil {
+				exit.Error(reason.InternalEnvScript, "Error generating unset output", err)
+			}
+			return
+		}
+
+		if runtime.GOOS == "windows" {
+			exit.Message(reason.Usage, `The containerd-env command is not supported on Windows, which cannot forward unix sockets over ssh`)
+		}
+
+		cname := ClusterFlagValue()
+		co := mustload.Running(cname)
+		driverName := co.CP.Host.DriverName
+
+		if driverName == driver.None {
+			exit.Message(reason.EnvDriverConflict, `'none' driver does not support 'minikube containerd-env' command`)
+		}
+
+		if len(co.Config.Nodes) > 1 {
+			exit.Message(reason.EnvMultiConflict, `The containerd-env command is incompatible with multi-node clusters. Use the 'registry' add-on: https://minikube.sigs.k8s.io/docs/handbook/registry/`)
+		}
+
+		if co.Config.KubernetesConfig.ContainerRuntime != "containerd" {
+			exit.Message(reason.Usage, `The containerd-env command is only compatible with the "containerd" runtime, but this cluster was configured to use the "{{.runtime}}" runtime. For "cri-o", use 'minikube podman-env' instead.`,
+				out.V{"runtime": co.Config.KubernetesConfig.ContainerRuntime})
+		}
+
+		d := co.CP.Host.Driver
+		if err := cruntime.EnableContainerdEnv(co.CP.Runner, d.GetSSHUsername()); err != nil {
+			exit.Message(reason.EnvContainerdUnavailable, `The containerd and buildkit services within '{{.cluster}}' are not available: {{.error}}`, out.V{"cluster": cname, "error": err})
+		}
+
+		client, err := createExternalSSHClient(d)
+		if err != nil {
+			exit.Error(reason.IfSSHClient, "Error getting ssh client", err)
+		}
+
+		if err := startContainerdTunnel(cname, client); err != nil {
+			exit.Error(reason.IfSSHClient, "Error forwarding the containerd socket", err)
+		}
+
+		ec := ContainerdEnvConfig{
+			EnvConfig: sh,
+			profile:   cname,
+		}
+
+		if ec.Shell == "" {
+			ec.Shell, err = shell.Detect()
+			if err != nil {
+				exit.Error(reason.InternalShellDetect, "Error detecting shell", err)
+			}
+		}
+
+		if err := containerdSetScript(ec, os.Stdout); err != nil {
+			exit.Error(reason.InternalEnvScript, "Error generating set output", err)
+		}
+	},
+}
+
+// ContainerdEnvConfig encapsulates all external inputs into shell generation for containerd
+type ContainerdEnvConfig struct {
+	shell.EnvConfig
+	profile string
+}
+
+// containerdSetScript writes out a shell-compatible 'containerd-env' script
+func containerdSetScript(ec ContainerdEnvConfig, w io.Writer) error {
+	envVars := containerdEnvVars(ec)
+	return shell.SetScript(ec.EnvConfig, w, containerdEnvTmpl, containerdShellCfgSet(ec, envVars))
+}
+
+// containerdUnsetScript writes out a shell-compatible 'containerd-env unset' script
+func containerdUnsetScript(ec ContainerdEnvConfig, w io.Writer) error {
+	vars := append(constants.ContainerdEnvs[:], constants.MinikubeActiveContainerdEnv)
+	return shell.UnsetScript(ec.EnvConfig, w, vars)
+}
+
+// containerdEnvVars gets the necessary containerd env variables to allow the use of minikube's containerd and buildkit
+func containerdEnvVars(ec ContainerdEnvConfig) map[string]string {
+	env := map[string]string{
+		constants.ContainerdAddressEnv:        containerdSocket(ec.profile),
+		constants.ContainerdNamespaceEnv:      cruntime.ContainerdNamespace,
+		constants.BuildkitHostEnv:             "unix://" + buildkitSocket(ec.profile),
+		constants.MinikubeActiveContainerdEnv: ec.profile,
+	}
+	if os.Getenv(constants.MinikubeActiveContainerdEnv) == "" {
+		for _, e := range constants.ContainerdEnvs {
+			if v := oci.InitialEnv(e); v != "" {
+				key := constants.MinikubeExistingPrefix + e
+				env[key] = v
+			}
+		}
+	}
+	return env
+}
+
+func init() {
+	containerdEnvCmd.Flags().StringVar(&shell.ForceShell, "shell", "", "Force environment to be configured for a specified shell: [fish, cmd, powershell, tcsh, bash, zsh], default is auto-detect")
+	containerdEnvCmd.Flags().BoolVarP(&containerdUnset, "unset", "u", false, "Unset variables instead of setting them")
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+func TestGenerateContainerdScripts(t *testing.T) {
+	profile := localpath.Profile("bash")
+
+	var tests = []struct {
+		shell     string
+		config    ContainerdEnvConfig
+		wantSet   string
+		wantUnset string
+	}{
+		{
+			"bash",
+			ContainerdEnvConfig{profile: "bash"},
+			fmt.Sprintf(`export CONTAINERD_ADDRESS="%[1]s/containerd.sock"
+export CONTAINERD_NAMESPACE="k8s.io"
+export BUILDKIT_HOST="unix://%[1]s/buildkitd.sock"
+export MINIKUBE_ACTIVE_CONTAINERD="bash"
+
+# To point your shell to minikube's containerd and buildkit, run:
+# eval $(minikube -p bash containerd-env)
+`, profile),
+			`unset CONTAINERD_ADDRESS;
+unset CONTAINERD_NAMESPACE;
+unset BUILDKIT_HOST;
+unset MINIKUBE_ACTIVE_CONTAINERD;
+`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.config.profile, func(t *testing.T) {
+			tc.config.EnvConfig.Shell = tc.shell
+			var b []byte
+			buf := bytes.NewBuffer(b)
+			if err := containerdSetScript(tc.config, buf); err != nil {
+				t.Errorf("setScript(%+v) error: %v", tc.config, err)
+			}
+			got := buf.String()
+			if diff := cmp.Diff(tc.wantSet, got); diff != "" {
+				t.Errorf("setScript(%+v) mismatch (-want +got):\n%s\n\nraw output:\n%s\nquoted: %q", tc.config, diff, got, got)
+			}
+
+			buf = bytes.NewBuffer(b)
+			if err := containerdUnsetScript(tc.config, buf); err != nil {
+				t.Errorf("unsetScript(%+v) error: %v", tc.config, err)
+			}
+			got = buf.String()
+			if diff := cmp.Diff(tc.wantUnset, got); diff != "" {
+				t.Errorf("unsetScript(%+v) mismatch (-want +got):\n%s\n\nraw output:\n%s\nquoted: %q", tc.config, diff, got, got)
+			}
+		})
+	}
+}
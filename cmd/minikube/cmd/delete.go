@@ -48,6 +48,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/out/register"
 	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/registrycache"
 	"k8s.io/minikube/pkg/minikube/style"
 )
 
@@ -115,8 +116,8 @@ func deleteContainersAndVolumes(ctx context.Context, ociBin string) {
 		klog.Warningf("error delete volumes by label %q (might be okay): %+v", delLabel, errs)
 	}
 
-	if ociBin == oci.Podman {
-		// podman prune does not support --filter
+	if ociBin == oci.Podman || ociBin == oci.Nerdctl {
+		// podman and nerdctl prune do not support --filter
 		return
 	}
 
@@ -154,6 +155,7 @@ func runDelete(cmd *cobra.Command, args []string) {
 	if deleteAll {
 		deleteContainersAndVolumes(delCtx, oci.Docker)
 		deleteContainersAndVolumes(delCtx, oci.Podman)
+		deleteContainersAndVolumes(delCtx, oci.Nerdctl)
 
 		errs := DeleteProfiles(profilesToDelete)
 		register.Reg.SetStep(register.Done)
@@ -188,11 +190,15 @@ func runDelete(cmd *cobra.Command, args []string) {
 			// TODO: generalize for non-KIC drivers: #8040
 			deletePossibleKicLeftOver(delCtx, cname, driver.Docker)
 			deletePossibleKicLeftOver(delCtx, cname, driver.Podman)
+			deletePossibleKicLeftOver(delCtx, cname, driver.Nerdctl)
 		}
 	}
 
-	// If the purge flag is set, go ahead and delete the .minikube directory.
+	// If the purge flag is set, go ahead and delete the .minikube directory, and the registry cache which outlives the profiles.
 	if purge {
+		if err := registrycache.Delete(); err != nil {
+			klog.Warningf("failed to delete the registry cache: %v", err)
+		}
 		purgeMinikubeDirectory()
 	}
 }
@@ -237,6 +243,8 @@ func deletePossibleKicLeftOver(ctx context.Context, cname string, driverName str
 		bin = oci.Docker
 	case driver.Podman:
 		bin = oci.Podman
+	case driver.Nerdctl:
+		bin = oci.Nerdctl
 	default:
 		return
 	}
@@ -286,8 +294,8 @@ func deletePossibleKicLeftOver(ctx context.Context, cname string, driverName str
 		klog.Warningf("error deleting leftover networks (might be okay).\nTo see the list of networks: 'docker network ls'\n:%v", errs)
 	}
 
-	if bin == oci.Podman {
-		// podman prune does not support --filter
+	if bin == oci.Podman || bin == oci.Nerdctl {
+		// podman and nerdctl prune do not support --filter
 		return
 	}
 
@@ -331,20 +339,28 @@ func deleteProfile(ctx context.Context, profile *config.Profile) error {
 	}
 
 	if err == nil && (driver.BareMetal(cc.Driver) || driver.IsSSH(cc.Driver)) {
-		if err := uninstallKubernetes(api, *cc, cc.Nodes[0], viper.GetString(cmdcfg.Bootstrapper)); err != nil {
-			deletionError, ok := err.(DeletionError)
-			if ok {
-				delErr := profileDeletionErr(profile.Name, fmt.Sprintf("%v", err))
-				deletionError.Err = delErr
-				return deletionError
+		nodes := cc.Nodes[:1]
+		// every node of the ssh driver is a host of its own
+		if driver.IsSSH(cc.Driver) {
+			nodes = cc.Nodes
+		}
+		for _, n := range nodes {
+			if err := uninstallKubernetes(api, *cc, n, viper.GetString(cmdcfg.Bootstrapper)); err != nil {
+				deletionError, ok := err.(DeletionError)
+				if ok {
+					delErr := profileDeletionErr(profile.Name, fmt.Sprintf("%v", err))
+					deletionError.Err = delErr
+					return deletionError
+				}
+				return err
 			}
-			return err
 		}
 	}
 
 	if err := killMountProcess(); err != nil {
 		out.FailureT("Failed to kill mount process: {{.error}}", out.V{"error": err})
 	}
+	stopContainerdTunnel(profile.Name)
 
 	deleteHosts(api, cc)
 
@@ -356,7 +372,7 @@ func deleteProfile(ctx context.Context, profile *config.Profile) error {
 		return err
 	}
 
-	if err := deleteContext(profile.Name); err != nil {
+	if err := deleteContext(profile.Name, kubeconfig.PathFromConfig(cc)); err != nil {
 		return err
 	}
 	out.Step(style.Deleted, `Removed all traces of the "{{.name}}" cluster.`, out.V{"name": profile.Name})
@@ -394,8 +410,8 @@ func deleteConfig(cname string) error {
 	return nil
 }
 
-func deleteContext(machineName string) error {
-	if err := kubeconfig.DeleteContext(machineName); err != nil {
+func deleteContext(machineName string, configPath string) error {
+	if err := kubeconfig.DeleteContext(machineName, configPath); err != nil {
 		return DeletionError{Err: fmt.Errorf("update config: %v", err), Errtype: Fatal}
 	}
 
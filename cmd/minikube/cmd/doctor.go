@@ -0,0 +1,101 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/doctor"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var doctorOutput string
+
+var doctorStyles = map[doctor.Status]style.Enum{
+	doctor.Pass: style.Check,
+	doctor.Warn: style.Warning,
+	doctor.Fail: style.Failure,
+}
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnoses common problems with a cluster",
+	Long: `Runs a series of health checks against a cluster, such as certificate expiry, disk pressure,
+clock skew, DNS resolution and the health of the apiserver and system pods, and suggests how to fix any problems found.
+Exit status is non-zero if any check failed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if doctorOutput != "text" && doctorOutput != "json" {
+			exit.Message(reason.Usage, "Invalid output format: {{.format}}. Valid values: 'text', 'json'", out.V{"format": doctorOutput})
+		}
+
+		api, cc := mustload.Partial(ClusterFlagValue())
+		defer api.Close()
+
+		results := doctor.Run(api, cc, viper.GetString(cmdcfg.Bootstrapper))
+		switch doctorOutput {
+		case "json":
+			printDoctorJSON(results)
+		default:
+			printDoctorText(results)
+		}
+
+		if !doctor.Healthy(results) {
+			os.Exit(reason.ExFailure)
+		}
+	},
+}
+
+func printDoctorText(results []doctor.Result) {
+	for _, r := range results {
+		check := r.Check
+		if r.Node != "" {
+			check = fmt.Sprintf("%s (%s)", r.Check, r.Node)
+		}
+		out.Step(doctorStyles[r.Status], "{{.check}}: {{.message}}", out.V{"check": check, "message": r.Message})
+		if r.Status == doctor.Pass {
+			continue
+		}
+		if r.Advice != "" {
+			out.Infof("{{.advice}}", out.V{"advice": r.Advice})
+		}
+		if r.URL != "" {
+			out.Infof("Documentation: {{.url}}", out.V{"url": r.URL})
+		}
+	}
+}
+
+func printDoctorJSON(results []doctor.Result) {
+	b, err := json.Marshal(results)
+	if err != nil {
+		exit.Error(reason.InternalJSONMarshal, "marshalling doctor results", err)
+	}
+	out.Ln("%s", b)
+}
+
+func init() {
+	doctorCmd.Flags().StringVarP(&doctorOutput, "output", "o", "text", "Format to print stdout in. Options include: [text,json]")
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/version"
+)
+
+var (
+	downloadK8sVersion string
+	downloadRuntime    string
+)
+
+// downloadCmd represents the download command
+var downloadCmd = &cobra.Command{
+	Use:   "download",
+	Short: "Manage where minikube downloads its artifacts from",
+	Long:  "Manage where minikube downloads the ISO, preloads, Kubernetes binaries and drivers from, which the artifact-mirror settings configure",
+}
+
+// downloadCheckCmd represents the download check command
+var downloadCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check that the artifacts to start a cluster can be downloaded",
+	Long: `Check that the mirrors configured by the artifact-mirror, iso-mirror, preload-mirror, binary-mirror and driver-mirror settings,
+then upstream, serve the artifacts to start a cluster and their checksum files, in the order minikube tries them, without downloading them.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		driverName := downloadedDriver()
+		dv, err := version.GetSemverVersion()
+		if err != nil {
+			klog.Warningf("not checking the %s driver: %v", driverName, err)
+			driverName = ""
+		}
+		if driverName != "" {
+			driverName = "docker-machine-driver-" + driverName
+		}
+
+		checks := download.Check(downloadK8sVersion, downloadRuntime, driverName, dv)
+		failed := 0
+		for _, c := range checks {
+			out.Step(style.Verifying, "{{.artifact}}:", out.V{"artifact": c.Name})
+			for _, l := range c.Locations {
+				if l.Err == nil {
+					out.Step(style.Check, "{{.location}}", out.V{"location": l.Location})
+					continue
+				}
+				out.Step(style.Failure, "{{.location}}: {{.error}}", out.V{"location": l.Location, "error": l.Err})
+			}
+			if !c.OK() {
+				failed++
+				out.WarningT("{{.artifact}} can't be downloaded from any location", out.V{"artifact": c.Name})
+			} else if c.MirrorFailed() {
+				out.WarningT("A mirror of {{.artifact}} failed, it will be downloaded from further down the fallback chain", out.V{"artifact": c.Name})
+			}
+		}
+		if failed > 0 {
+			exit.Message(reason.HostDownloadCheck, "{{.count}} artifacts can't be downloaded", out.V{"count": failed})
+		}
+		out.Step(style.Success, "All {{.count}} artifacts can be downloaded", out.V{"count": len(checks)})
+	},
+}
+
+// downloadedDriver returns the driver minikube downloads on this host, if any
+func downloadedDriver() string {
+	switch runtime.GOOS {
+	case "linux":
+		return driver.KVM2
+	case "darwin":
+		return driver.HyperKit
+	}
+	return ""
+}
+
+func init() {
+	downloadCheckCmd.Flags().StringVar(&downloadK8sVersion, "kubernetes-version", constants.DefaultKubernetesVersion, "The Kubernetes version to check the preload and binaries of.")
+	downloadCheckCmd.Flags().StringVar(&downloadRuntime, "container-runtime", "docker", fmt.Sprintf("The container runtime to check the preload of, one of: %s", strings.Join(cruntime.ValidRuntimes(), ", ")))
+	downloadCmd.AddCommand(downloadCheckCmd)
+}
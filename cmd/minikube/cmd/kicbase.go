@@ -0,0 +1,116 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/drivers/kic"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/version"
+)
+
+var (
+	kicbaseSource      string
+	kicbaseTag         string
+	kicbaseDriver      string
+	kicbaseK8sVersion  string
+	kicbaseCrioVersion string
+	kicbaseBuildArgs   []string
+)
+
+// kicbaseCmd represents the kicbase command
+var kicbaseCmd = &cobra.Command{
+	Use:   "kicbase",
+	Short: "Manage the base image of the docker and podman drivers",
+	Long:  "Manage the base image of the docker and podman drivers",
+}
+
+// kicbaseBuildCmd represents the kicbase build command
+var kicbaseBuildCmd = &cobra.Command{
+	Use:   "build",
+	Short: "Build a base image for the docker and podman drivers from local sources",
+	Long: `Build a base image for the docker and podman drivers from the deploy/kicbase sources, and store it in the local docker or podman.
+The built image is printed pinned to its image ID, which minikube verifies before creating containers from it.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if kicbaseDriver != oci.Docker && kicbaseDriver != oci.Podman {
+			exit.Message(reason.Usage, "--driver must be either {{.docker}} or {{.podman}}", out.V{"docker": oci.Docker, "podman": oci.Podman})
+		}
+		if _, err := os.Stat(filepath.Join(kicbaseSource, "Dockerfile")); err != nil {
+			exit.Message(reason.HostPathMissing, "No kicbase Dockerfile found in {{.source}}, please point --source to the deploy/kicbase directory of the minikube sources", out.V{"source": kicbaseSource})
+		}
+
+		buildArgs, err := kicbaseBuildArgList()
+		if err != nil {
+			exit.Message(reason.Usage, "{{.err}}", out.V{"err": err})
+		}
+
+		out.Step(style.Provisioner, "Building {{.tag}} from {{.source}} ...", out.V{"tag": kicbaseTag, "source": kicbaseSource})
+		if err := oci.BuildImage(kicbaseDriver, kicbaseSource, kicbaseTag, buildArgs, os.Stderr); err != nil {
+			exit.Error(reason.HostKicbaseBuild, "Failed to build the kicbase image", err)
+		}
+		id, err := oci.ImageID(kicbaseDriver, kicbaseTag)
+		if err != nil {
+			exit.Error(reason.HostKicbaseBuild, "Failed to get the ID of the kicbase image", err)
+		}
+
+		pinned := kicbaseTag + "@" + id
+		out.Step(style.Success, "Built {{.image}}", out.V{"image": pinned})
+		out.Step(style.Tip, "To create a cluster from it, run: minikube start --driver={{.driver}} --base-image={{.image}}", out.V{"driver": kicbaseDriver, "image": pinned})
+	},
+}
+
+// kicbaseBuildArgList returns the --build-arg values for the Dockerfile from the command line flags
+func kicbaseBuildArgList() ([]string, error) {
+	args := []string{fmt.Sprintf("COMMIT_SHA=%s-%s", version.GetVersion(), version.GetGitCommitID())}
+	if kicbaseK8sVersion != "" {
+		v, err := semver.Make(strings.TrimPrefix(kicbaseK8sVersion, version.VersionPrefix))
+		if err != nil {
+			return nil, fmt.Errorf("invalid --kubernetes-version %q: %v", kicbaseK8sVersion, err)
+		}
+		args = append(args, "KUBERNETES_VERSION=v"+v.String())
+	}
+	if kicbaseCrioVersion != "" {
+		args = append(args, "CRIO_VERSION="+kicbaseCrioVersion)
+	}
+	for _, a := range kicbaseBuildArgs {
+		if !strings.Contains(a, "=") {
+			return nil, fmt.Errorf("invalid --build-arg %q, expected KEY=VALUE", a)
+		}
+		args = append(args, a)
+	}
+	return args, nil
+}
+
+func init() {
+	kicbaseBuildCmd.Flags().StringVar(&kicbaseSource, "source", filepath.Join("deploy", "kicbase"), "The directory holding the kicbase Dockerfile.")
+	kicbaseBuildCmd.Flags().StringVar(&kicbaseTag, "tag", "local/kicbase:"+kic.Version, "The tag of the image to build.")
+	kicbaseBuildCmd.Flags().StringVar(&kicbaseDriver, "driver", oci.Docker, "The driver to build and store the image with, either docker or podman.")
+	kicbaseBuildCmd.Flags().StringVar(&kicbaseK8sVersion, "kubernetes-version", "", "The Kubernetes version whose binaries are baked into the image, none if empty.")
+	kicbaseBuildCmd.Flags().StringVar(&kicbaseCrioVersion, "crio-version", "", "The cri-o version to install, the Dockerfile default if empty.")
+	kicbaseBuildCmd.Flags().StringSliceVar(&kicbaseBuildArgs, "build-arg", []string{}, "Extra KEY=VALUE build arguments for the Dockerfile.")
+	kicbaseCmd.AddCommand(kicbaseBuildCmd)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/kubeconfig"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/util/lock"
+)
+
+var (
+	exportEmbedCerts  bool
+	exportOutput      string
+	exportContextName string
+)
+
+// kubeconfigCmd represents the set of kubeconfig subcommands
+var kubeconfigCmd = &cobra.Command{
+	Use:   "kubeconfig",
+	Short: "Manage kubeconfig files for a cluster",
+	Long:  "Manage kubeconfig files for a cluster",
+	Run: func(cmd *cobra.Command, args []string) {
+		exit.Message(reason.Usage, "Usage: minikube kubeconfig export")
+	},
+}
+
+// kubeconfigExportCmd represents the kubeconfig export command
+var kubeconfigExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write a standalone kubeconfig for the cluster",
+	Long: `Writes a kubeconfig which only contains the context for the specified cluster, without reading or modifying $KUBECONFIG or ~/.kube/config.
+Useful for mounting into containers or CI jobs, combined with --embed-certs.`,
+	Example: `minikube kubeconfig export --embed-certs --output ./kubeconfig`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cname := ClusterFlagValue()
+		co := mustload.Running(cname)
+
+		kcs := node.SetupKubeconfig(co.CP.Host, co.Config, co.CP.Node, cname)
+		kcs.EmbedCerts = exportEmbedCerts
+		if exportContextName != "" {
+			kcs.ClusterName = exportContextName
+		}
+
+		data, err := kubeconfig.Export(kcs)
+		if err != nil {
+			exit.Error(reason.HostKubeconfigUpdate, "export kubeconfig", err)
+		}
+
+		if exportOutput == "" {
+			if _, err := os.Stdout.Write(data); err != nil {
+				exit.Error(reason.HostKubeconfigUpdate, "write kubeconfig", err)
+			}
+			return
+		}
+
+		// write with restricted permissions, as the file may hold embedded credentials
+		if err := lock.WriteFile(exportOutput, data, 0600); err != nil {
+			exit.Error(reason.HostKubeconfigUpdate, "write kubeconfig", err)
+		}
+		out.Step(style.Celebrate, `Wrote the "{{.context}}" context to {{.path}}`, out.V{"context": kcs.ClusterName, "path": exportOutput})
+	},
+}
+
+func init() {
+	kubeconfigExportCmd.Flags().BoolVar(&exportEmbedCerts, "embed-certs", false, "If true, embed the certificates in the kubeconfig instead of referencing files under the minikube home directory.")
+	kubeconfigExportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "File to write the kubeconfig to. Defaults to STDOUT.")
+	kubeconfigExportCmd.Flags().StringVar(&exportContextName, "context-name", "", "Name of the cluster, user and context in the kubeconfig. Defaults to the profile name.")
+	kubeconfigCmd.AddCommand(kubeconfigExportCmd)
+}
@@ -48,6 +48,10 @@ minikube kubectl -- get pods --namespace kube-system`,
 			out.ErrLn("Error caching kubectl: %v", err)
 		}
 
+		if co.Config.KubeconfigPath != "" {
+			c.Env = append(os.Environ(), fmt.Sprintf("%s=%s", constants.KubeconfigEnvVar, co.Config.KubeconfigPath))
+		}
+
 		klog.Infof("Running %s %v", c.Path, args)
 		c.Stdin = os.Stdin
 		c.Stdout = os.Stdout
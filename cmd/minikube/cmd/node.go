@@ -17,17 +17,37 @@ limitations under the License.
 package cmd
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/node"
 	"k8s.io/minikube/pkg/minikube/reason"
 )
 
+var (
+	drainForce   bool
+	drainTimeout time.Duration
+)
+
 // nodeCmd represents the set of node subcommands
 var nodeCmd = &cobra.Command{
 	Use:   "node",
 	Short: "Add, remove, or list additional nodes",
 	Long:  "Operations on nodes",
 	Run: func(cmd *cobra.Command, args []string) {
-		exit.Message(reason.Usage, "Usage: minikube node [add|start|stop|delete|list]")
+		exit.Message(reason.Usage, "Usage: minikube node [add|start|stop|delete|drain|uncordon|list]")
 	},
 }
+
+// addDrainFlags adds the flags controlling how pods are evicted from worker nodes
+func addDrainFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&drainForce, "force", false, "Also evict pods not managed by a controller, and go on if the node can not be drained.")
+	cmd.Flags().DurationVar(&drainTimeout, "timeout", 5*time.Minute, "The maximum time to wait for the pods of the node to be evicted, zero waits forever.")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Format to print stdout in. Options include: [text,json]")
+}
+
+// drainOptions returns the drain options from the command line flags
+func drainOptions() node.DrainOptions {
+	return node.DrainOptions{Force: drainForce, Timeout: drainTimeout}
+}
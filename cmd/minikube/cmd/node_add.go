@@ -31,8 +31,13 @@ import (
 )
 
 var (
-	cp     bool
-	worker bool
+	cp          bool
+	worker      bool
+	nodeSSHIP   string
+	nodeSSHUser string
+	nodeSSHKey  string
+	nodeSSHPort int
+	nodeIP      string
 )
 
 var nodeAddCmd = &cobra.Command{
@@ -47,8 +52,46 @@ var nodeAddCmd = &cobra.Command{
 			out.FailureT("none driver does not support multi-node clusters")
 		}
 
+		if cp && !cc.HA {
+			exit.Message(reason.Usage, "Control plane nodes can only be added to highly available clusters, created with 'minikube start --ha'")
+		}
+
+		if driver.IsQEMUUserNetwork(cc.Driver, cc.Network) {
+			exit.Message(reason.Usage, "The VMs on the qemu user network can't reach each other, recreate the cluster with --network=socket_vmnet to add nodes")
+		}
+
+		if driver.IsSSH(cc.Driver) {
+			validateSSHIPAddress(nodeSSHIP)
+			for _, n := range cc.Nodes {
+				if n.SSHIPAddress == nodeSSHIP {
+					exit.Message(reason.Usage, "{{.ip}} is already node {{.name}} of cluster {{.cluster}}", out.V{"ip": nodeSSHIP, "name": config.MachineName(*cc, n), "cluster": cc.Name})
+				}
+			}
+		} else if cmd.Flags().Changed(sshIPAddress) {
+			exit.Message(reason.Usage, "--ssh-ip-address is only supported by the ssh driver")
+		}
+
 		name := node.Name(len(cc.Nodes) + 1)
 
+		if nodeIP != "" {
+			if cc.Subnet == "" {
+				exit.Message(reason.Usage, "--static-ip requires a cluster started with --subnet or --static-ip")
+			}
+			validateStaticIP(nodeIP, cc.Subnet)
+			for _, n := range cc.Nodes {
+				if n.StaticIP == nodeIP || n.IP == nodeIP || (n.StaticIP == "" && kicNodeIP(*cc, n) == nodeIP) {
+					exit.Message(reason.Usage, "{{.ip}} is already the IP of node {{.name}} of cluster {{.cluster}}", out.V{"ip": nodeIP, "name": config.MachineName(*cc, n), "cluster": cc.Name})
+				}
+			}
+		} else if ip := kicNodeIP(*cc, config.Node{Name: name}); ip != "" {
+			// the kic drivers compute the IP of the node from its index, which another node may have taken with --static-ip
+			for _, n := range cc.Nodes {
+				if n.StaticIP == ip {
+					exit.Message(reason.Usage, "{{.ip}} is the static IP of node {{.name}} of cluster {{.cluster}}, add the node with --static-ip", out.V{"ip": ip, "name": config.MachineName(*cc, n), "cluster": cc.Name})
+				}
+			}
+		}
+
 		out.Step(style.Happy, "Adding node {{.name}} to cluster {{.cluster}}", out.V{"name": name, "cluster": cc.Name})
 
 		// TODO: Deal with parameters better. Ideally we should be able to acceot any node-specific minikube start params here.
@@ -57,6 +100,20 @@ var nodeAddCmd = &cobra.Command{
 			Worker:            worker,
 			ControlPlane:      cp,
 			KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
+			StaticIP:          nodeIP,
+		}
+		if cp {
+			primary, err := config.PrimaryControlPlane(cc)
+			if err != nil {
+				exit.Error(reason.GuestCpConfig, "Error getting primary control plane", err)
+			}
+			n.Port = primary.Port
+		}
+		if driver.IsSSH(cc.Driver) {
+			n.SSHIPAddress = nodeSSHIP
+			n.SSHUser = nodeSSHUser
+			n.SSHKey = nodeSSHKey
+			n.SSHPort = nodeSSHPort
 		}
 
 		// Make sure to decrease the default amount of memory we use per VM if this is the first worker node
@@ -87,8 +144,13 @@ var nodeAddCmd = &cobra.Command{
 
 func init() {
 	// TODO(https://github.com/kubernetes/minikube/issues/7366): We should figure out which minikube start flags to actually import
-	nodeAddCmd.Flags().BoolVar(&cp, "control-plane", false, "If true, the node added will also be a control plane in addition to a worker.")
+	nodeAddCmd.Flags().BoolVar(&cp, "control-plane", false, "If true, the node added will also be a control plane in addition to a worker, for clusters created with --ha only.")
 	nodeAddCmd.Flags().BoolVar(&worker, "worker", true, "If true, the added node will be marked for work. Defaults to true.")
+	nodeAddCmd.Flags().StringVar(&nodeIP, staticIP, "", "Static IP of the node, within the subnet of the cluster (kvm2, docker, podman and nerdctl drivers only)")
+	nodeAddCmd.Flags().StringVar(&nodeSSHIP, sshIPAddress, "", "IP address of the existing host to join (ssh driver only)")
+	nodeAddCmd.Flags().StringVar(&nodeSSHUser, sshSSHUser, defaultSSHUser, "SSH user (ssh driver only)")
+	nodeAddCmd.Flags().StringVar(&nodeSSHKey, sshSSHKey, "", "SSH key (ssh driver only)")
+	nodeAddCmd.Flags().IntVar(&nodeSSHPort, sshSSHPort, defaultSSHPort, "SSH port (ssh driver only)")
 	nodeAddCmd.Flags().Bool(deleteOnFailure, false, "If set, delete the current cluster if start fails and try again. Defaults to false.")
 
 	nodeCmd.AddCommand(nodeAddCmd)
@@ -27,6 +27,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/mustload"
 	"k8s.io/minikube/pkg/minikube/node"
 	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/out/register"
 	"k8s.io/minikube/pkg/minikube/reason"
 	"k8s.io/minikube/pkg/minikube/style"
 )
@@ -39,12 +40,13 @@ var nodeDeleteCmd = &cobra.Command{
 		if len(args) == 0 {
 			exit.Message(reason.Usage, "Usage: minikube node delete [name]")
 		}
+		out.SetJSON(outputFormat == "json")
 		name := args[0]
 
 		co := mustload.Healthy(ClusterFlagValue())
 		out.Step(style.DeletingHost, "Deleting node {{.name}} from cluster {{.cluster}}", out.V{"name": name, "cluster": co.Config.Name})
 
-		n, err := node.Delete(*co.Config, name)
+		n, err := node.Delete(*co.Config, name, drainOptions())
 		if err != nil {
 			exit.Error(reason.GuestNodeDelete, "deleting node", err)
 		}
@@ -56,10 +58,12 @@ var nodeDeleteCmd = &cobra.Command{
 			deletePossibleKicLeftOver(ctx, machineName, co.Config.Driver)
 		}
 
+		register.Reg.SetStep(register.Done)
 		out.Step(style.Deleted, "Node {{.name}} was successfully deleted.", out.V{"name": name})
 	},
 }
 
 func init() {
+	addDrainFlags(nodeDeleteCmd)
 	nodeCmd.AddCommand(nodeDeleteCmd)
 }
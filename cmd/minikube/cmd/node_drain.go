@@ -0,0 +1,58 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/out/register"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var nodeDrainCmd = &cobra.Command{
+	Use:   "drain",
+	Short: "Cordons a node and evicts its pods.",
+	Long:  "Marks a node as unschedulable and evicts its pods, respecting their PodDisruptionBudgets. Pods of DaemonSets are left on the node.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.Message(reason.Usage, "Usage: minikube node drain [name]")
+		}
+		out.SetJSON(outputFormat == "json")
+		name := args[0]
+
+		co := mustload.Healthy(ClusterFlagValue())
+		n, _, err := node.Retrieve(*co.Config, name)
+		if err != nil {
+			exit.Error(reason.GuestNodeRetrieve, "retrieving node", err)
+		}
+
+		if err := node.Drain(*co.Config, *n, drainOptions()); err != nil {
+			exit.Error(reason.GuestNodeDrain, "draining node", err)
+		}
+		register.Reg.SetStep(register.Done)
+		out.Step(style.Ready, "Node {{.name}} was successfully drained.", out.V{"name": name})
+	},
+}
+
+func init() {
+	addDrainFlags(nodeDrainCmd)
+	nodeCmd.AddCommand(nodeDrainCmd)
+}
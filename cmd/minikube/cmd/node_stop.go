@@ -20,10 +20,10 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/exit"
-	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/minikube/mustload"
 	"k8s.io/minikube/pkg/minikube/node"
 	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/out/register"
 	"k8s.io/minikube/pkg/minikube/reason"
 	"k8s.io/minikube/pkg/minikube/style"
 )
@@ -37,6 +37,7 @@ var nodeStopCmd = &cobra.Command{
 			exit.Message(reason.Usage, "Usage: minikube node stop [name]")
 		}
 
+		out.SetJSON(outputFormat == "json")
 		name := args[0]
 		api, cc := mustload.Partial(ClusterFlagValue())
 
@@ -47,14 +48,16 @@ var nodeStopCmd = &cobra.Command{
 
 		machineName := config.MachineName(*cc, *n)
 
-		err = machine.StopHost(api, machineName)
+		err = node.Stop(api, *cc, *n, drainOptions())
 		if err != nil {
-			out.FatalT("Failed to stop node {{.name}}", out.V{"name": name})
+			out.FatalT("Failed to stop node {{.name}}: {{.error}}", out.V{"name": name, "error": err})
 		}
+		register.Reg.SetStep(register.Done)
 		out.Step(style.Stopped, "Successfully stopped node {{.name}}", out.V{"name": machineName})
 	},
 }
 
 func init() {
+	addDrainFlags(nodeStopCmd)
 	nodeCmd.AddCommand(nodeStopCmd)
 }
@@ -0,0 +1,58 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/out/register"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var nodeUncordonCmd = &cobra.Command{
+	Use:   "uncordon",
+	Short: "Marks a node as schedulable again.",
+	Long:  "Marks a node as schedulable again, after it was drained.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.Message(reason.Usage, "Usage: minikube node uncordon [name]")
+		}
+		out.SetJSON(outputFormat == "json")
+		name := args[0]
+
+		co := mustload.Healthy(ClusterFlagValue())
+		n, _, err := node.Retrieve(*co.Config, name)
+		if err != nil {
+			exit.Error(reason.GuestNodeRetrieve, "retrieving node", err)
+		}
+
+		if err := node.Uncordon(*co.Config, *n); err != nil {
+			exit.Error(reason.GuestNodeDrain, "uncordoning node", err)
+		}
+		register.Reg.SetStep(register.Done)
+		out.Step(style.Ready, "Node {{.name}} is schedulable again.", out.V{"name": name})
+	},
+}
+
+func init() {
+	nodeUncordonCmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Format to print stdout in. Options include: [text,json]")
+	nodeCmd.AddCommand(nodeUncordonCmd)
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	pkgpreload "k8s.io/minikube/pkg/minikube/preload"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/minikube/sysinit"
+	"k8s.io/minikube/pkg/version"
+)
+
+// throwawayPreloadNode is the name of the container preloaded tarballs are built in
+const throwawayPreloadNode = "minikube-preload-build"
+
+var (
+	preloadK8sVersion  string
+	preloadRuntime     string
+	preloadExtraImages []string
+	preloadDriver      string
+	preloadFromProfile bool
+)
+
+// preloadCmd represents the preload command
+var preloadCmd = &cobra.Command{
+	Use:   "preload",
+	Short: "Manage the preloaded images tarballs",
+	Long:  "Manage the preloaded images tarballs, which speed up starting clusters",
+}
+
+// preloadBuildCmd represents the preload build command
+var preloadBuildCmd = &cobra.Command{
+	Use:   "build",
+	Short: "Build a preloaded images tarball locally",
+	Long: `Build a preloaded images tarball and its checksum for a Kubernetes version and container runtime,
+and store them in the cache where 'minikube start' looks them up before downloading.
+
+The tarball is built in a throwaway docker or podman container, or in the running node of the profile with --from-profile,
+which is refused when the node holds images besides the ones of the preload, as the whole image store is archived.
+The kubelet and the containers of the profile are stopped while its store is archived, and the kubelet is started again afterwards.
+To share it, copy the tarball and its checksum file to a directory or web server, and point others at it with:
+'minikube config set preload-mirror <directory or URL>'.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var co mustload.ClusterController
+		if preloadFromProfile {
+			cname := ClusterFlagValue()
+			co = mustload.Running(cname)
+			kc := co.Config.KubernetesConfig
+			if !cmd.Flags().Changed("kubernetes-version") {
+				preloadK8sVersion = kc.KubernetesVersion
+			}
+			if !cmd.Flags().Changed("container-runtime") {
+				preloadRuntime = kc.ContainerRuntime
+			}
+			if preloadK8sVersion != kc.KubernetesVersion || !sameRuntime(preloadRuntime, kc.ContainerRuntime) {
+				exit.Message(reason.Usage, "The profile {{.profile}} runs Kubernetes {{.version}} on {{.runtime}}, which doesn't match the requested preload", out.V{"profile": cname, "version": kc.KubernetesVersion, "runtime": kc.ContainerRuntime})
+			}
+		}
+
+		cfg, err := preloadConfig()
+		if err != nil {
+			exit.Message(reason.Usage, "{{.err}}", out.V{"err": err})
+		}
+
+		var dst string
+		if preloadFromProfile {
+			cfg.ImageRepository = co.Config.KubernetesConfig.ImageRepository
+			cr, rerr := cruntime.New(cruntime.Config{Type: co.Config.KubernetesConfig.ContainerRuntime, Runner: co.CP.Runner})
+			if rerr != nil {
+				exit.Error(reason.InternalRuntime, "Failed runtime", rerr)
+			}
+			verifyPreloadStore(co, cr, cfg)
+			out.Step(style.Caching, "Building preload for Kubernetes {{.version}} on {{.runtime}} in {{.profile}} ...", out.V{"version": cfg.KubernetesVersion, "runtime": cfg.ContainerRuntime, "profile": co.Config.Name})
+			stopPreloadProfile(co, cr)
+			dst, err = buildPreload(co.CP.Runner, co.CP.Host.Driver, cfg)
+			startPreloadProfile(co)
+		} else {
+			if preloadDriver != oci.Docker && preloadDriver != oci.Podman {
+				exit.Message(reason.Usage, "--driver must be either {{.docker}} or {{.podman}}", out.V{"docker": oci.Docker, "podman": oci.Podman})
+			}
+			out.Step(style.Caching, "Building preload for Kubernetes {{.version}} on {{.runtime}} in a throwaway {{.driver}} container ...", out.V{"version": cfg.KubernetesVersion, "runtime": cfg.ContainerRuntime, "driver": preloadDriver})
+			dst, err = buildPreloadInThrowawayNode(cfg)
+		}
+		if err != nil {
+			exit.Error(reason.HostPreloadBuild, "Failed to build the preload", err)
+		}
+
+		out.Step(style.Success, "Built {{.path}}", out.V{"path": dst})
+		out.Step(style.Tip, "To share it, copy it along with {{.checksum}} to a directory or web server, and run: minikube config set preload-mirror <directory or URL>", out.V{"checksum": filepath.Base(dst) + ".checksum"})
+	},
+}
+
+// verifyPreloadStore exits when the runtime store of the profile holds images the preload doesn't include,
+// as the preload archives the whole store
+func verifyPreloadStore(co mustload.ClusterController, cr cruntime.Manager, cfg pkgpreload.Config) {
+	stored, err := cr.ListImages()
+	if err != nil {
+		exit.Error(reason.HostPreloadBuild, "Failed to list the images of the profile", err)
+	}
+	unexpected, err := pkgpreload.UnexpectedImages(stored, cfg)
+	if err != nil {
+		exit.Error(reason.HostPreloadBuild, "Failed to list the images of the preload", err)
+	}
+	if len(unexpected) > 0 {
+		exit.Message(reason.Usage, `The profile {{.profile}} holds images the preload doesn't include, which would be archived along with it: {{.images}}
+Add them with --extra-images, remove them from the profile, or build the preload in a throwaway container without --from-profile.`,
+			out.V{"profile": co.Config.Name, "images": strings.Join(unexpected, ", ")})
+	}
+}
+
+// stopPreloadProfile stops the kubelet and the containers of the profile, so that its store doesn't change while it is archived
+func stopPreloadProfile(co mustload.ClusterController, cr cruntime.Manager) {
+	out.Step(style.Waiting, "Stopping the kubelet and the containers of {{.profile}} while its image store is archived ...", out.V{"profile": co.Config.Name})
+	if err := sysinit.New(co.CP.Runner).ForceStop("kubelet"); err != nil {
+		exit.Error(reason.HostPreloadBuild, "Failed to stop the kubelet", err)
+	}
+	ids, err := cr.ListContainers(cruntime.ListOptions{State: cruntime.Running})
+	if err != nil {
+		startPreloadProfile(co)
+		exit.Error(reason.HostPreloadBuild, "Failed to list the containers of the profile", err)
+	}
+	if len(ids) == 0 {
+		return
+	}
+	if err := cr.StopContainers(ids); err != nil {
+		startPreloadProfile(co)
+		exit.Error(reason.HostPreloadBuild, "Failed to stop the containers of the profile", err)
+	}
+}
+
+// startPreloadProfile starts the kubelet of the profile again, which restarts its pods
+func startPreloadProfile(co mustload.ClusterController) {
+	if err := sysinit.New(co.CP.Runner).Start("kubelet"); err != nil {
+		klog.Warningf("unable to start kubelet: %v", err)
+		out.WarningT("Failed to start the kubelet of {{.profile}} again, run 'minikube start -p {{.profile}}' to restart it", out.V{"profile": co.Config.Name})
+	}
+}
+
+// sameRuntime compares container runtime names, of which cri-o has two spellings
+func sameRuntime(a, b string) bool {
+	normalize := func(r string) string {
+		if r == "crio" {
+			return "cri-o"
+		}
+		return r
+	}
+	return normalize(a) == normalize(b)
+}
+
+// preloadConfig validates the command line flags, and returns the preload to build
+func preloadConfig() (pkgpreload.Config, error) {
+	v, err := semver.Make(strings.TrimPrefix(preloadK8sVersion, version.VersionPrefix))
+	if err != nil {
+		return pkgpreload.Config{}, fmt.Errorf("invalid --kubernetes-version %q: %v", preloadK8sVersion, err)
+	}
+	valid := false
+	for _, r := range cruntime.ValidRuntimes() {
+		if sameRuntime(r, preloadRuntime) {
+			valid = true
+		}
+	}
+	if !valid {
+		return pkgpreload.Config{}, fmt.Errorf("invalid --container-runtime %q, expected one of %s", preloadRuntime, strings.Join(cruntime.ValidRuntimes(), ", "))
+	}
+	return pkgpreload.Config{
+		KubernetesVersion: version.VersionPrefix + v.String(),
+		ContainerRuntime:  preloadRuntime,
+		ExtraImages:       preloadExtraImages,
+	}, nil
+}
+
+// buildPreloadInThrowawayNode builds the preload in a new container, which is deleted afterwards
+func buildPreloadInThrowawayNode(cfg pkgpreload.Config) (string, error) {
+	n, err := pkgpreload.NewThrowawayNode(throwawayPreloadNode, preloadDriver, cfg)
+	if n != nil {
+		defer func() {
+			if err := n.Delete(); err != nil {
+				klog.Warningf("unable to delete %s: %v", throwawayPreloadNode, err)
+			}
+		}()
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "throwaway node")
+	}
+	return buildPreload(n.Runner, n.Driver, cfg)
+}
+
+// buildPreload builds the preload within the node, and adds it to the cache of the host
+func buildPreload(runner command.Runner, d drivers.Driver, cfg pkgpreload.Config) (string, error) {
+	name := download.TarballName(cfg.KubernetesVersion, cfg.ContainerRuntime)
+	nodePath := "/tmp/" + name
+	defer func() {
+		if _, err := runner.RunCmd(exec.Command("sudo", "rm", "-f", nodePath)); err != nil {
+			klog.Warningf("unable to remove %s: %v", nodePath, err)
+		}
+	}()
+	if err := pkgpreload.Generate(runner, cfg, nodePath); err != nil {
+		return "", errors.Wrap(err, "generate")
+	}
+
+	tmpDir, err := ioutil.TempDir("", "preload")
+	if err != nil {
+		return "", errors.Wrap(err, "temp dir")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	tmpPath := filepath.Join(tmpDir, name)
+	if err := pkgpreload.CopyToHost(d, nodePath, tmpPath); err != nil {
+		return "", errors.Wrap(err, "copy to host")
+	}
+	return download.AddPreload(tmpPath, cfg.KubernetesVersion, cfg.ContainerRuntime)
+}
+
+func init() {
+	preloadBuildCmd.Flags().StringVar(&preloadK8sVersion, "kubernetes-version", constants.DefaultKubernetesVersion, "The Kubernetes version to build the preload for.")
+	preloadBuildCmd.Flags().StringVar(&preloadRuntime, "container-runtime", "docker", fmt.Sprintf("The container runtime to build the preload for, one of: %s", strings.Join(cruntime.ValidRuntimes(), ", ")))
+	preloadBuildCmd.Flags().StringSliceVar(&preloadExtraImages, "extra-images", []string{}, "Images to preload in addition to the ones required by Kubernetes.")
+	preloadBuildCmd.Flags().StringVar(&preloadDriver, "driver", oci.Docker, "The driver of the throwaway container to build the preload in, either docker or podman.")
+	preloadBuildCmd.Flags().BoolVar(&preloadFromProfile, "from-profile", false, "Build the preload in the running node of the profile, instead of in a throwaway container. Its kubelet and containers are stopped during the build.")
+	preloadCmd.AddCommand(preloadBuildCmd)
+}
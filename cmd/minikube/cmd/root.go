@@ -115,6 +115,11 @@ func Execute() {
 		}
 	}
 
+	// discovering driver plugins runs them, so their flags are only added to the command which uses them
+	if c, _, err := RootCmd.Find(os.Args[1:]); err == nil && c == startCmd {
+		initPluginFlags(os.Args[1:])
+	}
+
 	for _, c := range RootCmd.Commands() {
 		c.Short = translate.T(c.Short)
 		c.Long = translate.T(c.Long)
@@ -216,8 +221,12 @@ func init() {
 			Commands: []*cobra.Command{
 				dockerEnvCmd,
 				podmanEnvCmd,
+				containerdEnvCmd,
 				cacheCmd,
 				imageCmd,
+				preloadCmd,
+				downloadCmd,
+				kicbaseCmd,
 			},
 		},
 		{
@@ -227,6 +236,8 @@ func init() {
 				configCmd.ConfigCmd,
 				configCmd.ProfileCmd,
 				updateContextCmd,
+				kubeconfigCmd,
+				runtimeCmd,
 			},
 		},
 		{
@@ -252,6 +263,7 @@ func init() {
 				sshHostCmd,
 				ipCmd,
 				logsCmd,
+				doctorCmd,
 				updateCheckCmd,
 				versionCmd,
 				optionsCmd,
@@ -284,6 +296,11 @@ func initConfig() {
 		}
 	}
 	setupViper()
+
+	// The podman CLI is run by the oci package, which does not read the minikube config
+	if viper.GetBool(config.Rootless) {
+		os.Setenv(constants.MinikubeRootlessEnv, "true")
+	}
 }
 
 func setupViper() {
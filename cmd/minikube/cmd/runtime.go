@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var runtimeBinary string
+
+// runtimeCmd represents the runtime command
+var runtimeCmd = &cobra.Command{
+	Use:   "runtime",
+	Short: "Manage the OCI runtimes pods can select with a RuntimeClass",
+	Long: `Manage the OCI runtimes, such as gVisor (runsc), crun and youki, installed on the nodes alongside the default one.
+
+Each enabled runtime handler is registered with containerd or CRI-O, and pods select it with the RuntimeClass listed next to it.`,
+}
+
+// runtimeListCmd represents the runtime list command
+var runtimeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the runtime handlers minikube can install, and whether they are enabled",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			exit.Message(reason.Usage, "usage: minikube runtime list")
+		}
+		cname := ClusterFlagValue()
+		enabled := map[string]bool{}
+		cc, err := config.Load(cname)
+		if err != nil && !config.IsNotExist(err) {
+			exit.Error(reason.HostConfigLoad, "Error loading profile config", err)
+		}
+		if cc != nil {
+			for _, name := range cc.KubernetesConfig.RuntimeHandlers {
+				enabled[name] = true
+			}
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Handler", "RuntimeClass", "Version", "Profile", "Status", "Description"})
+		table.SetAutoFormatHeaders(true)
+		table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		table.SetCenterSeparator("|")
+		for _, name := range cruntime.RuntimeHandlerNames() {
+			h := cruntime.RuntimeHandlers[name]
+			status := "disabled"
+			if enabled[name] {
+				status = "enabled ✅"
+			}
+			table.Append([]string{name, h.ClassName(), h.Version, cname, status, h.Description})
+		}
+		table.Render()
+	},
+}
+
+// runtimeEnableCmd represents the runtime enable command
+var runtimeEnableCmd = &cobra.Command{
+	Use:     "enable HANDLER",
+	Short:   "Installs a runtime handler on the nodes, and creates its RuntimeClass",
+	Long:    "Installs a runtime handler on the nodes, registers it with containerd or CRI-O, and creates its RuntimeClass for pods to select it.",
+	Example: "minikube runtime enable runsc\nminikube runtime enable youki --binary=./target/release/youki",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "usage: minikube runtime enable HANDLER")
+		}
+		h, err := cruntime.LookupRuntimeHandler(args[0])
+		if err != nil {
+			exit.Message(reason.Usage, "{{.error}}", out.V{"error": err})
+		}
+
+		cname := ClusterFlagValue()
+		co := mustload.Running(cname)
+		if !cruntime.SupportsRuntimeHandlers(co.Config.KubernetesConfig.ContainerRuntime) {
+			exit.Message(reason.Usage, `Runtime handlers are only compatible with the "containerd" and "cri-o" runtimes, but this cluster was configured to use the "{{.runtime}}" runtime. Switch it with 'minikube start --container-runtime=containerd'.`,
+				out.V{"runtime": co.Config.KubernetesConfig.ContainerRuntime})
+		}
+
+		if runtimeBinary != "" {
+			if len(h.Binaries) != 1 {
+				exit.Message(reason.Usage, "{{.handler}} needs several binaries, which --binary can't provide", out.V{"handler": h.Name})
+			}
+			if _, err := download.CacheRuntimeBinary(h.Name, h.Version, h.Binaries[0], runtimeBinary); err != nil {
+				exit.Error(reason.RuntimeHandler, "Failed to cache the binary", err)
+			}
+		}
+
+		if err := node.EnableRuntimeHandler(co.API, co.Config, h.Name); err != nil {
+			exit.Error(reason.RuntimeHandler, "Failed to enable the runtime handler", err)
+		}
+		if err := config.SaveProfile(cname, co.Config); err != nil {
+			exit.Error(reason.HostSaveProfile, "Failed to save config", err)
+		}
+		out.Step(style.Ready, "Pods can now select {{.handler}} with runtimeClassName: {{.class}}", out.V{"handler": h.Name, "class": h.ClassName()})
+	},
+}
+
+func init() {
+	runtimeEnableCmd.Flags().StringVar(&runtimeBinary, "binary", "", "A local build of the OCI runtime of the handler to install, in place of downloading it")
+	runtimeCmd.AddCommand(runtimeListCmd)
+	runtimeCmd.AddCommand(runtimeEnableCmd)
+}
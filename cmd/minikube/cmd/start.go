@@ -22,7 +22,6 @@ import (
 	"fmt"
 	"math"
 	"net"
-	"net/url"
 	"os"
 	"os/exec"
 	"os/user"
@@ -31,7 +30,9 @@ import (
 	"strings"
 
 	"github.com/blang/semver"
+	"github.com/docker/go-units"
 	"github.com/docker/machine/libmachine/ssh"
+	"github.com/docker/machine/libmachine/state"
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
@@ -44,8 +45,10 @@ import (
 	"k8s.io/klog/v2"
 	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
 	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/drivers/qemu"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
+	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/cruntime"
@@ -66,6 +69,7 @@ import (
 
 	"k8s.io/minikube/pkg/minikube/registry"
 	"k8s.io/minikube/pkg/minikube/translate"
+	"k8s.io/minikube/pkg/minikube/tunnel"
 	"k8s.io/minikube/pkg/util"
 	"k8s.io/minikube/pkg/version"
 )
@@ -199,6 +203,9 @@ func runStart(cmd *cobra.Command, args []string) {
 				},
 			)
 		}
+		if img := viper.GetString(kicBaseImage); !strings.Contains(img, "@sha256:") {
+			out.WarningT("The base image {{.image}} is not pinned to a digest and will not be verified, use --{{.imgFlag}}=<image>@sha256:<digest> to pin it", out.V{"image": img, "imgFlag": kicBaseImage})
+		}
 	}
 
 	starter, err := provisionWithDriver(cmd, ds, existing)
@@ -261,6 +268,13 @@ func runStart(cmd *cobra.Command, args []string) {
 		exit.Error(reason.GuestStart, "failed to start node", err)
 	}
 
+	// the artifacts of this cluster were just used, so they are the last to evict
+	if n, freed, err := machine.EvictCache(); err != nil {
+		klog.Warningf("evicting cache: %v", err)
+	} else if n > 0 {
+		out.Step(style.Deleted, "Evicted {{.count}} unused cached artifacts, freeing {{.size}}", out.V{"count": n, "size": units.BytesSize(float64(freed))})
+	}
+
 	if err := showKubectlInfo(kubeconfig, starter.Node.KubernetesVersion, starter.Cfg.Name); err != nil {
 		klog.Errorf("kubectl info: %v", err)
 	}
@@ -287,6 +301,7 @@ func provisionWithDriver(cmd *cobra.Command, ds registry.DriverState, existing *
 	}
 
 	k8sVersion := getKubernetesVersion(existing)
+	validateRootless(driverName, k8sVersion)
 	cc, n, err := generateClusterConfig(cmd, existing, k8sVersion, driverName)
 	if err != nil {
 		return node.Starter{}, errors.Wrap(err, "Failed to generate config")
@@ -298,8 +313,21 @@ func provisionWithDriver(cmd *cobra.Command, ds registry.DriverState, existing *
 		os.Exit(0)
 	}
 
+	if existing != nil && existing.CPUs != 0 && existing.Memory != 0 && (cc.CPUs != existing.CPUs || cc.Memory != existing.Memory) {
+		resizeExisting(&cc)
+	}
+
+	if existing != nil && !sameRuntime(existing.KubernetesConfig.ContainerRuntime, cc.KubernetesConfig.ContainerRuntime) {
+		switchRuntime(existing, &cc)
+	}
+
 	if driver.IsVM(driverName) && !driver.IsSSH(driverName) {
-		url, err := download.ISO(viper.GetStringSlice(isoURL), cmd.Flags().Changed(isoURL))
+		urls := viper.GetStringSlice(isoURL)
+		if !cmd.Flags().Changed(isoURL) {
+			// the flag default was computed before the mirror settings were loaded
+			urls = download.DefaultISOURLs()
+		}
+		url, err := download.ISO(urls, cmd.Flags().Changed(isoURL))
 		if err != nil {
 			return node.Starter{}, errors.Wrap(err, "Failed to cache ISO")
 		}
@@ -336,6 +364,52 @@ func provisionWithDriver(cmd *cobra.Command, ds registry.DriverState, existing *
 	}, nil
 }
 
+// resizeExisting applies a changed CPU or memory allocation to the machines of an existing cluster
+func resizeExisting(cc *config.ClusterConfig) {
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		exit.Error(reason.NewAPIClient, "Failed to get machine client", err)
+	}
+	defer api.Close()
+
+	if err := machine.ResizeCluster(api, cc, cluster.KubeletReconfigurer(api, viper.GetString(cmdcfg.Bootstrapper))); err != nil {
+		exit.Error(reason.GuestResize, "Failed to resize cluster", err)
+	}
+}
+
+// switchRuntime switches the running nodes of an existing cluster to the container runtime of cc
+func switchRuntime(existing *config.ClusterConfig, cc *config.ClusterConfig) {
+	from, to := existing.KubernetesConfig.ContainerRuntime, cc.KubernetesConfig.ContainerRuntime
+	if driver.BareMetal(cc.Driver) {
+		exit.Message(reason.Usage, "The {{.driver}} driver can't switch the container runtime of an existing cluster, as it would disable {{.runtime}} on the host. Please first delete the cluster.", out.V{"driver": cc.Driver, "runtime": from})
+	}
+
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		exit.Error(reason.NewAPIClient, "Failed to get machine client", err)
+	}
+	defer api.Close()
+
+	cp, err := config.PrimaryControlPlane(existing)
+	if err != nil {
+		exit.Error(reason.GuestCpConfig, "Failed to get control plane", err)
+	}
+	st, err := machine.Status(api, config.MachineName(*existing, cp))
+	if err != nil {
+		exit.Error(reason.GuestStatus, "Failed to get the status of the control plane", err)
+	}
+	if st != state.Running.String() {
+		// starting the nodes enables the runtime of the config, and the images are pulled again
+		out.Step(style.Notice, "{{.cluster}} will start with the {{.runtime}} container runtime instead of {{.old}}", out.V{"cluster": cc.Name, "runtime": to, "old": from})
+		return
+	}
+
+	out.Step(style.Notice, "Switching the container runtime of {{.cluster}} from {{.old}} to {{.runtime}} ...", out.V{"cluster": cc.Name, "runtime": to, "old": from})
+	if err := node.SwitchRuntime(api, *existing, *cc); err != nil {
+		exit.Error(reason.RuntimeSwitch, "Failed to switch the container runtime, the nodes were switched back", err)
+	}
+}
+
 func startWithDriver(cmd *cobra.Command, starter node.Starter, existing *config.ClusterConfig) (*kubeconfig.Settings, error) {
 	kubeconfig, err := node.Start(starter, true)
 	if err != nil {
@@ -346,6 +420,9 @@ func startWithDriver(cmd *cobra.Command, starter node.Starter, existing *config.
 	}
 
 	numNodes := viper.GetInt(nodes)
+	if existing == nil && starter.Cfg.HA && numNodes < haControlPlanes {
+		numNodes = haControlPlanes
+	}
 	if existing != nil {
 		if numNodes > 1 {
 			// We ignore the --nodes parameter if we're restarting an existing cluster
@@ -363,9 +440,12 @@ func startWithDriver(cmd *cobra.Command, starter node.Starter, existing *config.
 					n := config.Node{
 						Name:              nodeName,
 						Worker:            true,
-						ControlPlane:      false,
+						ControlPlane:      starter.Cfg.HA && i < haControlPlanes,
 						KubernetesVersion: starter.Cfg.KubernetesConfig.KubernetesVersion,
 					}
+					if n.ControlPlane {
+						n.Port = starter.Node.Port
+					}
 					out.Ln("") // extra newline for clarity on the command line
 					err := node.Add(starter.Cfg, n, viper.GetBool(deleteOnFailure))
 					if err != nil {
@@ -373,6 +453,15 @@ func startWithDriver(cmd *cobra.Command, starter node.Starter, existing *config.
 					}
 				}
 			} else {
+				// the other control plane nodes join before the workers
+				for _, n := range existing.Nodes {
+					if n.ControlPlane && !config.IsPrimaryControlPlane(*existing, n) {
+						err := node.Add(starter.Cfg, n, viper.GetBool(deleteOnFailure))
+						if err != nil {
+							return nil, errors.Wrap(err, "adding node")
+						}
+					}
+				}
 				for _, n := range existing.Nodes {
 					if !n.ControlPlane {
 						err := node.Add(starter.Cfg, n, viper.GetBool(deleteOnFailure))
@@ -427,7 +516,9 @@ func showKubectlInfo(kcs *kubeconfig.Settings, k8sVersion string, machineName st
 	// To be shown at the end, regardless of exit path
 	defer func() {
 		register.Reg.SetStep(register.Done)
-		if kcs.KeepContext {
+		if kcs.FilePath() != kubeconfig.PathFromEnv() {
+			out.Step(style.Kubectl, "To connect to this cluster, use:  --kubeconfig={{.path}} --context={{.name}}", out.V{"path": kcs.FilePath(), "name": kcs.ClusterName})
+		} else if kcs.KeepContext {
 			out.Step(style.Kubectl, "To connect to this cluster, use:  --context={{.name}}", out.V{"name": kcs.ClusterName})
 		} else {
 			out.Step(style.Ready, `Done! kubectl is now configured to use "{{.name}}" cluster and "{{.ns}}" namespace by default`, out.V{"name": machineName, "ns": kcs.Namespace})
@@ -481,7 +572,8 @@ func maybeDeleteAndRetry(cmd *cobra.Command, existing config.ClusterConfig, n co
 		cc := updateExistingConfigFromFlags(cmd, &existing)
 		var kubeconfig *kubeconfig.Settings
 		for _, n := range cc.Nodes {
-			r, p, m, h, err := node.Provision(&cc, &n, n.ControlPlane, false)
+			primary := config.IsPrimaryControlPlane(cc, n)
+			r, p, m, h, err := node.Provision(&cc, &n, primary, false)
 			s := node.Starter{
 				Runner:         r,
 				PreExists:      p,
@@ -496,8 +588,8 @@ func maybeDeleteAndRetry(cmd *cobra.Command, existing config.ClusterConfig, n co
 				return nil, err
 			}
 
-			k, err := node.Start(s, n.ControlPlane)
-			if n.ControlPlane {
+			k, err := node.Start(s, primary)
+			if primary {
 				kubeconfig = k
 			}
 			if err != nil {
@@ -579,6 +671,9 @@ func selectDriver(existing *config.ClusterConfig) (registry.DriverState, []regis
 		return ds, nil, true
 	}
 
+	if viper.GetBool(driverPlugins) {
+		registry.DiscoverAll()
+	}
 	choices := driver.Choices(viper.GetBool("vm"))
 	pick, alts, rejects := driver.Suggest(choices)
 	if pick.Name == "" {
@@ -1062,6 +1157,34 @@ func validateFlags(cmd *cobra.Command, drvName string) {
 		}
 	}
 
+	if cmd.Flags().Changed(extraDisks) {
+		if err := validateExtraDisks(drvName, viper.GetInt(extraDisks)); err != nil {
+			exit.Message(reason.Usage, "{{.error}}", out.V{"error": err})
+		}
+	}
+
+	if cmd.Flags().Changed(subnet) || cmd.Flags().Changed(staticIP) {
+		if drvName != driver.KVM2 && !driver.IsKIC(drvName) {
+			exit.Message(reason.Usage, "The {{.driver}} driver doesn't support --subnet and --static-ip, use the kvm2, docker, podman or nerdctl driver", out.V{"driver": drvName})
+		}
+		if !config.ProfileExists(ClusterFlagValue()) {
+			validateSubnet(clusterSubnet())
+		}
+		if viper.GetString(staticIP) != "" {
+			validateStaticIP(viper.GetString(staticIP), clusterSubnet())
+		}
+	}
+
+	if cmd.Flags().Changed(extraDiskSize) {
+		if _, err := extraDiskSizeMB(viper.GetString(extraDiskSize)); err != nil {
+			exitIfNotForced(reason.Usage, "Validation unable to parse extra disk size '{{.diskSize}}': {{.error}}", out.V{"diskSize": viper.GetString(extraDiskSize), "error": err})
+		}
+	}
+
+	if viper.GetBool(ha) && (driver.BareMetal(drvName) || driver.IsSSH(drvName) || driver.IsQEMUUserNetwork(drvName, viper.GetString(network))) {
+		exit.Message(reason.Usage, "The {{.driver}} driver doesn't support highly available clusters", out.V{"driver": drvName})
+	}
+
 	if cmd.Flags().Changed(cpus) {
 		if !driver.HasResourceLimits(drvName) {
 			out.WarningT("The '{{.name}}' driver does not respect the --cpus flag", out.V{"name": drvName})
@@ -1125,16 +1248,20 @@ func validateFlags(cmd *cobra.Command, drvName string) {
 	}
 
 	if driver.IsSSH(drvName) {
-		sshIPAddress := viper.GetString(sshIPAddress)
-		if sshIPAddress == "" {
-			exit.Message(reason.Usage, "No IP address provided. Try specifying --ssh-ip-address, or see https://minikube.sigs.k8s.io/docs/drivers/ssh/")
+		validateSSHIPAddress(viper.GetString(sshIPAddress))
+		if viper.GetInt(nodes) > 1 {
+			exit.Message(reason.Usage, "The ssh driver can't create hosts, start a single node and join the other hosts with 'minikube node add --ssh-ip-address'")
 		}
+	}
 
-		if net.ParseIP(sshIPAddress) == nil {
-			_, err := net.LookupIP(sshIPAddress)
-			if err != nil {
-				exit.Error(reason.Usage, "Could not resolve IP address", err)
-			}
+	if driver.IsQEMU(drvName) {
+		switch viper.GetString(network) {
+		case "", qemu.NetworkUser, qemu.NetworkSocketVMnet:
+		default:
+			exit.Message(reason.Usage, "Invalid network for the qemu driver: {{.network}}. Valid networks are: {{.valid}}", out.V{"network": viper.GetString(network), "valid": strings.Join([]string{qemu.NetworkUser, qemu.NetworkSocketVMnet}, ", ")})
+		}
+		if driver.IsQEMUUserNetwork(drvName, viper.GetString(network)) && viper.GetInt(nodes) > 1 {
+			exit.Message(reason.Usage, "The VMs on the qemu user network can't reach each other, use --network=socket_vmnet to start multiple nodes")
 		}
 	}
 
@@ -1165,22 +1292,31 @@ func validateFlags(cmd *cobra.Command, drvName string) {
 
 	validateRegistryMirror()
 	validateInsecureRegistry()
+	validateRegistryCA()
 
 }
 
 // This function validates if the --registry-mirror
-// args match the format of http://localhost
+// args match the format of http://localhost, or <registry>=http://localhost
 func validateRegistryMirror() {
 	if len(registryMirror) > 0 {
 		for _, loc := range registryMirror {
-			URL, err := url.Parse(loc)
-			if err != nil {
-				klog.Errorln("Error Parsing URL: ", err)
-			}
-			if (URL.Scheme != "http" && URL.Scheme != "https") || URL.Path != "" {
+			if _, _, err := cruntime.ParseRegistryMirror(loc); err != nil {
 				exit.Message(reason.Usage, "Sorry, the url provided with the --registry-mirror flag is invalid: {{.url}}", out.V{"url": loc})
 			}
+		}
+	}
+}
 
+// validateRegistryCA validates that the --registry-ca args are formatted as <registry>=<path>, with an existing path
+func validateRegistryCA() {
+	for _, c := range viper.GetStringSlice(registryCA) {
+		i := strings.Index(c, "=")
+		if i <= 0 || i == len(c)-1 {
+			exit.Message(reason.Usage, "Sorry, the value provided with the --registry-ca flag is invalid: {{.ca}}. Expected format is <registry>=<path>", out.V{"ca": c})
+		}
+		if _, err := os.Stat(c[i+1:]); err != nil {
+			exit.Message(reason.Usage, "The CA of registry {{.registry}} can't be read: {{.error}}", out.V{"registry": c[:i], "error": err})
 		}
 	}
 }
@@ -1213,6 +1349,109 @@ func validateInsecureRegistry() {
 	}
 }
 
+// validateExtraDisks makes sure a number of extra disks can be attached to the nodes of a driver
+func validateExtraDisks(drvName string, count int) error {
+	if count < 0 {
+		return errors.New("--extra-disks can't be negative")
+	}
+	if count > 0 && drvName != driver.KVM2 && !driver.IsKIC(drvName) {
+		return errors.Errorf("The %s driver doesn't support extra disks, use the kvm2, docker, podman or nerdctl driver", drvName)
+	}
+	// the extra disks of kvm2 are named vdb to vdz
+	if count > 25 {
+		return errors.New("--extra-disks can't exceed 25")
+	}
+	return nil
+}
+
+// extraDiskSizeMB parses the size of the extra disks, which truncate rounds to whole MBs
+func extraDiskSizeMB(s string) (int, error) {
+	mb, err := util.CalculateSizeInMB(s)
+	if err != nil {
+		return 0, err
+	}
+	if mb < 1 {
+		return 0, errors.Errorf("%s is less than 1MB", s)
+	}
+	return mb, nil
+}
+
+// validateSubnet makes sure the subnet of a new cluster network is valid, and doesn't overlap the routes of the host
+func validateSubnet(s string) {
+	ip, ipnet, err := net.ParseCIDR(s)
+	if err != nil {
+		exit.Message(reason.Usage, "Invalid subnet {{.subnet}}, expected an IPv4 subnet in CIDR notation, such as 192.168.50.0/24", out.V{"subnet": s})
+	}
+	if ip.To4() == nil {
+		exit.Message(reason.Usage, "Invalid subnet {{.subnet}}, only IPv4 subnets are supported", out.V{"subnet": s})
+	}
+	if ones, _ := ipnet.Mask.Size(); ones > 29 {
+		exit.Message(reason.Usage, "Subnet {{.subnet}} is too small, use a /29 subnet or a larger one", out.V{"subnet": s})
+	}
+
+	// the networks of the interfaces of the host and the ones reachable through a gateway, such as over a VPN
+	routes, err := tunnel.OverlappingRoutes(ipnet)
+	if err != nil {
+		klog.Warningf("unable to read the routing table of the host: %v", err)
+		return
+	}
+	if len(routes) > 0 {
+		exit.Message(reason.Usage, "Subnet {{.subnet}} overlaps the routes of the host, pick another one:\n{{.routes}}", out.V{"subnet": s, "routes": strings.Join(routes, "\n")})
+	}
+}
+
+// validateStaticIP makes sure a static IP can be assigned to a node in subnet: the first address goes to the gateway
+func validateStaticIP(s string, subnet string) {
+	ip := net.ParseIP(s).To4()
+	if ip == nil {
+		exit.Message(reason.Usage, "Invalid static IP {{.ip}}, expected an IPv4 address", out.V{"ip": s})
+	}
+	_, ipnet, err := net.ParseCIDR(subnet)
+	if err != nil || !ipnet.Contains(ip) {
+		exit.Message(reason.Usage, "Static IP {{.ip}} is not in the subnet {{.subnet}}", out.V{"ip": s, "subnet": subnet})
+	}
+	network := ipnet.IP.To4()
+	broadcast := make(net.IP, 4)
+	for i := range network {
+		broadcast[i] = network[i] | ^ipnet.Mask[i]
+	}
+	gateway := make(net.IP, 4)
+	copy(gateway, network)
+	gateway[3]++
+	if ip.Equal(network) || ip.Equal(gateway) || ip.Equal(broadcast) {
+		exit.Message(reason.Usage, "Static IP {{.ip}} is reserved in the subnet {{.subnet}}, pick another one", out.V{"ip": s, "subnet": subnet})
+	}
+}
+
+// kicNodeIP returns the IP the kic drivers compute for a node without a static IP: the gateway of the cluster subnet
+// plus the index of the node. It returns an empty string for the other drivers and for subnets picked automatically.
+func kicNodeIP(cc config.ClusterConfig, n config.Node) string {
+	if !driver.IsKIC(cc.Driver) || cc.Subnet == "" {
+		return ""
+	}
+	_, ipnet, err := net.ParseCIDR(cc.Subnet)
+	if err != nil || ipnet.IP.To4() == nil {
+		return ""
+	}
+	ip := ipnet.IP.To4()
+	ip[3] += byte(1 + driver.IndexFromMachineName(config.MachineName(cc, n)))
+	return ip.String()
+}
+
+// validateSSHIPAddress makes sure the address of a host of the ssh driver is provided and resolves
+func validateSSHIPAddress(ip string) {
+	if ip == "" {
+		exit.Message(reason.Usage, "No IP address provided. Try specifying --ssh-ip-address, or see https://minikube.sigs.k8s.io/docs/drivers/ssh/")
+	}
+
+	if net.ParseIP(ip) == nil {
+		_, err := net.LookupIP(ip)
+		if err != nil {
+			exit.Error(reason.Usage, "Could not resolve IP address", err)
+		}
+	}
+}
+
 func createNode(cc config.ClusterConfig, kubeNodeName string, existing *config.ClusterConfig) (config.ClusterConfig, config.Node, error) {
 	// Create the initial node, which will necessarily be a control plane
 	if existing != nil {
@@ -1241,6 +1480,13 @@ func createNode(cc config.ClusterConfig, kubeNodeName string, existing *config.C
 		ControlPlane:      true,
 		Worker:            true,
 	}
+	cp.StaticIP = viper.GetString(staticIP)
+	if driver.IsSSH(cc.Driver) {
+		cp.SSHIPAddress = viper.GetString(sshIPAddress)
+		cp.SSHUser = viper.GetString(sshSSHUser)
+		cp.SSHKey = viper.GetString(sshSSHKey)
+		cp.SSHPort = viper.GetInt(sshSSHPort)
+	}
 	cc.Nodes = []config.Node{cp}
 	return cc, cp, nil
 }
@@ -1375,6 +1621,22 @@ func validateDockerStorageDriver(drvName string) {
 	viper.Set(preload, false)
 }
 
+// validateRootless warns about Kubernetes versions whose kubelet can't run in a rootless container
+func validateRootless(drvName string, k8sVersion string) {
+	if !driver.IsKIC(drvName) || !oci.IsRootless(drvName) {
+		return
+	}
+	klog.Infof("%s is rootless", drvName)
+	v, err := util.ParseKubernetesVersion(k8sVersion)
+	if err != nil {
+		klog.Warningf("unable to parse Kubernetes version %q: %v", k8sVersion, err)
+		return
+	}
+	if v.LT(semver.MustParse("1.22.0-alpha.0")) {
+		out.WarningT("Rootless {{.driver}} requires Kubernetes v1.22 or later, Kubernetes {{.version}} is likely to fail to start", out.V{"driver": drvName, "version": k8sVersion})
+	}
+}
+
 func exitIfNotForced(r reason.Kind, message string, v ...out.V) {
 	if !viper.GetBool(force) {
 		exit.Message(r, message, v...)
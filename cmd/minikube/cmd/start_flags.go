@@ -18,6 +18,9 @@ package cmd
 
 import (
 	"fmt"
+	"net"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -27,6 +30,8 @@ import (
 	"github.com/spf13/viper"
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/drivers/kic"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/drivers/qemu"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/kverify"
 	"k8s.io/minikube/pkg/minikube/cni"
@@ -39,6 +44,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/proxy"
 	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/registry"
 	"k8s.io/minikube/pkg/minikube/style"
 	pkgutil "k8s.io/minikube/pkg/util"
 	"k8s.io/minikube/pkg/version"
@@ -84,6 +90,7 @@ const (
 	vpnkitSock              = "hyperkit-vpnkit-sock"
 	vsockPorts              = "hyperkit-vsock-ports"
 	embedCerts              = "embed-certs"
+	kubeconfigFile          = "kubeconfig"
 	noVTXCheck              = "no-vtx-check"
 	downloadOnly            = "download-only"
 	dnsProxy                = "dns-proxy"
@@ -99,6 +106,8 @@ const (
 	minimumCPUS             = 2
 	minimumDiskSize         = 2000
 	autoUpdate              = "auto-update-drivers"
+	driverPlugins           = "driver-plugins"
+	reserveResources        = "reserve-resources"
 	hostOnlyNicType         = "host-only-nic-type"
 	natNicType              = "nat-nic-type"
 	nodes                   = "nodes"
@@ -116,6 +125,17 @@ const (
 	sshSSHPort              = "ssh-port"
 	defaultSSHUser          = "root"
 	defaultSSHPort          = 22
+	socketVMnetClientPath   = "socket-vmnet-client-path"
+	socketVMnetPath         = "socket-vmnet-path"
+	extraDisks              = "extra-disks"
+	extraDiskSize           = "extra-disk-size"
+	defaultExtraDiskSize    = "20000mb"
+	subnet                  = "subnet"
+	staticIP                = "static-ip"
+	ha                      = "ha"
+	registryCA              = "registry-ca"
+	registryAuthFromHost    = "registry-auth-from-host"
+	haControlPlanes         = 3
 )
 
 var (
@@ -135,13 +155,17 @@ func initMinikubeFlags() {
 
 	startCmd.Flags().Int(cpus, 2, "Number of CPUs allocated to Kubernetes.")
 	startCmd.Flags().String(memory, "", "Amount of RAM to allocate to Kubernetes (format: <number>[<unit>], where unit = b, k, m or g).")
+	startCmd.Flags().Bool(reserveResources, false, "If set, the kubelet reserves some of the CPUs and memory of the nodes for the system, following their size. Set once the cluster is resized.")
 	startCmd.Flags().String(humanReadableDiskSize, defaultDiskSize, "Disk size allocated to the minikube VM (format: <number>[<unit>], where unit = b, k, m or g).")
+	startCmd.Flags().Int(extraDisks, 0, "Number of extra raw disks attached to each node (kvm2, docker, podman and nerdctl drivers only)")
+	startCmd.Flags().String(extraDiskSize, defaultExtraDiskSize, "Size of each extra disk (format: <number>[<unit>], where unit = b, k, m or g).")
 	startCmd.Flags().Bool(downloadOnly, false, "If true, only download and cache files for later use - don't install or start anything.")
 	startCmd.Flags().Bool(cacheImages, true, "If true, cache docker images for the current bootstrapper and load them into the machine. Always false with --driver=none.")
 	startCmd.Flags().StringSlice(isoURL, download.DefaultISOURLs(), "Locations to fetch the minikube ISO from.")
 	startCmd.Flags().String(kicBaseImage, kic.BaseImage, "The base image to use for docker/podman drivers. Intended for local development.")
 	startCmd.Flags().Bool(keepContext, false, "This will keep the existing kubectl context and will create a minikube context.")
 	startCmd.Flags().Bool(embedCerts, false, "if true, will embed the certs in kubeconfig.")
+	startCmd.Flags().String(kubeconfigFile, "", "Write the cluster's kubectl context to this file instead of $KUBECONFIG or ~/.kube/config.")
 	startCmd.Flags().String(containerRuntime, constants.DefaultContainerRuntime, fmt.Sprintf("The container runtime to be used (%s).", strings.Join(cruntime.ValidRuntimes(), ", ")))
 	startCmd.Flags().Bool(createMount, false, "This will start the mount daemon and automatically mount files into minikube.")
 	startCmd.Flags().String(mountString, constants.DefaultMountDir+":/minikube-host", "The argument to pass the minikube mount command on start.")
@@ -156,10 +180,19 @@ func initMinikubeFlags() {
 	startCmd.Flags().Bool(autoUpdate, true, "If set, automatically updates drivers to the latest version. Defaults to true.")
 	startCmd.Flags().Bool(installAddons, true, "If set, install addons. Defaults to true.")
 	startCmd.Flags().IntP(nodes, "n", 1, "The number of nodes to spin up. Defaults to 1.")
+	startCmd.Flags().Bool(ha, false, "Create a highly available cluster: 3 control plane nodes behind a virtual IP, and workers for the --nodes above 3. Not supported by the none and ssh drivers.")
 	startCmd.Flags().Bool(preload, true, "If set, download tarball of preloaded images if available to improve start time. Defaults to true.")
+	startCmd.Flags().String(download.PreloadMirrorKey, "", "Mirror to get preloaded tarballs from instead of the minikube bucket: a base URL, or a directory holding the tarballs and their checksum files, such as built by 'minikube preload build'.")
+	startCmd.Flags().String(download.ArtifactMirrorKey, "", "Comma separated mirrors to get the ISO, preloads, Kubernetes binaries and drivers from before upstream: base URLs or directories holding the upstream paths.")
+	startCmd.Flags().String(download.ISOMirrorKey, "", "Mirror to get the ISO from before the artifact mirrors: a base URL, or a directory holding minikube-<version>.iso and its .sha256 file.")
+	startCmd.Flags().String(download.BinaryMirrorKey, "", "Mirror to get the Kubernetes binaries from before the artifact mirrors: a base URL, or a directory holding <version>/bin/<os>/<arch>/<binary> and their checksum files.")
+	startCmd.Flags().String(download.DriverMirrorKey, "", "Mirror to get the drivers from before the artifact mirrors: a base URL, or a directory holding v<version>/<driver> and their .sha256 files.")
+	startCmd.Flags().String(download.DownloadRateLimitKey, "", "Maximum bandwidth per second of the downloads, shared by their connections, such as 2MB. Unlimited by default.")
+	startCmd.Flags().Int(download.DownloadConnectionsKey, 1, "Number of parallel connections to download each large artifact over, from servers supporting range requests.")
+	startCmd.Flags().Bool(download.ArtifactMirrorNetrcKey, false, "If set, authenticate to the mirrors with the credentials of the netrc file: $NETRC, or ~/.netrc.")
 	startCmd.Flags().Bool(deleteOnFailure, false, "If set, delete the current cluster if start fails and try again. Defaults to false.")
 	startCmd.Flags().Bool(forceSystemd, false, "If set, force the container runtime to use sytemd as cgroup manager. Defaults to false.")
-	startCmd.Flags().StringP(network, "", "", "network to run minikube with. Only available with the docker/podman drivers. If left empty, minikube will create a new network.")
+	startCmd.Flags().StringP(network, "", "", "network to run minikube with. Only available with the docker/podman and qemu drivers. If left empty, minikube will create a new network for docker/podman. For qemu, one of 'user' (default) or 'socket_vmnet'.")
 	startCmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Format to print stdout in. Options include: [text,json]")
 	startCmd.Flags().StringP(trace, "", "", "Send trace events. Options include: [gcp]")
 }
@@ -185,6 +218,7 @@ func initKubernetesFlags() {
 func initDriverFlags() {
 	startCmd.Flags().String("driver", "", fmt.Sprintf("Driver is one of: %v (defaults to auto-detect)", driver.DisplaySupportedDrivers()))
 	startCmd.Flags().String("vm-driver", "", "DEPRECATED, use `driver` instead.")
+	startCmd.Flags().Bool(driverPlugins, false, "If set, the driver plugins (minikube-driver-<name> executables in $MINIKUBE_HOME/drivers and the PATH) are considered when picking a driver automatically, which runs each of them.")
 	startCmd.Flags().Bool(disableDriverMounts, false, "Disables the filesystem mounts provided by the hypervisors")
 	startCmd.Flags().Bool("vm", false, "Filter to use only VM Drivers")
 
@@ -214,14 +248,107 @@ func initDriverFlags() {
 	startCmd.Flags().Bool(hypervUseExternalSwitch, false, "Whether to use external switch over Default Switch if virtual switch not explicitly specified. (hyperv driver only)")
 	startCmd.Flags().String(hypervExternalAdapter, "", "External Adapter on which external switch will be created if no external switch is found. (hyperv driver only)")
 
+	// qemu
+	startCmd.Flags().String(socketVMnetClientPath, qemu.DefaultSocketVMnetClientPath, "Path to the socket_vmnet client binary (qemu driver with --network=socket_vmnet only)")
+	startCmd.Flags().String(socketVMnetPath, qemu.DefaultSocketVMnetPath, "Path to the socket_vmnet socket (qemu driver with --network=socket_vmnet only)")
+
 	// docker & podman
 	startCmd.Flags().StringSlice(ports, []string{}, "List of ports that should be exposed (docker and podman driver only)")
+
+	// kvm2, docker & podman
+	startCmd.Flags().String(subnet, "", "Subnet of the cluster network, in CIDR notation, such as 192.168.50.0/24. Picked automatically if not set (kvm2, docker, podman and nerdctl drivers only)")
+	startCmd.Flags().String(staticIP, "", "Static IP of the control plane node, within --subnet, which defaults to the /24 subnet of this IP (kvm2, docker, podman and nerdctl drivers only)")
+}
+
+// initPluginFlags inits the commandline flags of the driver plugins, which are only known once they have been discovered.
+// As discovering a plugin runs it, only the plugin named by --driver is, unless --driver-plugins is set.
+func initPluginFlags(args []string) {
+	if argFlagSet(args, driverPlugins) {
+		registry.DiscoverAll()
+	} else {
+		for _, name := range []string{"driver", "vm-driver"} {
+			if d := argFlagValue(args, name); d != "" {
+				registry.Driver(d)
+			}
+		}
+	}
+	for _, def := range registry.List() {
+		for _, f := range def.Flags {
+			if startCmd.Flags().Lookup(f.Name) != nil {
+				klog.Warningf("ignoring the %s flag of the %s driver plugin, which is already defined", f.Name, def.Name)
+				continue
+			}
+			startCmd.Flags().String(f.Name, f.Default, fmt.Sprintf("%s (%s driver only)", f.Usage, def.Name))
+			if err := viper.BindPFlag(f.Name, startCmd.Flags().Lookup(f.Name)); err != nil {
+				klog.Warningf("unable to bind the %s flag: %v", f.Name, err)
+			}
+		}
+	}
+}
+
+// argFlagValue returns the value of a string flag in the commandline arguments, before they are parsed
+func argFlagValue(args []string, name string) string {
+	for i, a := range args {
+		if a == "--" {
+			break
+		}
+		if a == "--"+name && i+1 < len(args) {
+			return args[i+1]
+		}
+		if strings.HasPrefix(a, "--"+name+"=") {
+			return strings.TrimPrefix(a, "--"+name+"=")
+		}
+	}
+	return ""
+}
+
+// argFlagSet returns whether a bool flag is set in the commandline arguments, before they are parsed
+func argFlagSet(args []string, name string) bool {
+	for _, a := range args {
+		if a == "--" {
+			break
+		}
+		if a == "--"+name {
+			return true
+		}
+		if strings.HasPrefix(a, "--"+name+"=") {
+			v, err := strconv.ParseBool(strings.TrimPrefix(a, "--"+name+"="))
+			return err == nil && v
+		}
+	}
+	return false
+}
+
+// clusterSubnet returns the subnet requested for the cluster network, which defaults to the /24 subnet of the static IP
+func clusterSubnet() string {
+	if viper.GetString(subnet) != "" {
+		return viper.GetString(subnet)
+	}
+	if ip := net.ParseIP(viper.GetString(staticIP)).To4(); ip != nil {
+		return (&net.IPNet{IP: ip.Mask(net.CIDRMask(24, 32)), Mask: net.CIDRMask(24, 32)}).String()
+	}
+	return ""
+}
+
+// driverOptions returns the values of the flags of a driver plugin
+func driverOptions(drvName string) map[string]string {
+	flags := registry.Driver(drvName).Flags
+	if len(flags) == 0 {
+		return nil
+	}
+	opts := map[string]string{}
+	for _, f := range flags {
+		opts[f.Name] = viper.GetString(f.Name)
+	}
+	return opts
 }
 
 // initNetworkingFlags inits the commandline flags for connectivity related flags for start
 func initNetworkingFlags() {
 	startCmd.Flags().StringSliceVar(&insecureRegistry, "insecure-registry", nil, "Insecure Docker registries to pass to the Docker daemon.  The default service CIDR range will automatically be added.")
-	startCmd.Flags().StringSliceVar(&registryMirror, "registry-mirror", nil, "Registry mirrors to pass to the Docker daemon")
+	startCmd.Flags().StringSliceVar(&registryMirror, "registry-mirror", nil, "Registry mirrors to configure the container runtime with: the URL of a Docker Hub mirror, or <registry>=<url> for a mirror of another registry")
+	startCmd.Flags().StringSlice(registryCA, nil, "CAs of registries to configure the container runtime with (format: <registry>=<path>)")
+	startCmd.Flags().Bool(registryAuthFromHost, false, "Copy the registry credentials of the docker config of the host ($DOCKER_CONFIG or ~/.docker/config.json) to the cluster")
 	startCmd.Flags().String(imageRepository, "", "Alternative image repository to pull docker images from. This can be used when you have limited access to gcr.io. Set it to \"auto\" to let minikube decide one for you. For Chinese mainland users, you may use local gcr.io mirrors such as registry.cn-hangzhou.aliyuncs.com/google_containers")
 	startCmd.Flags().String(imageMirrorCountry, "", "Country code of the image mirror to be used. Leave empty to use the global one. For Chinese mainland users, set it to cn.")
 	startCmd.Flags().String(serviceCIDR, constants.DefaultServiceCIDR, "The CIDR to be used for service cluster IPs.")
@@ -272,6 +399,11 @@ func generateClusterConfig(cmd *cobra.Command, existing *config.ClusterConfig, k
 			exit.Message(reason.Usage, "Generate unable to parse disk size '{{.diskSize}}': {{.error}}", out.V{"diskSize": viper.GetString(humanReadableDiskSize), "error": err})
 		}
 
+		extraDiskMB, err := extraDiskSizeMB(viper.GetString(extraDiskSize))
+		if err != nil {
+			exit.Message(reason.Usage, "Generate unable to parse extra disk size '{{.diskSize}}': {{.error}}", out.V{"diskSize": viper.GetString(extraDiskSize), "error": err})
+		}
+
 		repository := viper.GetString(imageRepository)
 		mirrorCountry := strings.ToLower(viper.GetString(imageMirrorCountry))
 		if strings.ToLower(repository) == "auto" || (mirrorCountry != "" && repository == "") {
@@ -307,14 +439,24 @@ func generateClusterConfig(cmd *cobra.Command, existing *config.ClusterConfig, k
 			out.WarningT("With --network-plugin=cni, you will need to provide your own CNI. See --cni flag as a user-friendly alternative")
 		}
 
-		if !driver.IsKIC(drvName) && viper.GetString(network) != "" {
-			out.WarningT("--network flag is only valid with the docker/podman drivers, it will be ignored")
+		if !driver.IsKIC(drvName) && !driver.IsQEMU(drvName) && viper.GetString(network) != "" {
+			out.WarningT("--network flag is only valid with the docker/podman and qemu drivers, it will be ignored")
+		}
+
+		apiPort := viper.GetInt(apiServerPort)
+		if driver.IsQEMUUserNetwork(drvName, viper.GetString(network)) && !cmd.Flags().Changed(apiServerPort) {
+			// the apiserver port is forwarded to the same port of the host, which has to be free
+			apiPort, err = getPort()
+			if err != nil {
+				return cc, config.Node{}, errors.Wrap(err, "apiserver port")
+			}
 		}
 
 		cc = config.ClusterConfig{
 			Name:                    ClusterFlagValue(),
 			KeepContext:             viper.GetBool(keepContext),
 			EmbedCerts:              viper.GetBool(embedCerts),
+			KubeconfigPath:          kubeconfigPath(),
 			MinikubeISO:             viper.GetString(isoURL),
 			KicBaseImage:            viper.GetString(kicBaseImage),
 			Network:                 viper.GetString(network),
@@ -330,6 +472,8 @@ func generateClusterConfig(cmd *cobra.Command, existing *config.ClusterConfig, k
 			DockerOpt:               config.DockerOpt,
 			InsecureRegistry:        insecureRegistry,
 			RegistryMirror:          registryMirror,
+			RegistryCA:              viper.GetStringSlice(registryCA),
+			RegistryAuthFromHost:    viper.GetBool(registryAuthFromHost),
 			HostOnlyCIDR:            viper.GetString(hostOnlyCIDR),
 			HypervVirtualSwitch:     viper.GetString(hypervVirtualSwitch),
 			HypervUseExternalSwitch: viper.GetBool(hypervUseExternalSwitch),
@@ -347,10 +491,13 @@ func generateClusterConfig(cmd *cobra.Command, existing *config.ClusterConfig, k
 			NatNicType:              viper.GetString(natNicType),
 			StartHostTimeout:        viper.GetDuration(waitTimeout),
 			ExposedPorts:            viper.GetStringSlice(ports),
-			SSHIPAddress:            viper.GetString(sshIPAddress),
-			SSHUser:                 viper.GetString(sshSSHUser),
-			SSHKey:                  viper.GetString(sshSSHKey),
-			SSHPort:                 viper.GetInt(sshSSHPort),
+			SocketVMnetClientPath:   viper.GetString(socketVMnetClientPath),
+			SocketVMnetPath:         viper.GetString(socketVMnetPath),
+			DriverOptions:           driverOptions(drvName),
+			ExtraDisks:              viper.GetInt(extraDisks),
+			ExtraDiskSize:           extraDiskMB,
+			Subnet:                  clusterSubnet(),
+			ReserveResources:        viper.GetBool(reserveResources),
 			KubernetesConfig: config.KubernetesConfig{
 				KubernetesVersion:      k8sVersion,
 				ClusterName:            ClusterFlagValue(),
@@ -368,9 +515,10 @@ func generateClusterConfig(cmd *cobra.Command, existing *config.ClusterConfig, k
 				ExtraOptions:           config.ExtraOptions,
 				ShouldLoadCachedImages: viper.GetBool(cacheImages),
 				CNI:                    chosenCNI,
-				NodePort:               viper.GetInt(apiServerPort),
+				NodePort:               apiPort,
 			},
-			MultiNodeRequested: viper.GetInt(nodes) > 1,
+			MultiNodeRequested: viper.GetInt(nodes) > 1 || viper.GetBool(ha),
+			HA:                 viper.GetBool(ha),
 		}
 		cc.VerifyComponents = interpretWaitFlag(*cmd)
 		if viper.GetBool(createMount) && driver.IsKIC(drvName) {
@@ -387,6 +535,9 @@ func generateClusterConfig(cmd *cobra.Command, existing *config.ClusterConfig, k
 		}
 	}
 
+	// detected on every start, as the daemon of an existing cluster may have been switched to rootless
+	cc.Rootless = driver.IsKIC(cc.Driver) && oci.IsRootless(cc.Driver)
+
 	klog.Infof("config:\n%+v", cc)
 
 	r, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime})
@@ -431,7 +582,7 @@ func upgradeExistingConfig(cc *config.ClusterConfig) {
 }
 
 // updateExistingConfigFromFlags will update the existing config from the flags - used on a second start
-// skipping updating existing docker env , docker opt, InsecureRegistry, registryMirror, extra-config, apiserver-ips
+// skipping updating existing docker env , docker opt, extra-config, apiserver-ips
 func updateExistingConfigFromFlags(cmd *cobra.Command, existing *config.ClusterConfig) config.ClusterConfig { //nolint to suppress cyclomatic complexity 45 of func `updateExistingConfigFromFlags` is high (> 30)
 
 	validateFlags(cmd, existing.Driver)
@@ -450,6 +601,10 @@ func updateExistingConfigFromFlags(cmd *cobra.Command, existing *config.ClusterC
 		cc.EmbedCerts = viper.GetBool(embedCerts)
 	}
 
+	if cmd.Flags().Changed(kubeconfigFile) {
+		cc.KubeconfigPath = kubeconfigPath()
+	}
+
 	if cmd.Flags().Changed(isoURL) {
 		cc.MinikubeISO = viper.GetString(isoURL)
 	}
@@ -469,7 +624,11 @@ func updateExistingConfigFromFlags(cmd *cobra.Command, existing *config.ClusterC
 			klog.Warningf("error calculate memory size in mb : %v", err)
 		}
 		if memInMB != cc.Memory {
-			out.WarningT("You cannot change the memory size for an exiting minikube cluster. Please first delete the cluster.")
+			if driver.Resize(cc.Driver) == driver.ResizeUnsupported {
+				out.WarningT("You cannot change the memory size for an existing {{.driver}} cluster. Please first delete the cluster.", out.V{"driver": cc.Driver})
+			} else {
+				cc.Memory = memInMB
+			}
 		}
 	}
 
@@ -482,7 +641,11 @@ func updateExistingConfigFromFlags(cmd *cobra.Command, existing *config.ClusterC
 	}
 	if cmd.Flags().Changed(cpus) {
 		if viper.GetInt(cpus) != cc.CPUs {
-			out.WarningT("You cannot change the CPUs for an existing minikube cluster. Please first delete the cluster.")
+			if driver.Resize(cc.Driver) == driver.ResizeUnsupported {
+				out.WarningT("You cannot change the CPUs for an existing {{.driver}} cluster. Please first delete the cluster.", out.V{"driver": cc.Driver})
+			} else {
+				cc.CPUs = viper.GetInt(cpus)
+			}
 		}
 	}
 
@@ -497,6 +660,24 @@ func updateExistingConfigFromFlags(cmd *cobra.Command, existing *config.ClusterC
 		}
 	}
 
+	if cmd.Flags().Changed(ha) && viper.GetBool(ha) != existing.HA {
+		out.WarningT("You cannot change the high availability of an existing minikube cluster. Please first delete the cluster.")
+	}
+
+	if cmd.Flags().Changed(subnet) && viper.GetString(subnet) != existing.Subnet {
+		out.WarningT("You cannot change the subnet of an existing minikube cluster. Please first delete the cluster.")
+	}
+
+	if cmd.Flags().Changed(staticIP) {
+		if cp, err := config.PrimaryControlPlane(existing); err == nil && viper.GetString(staticIP) != cp.StaticIP {
+			out.WarningT("You cannot change the static IP of an existing minikube cluster. Please first delete the cluster.")
+		}
+	}
+
+	if cmd.Flags().Changed(extraDisks) && viper.GetInt(extraDisks) != existing.ExtraDisks {
+		out.WarningT("You cannot change the number of extra disks for an existing minikube cluster. Please first delete the cluster.")
+	}
+
 	if cmd.Flags().Changed(vpnkitSock) {
 		cc.HyperkitVpnKitSock = viper.GetString(vpnkitSock)
 	}
@@ -569,10 +750,40 @@ func updateExistingConfigFromFlags(cmd *cobra.Command, existing *config.ClusterC
 		cc.HostOnlyNicType = viper.GetString(hostOnlyNicType)
 	}
 
+	// the container runtime is configured with the registries on each start
+	if cmd.Flags().Changed("insecure-registry") {
+		cc.InsecureRegistry = insecureRegistry
+	}
+
+	if len(registryMirror) > 0 {
+		cc.RegistryMirror = registryMirror
+	}
+
+	if cmd.Flags().Changed(registryCA) {
+		cc.RegistryCA = viper.GetStringSlice(registryCA)
+	}
+
+	if cmd.Flags().Changed(registryAuthFromHost) {
+		cc.RegistryAuthFromHost = viper.GetBool(registryAuthFromHost)
+	}
+
 	if cmd.Flags().Changed(natNicType) {
 		cc.NatNicType = viper.GetString(natNicType)
 	}
 
+	if cmd.Flags().Changed(reserveResources) {
+		cc.ReserveResources = viper.GetBool(reserveResources)
+	}
+
+	for _, f := range registry.Driver(cc.Driver).Flags {
+		if cmd.Flags().Changed(f.Name) {
+			if cc.DriverOptions == nil {
+				cc.DriverOptions = map[string]string{}
+			}
+			cc.DriverOptions[f.Name] = viper.GetString(f.Name)
+		}
+	}
+
 	if cmd.Flags().Changed(kubernetesVersion) {
 		cc.KubernetesConfig.KubernetesVersion = getKubernetesVersion(existing)
 	}
@@ -664,6 +875,19 @@ func updateExistingConfigFromFlags(cmd *cobra.Command, existing *config.ClusterC
 	return cc
 }
 
+// kubeconfigPath returns the absolute path of the --kubeconfig flag, as later commands may run from another directory
+func kubeconfigPath() string {
+	p := viper.GetString(kubeconfigFile)
+	if p == "" {
+		return ""
+	}
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		exit.Message(reason.Usage, "Unable to resolve kubeconfig path {{.path}}: {{.error}}", out.V{"path": p, "error": err})
+	}
+	return abs
+}
+
 // interpretWaitFlag interprets the wait flag and respects the legacy minikube users
 // returns map of components to wait for
 func interpretWaitFlag(cmd cobra.Command) map[string]bool {
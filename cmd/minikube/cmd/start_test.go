@@ -310,3 +310,113 @@ func TestBaseImageFlagDriverCombo(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateExtraDisks(t *testing.T) {
+	var tests = []struct {
+		driver  string
+		count   int
+		wantErr bool
+	}{
+		{driver.KVM2, 0, false},
+		{driver.KVM2, 3, false},
+		{driver.Docker, 1, false},
+		{driver.Podman, 25, false},
+		{driver.Docker, 26, true},
+		{driver.KVM2, -1, true},
+		{driver.VirtualBox, 1, true},
+		{driver.VirtualBox, 0, false},
+	}
+	for _, tc := range tests {
+		if err := validateExtraDisks(tc.driver, tc.count); (err != nil) != tc.wantErr {
+			t.Errorf("validateExtraDisks(%s, %d) = %v, want error: %t", tc.driver, tc.count, err, tc.wantErr)
+		}
+	}
+}
+
+func TestExtraDiskSizeMB(t *testing.T) {
+	var tests = []struct {
+		size    string
+		want    int
+		wantErr bool
+	}{
+		{"20000mb", 20000, false},
+		{"512", 512, false},
+		{"5g", 5120, false},
+		{"1024k", 1, false},
+		{"100k", 0, true},
+		{"0", 0, true},
+		{"big", 0, true},
+	}
+	for _, tc := range tests {
+		got, err := extraDiskSizeMB(tc.size)
+		if (err != nil) != tc.wantErr || got != tc.want {
+			t.Errorf("extraDiskSizeMB(%q) = %d, %v, want %d, error: %t", tc.size, got, err, tc.want, tc.wantErr)
+		}
+	}
+}
+
+func TestKicNodeIP(t *testing.T) {
+	cc := cfg.ClusterConfig{Name: "p", Driver: driver.Docker, Subnet: "192.168.50.0/24", Nodes: []cfg.Node{
+		{Name: "", ControlPlane: true},
+		{Name: "m02"},
+	}}
+	var tests = []struct {
+		driver string
+		subnet string
+		node   cfg.Node
+		want   string
+	}{
+		{driver.Docker, "192.168.50.0/24", cc.Nodes[0], "192.168.50.2"},
+		{driver.Docker, "192.168.50.0/24", cc.Nodes[1], "192.168.50.3"},
+		{driver.Podman, "192.168.50.0/24", cfg.Node{Name: "m03"}, "192.168.50.4"},
+		{driver.Docker, "", cc.Nodes[1], ""},
+		{driver.KVM2, "192.168.50.0/24", cc.Nodes[1], ""},
+	}
+	for _, tc := range tests {
+		cc.Driver = tc.driver
+		cc.Subnet = tc.subnet
+		if got := kicNodeIP(cc, tc.node); got != tc.want {
+			t.Errorf("kicNodeIP(%s, %s, %q) = %q, want %q", tc.driver, tc.subnet, tc.node.Name, got, tc.want)
+		}
+	}
+}
+
+func TestClusterSubnet(t *testing.T) {
+	defer viper.Reset()
+	var tests = []struct {
+		subnet   string
+		staticIP string
+		want     string
+	}{
+		{"", "", ""},
+		{"10.10.0.0/16", "", "10.10.0.0/16"},
+		{"10.10.0.0/16", "10.10.3.4", "10.10.0.0/16"},
+		{"", "192.168.50.10", "192.168.50.0/24"},
+	}
+	for _, tc := range tests {
+		viper.Set(subnet, tc.subnet)
+		viper.Set(staticIP, tc.staticIP)
+		if got := clusterSubnet(); got != tc.want {
+			t.Errorf("clusterSubnet() with --subnet=%q --static-ip=%q = %q, want %q", tc.subnet, tc.staticIP, got, tc.want)
+		}
+	}
+}
+
+func TestArgFlags(t *testing.T) {
+	args := []string{"start", "--driver", "fake", "--driver-plugins=false", "-p", "p1", "--", "--vm-driver=other"}
+	if got := argFlagValue(args, "driver"); got != "fake" {
+		t.Errorf("argFlagValue(driver) = %q, want fake", got)
+	}
+	if got := argFlagValue([]string{"start", "--driver=fake"}, "driver"); got != "fake" {
+		t.Errorf("argFlagValue(--driver=fake) = %q, want fake", got)
+	}
+	if got := argFlagValue(args, "vm-driver"); got != "" {
+		t.Errorf("argFlagValue(vm-driver) = %q, want none after --", got)
+	}
+	if argFlagSet(args, driverPlugins) {
+		t.Errorf("argFlagSet(%s) with --%s=false is true", driverPlugins, driverPlugins)
+	}
+	if !argFlagSet([]string{"start", "--" + driverPlugins}, driverPlugins) {
+		t.Errorf("argFlagSet(%s) is false", driverPlugins)
+	}
+}
@@ -55,6 +55,8 @@ var (
 	output       string
 	layout       string
 	watch        time.Duration
+	statusSocket string
+	resources    bool
 )
 
 const (
@@ -136,6 +138,7 @@ type Status struct {
 	Kubeconfig string
 	Worker     bool
 	TimeToStop string
+	Resources  *machine.ResourceUsage `json:",omitempty"`
 }
 
 // ClusterState holds a cluster state representation
@@ -151,7 +154,8 @@ type ClusterState struct {
 // NodeState holds a node state representation
 type NodeState struct {
 	BaseState
-	Components map[string]BaseState `json:",omitempty"`
+	Components map[string]BaseState   `json:",omitempty"`
+	Resources  *machine.ResourceUsage `json:",omitempty"`
 }
 
 // BaseState holds a component state representation, such as "apiserver" or "kubeconfig"
@@ -190,6 +194,13 @@ type: Worker
 host: {{.Host}}
 kubelet: {{.Kubelet}}
 
+`
+	resourcesFormat = `cpu: {{printf "%.1f" .Resources.CPUPercent}}%
+memory: {{.Resources.MemoryUsedMB}}MB / {{.Resources.MemoryTotalMB}}MB
+disk: {{.Resources.DiskUsedMB}}MB / {{.Resources.DiskTotalMB}}MB
+images: {{.Resources.ImagesMB}}MB
+volumes: {{.Resources.VolumesMB}}MB
+
 `
 )
 
@@ -206,7 +217,17 @@ var statusCmd = &cobra.Command{
 			exit.Message(reason.Usage, "Cannot use both --output and --format options")
 		}
 
-		out.SetJSON(output == "json")
+		if output == "json-stream" && nodeName != "" {
+			exit.Message(reason.Usage, "Cannot use both --output=json-stream and --node options")
+		}
+		if resources && output == "json-stream" {
+			exit.Message(reason.Usage, "Cannot use both --output=json-stream and --resources options")
+		}
+		if statusSocket != "" && output != "json-stream" {
+			exit.Message(reason.Usage, "The --socket option requires --output=json-stream")
+		}
+
+		out.SetJSON(output == "json" || output == "json-stream")
 
 		cname := ClusterFlagValue()
 		api, cc := mustload.Partial(cname)
@@ -215,6 +236,14 @@ var statusCmd = &cobra.Command{
 		if !cmd.Flags().Changed("watch") || watch < 0 {
 			duration = 0
 		}
+		if statusSocket != "" && duration == 0 {
+			exit.Message(reason.Usage, "The --socket option requires --watch")
+		}
+
+		if output == "json-stream" {
+			streamStatusesAtInterval(duration, api, cname)
+			return
+		}
 		writeStatusesAtInterval(duration, api, cc)
 	},
 }
@@ -236,20 +265,11 @@ func writeStatusesAtInterval(duration time.Duration, api libmachine.API, cc *con
 			}
 			statuses = append(statuses, st)
 		} else {
-			for _, n := range cc.Nodes {
-				machineName := config.MachineName(*cc, n)
-				klog.Infof("checking status of %s ...", machineName)
-				st, err := nodeStatus(api, *cc, n)
-				klog.Infof("%s status: %+v", machineName, st)
-
-				if err != nil {
-					klog.Errorf("status error: %v", err)
-				}
-				if st.Host == Nonexistent {
-					klog.Errorf("The %q host does not exist!", machineName)
-				}
-				statuses = append(statuses, st)
-			}
+			statuses = allNodeStatuses(api, cc)
+		}
+
+		if resources {
+			addResourceUsage(api, *cc, statuses)
 		}
 
 		switch output {
@@ -271,7 +291,11 @@ func writeStatusesAtInterval(duration time.Duration, api libmachine.API, cc *con
 				}
 			}
 		default:
-			exit.Message(reason.Usage, fmt.Sprintf("invalid output format: %s. Valid values: 'text', 'json'", output))
+			exit.Message(reason.Usage, fmt.Sprintf("invalid output format: %s. Valid values: 'text', 'json', 'json-stream'", output))
+		}
+
+		if cc.HA && nodeName == "" {
+			warnControlPlaneQuorum(statuses)
 		}
 
 		if duration == 0 {
@@ -281,6 +305,72 @@ func writeStatusesAtInterval(duration time.Duration, api libmachine.API, cc *con
 	}
 }
 
+// addResourceUsage looks up the resources consumed by each running node
+func addResourceUsage(api libmachine.API, cc config.ClusterConfig, statuses []*Status) {
+	for _, st := range statuses {
+		if st.Host != state.Running.String() {
+			continue
+		}
+		h, err := machine.LoadHost(api, st.Name)
+		if err != nil {
+			klog.Errorf("unable to load host %s: %v", st.Name, err)
+			continue
+		}
+		u, err := machine.Usage(h, cc.KubernetesConfig.ContainerRuntime)
+		if err != nil {
+			klog.Errorf("unable to get resource usage of %s: %v", st.Name, err)
+			continue
+		}
+		st.Resources = u
+	}
+}
+
+// allNodeStatuses looks up the status of every node in a cluster
+func allNodeStatuses(api libmachine.API, cc *config.ClusterConfig) []*Status {
+	var statuses []*Status
+	for _, n := range cc.Nodes {
+		machineName := config.MachineName(*cc, n)
+		klog.Infof("checking status of %s ...", machineName)
+		st, err := nodeStatus(api, *cc, n)
+		klog.Infof("%s status: %+v", machineName, st)
+
+		if err != nil {
+			klog.Errorf("status error: %v", err)
+		}
+		if st.Host == Nonexistent {
+			klog.Errorf("The %q host does not exist!", machineName)
+		}
+		statuses = append(statuses, st)
+	}
+	return statuses
+}
+
+// controlPlaneQuorum returns the number of control plane nodes with a running API server,
+// the number of control plane nodes, and how many of them etcd needs running for a quorum
+func controlPlaneQuorum(statuses []*Status) (int, int, int) {
+	running, total := 0, 0
+	for _, st := range statuses {
+		if st.Worker {
+			continue
+		}
+		total++
+		if st.APIServer == state.Running.String() {
+			running++
+		}
+	}
+	return running, total, total/2 + 1
+}
+
+// warnControlPlaneQuorum warns about control plane nodes of HA clusters being down
+func warnControlPlaneQuorum(statuses []*Status) {
+	running, total, quorum := controlPlaneQuorum(statuses)
+	if running < quorum {
+		out.WarningT("Only {{.running}} of {{.total}} control plane nodes are running, etcd needs {{.quorum}} for a quorum: the cluster does not accept changes", out.V{"running": running, "total": total, "quorum": quorum})
+	} else if running < total {
+		out.WarningT("{{.running}} of {{.total}} control plane nodes are running, the cluster keeps working as long as {{.quorum}} of them do", out.V{"running": running, "total": total, "quorum": quorum})
+	}
+}
+
 // exitCode calcluates the appropriate exit code given a set of status messages
 func exitCode(statuses []*Status) int {
 	c := 0
@@ -384,13 +474,21 @@ func nodeStatus(api libmachine.API, cc config.ClusterConfig, n config.Node) (*St
 		klog.Errorf("forwarded endpoint: %v", err)
 		st.Kubeconfig = Misconfigured
 	} else {
-		err := kubeconfig.VerifyEndpoint(cc.Name, hostname, port)
+		err := kubeconfig.VerifyEndpoint(cc.Name, hostname, port, kubeconfig.PathFromConfig(&cc))
 		if err != nil {
 			klog.Errorf("kubeconfig endpoint: %v", err)
 			st.Kubeconfig = Misconfigured
 		}
 	}
 
+	if cc.HA {
+		// the virtual IP reaches any of the control plane nodes, check the API server of this one
+		hostname, _, port, err = driver.APIServerEndpoint(&cc, &n, host.DriverName)
+		if err != nil {
+			klog.Errorf("apiserver endpoint: %v", err)
+		}
+	}
+
 	sta, err := kverify.APIServerStatus(cr, hostname, port)
 	klog.Infof("%s apiserver status = %s (err=%v)", name, stk, err)
 
@@ -409,19 +507,26 @@ func init() {
 		`Go template format string for the status output.  The format for Go templates can be found here: https://golang.org/pkg/text/template/
 For the list accessible variables for the template, see the struct values here: https://godoc.org/k8s.io/minikube/cmd/minikube/cmd#Status`)
 	statusCmd.Flags().StringVarP(&output, "output", "o", "text",
-		`minikube status --output OUTPUT. json, text`)
+		`minikube status --output OUTPUT. json, json-stream, text`)
 	statusCmd.Flags().StringVarP(&layout, "layout", "l", "nodes",
 		`output layout (EXPERIMENTAL, JSON only): 'nodes' or 'cluster'`)
 	statusCmd.Flags().StringVarP(&nodeName, "node", "n", "", "The node to check status for. Defaults to control plane. Leave blank with default format for status on all nodes.")
 	statusCmd.Flags().DurationVarP(&watch, "watch", "w", 1*time.Second, "Continuously listing/getting the status with optional interval duration.")
 	statusCmd.Flags().Lookup("watch").NoOptDefVal = "1s"
+	statusCmd.Flags().BoolVar(&resources, "resources", false, "Also report the CPU, memory and disk consumed by each running node.")
+	statusCmd.Flags().StringVar(&statusSocket, "socket", "", "Also serve the json-stream output on this Unix socket. Requires --output=json-stream and --watch.")
 }
 
 func statusText(st *Status, w io.Writer) error {
-	tmpl, err := template.New("status").Parse(statusFormat)
+	format := statusFormat
 	if st.Worker && statusFormat == defaultStatusFormat {
-		tmpl, err = template.New("worker-status").Parse(workerStatusFormat)
+		format = workerStatusFormat
+	}
+	// Resource usage is appended within the default formats, before their trailing blank line
+	if st.Resources != nil && statusFormat == defaultStatusFormat {
+		format = strings.TrimSuffix(format, "\n") + resourcesFormat
 	}
+	tmpl, err := template.New("status").Parse(format)
 	if err != nil {
 		return err
 	}
@@ -512,6 +617,7 @@ func clusterState(sts []*Status) ClusterState {
 			Components: map[string]BaseState{
 				"kubelet": {Name: "kubelet", StatusCode: statusCode(st.Kubelet)},
 			},
+			Resources: st.Resources,
 		}
 
 		if st.APIServer != Irrelevant {
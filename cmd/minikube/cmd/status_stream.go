@@ -0,0 +1,282 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io"
+	"net"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out/register"
+	"k8s.io/minikube/pkg/minikube/reason"
+)
+
+// clusterComponent is the name of the cluster-wide component reported by the status stream
+const clusterComponent = "cluster"
+
+// socketWriteTimeout is how long a socket client may block the status stream before being dropped
+const socketWriteTimeout = 1 * time.Second
+
+// statusKey identifies a component whose state is tracked by the status stream
+type statusKey struct {
+	// Node is the machine name, empty for the cluster-wide component
+	Node      string
+	Component string
+}
+
+// statusSnapshot is the state of every component of a cluster at a point in time
+type statusSnapshot struct {
+	// keys lists the components in the order changes are emitted in
+	keys   []statusKey
+	states map[statusKey]string
+}
+
+// statusChange is a component whose state differs between two snapshots
+type statusChange struct {
+	key      statusKey
+	previous string
+	current  string
+}
+
+// newStatusSnapshot converts Status structs into a statusSnapshot. An empty list means the cluster does not exist.
+func newStatusSnapshot(sts []*Status) statusSnapshot {
+	ss := statusSnapshot{states: map[statusKey]string{}}
+	add := func(k statusKey, state string) {
+		if state == Irrelevant {
+			return
+		}
+		ss.keys = append(ss.keys, k)
+		ss.states[k] = state
+	}
+
+	cs := codeNames[NotFound]
+	if len(sts) > 0 {
+		cs = clusterState(sts).StatusName
+	}
+	add(statusKey{Component: clusterComponent}, cs)
+
+	for _, st := range sts {
+		add(statusKey{Node: st.Name, Component: "host"}, st.Host)
+		add(statusKey{Node: st.Name, Component: "kubelet"}, st.Kubelet)
+		add(statusKey{Node: st.Name, Component: "apiserver"}, st.APIServer)
+		add(statusKey{Node: st.Name, Component: "kubeconfig"}, st.Kubeconfig)
+	}
+	return ss
+}
+
+// changes returns the components whose state differs from prev, or every component if prev is nil.
+// Components which are no longer part of the cluster, such as deleted nodes, are reported as Nonexistent.
+func (ss statusSnapshot) changes(prev *statusSnapshot) []statusChange {
+	var cs []statusChange
+	if prev == nil {
+		for _, k := range ss.keys {
+			cs = append(cs, statusChange{key: k, current: ss.states[k]})
+		}
+		return cs
+	}
+
+	for _, k := range ss.keys {
+		p, ok := prev.states[k]
+		if !ok {
+			p = Nonexistent
+		}
+		if p != ss.states[k] {
+			cs = append(cs, statusChange{key: k, previous: p, current: ss.states[k]})
+		}
+	}
+	for _, k := range prev.keys {
+		if _, ok := ss.states[k]; ok {
+			continue
+		}
+		if prev.states[k] != Nonexistent {
+			cs = append(cs, statusChange{key: k, previous: prev.states[k], current: Nonexistent})
+		}
+	}
+	return cs
+}
+
+// statusEvent is an encoded status change, along with its sequence number
+type statusEvent struct {
+	sequence int
+	data     []byte
+}
+
+// statusStream emits a CloudEvent for every change in the state of a cluster, to a writer and to any socket clients
+type statusStream struct {
+	cluster string
+	w       io.Writer
+
+	mu       sync.Mutex
+	sequence int
+	last     *statusSnapshot
+	// latest holds the most recent event for each component, which is replayed to new socket clients
+	latest   map[statusKey]statusEvent
+	clients  map[net.Conn]bool
+	listener net.Listener
+}
+
+func newStatusStream(cluster string, w io.Writer) *statusStream {
+	return &statusStream{
+		cluster: cluster,
+		w:       w,
+		latest:  map[statusKey]statusEvent{},
+		clients: map[net.Conn]bool{},
+	}
+}
+
+// publish emits an event for each component which changed since the last snapshot
+func (s *statusStream) publish(ss statusSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, c := range ss.changes(s.last) {
+		s.sequence++
+		bs, err := register.MarshalStatusChange(register.NewStatusChange(s.sequence, s.cluster, c.key.Node, c.key.Component, c.previous, c.current))
+		if err != nil {
+			return errors.Wrap(err, "marshal")
+		}
+		bs = append(bs, '\n')
+
+		if _, err := s.w.Write(bs); err != nil {
+			return errors.Wrap(err, "write")
+		}
+		for conn := range s.clients {
+			if err := writeSocket(conn, bs); err != nil {
+				klog.Warningf("dropping status socket client: %v", err)
+				conn.Close()
+				delete(s.clients, conn)
+			}
+		}
+
+		if _, ok := ss.states[c.key]; ok {
+			s.latest[c.key] = statusEvent{sequence: s.sequence, data: bs}
+		} else {
+			delete(s.latest, c.key)
+		}
+	}
+	s.last = &ss
+	return nil
+}
+
+// listen serves the stream on a Unix socket, replacing any stale socket at path
+func (s *statusStream) listen(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "remove stale socket")
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return errors.Wrap(err, "listen")
+	}
+	s.listener = l
+	go s.accept()
+	return nil
+}
+
+// accept replays the current state to each new socket client, then subscribes it to further changes
+func (s *statusStream) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			klog.Infof("status socket closed: %v", err)
+			return
+		}
+
+		s.mu.Lock()
+		evs := []statusEvent{}
+		for _, ev := range s.latest {
+			evs = append(evs, ev)
+		}
+		sort.Slice(evs, func(i, j int) bool { return evs[i].sequence < evs[j].sequence })
+
+		subscribe := true
+		for _, ev := range evs {
+			if err := writeSocket(conn, ev.data); err != nil {
+				klog.Warningf("unable to replay status to socket client: %v", err)
+				conn.Close()
+				subscribe = false
+				break
+			}
+		}
+		if subscribe {
+			s.clients[conn] = true
+		}
+		s.mu.Unlock()
+	}
+}
+
+// close stops serving the stream on its socket
+func (s *statusStream) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listener != nil {
+		s.listener.Close()
+	}
+	for conn := range s.clients {
+		conn.Close()
+	}
+	s.clients = map[net.Conn]bool{}
+}
+
+func writeSocket(conn net.Conn, bs []byte) error {
+	if err := conn.SetWriteDeadline(time.Now().Add(socketWriteTimeout)); err != nil {
+		return err
+	}
+	_, err := conn.Write(bs)
+	return err
+}
+
+// streamStatusesAtInterval writes an event for every change in the state of a cluster - at intervals defined by duration
+func streamStatusesAtInterval(duration time.Duration, api libmachine.API, cname string) {
+	s := newStatusStream(cname, os.Stdout)
+	if statusSocket != "" {
+		if err := s.listen(statusSocket); err != nil {
+			exit.Error(reason.HostStatusSocket, "Unable to serve status on socket", err)
+		}
+		defer s.close()
+	}
+
+	for {
+		// Reload the config each time, so that added and deleted nodes are noticed
+		sts := []*Status{}
+		cc, err := config.Load(cname)
+		if err != nil && !config.IsNotExist(err) {
+			exit.Error(reason.HostConfigLoad, "Unable to load cluster config", err)
+		}
+		if err == nil {
+			sts = allNodeStatuses(api, cc)
+		}
+
+		if err := s.publish(newStatusSnapshot(sts)); err != nil {
+			exit.Error(reason.InternalStatusJSON, "status json-stream failure", err)
+		}
+
+		if duration == 0 {
+			s.close()
+			os.Exit(exitCode(sts))
+		}
+		time.Sleep(duration)
+	}
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// snapshot builds a statusSnapshot from alternating keys and states
+func snapshot(entries ...interface{}) statusSnapshot {
+	ss := statusSnapshot{states: map[statusKey]string{}}
+	for i := 0; i < len(entries); i += 2 {
+		k := entries[i].(statusKey)
+		ss.keys = append(ss.keys, k)
+		ss.states[k] = entries[i+1].(string)
+	}
+	return ss
+}
+
+func TestStatusSnapshotChanges(t *testing.T) {
+	cluster := statusKey{Component: clusterComponent}
+	apiserver := statusKey{Node: "minikube", Component: "apiserver"}
+	workerHost := statusKey{Node: "minikube-m02", Component: "host"}
+
+	running := snapshot(cluster, "OK", apiserver, "Running")
+	paused := snapshot(cluster, "Paused", apiserver, "Paused")
+	added := snapshot(cluster, "OK", apiserver, "Running", workerHost, "Running")
+
+	var tests = []struct {
+		name string
+		prev *statusSnapshot
+		cur  statusSnapshot
+		want []statusChange
+	}{
+		{"initial", nil, running, []statusChange{
+			{key: cluster, current: "OK"},
+			{key: apiserver, current: "Running"},
+		}},
+		{"unchanged", &running, running, nil},
+		{"paused", &running, paused, []statusChange{
+			{key: cluster, previous: "OK", current: "Paused"},
+			{key: apiserver, previous: "Running", current: "Paused"},
+		}},
+		{"node added", &running, added, []statusChange{
+			{key: workerHost, previous: Nonexistent, current: "Running"},
+		}},
+		{"node deleted", &added, running, []statusChange{
+			{key: workerHost, previous: "Running", current: Nonexistent},
+		}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.cur.changes(tc.prev)
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(statusChange{})); diff != "" {
+				t.Errorf("changes() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStatusStreamPublish(t *testing.T) {
+	cluster := statusKey{Component: clusterComponent}
+	host := statusKey{Node: "minikube", Component: "host"}
+
+	var b bytes.Buffer
+	s := newStatusStream("minikube", &b)
+	for _, ss := range []statusSnapshot{
+		snapshot(cluster, "OK", host, "Running"),
+		snapshot(cluster, "OK", host, "Running"),
+		snapshot(cluster, "Stopped", host, "Stopped"),
+	} {
+		if err := s.publish(ss); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+
+	type event struct {
+		Type string
+		Data map[string]string
+	}
+	var got []map[string]string
+	scanner := bufio.NewScanner(&b)
+	for scanner.Scan() {
+		ev := event{}
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			t.Fatalf("unmarshal %q: %v", scanner.Text(), err)
+		}
+		if ev.Type != "io.k8s.sigs.minikube.status" {
+			t.Errorf("event type = %q, want io.k8s.sigs.minikube.status", ev.Type)
+		}
+		got = append(got, ev.Data)
+	}
+
+	want := []map[string]string{
+		{"sequence": "1", "cluster": "minikube", "node": "", "component": "cluster", "previous": "", "current": "OK"},
+		{"sequence": "2", "cluster": "minikube", "node": "minikube", "component": "host", "previous": "", "current": "Running"},
+		{"sequence": "3", "cluster": "minikube", "node": "", "component": "cluster", "previous": "OK", "current": "Stopped"},
+		{"sequence": "4", "cluster": "minikube", "node": "minikube", "component": "host", "previous": "Running", "current": "Stopped"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("published events mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestStatusStreamSocket(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "status")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cluster := statusKey{Component: clusterComponent}
+	host := statusKey{Node: "minikube", Component: "host"}
+
+	s := newStatusStream("minikube", ioutil.Discard)
+	path := filepath.Join(tempDir, "status.sock")
+	if err := s.listen(path); err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer s.close()
+
+	if err := s.publish(snapshot(cluster, "OK", host, "Running")); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if err := s.publish(snapshot(cluster, "OK", host, "Stopped")); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+
+	// A new client is sent the latest event for each component, in sequence order
+	scanner := bufio.NewScanner(conn)
+	var got []string
+	for len(got) < 2 && scanner.Scan() {
+		ev := struct{ Data map[string]string }{}
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			t.Fatalf("unmarshal %q: %v", scanner.Text(), err)
+		}
+		got = append(got, ev.Data["sequence"]+":"+ev.Data["component"]+"="+ev.Data["current"])
+	}
+	want := []string{"1:cluster=OK", "3:host=Stopped"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("replayed events mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -20,6 +20,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"testing"
+
+	"k8s.io/minikube/pkg/minikube/machine"
 )
 
 func TestExitCode(t *testing.T) {
@@ -43,6 +45,27 @@ func TestExitCode(t *testing.T) {
 	}
 }
 
+func TestControlPlaneQuorum(t *testing.T) {
+	var tests = []struct {
+		name     string
+		statuses []*Status
+		running  int
+		quorum   int
+	}{
+		{"all running", []*Status{{APIServer: "Running"}, {APIServer: "Running"}, {APIServer: "Running"}, {Worker: true, APIServer: Irrelevant}}, 3, 2},
+		{"one down", []*Status{{APIServer: "Running"}, {APIServer: "Stopped"}, {APIServer: "Running"}}, 2, 2},
+		{"quorum lost", []*Status{{APIServer: "Running"}, {APIServer: "Stopped"}, {APIServer: "Error"}}, 1, 2},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			running, total, quorum := controlPlaneQuorum(tc.statuses)
+			if running != tc.running || total != 3 || quorum != tc.quorum {
+				t.Errorf("controlPlaneQuorum() = %d, %d, %d, want %d, 3, %d", running, total, quorum, tc.running, tc.quorum)
+			}
+		})
+	}
+}
+
 func TestStatusText(t *testing.T) {
 	var tests = []struct {
 		name  string
@@ -64,6 +87,12 @@ func TestStatusText(t *testing.T) {
 			state: &Status{Name: "minikube", Host: "Stopped", Kubelet: "Stopped", APIServer: "Stopped", Kubeconfig: Misconfigured, TimeToStop: Nonexistent},
 			want:  "minikube\ntype: Control Plane\nhost: Stopped\nkubelet: Stopped\napiserver: Stopped\nkubeconfig: Misconfigured\ntimeToStop: Nonexistent\n\n\nWARNING: Your kubectl is pointing to stale minikube-vm.\nTo fix the kubectl context, run `minikube update-context`\n",
 		},
+		{
+			name: "resources",
+			state: &Status{Name: "minikube-m02", Host: "Running", Kubelet: "Running", APIServer: Irrelevant, Kubeconfig: Irrelevant, Worker: true, TimeToStop: Nonexistent,
+				Resources: &machine.ResourceUsage{CPUPercent: 12.34, MemoryUsedMB: 900, MemoryTotalMB: 2200, DiskUsedMB: 3000, DiskTotalMB: 17000, ImagesMB: 1200, VolumesMB: 5}},
+			want: "minikube-m02\ntype: Worker\nhost: Running\nkubelet: Running\ncpu: 12.3%\nmemory: 900MB / 2200MB\ndisk: 3000MB / 17000MB\nimages: 1200MB\nvolumes: 5MB\n\n",
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -132,7 +132,7 @@ func stopProfile(profile string) int {
 	api, cc := mustload.Partial(profile)
 	defer api.Close()
 
-	for _, n := range cc.Nodes {
+	for _, n := range config.StopOrder(*cc) {
 		machineName := config.MachineName(*cc, n)
 
 		nonexistent := stop(api, machineName)
@@ -144,9 +144,11 @@ func stopProfile(profile string) int {
 	if err := killMountProcess(); err != nil {
 		out.WarningT("Unable to kill mount process: {{.error}}", out.V{"error": err})
 	}
+	// the tunnel of containerd-env outlives the node otherwise
+	stopContainerdTunnel(profile)
 
 	if !keepActive {
-		if err := kubeconfig.DeleteContext(profile, kubeconfig.PathFromEnv()); err != nil {
+		if err := kubeconfig.DeleteContext(profile, kubeconfig.PathFromConfig(cc)); err != nil {
 			exit.Error(reason.HostKubeconfigDeleteCtx, "delete ctx", err)
 		}
 	}
@@ -100,7 +100,6 @@ gsutil -qm cp \
 
 gsutil -qm cp -r "gs://minikube-builds/${MINIKUBE_LOCATION}/testdata"/* testdata/
 
-gsutil -qm cp "gs://minikube-builds/${MINIKUBE_LOCATION}/gvisor-addon" testdata/
 
 
 # Set the executable bit on the e2e binary and out binary
@@ -262,14 +261,6 @@ mkdir -p "${TEST_HOME}"
 export MINIKUBE_HOME="${TEST_HOME}/.minikube"
 
 
-# Build the gvisor image so that we can integration test changes to pkg/gvisor
-chmod +x ./testdata/gvisor-addon
-# skipping gvisor mac because ofg https://github.com/kubernetes/minikube/issues/5137
-if [ "$(uname)" != "Darwin" ]; then
-  # Should match GVISOR_IMAGE_VERSION in Makefile
-  docker build -t gcr.io/k8s-minikube/gvisor-addon:2 -f testdata/gvisor-addon-Dockerfile ./testdata
-fi
-
 readonly LOAD=$(uptime | egrep -o "load average.*: [0-9]+" | cut -d" " -f3)
 if [[ "${LOAD}" -gt 2 ]]; then
   echo ""
@@ -17,14 +17,10 @@ limitations under the License.
 package main
 
 import (
-	"bytes"
-	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
-	"os/exec"
 	"runtime/debug"
-	"strings"
 
 	"github.com/spf13/viper"
 	"k8s.io/minikube/pkg/minikube/constants"
@@ -37,11 +33,9 @@ const (
 )
 
 var (
-	dockerStorageDriver = "overlay2"
-	podmanStorageDriver = "overlay"
-	containerRuntimes   = []string{"docker", "containerd", "cri-o"}
-	k8sVersion          string
-	k8sVersions         []string
+	containerRuntimes = []string{"docker", "containerd", "cri-o"}
+	k8sVersion        string
+	k8sVersions       []string
 )
 
 func init() {
@@ -97,42 +91,6 @@ func main() {
 	}
 }
 
-func verifyDockerStorage() error {
-	cmd := exec.Command("docker", "exec", profile, "docker", "info", "-f", "{{.Info.Driver}}")
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-	output, err := cmd.Output()
-	if err != nil {
-		return fmt.Errorf("%v: %v:\n%s", cmd.Args, err, stderr.String())
-	}
-	driver := strings.Trim(string(output), " \n")
-	if driver != dockerStorageDriver {
-		return fmt.Errorf("docker storage driver %s does not match requested %s", driver, dockerStorageDriver)
-	}
-	return nil
-}
-
-func verifyPodmanStorage() error {
-	cmd := exec.Command("docker", "exec", profile, "sudo", "podman", "info", "-f", "json")
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-	output, err := cmd.Output()
-	if err != nil {
-		return fmt.Errorf("%v: %v:\n%s", cmd.Args, err, stderr.String())
-	}
-	var info map[string]map[string]interface{}
-	err = json.Unmarshal(output, &info)
-	if err != nil {
-		return err
-	}
-	driver := info["store"]["graphDriverName"]
-	if driver != podmanStorageDriver {
-		return fmt.Errorf("podman storage driver %s does not match requested %s", driver, podmanStorageDriver)
-	}
-	return nil
-}
-
-// exit will exit and clean up minikube
 func exit(msg string, err error) {
 	fmt.Printf("WithError(%s)=%v called from:\n%s", msg, err, debug.Stack())
 	if err := deleteMinikube(); err != nil {
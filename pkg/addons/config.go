@@ -66,8 +66,8 @@ var Addons = []*Addon{
 	{
 		name:        "gvisor",
 		set:         SetBool,
-		validations: []setFn{IsRuntimeContainerd},
-		callbacks:   []setFn{EnableOrDisableAddon, verifyAddonStatus},
+		validations: []setFn{SupportsRuntimeHandlers},
+		callbacks:   []setFn{enableOrDisableGvisor},
 	},
 	{
 		name:      "helm-tiller",
@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"strconv"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+// gvisorHandler is the runtime handler the gvisor addon installs
+const gvisorHandler = "runsc"
+
+// EnableRuntimeHandler and DisableRuntimeHandler install and remove a runtime handler on the nodes of a cluster,
+// set by the node package, which imports this one
+var (
+	EnableRuntimeHandler  func(api libmachine.API, cc *config.ClusterConfig, name string) error
+	DisableRuntimeHandler func(api libmachine.API, cc *config.ClusterConfig, name string) error
+)
+
+// enableOrDisableGvisor installs gVisor as the runsc runtime handler, the same as 'minikube runtime enable runsc'
+func enableOrDisableGvisor(cc *config.ClusterConfig, name string, val string) error {
+	enable, err := strconv.ParseBool(val)
+	if err != nil {
+		return errors.Wrapf(err, "parsing bool: %s", name)
+	}
+	if EnableRuntimeHandler == nil || DisableRuntimeHandler == nil {
+		return errors.New("runtime handlers are unavailable")
+	}
+
+	enabled := false
+	for _, h := range cc.KubernetesConfig.RuntimeHandlers {
+		enabled = enabled || h == gvisorHandler
+	}
+	if enabled == enable {
+		// the starts install the enabled runtime handlers
+		klog.Infof("runtime handler %s is already in state %v", gvisorHandler, enable)
+		return nil
+	}
+
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		return errors.Wrap(err, "machine client")
+	}
+	defer api.Close()
+
+	cp, err := config.PrimaryControlPlane(cc)
+	if err != nil {
+		return errors.Wrap(err, "control plane")
+	}
+	if mName := config.MachineName(*cc, cp); !machine.IsRunning(api, mName) {
+		klog.Warningf("%q is not running, setting %s=%v and skipping enablement", mName, name, enable)
+		if !enable {
+			// the next start leaves the runtime handler out
+			var hs []string
+			for _, h := range cc.KubernetesConfig.RuntimeHandlers {
+				if h != gvisorHandler {
+					hs = append(hs, h)
+				}
+			}
+			cc.KubernetesConfig.RuntimeHandlers = hs
+		}
+		return nil
+	}
+
+	if !enable {
+		return DisableRuntimeHandler(api, cc, gvisorHandler)
+	}
+	if err := EnableRuntimeHandler(api, cc, gvisorHandler); err != nil {
+		return err
+	}
+	out.Step(style.Ready, "Pods can now run in gVisor with runtimeClassName: {{.class}}", out.V{"class": cruntime.RuntimeHandlers[gvisorHandler].ClassName()})
+	return nil
+}
@@ -28,15 +28,11 @@ import (
 
 const volumesnapshotsAddon = "volumesnapshots"
 
-// containerdOnlyMsg is the message shown when a containerd-only addon is enabled
-const containerdOnlyAddonMsg = `
-This addon can only be enabled with the containerd runtime backend. To enable this backend, please first stop minikube with:
+// runtimeHandlerAddonMsg is the message shown when an addon installing a runtime handler is enabled with another runtime
+const runtimeHandlerAddonMsg = `
+This addon can only be enabled with the containerd or cri-o runtimes. To switch the runtime of the cluster, run:
 
-minikube stop
-
-and then start minikube again with the following flags:
-
-minikube start --container-runtime=containerd --docker-opt containerd=/var/run/containerd/containerd.sock`
+minikube start --container-runtime=containerd`
 
 // volumesnapshotsDisabledMsg is the message shown when csi-hostpath-driver addon is enabled without the volumesnapshots addon
 const volumesnapshotsDisabledMsg = `[WARNING] For full functionality, the 'csi-hostpath-driver' addon requires the 'volumesnapshots' addon to be enabled.
@@ -44,15 +40,10 @@ const volumesnapshotsDisabledMsg = `[WARNING] For full functionality, the 'csi-h
 You can enable 'volumesnapshots' addon by running: 'minikube addons enable volumesnapshots'
 `
 
-// IsRuntimeContainerd is a validator which returns an error if the current runtime is not containerd
-func IsRuntimeContainerd(cc *config.ClusterConfig, _, _ string) error {
-	r, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime})
-	if err != nil {
-		return err
-	}
-	_, ok := r.(*cruntime.Containerd)
-	if !ok {
-		return fmt.Errorf(containerdOnlyAddonMsg)
+// SupportsRuntimeHandlers is a validator which returns an error if the current runtime can't register runtime handlers
+func SupportsRuntimeHandlers(cc *config.ClusterConfig, _, _ string) error {
+	if !cruntime.SupportsRuntimeHandlers(cc.KubernetesConfig.ContainerRuntime) {
+		return fmt.Errorf(runtimeHandlerAddonMsg)
 	}
 	return nil
 }
@@ -16,7 +16,11 @@ limitations under the License.
 
 package addons
 
-import "testing"
+import (
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+)
 
 func TestIsAddonValid(t *testing.T) {
 	tests := []struct {
@@ -76,3 +80,12 @@ func TestContains(t *testing.T) {
 		})
 	}
 }
+
+func TestSupportsRuntimeHandlers(t *testing.T) {
+	for runtime, supported := range map[string]bool{"docker": false, "containerd": true, "crio": true} {
+		cc := &config.ClusterConfig{KubernetesConfig: config.KubernetesConfig{ContainerRuntime: runtime}}
+		if err := SupportsRuntimeHandlers(cc, "gvisor", "true"); (err == nil) != supported {
+			t.Errorf("SupportsRuntimeHandlers(%s) error = %v, want supported %v", runtime, err, supported)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kic
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+)
+
+// extraDiskImage returns the path of the sparse file backing an extra disk, inside the node
+func extraDiskImage(index int) string {
+	return path.Join(oci.ExtraDiskMountPath(index), "disk.img")
+}
+
+// attachExtraDisks exposes the extra disks to the node as raw loop devices.
+// Loop devices don't survive a restart of the container, so this is done on every start.
+func (d *Driver) attachExtraDisks() error {
+	for i := 1; i <= d.NodeConfig.ExtraDisks; i++ {
+		img := extraDiskImage(i)
+		script := fmt.Sprintf("test -f %[1]s || truncate -s %[2]dM %[1]s; losetup -j %[1]s | grep -q . || losetup --find --show %[1]s", img, d.NodeConfig.ExtraDiskSize)
+		rr, err := d.exec.RunCmd(exec.Command("sudo", "/bin/bash", "-c", script))
+		if err != nil {
+			return errors.Wrapf(err, "attaching extra disk %d", i)
+		}
+		klog.Infof("attached extra disk %d of %s: %s", i, d.MachineName, strings.TrimSpace(rr.Stdout.String()))
+	}
+	return nil
+}
+
+// detachExtraDisks releases the loop devices of the extra disks, which would otherwise outlive the container
+func (d *Driver) detachExtraDisks() {
+	for i := 1; i <= d.NodeConfig.ExtraDisks; i++ {
+		script := fmt.Sprintf("losetup -j %s | cut -d: -f1 | xargs -r losetup -d", extraDiskImage(i))
+		if _, err := d.exec.RunCmd(exec.Command("sudo", "/bin/bash", "-c", script)); err != nil {
+			klog.Warningf("unable to detach extra disk %d of %s: %v", i, d.MachineName, err)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kic
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+func attachCmd(img string, sizeMB int) string {
+	script := fmt.Sprintf("test -f %[1]s || truncate -s %[2]dM %[1]s; losetup -j %[1]s | grep -q . || losetup --find --show %[1]s", img, sizeMB)
+	return command.RunResult{Args: exec.Command("sudo", "/bin/bash", "-c", script).Args}.Command()
+}
+
+func TestExtraDiskImage(t *testing.T) {
+	tests := []struct {
+		index int
+		want  string
+	}{
+		{1, "/extra-disks/disk1/disk.img"},
+		{2, "/extra-disks/disk2/disk.img"},
+		{10, "/extra-disks/disk10/disk.img"},
+	}
+	for _, tc := range tests {
+		if got := extraDiskImage(tc.index); got != tc.want {
+			t.Errorf("extraDiskImage(%d) = %q, want %q", tc.index, got, tc.want)
+		}
+	}
+}
+
+func TestAttachExtraDisks(t *testing.T) {
+	tests := []struct {
+		name    string
+		count   int
+		sizeMB  int
+		cmds    map[string]string
+		wantErr string
+	}{
+		{"no disks", 0, 20000, nil, ""},
+		{"one disk", 1, 20000, map[string]string{
+			attachCmd("/extra-disks/disk1/disk.img", 20000): "/dev/loop0\n",
+		}, ""},
+		{"two disks", 2, 512, map[string]string{
+			attachCmd("/extra-disks/disk1/disk.img", 512): "/dev/loop0\n",
+			attachCmd("/extra-disks/disk2/disk.img", 512): "/dev/loop1\n",
+		}, ""},
+		{"missing disk", 2, 512, map[string]string{
+			attachCmd("/extra-disks/disk1/disk.img", 512): "/dev/loop0\n",
+		}, "attaching extra disk 2"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			runner := command.NewFakeCommandRunner()
+			runner.SetCommandToOutput(tc.cmds)
+			d := &Driver{BaseDriver: &drivers.BaseDriver{MachineName: "minikube"}, exec: runner, NodeConfig: Config{MachineName: "minikube", ExtraDisks: tc.count, ExtraDiskSize: tc.sizeMB}}
+
+			err := d.attachExtraDisks()
+			if tc.wantErr == "" && err != nil {
+				t.Errorf("attachExtraDisks: %v", err)
+			}
+			if tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)) {
+				t.Errorf("attachExtraDisks error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
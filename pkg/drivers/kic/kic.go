@@ -70,13 +70,30 @@ func NewDriver(c Config) *Driver {
 	return d
 }
 
+// baseImage returns the image to create the node from. Custom base images pinned to a digest are verified against it first.
+func (d *Driver) baseImage() (string, error) {
+	img := d.NodeConfig.ImageDigest
+	if !strings.HasSuffix(img, "@sha256:"+baseImageSHA) {
+		return oci.VerifyImage(d.OCIBinary, img)
+	}
+	if d.OCIBinary == oci.Podman {
+		// podman does not support docker images references with both a tag and digest.
+		return strings.Split(img, "@")[0], nil
+	}
+	return img, nil
+}
+
 // Create a host using the driver's config
 func (d *Driver) Create() error {
 	ctx := context.Background()
+	img, err := d.baseImage()
+	if err != nil {
+		return errors.Wrap(err, "verifying base image")
+	}
 	params := oci.CreateParams{
 		Mounts:        d.NodeConfig.Mounts,
 		Name:          d.NodeConfig.MachineName,
-		Image:         d.NodeConfig.ImageDigest,
+		Image:         img,
 		ClusterLabel:  oci.ProfileLabelKey + "=" + d.MachineName,
 		NodeLabel:     oci.NodeLabelKey + "=" + d.NodeConfig.MachineName,
 		CPUs:          strconv.Itoa(d.NodeConfig.CPU),
@@ -85,21 +102,36 @@ func (d *Driver) Create() error {
 		ExtraArgs:     append([]string{"--expose", fmt.Sprintf("%d", d.NodeConfig.APIServerPort)}, d.NodeConfig.ExtraArgs...),
 		OCIBinary:     d.NodeConfig.OCIBinary,
 		APIServerPort: d.NodeConfig.APIServerPort,
+		ExtraDisks:    d.NodeConfig.ExtraDisks,
 	}
 
 	networkName := d.NodeConfig.Network
 	if networkName == "" {
 		networkName = d.NodeConfig.ClusterName
 	}
-	if gateway, err := oci.CreateNetwork(d.OCIBinary, networkName); err != nil {
+	if gateway, err := oci.CreateNetwork(d.OCIBinary, networkName, d.NodeConfig.Subnet); err != nil {
+		if d.NodeConfig.Subnet != "" || d.NodeConfig.StaticIP != "" {
+			return errors.Wrapf(err, "creating network %s", networkName)
+		}
 		out.WarningT("Unable to create dedicated network, this might result in cluster IP change after restart: {{.error}}", out.V{"error": err})
 	} else if gateway != nil {
 		params.Network = networkName
-		ip := gateway.To4()
-		// calculate the container IP based on guessing the machine index
-		ip[3] += byte(driver.IndexFromMachineName(d.NodeConfig.MachineName))
-		klog.Infof("calculated static IP %q for the %q container", ip.String(), d.NodeConfig.MachineName)
-		params.IP = ip.String()
+		if d.NodeConfig.StaticIP != "" {
+			params.IP = d.NodeConfig.StaticIP
+		} else {
+			ip := gateway.To4()
+			// calculate the container IP based on guessing the machine index
+			ip[3] += byte(driver.IndexFromMachineName(d.NodeConfig.MachineName))
+			for _, r := range d.NodeConfig.ReservedIPs {
+				if r == ip.String() {
+					return fmt.Errorf("the IP %s calculated for %q is the static IP of another node, add the node with --static-ip", r, d.NodeConfig.MachineName)
+				}
+			}
+			klog.Infof("calculated static IP %q for the %q container", ip.String(), d.NodeConfig.MachineName)
+			params.IP = ip.String()
+		}
+	} else if d.NodeConfig.StaticIP != "" {
+		return fmt.Errorf("a static IP requires a dedicated network, not the default %s network", networkName)
 	}
 	drv := d.DriverName()
 	listAddr := oci.DefaultBindIPV4
@@ -108,6 +140,11 @@ func (d *Driver) Create() error {
 			out.V{"host": oci.DaemonHost(drv)})
 		listAddr = "0.0.0.0"
 	}
+	if oci.IsRootless(d.OCIBinary) {
+		if ports := oci.PrivilegedPorts(publishedPorts(d.NodeConfig.ExtraArgs)); len(ports) > 0 {
+			out.WarningT("Rootless {{.driver}} can not publish the ports {{.ports}} unless net.ipv4.ip_unprivileged_port_start is lowered on the host", out.V{"driver": drv, "ports": ports})
+		}
+	}
 
 	// control plane specific options
 	params.PortMappings = append(params.PortMappings,
@@ -163,12 +200,13 @@ func (d *Driver) Create() error {
 		t := time.Now()
 		klog.Infof("Starting extracting preloaded images to volume ...")
 		// Extract preloaded images to container
-		if err := oci.ExtractTarballToVolume(d.NodeConfig.OCIBinary, download.TarballPath(d.NodeConfig.KubernetesVersion, d.NodeConfig.ContainerRuntime), params.Name, d.NodeConfig.ImageDigest); err != nil {
+		if err := oci.ExtractTarballToVolume(d.NodeConfig.OCIBinary, download.TarballPath(d.NodeConfig.KubernetesVersion, d.NodeConfig.ContainerRuntime), params.Name, params.Image); err != nil {
 			if strings.Contains(err.Error(), "No space left on device") {
 				pErr = oci.ErrInsufficientDockerStorage
 				return
 			}
 			klog.Infof("Unable to extract preloaded tarball to volume: %v", err)
+			download.VerifyFailedPreload(d.NodeConfig.KubernetesVersion, d.NodeConfig.ContainerRuntime)
 		} else {
 			klog.Infof("duration metric: took %f seconds to extract preloaded images to volume", time.Since(t).Seconds())
 		}
@@ -185,6 +223,10 @@ func (d *Driver) Create() error {
 		return errors.Wrap(err, "prepare kic ssh")
 	}
 
+	if err := d.attachExtraDisks(); err != nil {
+		return errors.Wrap(err, "attach extra disks")
+	}
+
 	waitForPreload.Wait()
 	return nil
 }
@@ -253,8 +295,9 @@ func (d *Driver) prepareSSH() error {
 
 // DriverName returns the name of the driver
 func (d *Driver) DriverName() string {
-	if d.NodeConfig.OCIBinary == oci.Podman {
-		return oci.Podman
+	switch d.NodeConfig.OCIBinary {
+	case oci.Podman, oci.Nerdctl:
+		return d.NodeConfig.OCIBinary
 	}
 	return oci.Docker
 }
@@ -339,6 +382,18 @@ func (d *Driver) Remove() error {
 		klog.Infof("could not find the container %s to remove it. will try anyways", d.MachineName)
 	}
 
+	if d.NodeConfig.ExtraDisks > 0 {
+		if s, err := d.GetState(); err == nil && s == state.Running {
+			d.exec = command.NewKICRunner(d.MachineName, d.OCIBinary)
+			d.detachExtraDisks()
+		}
+		defer func() {
+			if err := oci.RemoveExtraDiskVolumes(d.OCIBinary, d.MachineName, d.NodeConfig.ExtraDisks); err != nil {
+				klog.Warningf("failed to remove the extra disks of %s: %v", d.MachineName, err)
+			}
+		}()
+	}
+
 	if err := oci.DeleteContainer(context.Background(), d.NodeConfig.OCIBinary, d.MachineName); err != nil {
 		if strings.Contains(err.Error(), "is already in progress") {
 			return errors.Wrap(err, "stuck delete")
@@ -409,7 +464,10 @@ func (d *Driver) Start() error {
 
 		return errors.Wrapf(oci.ErrExitedUnexpectedly, "container name %q: log: %s", d.MachineName, excerpt)
 	}
-	return nil
+
+	// on init this doesn't get filled when called from cmd
+	d.exec = command.NewKICRunner(d.MachineName, d.OCIBinary)
+	return d.attachExtraDisks()
 }
 
 // Stop a host gracefully, including any containers that we are managing.
@@ -450,6 +508,8 @@ func (d *Driver) Stop() error {
 		klog.Warningf("couldn't stop kube-apiserver proc: %v", err)
 	}
 
+	d.detachExtraDisks()
+
 	cmd := exec.Command(d.NodeConfig.OCIBinary, "stop", d.MachineName)
 	if err := cmd.Run(); err != nil {
 		return errors.Wrapf(err, "stopping %s", d.MachineName)
@@ -478,3 +538,14 @@ func killAPIServerProc(runner command.Runner) error {
 	}
 	return nil
 }
+
+// publishedPorts returns the port specs passed with -p in the extra args of a container
+func publishedPorts(args []string) []string {
+	specs := []string{}
+	for i := 0; i < len(args)-1; i++ {
+		if args[i] == "-p" || args[i] == "--publish" {
+			specs = append(specs, args[i+1])
+		}
+	}
+	return specs
+}
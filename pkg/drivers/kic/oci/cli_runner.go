@@ -72,7 +72,9 @@ func (rr RunResult) Output() string {
 
 // PrefixCmd adds any needed prefix (such as sudo) to the command
 func PrefixCmd(cmd *exec.Cmd) *exec.Cmd {
-	if cmd.Args[0] == Podman && runtime.GOOS == "linux" { // want sudo when not running podman-remote
+	// want sudo when not running podman-remote, unless podman is meant to run rootless
+	// nerdctl talks to the containerd socket, which is only accessible by root unless containerd is rootless
+	if (cmd.Args[0] == Podman || cmd.Args[0] == Nerdctl) && runtime.GOOS == "linux" && !IsRootlessForced() {
 		cmdWithSudo := exec.Command("sudo", append([]string{"-n"}, cmd.Args...)...)
 		cmdWithSudo.Env = cmd.Env
 		cmdWithSudo.Dir = cmd.Dir
@@ -44,6 +44,16 @@ func init() {
 	if v, set := os.LookupEnv(exEnv); set {
 		initialEnvs[exEnv] = v
 	}
+	// containerd
+	for _, env := range constants.ContainerdEnvs {
+		if v, set := os.LookupEnv(env); set {
+			initialEnvs[env] = v
+		}
+		exEnv := constants.MinikubeExistingPrefix + env
+		if v, set := os.LookupEnv(exEnv); set {
+			initialEnvs[exEnv] = v
+		}
+	}
 }
 
 // InitialEnv returns the value of the environment variable env before any environment changes made by minikube
@@ -57,6 +57,9 @@ var ErrInsufficientDockerStorage = &FailFastError{errors.New("insufficient docke
 // ErrVolumeNotFound is when given volume was not found
 var ErrVolumeNotFound = errors.New("kic volume not found")
 
+// ErrImageDigestMismatch is thrown when the local copy of an image doesn't match the digest it is pinned to
+var ErrImageDigestMismatch = &FailFastError{errors.New("image does not match its pinned digest")}
+
 // ErrNetworkSubnetTaken is thrown when a subnet is taken by another network
 var ErrNetworkSubnetTaken = errors.New("subnet is taken")
 
@@ -84,12 +87,12 @@ func LogContainerDebug(ociBin string, name string) string {
 	} else {
 		klog.Infof("Postmortem logs (%q): %s", rr.Command(), rr.Output())
 	}
-	if ociBin == Docker {
-		di, err := dockerSystemInfo()
+	if ociBin == Docker || ociBin == Nerdctl {
+		di, err := dockerSystemInfo(ociBin)
 		if err != nil {
-			klog.Warningf("Failed to get postmortem docker info: %v", err)
+			klog.Warningf("Failed to get postmortem %s info: %v", ociBin, err)
 		} else {
-			klog.Infof("postmortem docker info: %+v", di)
+			klog.Infof("postmortem %s info: %+v", ociBin, di)
 		}
 		logDockerNetworkInspect(ociBin, name)
 	} else {
@@ -119,7 +122,7 @@ func containerLogs(ociBin string, name string) (*RunResult, error) {
 	if ociBin == Docker {
 		return runCmd(exec.Command(ociBin, "logs", "--timestamps", "--details", name))
 	}
-	// podman doesn't have --details
+	// podman and nerdctl don't have --details
 	return runCmd(exec.Command(ociBin, "logs", "--timestamps", name))
 }
 
@@ -0,0 +1,171 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+const digestPrefix = "sha256:"
+
+// imageInfo is the part of an image inspection needed to verify its digest
+type imageInfo struct {
+	ID          string
+	RepoDigests []string
+}
+
+// splitDigest splits a reference like name:tag@sha256:abc into name:tag and abc
+func splitDigest(img string) (string, string) {
+	i := strings.Index(img, "@"+digestPrefix)
+	if i < 0 {
+		return img, ""
+	}
+	return img[:i], img[i+len("@"+digestPrefix):]
+}
+
+// hasTag returns true if the image name includes a tag, a port in the registry host is not a tag
+func hasTag(name string) bool {
+	return strings.Contains(name[strings.LastIndex(name, "/")+1:], ":")
+}
+
+// imageInspect returns the ID and repository digests of a local image
+func imageInspect(ociBin string, ref string) (imageInfo, error) {
+	rr, err := runCmd(exec.Command(ociBin, "image", "inspect", "--format", "{{.Id}} {{range .RepoDigests}}{{.}} {{end}}", ref))
+	if err != nil {
+		return imageInfo{}, err
+	}
+	fields := strings.Fields(rr.Stdout.String())
+	if len(fields) == 0 {
+		return imageInfo{}, fmt.Errorf("no image ID for %s", ref)
+	}
+	return imageInfo{ID: strings.TrimPrefix(fields[0], digestPrefix), RepoDigests: fields[1:]}, nil
+}
+
+// ImageID returns the ID of a local image, which is the digest a locally built image can be pinned to
+func ImageID(ociBin string, ref string) (string, error) {
+	info, err := imageInspect(ociBin, ref)
+	if err != nil {
+		return "", errors.Wrapf(err, "inspecting image %s", ref)
+	}
+	return digestPrefix + info.ID, nil
+}
+
+// ImageExistsLocally returns whether img is in the local docker or podman. Images pinned to a digest must match it,
+// by their repository digest if pulled from a registry, or by their image ID if built locally, which have no repository digest.
+func ImageExistsLocally(ociBin string, img string) bool {
+	name, digest := splitDigest(img)
+	refs := []string{img}
+	if digest != "" && hasTag(name) {
+		refs = append(refs, name)
+	}
+	for _, ref := range refs {
+		info, err := imageInspect(ociBin, ref)
+		if err != nil {
+			continue
+		}
+		if digest == "" || info.ID == digest {
+			return true
+		}
+		for _, rd := range info.RepoDigests {
+			if strings.HasSuffix(rd, "@"+digestPrefix+digest) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// BuildImage builds the image tag from the Dockerfile in dir, streaming the build output to w
+func BuildImage(ociBin string, dir string, tag string, buildArgs []string, w io.Writer) error {
+	args := []string{"build", "-t", tag}
+	for _, a := range buildArgs {
+		args = append(args, "--build-arg", a)
+	}
+	cmd := exec.Command(ociBin, append(args, dir)...)
+	cmd.Stdout = w
+	cmd.Stderr = w
+	if _, err := runCmd(cmd); err != nil {
+		return errors.Wrapf(err, "building %s", tag)
+	}
+	return nil
+}
+
+// VerifyImage checks that the local copy of img matches the digest img is pinned to, and returns the reference to create containers from:
+// the ID of the verified local image, so that retagging the image after the check doesn't change what the container runs,
+// or img itself when the runtime pulls it by digest. Images pulled from a registry are matched against their repository digests,
+// images built locally against their image ID.
+func VerifyImage(ociBin string, img string) (string, error) {
+	name, digest := splitDigest(img)
+	if digest == "" {
+		return img, nil
+	}
+	if ociBin != Docker && ociBin != Podman {
+		out.WarningT("Skipping the verification of {{.image}} against its digest, which {{.ocibin}} doesn't support", out.V{"image": img, "ocibin": ociBin})
+		return img, nil
+	}
+
+	if ociBin == Docker {
+		// docker looks up references with both a tag and digest by the digest alone
+		if info, err := imageInspect(ociBin, img); err == nil {
+			klog.Infof("found %s in the local daemon by its digest", img)
+			return digestPrefix + info.ID, nil
+		}
+	}
+
+	inspectRef := img
+	if hasTag(name) {
+		// podman does not support docker images references with both a tag and digest.
+		inspectRef = name
+	}
+	info, err := imageInspect(ociBin, inspectRef)
+	if err != nil && ociBin == Podman {
+		klog.Infof("%s not found locally, pulling it to verify its digest: %v", inspectRef, err)
+		if _, err := runCmd(exec.Command(ociBin, "pull", inspectRef)); err != nil {
+			return "", errors.Wrapf(err, "pulling %s", inspectRef)
+		}
+		info, err = imageInspect(ociBin, inspectRef)
+	}
+	if err != nil {
+		// docker verifies the digest itself when it pulls the image
+		klog.Infof("%s not found locally, it will be pulled by digest: %v", inspectRef, err)
+		return img, nil
+	}
+
+	if info.ID == digest {
+		klog.Infof("%s matches the ID of the locally built %s", img, inspectRef)
+		return digestPrefix + info.ID, nil
+	}
+	for _, rd := range info.RepoDigests {
+		if strings.HasSuffix(rd, "@"+digestPrefix+digest) {
+			klog.Infof("%s matches the repository digest %s of image %s%s", img, rd, digestPrefix, info.ID)
+			return digestPrefix + info.ID, nil
+		}
+	}
+	if ociBin == Docker && len(info.RepoDigests) == 0 {
+		// images loaded from a tarball have no repository digest, docker pulls the pinned one
+		klog.Infof("%s has no repository digest, it will be pulled by digest", inspectRef)
+		return img, nil
+	}
+	return "", errors.Wrapf(ErrImageDigestMismatch, "local image %s (ID %s%s, digests %v) is not %s%s", inspectRef, digestPrefix, info.ID, info.RepoDigests, digestPrefix, digest)
+}
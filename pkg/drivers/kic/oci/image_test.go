@@ -0,0 +1,133 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSplitDigest(t *testing.T) {
+	testCases := []struct {
+		Image      string
+		WantName   string
+		WantDigest string
+		WantTag    bool
+	}{
+		{"local/kicbase:v0.0.17@sha256:abc", "local/kicbase:v0.0.17", "abc", true},
+		{"gcr.io/k8s-minikube/kicbase@sha256:abc", "gcr.io/k8s-minikube/kicbase", "abc", false},
+		{"localhost:5000/kicbase:dev", "localhost:5000/kicbase:dev", "", true},
+		{"localhost:5000/kicbase@sha256:abc", "localhost:5000/kicbase", "abc", false},
+		{"kicbase", "kicbase", "", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Image, func(t *testing.T) {
+			name, digest := splitDigest(tc.Image)
+			if name != tc.WantName || digest != tc.WantDigest {
+				t.Errorf("splitDigest(%q) = %q, %q, want %q, %q", tc.Image, name, digest, tc.WantName, tc.WantDigest)
+			}
+			if got := hasTag(name); got != tc.WantTag {
+				t.Errorf("hasTag(%q) = %v, want %v", name, got, tc.WantTag)
+			}
+		})
+	}
+}
+
+func TestVerifyImageUnpinned(t *testing.T) {
+	for _, img := range []string{"local/kicbase:dev", "kicbase"} {
+		got, err := VerifyImage(Docker, img)
+		if err != nil || got != img {
+			t.Errorf("VerifyImage(%q) = %q, %v, want it unchanged", img, got, err)
+		}
+	}
+}
+
+// fakeDocker writes a fake docker to dir, knowing a locally built image, which has no repository digest, and a pulled one
+func fakeDocker(t *testing.T, dir string) string {
+	bin := filepath.Join(dir, "docker")
+	script := `#!/bin/sh
+case "$5" in
+local/kicbase:dev) echo "sha256:abc " ;;
+gcr.io/k8s-minikube/kicbase:v0.0.17) echo "sha256:def gcr.io/k8s-minikube/kicbase@sha256:123 " ;;
+*) exit 1 ;;
+esac
+`
+	if err := ioutil.WriteFile(bin, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return bin
+}
+
+func TestVerifyImage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "oci")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	// VerifyImage compares the name of the binary to tell docker and podman apart
+	defer func(p string) { os.Setenv("PATH", p) }(os.Getenv("PATH"))
+	os.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	fakeDocker(t, dir)
+
+	testCases := []struct {
+		Image   string
+		Want    string
+		WantErr bool
+	}{
+		// the verified image is run by its ID, which retagging it afterwards doesn't change
+		{Image: "local/kicbase:dev@sha256:abc", Want: "sha256:abc"},
+		{Image: "gcr.io/k8s-minikube/kicbase:v0.0.17@sha256:123", Want: "sha256:def"},
+		{Image: "gcr.io/k8s-minikube/kicbase:v0.0.17@sha256:456", WantErr: true},
+		// docker verifies the digest itself when it pulls the image
+		{Image: "local/missing:dev@sha256:abc", Want: "local/missing:dev@sha256:abc"},
+		{Image: "local/kicbase:dev@sha256:bad", Want: "local/kicbase:dev@sha256:bad"},
+	}
+	for _, tc := range testCases {
+		got, err := VerifyImage(Docker, tc.Image)
+		if (err != nil) != tc.WantErr || got != tc.Want {
+			t.Errorf("VerifyImage(%q) = %q, %v, want %q, error: %v", tc.Image, got, err, tc.Want, tc.WantErr)
+		}
+	}
+}
+
+func TestImageExistsLocally(t *testing.T) {
+	dir, err := ioutil.TempDir("", "oci")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	bin := fakeDocker(t, dir)
+
+	testCases := []struct {
+		Image string
+		Want  bool
+	}{
+		{"local/kicbase:dev", true},
+		{"local/kicbase:dev@sha256:abc", true},
+		{"local/kicbase:dev@sha256:bad", false},
+		{"gcr.io/k8s-minikube/kicbase:v0.0.17@sha256:123", true},
+		{"gcr.io/k8s-minikube/kicbase:v0.0.17@sha256:456", false},
+		{"local/missing:dev", false},
+	}
+	for _, tc := range testCases {
+		if got := ImageExistsLocally(bin, tc.Image); got != tc.Want {
+			t.Errorf("ImageExistsLocally(%q) = %v, want %v", tc.Image, got, tc.Want)
+		}
+	}
+}
@@ -34,6 +34,8 @@ type SysInfo struct {
 	Swarm         bool     // Weather or not the docker swarm is active
 	StorageDriver string   // the storage driver for the daemon  (for example overlay2)
 	Errors        []string // any server issues
+	Rootless      bool     // whether the daemon runs as an unprivileged user
+	CgroupVersion string   // the cgroup version of the daemon host ("1" or "2"), empty if unknown
 }
 
 var (
@@ -58,11 +60,11 @@ func CachedDaemonInfo(ociBin string) (SysInfo, error) {
 func DaemonInfo(ociBin string) (SysInfo, error) {
 	if ociBin == Podman {
 		p, err := podmanSystemInfo()
-		cachedSysInfo = &SysInfo{CPUs: p.Host.Cpus, TotalMemory: p.Host.MemTotal, OSType: p.Host.Os, Swarm: false, StorageDriver: p.Store.GraphDriverName}
+		cachedSysInfo = &SysInfo{CPUs: p.Host.Cpus, TotalMemory: p.Host.MemTotal, OSType: p.Host.Os, Swarm: false, StorageDriver: p.Store.GraphDriverName, Rootless: p.Host.Rootless, CgroupVersion: strings.TrimPrefix(p.Host.CgroupVersion, "v")}
 		return *cachedSysInfo, err
 	}
-	d, err := dockerSystemInfo()
-	cachedSysInfo = &SysInfo{CPUs: d.NCPU, TotalMemory: d.MemTotal, OSType: d.OSType, Swarm: d.Swarm.LocalNodeState == "active", StorageDriver: d.Driver, Errors: d.ServerErrors}
+	d, err := dockerSystemInfo(ociBin)
+	cachedSysInfo = &SysInfo{CPUs: d.NCPU, TotalMemory: d.MemTotal, OSType: d.OSType, Swarm: d.Swarm.LocalNodeState == "active", StorageDriver: d.Driver, Errors: d.ServerErrors, Rootless: d.rootless(), CgroupVersion: d.CgroupVersion}
 	return *cachedSysInfo, err
 }
 
@@ -102,6 +104,7 @@ type dockerSysInfo struct {
 	SystemTime         time.Time `json:"SystemTime"`
 	LoggingDriver      string    `json:"LoggingDriver"`
 	CgroupDriver       string    `json:"CgroupDriver"`
+	CgroupVersion      string    `json:"CgroupVersion"`
 	NEventsListener    int       `json:"NEventsListener"`
 	KernelVersion      string    `json:"KernelVersion"`
 	OperatingSystem    string    `json:"OperatingSystem"`
@@ -175,6 +178,16 @@ type dockerSysInfo struct {
 	} `json:"ClientInfo"`
 }
 
+// rootless returns whether the docker daemon runs in rootless mode
+func (d dockerSysInfo) rootless() bool {
+	for _, o := range d.SecurityOptions {
+		if o == "name=rootless" {
+			return true
+		}
+	}
+	return false
+}
+
 // podmanSysInfo represents the output of podman system info --format '{{json .}}'
 type podmanSysInfo struct {
 	Host struct {
@@ -239,18 +252,27 @@ var dockerInfoGetter = func() (string, error) {
 	return rr.Stdout.String(), err
 }
 
-// dockerSystemInfo returns docker system info --format '{{json .}}'
-func dockerSystemInfo() (dockerSysInfo, error) {
+var nerdctlInfoGetter = func() (string, error) {
+	rr, err := runCmd(exec.Command(Nerdctl, "system", "info", "--format", "{{json .}}"))
+	return rr.Stdout.String(), err
+}
+
+// dockerSystemInfo returns docker system info --format '{{json .}}', which nerdctl emulates
+func dockerSystemInfo(ociBin string) (dockerSysInfo, error) {
 	var ds dockerSysInfo
-	rawJSON, err := dockerInfoGetter()
+	getter := dockerInfoGetter
+	if ociBin == Nerdctl {
+		getter = nerdctlInfoGetter
+	}
+	rawJSON, err := getter()
 	if err != nil {
-		return ds, errors.Wrap(err, "docker system info")
+		return ds, errors.Wrapf(err, "%s system info", ociBin)
 	}
 	if err := json.Unmarshal([]byte(strings.TrimSpace(rawJSON)), &ds); err != nil {
-		return ds, errors.Wrapf(err, "unmarshal docker system info")
+		return ds, errors.Wrapf(err, "unmarshal %s system info", ociBin)
 	}
 
-	klog.Infof("docker info: %+v", ds)
+	klog.Infof("%s info: %+v", ociBin, ds)
 	return ds, nil
 }
 
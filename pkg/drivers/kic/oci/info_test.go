@@ -36,6 +36,8 @@ func TestDockerSystemInfo(t *testing.T) {
 		OS            string
 		Swarm         bool
 		StorageDriver string
+		Rootless      bool
+		CgroupVersion string
 	}{
 		{
 			Name:          "linux_docker",
@@ -138,6 +140,7 @@ func TestDockerSystemInfo(t *testing.T) {
 			OS:            "linux",
 			Swarm:         false,
 			StorageDriver: "overlay",
+			CgroupVersion: "1",
 		},
 		{
 			Name:          "mac_swarm_enabled",
@@ -150,6 +153,17 @@ func TestDockerSystemInfo(t *testing.T) {
 			Swarm:         true,
 			StorageDriver: "overlay2",
 		},
+		{
+			Name:          "linux_rootless_docker",
+			OciBin:        "docker",
+			RawJSON:       `{"ID":"QJ5V:2RMA:DXGX:6UHR:DBKM:ZI7X:ZCTO:FHHZ:NGGI:WFFF:IFK2:NAIH","Driver":"overlay2","MemoryLimit":true,"SwapLimit":true,"CpuCfsPeriod":true,"CpuCfsQuota":true,"CgroupDriver":"systemd","CgroupVersion":"2","KernelVersion":"5.10.0-1-amd64","OperatingSystem":"Debian GNU/Linux bullseye/sid","OSType":"linux","Architecture":"x86_64","NCPU":8,"MemTotal":16654336000,"DockerRootDir":"/home/user/.local/share/docker","ServerVersion":"20.10.3","Swarm":{"NodeID":"","NodeAddr":"","LocalNodeState":"inactive","ControlAvailable":false,"Error":"","RemoteManagers":null},"SecurityOptions":["name=seccomp,profile=default","name=rootless","name=cgroupns"],"Warnings":null}`,
+			CPUs:          8,
+			Memory:        16654336000,
+			OS:            "linux",
+			StorageDriver: "overlay2",
+			Rootless:      true,
+			CgroupVersion: "2",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -178,6 +192,12 @@ func TestDockerSystemInfo(t *testing.T) {
 			if s.Swarm != tc.Swarm {
 				t.Errorf("Expected Swarm to be %t but got %t", tc.Swarm, s.Swarm)
 			}
+			if s.Rootless != tc.Rootless {
+				t.Errorf("Expected Rootless to be %t but got %t", tc.Rootless, s.Rootless)
+			}
+			if s.CgroupVersion != tc.CgroupVersion {
+				t.Errorf("Expected CgroupVersion to be %q but got %q", tc.CgroupVersion, s.CgroupVersion)
+			}
 
 		})
 
@@ -33,14 +33,14 @@ import (
 // RoutableHostIPFromInside returns the ip/dns of the host that container lives on
 // is routable from inside the container
 func RoutableHostIPFromInside(ociBin string, clusterName string, containerName string) (net.IP, error) {
-	if ociBin == Docker {
+	if ociBin == Docker || ociBin == Nerdctl {
 		if runtime.GOOS == "linux" {
 			info, err := containerNetworkInspect(ociBin, clusterName)
 			if err != nil {
 				if errors.Is(err, ErrNetworkNotFound) {
 					klog.Infof("The container %s is not attached to a network, this could be because the cluster was created by minikube <v1.14, will try to get the IP using container gatway", containerName)
 
-					return containerGatewayIP(Docker, containerName)
+					return containerGatewayIP(ociBin, containerName)
 				}
 				return info.gateway, errors.Wrap(err, "network inspect")
 			}
@@ -43,8 +43,12 @@ const dockerDefaultBridge = "bridge"
 // name of the default bridge network
 const podmanDefaultBridge = "podman"
 
-// CreateNetwork creates a network returns gateway and error, minikube creates one network per cluster
-func CreateNetwork(ociBin string, networkName string) (net.IP, error) {
+// name of the default bridge network of nerdctl
+const nerdctlDefaultBridge = "bridge"
+
+// CreateNetwork creates a network returns gateway and error, minikube creates one network per cluster.
+// The network uses subnet if it isn't empty, otherwise the first free subnet from 192.168.49.0/24.
+func CreateNetwork(ociBin string, networkName string, subnet string) (net.IP, error) {
 	var defaultBridgeName string
 	if ociBin == Docker {
 		defaultBridgeName = dockerDefaultBridge
@@ -52,6 +56,9 @@ func CreateNetwork(ociBin string, networkName string) (net.IP, error) {
 	if ociBin == Podman {
 		defaultBridgeName = podmanDefaultBridge
 	}
+	if ociBin == Nerdctl {
+		defaultBridgeName = nerdctlDefaultBridge
+	}
 	if networkName == defaultBridgeName {
 		klog.Infof("skipping creating network since default network %s was specified", networkName)
 		return nil, nil
@@ -61,6 +68,9 @@ func CreateNetwork(ociBin string, networkName string) (net.IP, error) {
 	info, err := containerNetworkInspect(ociBin, networkName)
 	if err == nil {
 		klog.Infof("Found existing network %+v", info)
+		if subnet != "" && info.subnet != nil && info.subnet.String() != subnet {
+			return nil, fmt.Errorf("network %s already exists with subnet %s, not %s", networkName, info.subnet, subnet)
+		}
 		return info.gateway, nil
 	}
 
@@ -70,6 +80,16 @@ func CreateNetwork(ociBin string, networkName string) (net.IP, error) {
 	if err != nil {
 		klog.Warningf("failed to get mtu information from the %s's default network %q: %v", ociBin, defaultBridgeName, err)
 	}
+
+	if subnet != "" {
+		_, ipnet, err := net.ParseCIDR(subnet)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing subnet %s", subnet)
+		}
+		mask, _ := ipnet.Mask.Size()
+		return tryCreateDockerNetwork(ociBin, ipnet.IP.String(), mask, info.mtu, networkName)
+	}
+
 	attempts := 0
 	subnetAddr := firstSubnetAddr
 	// Rather than iterate through all of the valid subnets, give up at 20 to avoid a lengthy user delay for something that is unlikely to work.
@@ -124,6 +144,15 @@ func tryCreateDockerNetwork(ociBin string, subnetAddr string, subnetMask int, mt
 
 		args = append(args, fmt.Sprintf("--label=%s=%s", CreatedByLabelKey, "true"))
 	}
+	if ociBin == Nerdctl {
+		// nerdctl only understands the MTU option of the docker bridge driver
+		if mtu > 0 {
+			args = append(args, "-o")
+			args = append(args, fmt.Sprintf("com.docker.network.driver.mtu=%d", mtu))
+		}
+
+		args = append(args, fmt.Sprintf("--label=%s=%s", CreatedByLabelKey, "true"))
+	}
 	args = append(args, name)
 
 	rr, err := runCmd(exec.Command(ociBin, args...))
@@ -158,6 +187,9 @@ func containerNetworkInspect(ociBin string, name string) (netInfo, error) {
 	if ociBin == Podman {
 		return podmanNetworkInspect(name)
 	}
+	if ociBin == Nerdctl {
+		return nerdctlNetworkInspect(name)
+	}
 	return netInfo{}, fmt.Errorf("%s unknown", ociBin)
 }
 
@@ -212,6 +244,40 @@ func dockerNetworkInspect(name string) (netInfo, error) {
 	return info, nil
 }
 
+var nerdctlInspectGetter = func(name string) (*RunResult, error) {
+	// nerdctl networks have neither a driver nor options, and don't list their containers
+	cmd := exec.Command(Nerdctl, "network", "inspect", name, "--format", `{"Name": "{{.Name}}","Subnet": "{{range .IPAM.Config}}{{.Subnet}}{{end}}","Gateway": "{{range .IPAM.Config}}{{.Gateway}}{{end}}"}`)
+	return runCmd(cmd)
+}
+
+// if exists returns subnet and gateway
+func nerdctlNetworkInspect(name string) (netInfo, error) {
+	var vals networkInspect
+	var info = netInfo{name: name}
+
+	rr, err := nerdctlInspectGetter(name)
+	if err != nil {
+		logDockerNetworkInspect(Nerdctl, name)
+		// nerdctl: no such network: "minikube"
+		if strings.Contains(strings.ToLower(rr.Output()), "no such network") {
+			return info, ErrNetworkNotFound
+		}
+		return info, err
+	}
+
+	if err := json.Unmarshal(rr.Stdout.Bytes(), &vals); err != nil {
+		return info, fmt.Errorf("error parsing network inspect output: %q", rr.Stdout.String())
+	}
+
+	info.gateway = net.ParseIP(vals.Gateway)
+	_, info.subnet, err = net.ParseCIDR(vals.Subnet)
+	if err != nil {
+		return info, errors.Wrapf(err, "parse subnet for %s", name)
+	}
+
+	return info, nil
+}
+
 func podmanNetworkInspect(name string) (netInfo, error) {
 	var info = netInfo{name: name}
 	cmd := exec.Command(Podman, "network", "inspect", name, "--format", `{{range .plugins}}{{if eq .type "bridge"}}{{(index (index .ipam.ranges 0) 0).subnet}},{{(index (index .ipam.ranges 0) 0).gateway}}{{end}}{{end}}`)
@@ -82,3 +82,23 @@ func TestDockerInspect(t *testing.T) {
 		})
 	}
 }
+
+func TestNerdctlInspect(t *testing.T) {
+	defer func(g func(string) (*RunResult, error)) { nerdctlInspectGetter = g }(nerdctlInspectGetter)
+	nerdctlInspectGetter = func(name string) (*RunResult, error) {
+		var b bytes.Buffer
+		b.WriteString(`{"Name": "minikube","Subnet": "192.168.49.0/24","Gateway": "192.168.49.1"}`)
+		return &RunResult{Stdout: b}, nil
+	}
+
+	netInfo, err := nerdctlNetworkInspect("minikube")
+	if err != nil {
+		t.Fatalf("Expected not to have error but got %v", err)
+	}
+	if !netInfo.gateway.Equal(net.ParseIP("192.168.49.1")) {
+		t.Errorf("Expected gateway to be 192.168.49.1 but got %v", netInfo.gateway)
+	}
+	if !netInfo.subnet.IP.Equal(net.ParseIP("192.168.49.0")) {
+		t.Errorf("Expected subnet to be 192.168.49.0 but got %v", netInfo.subnet.IP)
+	}
+}
@@ -104,6 +104,11 @@ func PrepareContainerNode(p CreateParams) error {
 		return errors.Wrapf(err, "preparing volume for %s container", p.Name)
 	}
 	klog.Infof("Successfully prepared a %s volume %s", p.OCIBinary, p.Name)
+	for i := 1; i <= p.ExtraDisks; i++ {
+		if err := createVolume(p.OCIBinary, p.Name, ExtraDiskVolume(p.Name, i)); err != nil {
+			return errors.Wrapf(err, "creating volume for extra disk %d of %s container", i, p.Name)
+		}
+	}
 	return nil
 }
 
@@ -152,14 +157,7 @@ func CreateContainerNode(p CreateParams) error {
 		runArgs = append(runArgs, "--ip", p.IP)
 	}
 
-	memcgSwap := true
-	if runtime.GOOS == "linux" {
-		if _, err := os.Stat("/sys/fs/cgroup/memory/memsw.limit_in_bytes"); os.IsNotExist(err) {
-			// requires CONFIG_MEMCG_SWAP_ENABLED or cgroup_enable=memory in grub
-			klog.Warning("Your kernel does not support swap limit capabilities or the cgroup is not mounted.")
-			memcgSwap = false
-		}
-	}
+	limits := supportedLimits(p.OCIBinary)
 
 	// https://www.freedesktop.org/wiki/Software/systemd/ContainerInterface/
 	var virtualization string
@@ -167,7 +165,7 @@ func CreateContainerNode(p CreateParams) error {
 		// podman mounts var/lib with no-exec by default  https://github.com/containers/libpod/issues/5103
 		runArgs = append(runArgs, "--volume", fmt.Sprintf("%s:/var:exec", p.Name))
 
-		if memcgSwap {
+		if limits.memorySwap {
 			runArgs = append(runArgs, fmt.Sprintf("--memory=%s", p.Memory))
 			// Disable swap by setting the value to match
 			runArgs = append(runArgs, fmt.Sprintf("--memory-swap=%s", p.Memory))
@@ -175,34 +173,24 @@ func CreateContainerNode(p CreateParams) error {
 
 		virtualization = "podman" // VIRTUALIZATION_PODMAN
 	}
-	if p.OCIBinary == Docker {
+	if p.OCIBinary == Docker || p.OCIBinary == Nerdctl {
 		runArgs = append(runArgs, "--volume", fmt.Sprintf("%s:/var", p.Name))
 		// ignore apparmore github actions docker: https://github.com/kubernetes/minikube/issues/7624
 		runArgs = append(runArgs, "--security-opt", "apparmor=unconfined")
 
-		runArgs = append(runArgs, fmt.Sprintf("--memory=%s", p.Memory))
-		// Disable swap by setting the value to match
-		runArgs = append(runArgs, fmt.Sprintf("--memory-swap=%s", p.Memory))
+		if limits.memory {
+			runArgs = append(runArgs, fmt.Sprintf("--memory=%s", p.Memory))
+			// Disable swap by setting the value to match
+			runArgs = append(runArgs, fmt.Sprintf("--memory-swap=%s", p.Memory))
+		}
 
+		// systemd knows no better match for containerd than docker
 		virtualization = "docker" // VIRTUALIZATION_DOCKER
 	}
 
-	cpuCfsPeriod := true
-	cpuCfsQuota := true
-	if runtime.GOOS == "linux" {
-		if _, err := os.Stat("/sys/fs/cgroup/cpu/cpu.cfs_period_us"); os.IsNotExist(err) {
-			cpuCfsPeriod = false
-		}
-		if _, err := os.Stat("/sys/fs/cgroup/cpu/cpu.cfs_quota_us"); os.IsNotExist(err) {
-			cpuCfsQuota = false
-		}
-		if !cpuCfsPeriod || !cpuCfsQuota {
-			// requires CONFIG_CFS_BANDWIDTH
-			klog.Warning("Your kernel does not support CPU cfs period/quota or the cgroup is not mounted.")
-		}
-	}
+	runArgs = append(runArgs, extraDiskMounts(p.Name, p.ExtraDisks)...)
 
-	if cpuCfsPeriod && cpuCfsQuota {
+	if limits.cpu {
 		runArgs = append(runArgs, fmt.Sprintf("--cpus=%s", p.CPUs))
 	}
 
@@ -279,7 +267,8 @@ func createContainer(ociBin string, image string, opts ...createOpt) error {
 
 	// to run nested container from privileged container in podman https://bugzilla.redhat.com/show_bug.cgi?id=1687713
 	// only add when running locally (linux), when running remotely it needs to be configured on server in libpod.conf
-	if ociBin == Podman && runtime.GOOS == "linux" {
+	// rootless podman can only use the systemd cgroup manager on cgroup v2 hosts, and has no use for cgroupfs on v1
+	if ociBin == Podman && runtime.GOOS == "linux" && !IsRootlessForced() {
 		args = append(args, "--cgroup-manager", "cgroupfs")
 	}
 
@@ -309,7 +298,8 @@ func StartContainer(ociBin string, container string) error {
 
 	// to run nested container from privileged container in podman https://bugzilla.redhat.com/show_bug.cgi?id=1687713
 	// only add when running locally (linux), when running remotely it needs to be configured on server in libpod.conf
-	if ociBin == Podman && runtime.GOOS == "linux" {
+	// rootless podman can only use the systemd cgroup manager on cgroup v2 hosts, and has no use for cgroupfs on v1
+	if ociBin == Podman && runtime.GOOS == "linux" && !IsRootlessForced() {
 		args = append(args, "--cgroup-manager", "cgroupfs")
 	}
 
@@ -621,6 +611,17 @@ func ShutDown(ociBin string, name string) error {
 	return nil
 }
 
+// UpdateContainerResources changes the CPU and memory limits of an existing container, which takes effect immediately
+func UpdateContainerResources(ociBin string, name string, cpus int, memoryMB int) error {
+	mem := fmt.Sprintf("%dmb", memoryMB)
+	// Disable swap by setting the value to match, as at creation
+	args := []string{"update", fmt.Sprintf("--cpus=%d", cpus), fmt.Sprintf("--memory=%s", mem), fmt.Sprintf("--memory-swap=%s", mem), name}
+	if _, err := runCmd(exec.Command(ociBin, args...)); err != nil {
+		return errors.Wrapf(err, "%s update", ociBin)
+	}
+	return nil
+}
+
 // iptablesFileExists checks if /var/lib/dpkg/alternatives/iptables exists in minikube
 // this file is necessary for the entrypoint script to pass
 // TODO: https://github.com/kubernetes/minikube/issues/8179
@@ -0,0 +1,164 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// cgroupRoot is where the cgroup filesystem of the host is mounted
+var cgroupRoot = "/sys/fs/cgroup"
+
+// IsRootlessForced returns whether podman should be run without sudo, as the current user.
+// Rootless docker needs no such setting, as the docker CLI never uses sudo.
+func IsRootlessForced() bool {
+	v, _ := strconv.ParseBool(os.Getenv(constants.MinikubeRootlessEnv))
+	return v
+}
+
+// IsRootless returns whether the daemon behind ociBin runs containers as an unprivileged user
+func IsRootless(ociBin string) bool {
+	si, err := CachedDaemonInfo(ociBin)
+	if err != nil {
+		klog.Warningf("unable to tell whether %s is rootless: %v", ociBin, err)
+		return false
+	}
+	return si.Rootless
+}
+
+// resourceLimits is which cgroup controllers are usable to limit the resources of a container
+type resourceLimits struct {
+	memory bool
+	// memorySwap is whether the swap usage can be limited along with memory
+	memorySwap bool
+	cpu        bool
+}
+
+// supportedLimits returns which resource limits may be set on containers run by ociBin
+func supportedLimits(ociBin string) resourceLimits {
+	// Docker Desktop and remote podman run containers in a VM we can't inspect
+	if runtime.GOOS != "linux" {
+		return resourceLimits{memory: true, memorySwap: true, cpu: true}
+	}
+
+	si, err := CachedDaemonInfo(ociBin)
+	if err != nil {
+		klog.Warningf("unable to get daemon info, assuming cgroup v1: %v", err)
+	}
+
+	if si.CgroupVersion == "2" {
+		cs, err := cgroupV2Controllers(si.Rootless)
+		if err != nil {
+			klog.Warningf("unable to read the cgroup controllers, not limiting resources: %v", err)
+			return resourceLimits{}
+		}
+		l := resourceLimits{memory: cs["memory"], memorySwap: cs["memory"], cpu: cs["cpu"]}
+		if !l.memory || !l.cpu {
+			klog.Warningf("The memory or cpu cgroup controller is not available (rootless: %v): %v", si.Rootless, cs)
+		}
+		return l
+	}
+
+	if si.Rootless {
+		klog.Warning("Resource limits are not supported by rootless containers on cgroup v1 hosts.")
+		return resourceLimits{}
+	}
+
+	l := resourceLimits{memory: true, memorySwap: true, cpu: true}
+	if _, err := os.Stat(cgroupRoot + "/memory/memsw.limit_in_bytes"); os.IsNotExist(err) {
+		// requires CONFIG_MEMCG_SWAP_ENABLED or cgroup_enable=memory in grub
+		klog.Warning("Your kernel does not support swap limit capabilities or the cgroup is not mounted.")
+		l.memorySwap = false
+	}
+	_, perr := os.Stat(cgroupRoot + "/cpu/cpu.cfs_period_us")
+	_, qerr := os.Stat(cgroupRoot + "/cpu/cpu.cfs_quota_us")
+	if os.IsNotExist(perr) || os.IsNotExist(qerr) {
+		// requires CONFIG_CFS_BANDWIDTH
+		klog.Warning("Your kernel does not support CPU cfs period/quota or the cgroup is not mounted.")
+		l.cpu = false
+	}
+	return l
+}
+
+// cgroupV2Controllers returns the cgroup v2 controllers available to containers.
+// Rootless containers may only use the controllers systemd delegates to the user.
+func cgroupV2Controllers(rootless bool) (map[string]bool, error) {
+	path := cgroupRoot + "/cgroup.controllers"
+	if rootless {
+		uid := os.Getuid()
+		path = fmt.Sprintf("%s/user.slice/user-%d.slice/user@%d.service/cgroup.controllers", cgroupRoot, uid, uid)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cs := map[string]bool{}
+	for _, c := range strings.Fields(string(b)) {
+		cs[c] = true
+	}
+	return cs, nil
+}
+
+// DelegatedControllersMissing returns the cgroup v2 controllers which a rootless ociBin needs but are not delegated to the user
+func DelegatedControllersMissing(ociBin string) []string {
+	si, err := CachedDaemonInfo(ociBin)
+	if err != nil || !si.Rootless || si.CgroupVersion != "2" {
+		return nil
+	}
+	cs, err := cgroupV2Controllers(true)
+	if err != nil {
+		klog.Warningf("unable to read the delegated cgroup controllers: %v", err)
+		return nil
+	}
+	missing := []string{}
+	for _, c := range []string{"cpu", "cpuset", "io", "memory", "pids"} {
+		if !cs[c] {
+			missing = append(missing, c)
+		}
+	}
+	return missing
+}
+
+// PrivilegedPorts returns the host ports below 1024 in a list of port publishing specs (such as "80:80" or "127.0.0.1:443:8443/tcp"),
+// which a rootless daemon can't bind to unless net.ipv4.ip_unprivileged_port_start has been lowered
+func PrivilegedPorts(specs []string) []int {
+	ports := []int{}
+	for _, spec := range specs {
+		parts := strings.Split(strings.Split(spec, "/")[0], ":")
+		if len(parts) < 2 {
+			// only the container port, the host port is chosen by the daemon
+			continue
+		}
+		p, err := strconv.Atoi(parts[len(parts)-2])
+		if err != nil {
+			// empty or a port range
+			continue
+		}
+		if p > 0 && p < 1024 {
+			ports = append(ports, p)
+		}
+	}
+	return ports
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+func TestPrefixCmdRootless(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("podman is only run with sudo on linux")
+	}
+	defer os.Unsetenv(constants.MinikubeRootlessEnv)
+
+	os.Unsetenv(constants.MinikubeRootlessEnv)
+	if got := PrefixCmd(exec.Command(Podman, "ps")).Args[0]; got != "sudo" {
+		t.Errorf("PrefixCmd() ran %q, want sudo", got)
+	}
+
+	os.Setenv(constants.MinikubeRootlessEnv, "true")
+	if got := PrefixCmd(exec.Command(Podman, "ps")).Args[0]; got != Podman {
+		t.Errorf("PrefixCmd() ran %q with rootless forced, want %s", got, Podman)
+	}
+}
+
+func TestCgroupV2Controllers(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "cgroup")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	defer func(root string) { cgroupRoot = root }(cgroupRoot)
+	cgroupRoot = tempDir
+
+	uid := os.Getuid()
+	userDir := filepath.Join(tempDir, "user.slice", fmt.Sprintf("user-%d.slice", uid), fmt.Sprintf("user@%d.service", uid))
+	if err := os.MkdirAll(userDir, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(tempDir, "cgroup.controllers"), []byte("cpuset cpu io memory hugetlb pids rdma\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	// systemd only delegates memory and pids by default
+	if err := ioutil.WriteFile(filepath.Join(userDir, "cgroup.controllers"), []byte("memory pids\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	var tests = []struct {
+		rootless bool
+		want     map[string]bool
+	}{
+		{false, map[string]bool{"cpuset": true, "cpu": true, "io": true, "memory": true, "hugetlb": true, "pids": true, "rdma": true}},
+		{true, map[string]bool{"memory": true, "pids": true}},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("rootless=%v", tc.rootless), func(t *testing.T) {
+			got, err := cgroupV2Controllers(tc.rootless)
+			if err != nil {
+				t.Fatalf("cgroupV2Controllers: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("cgroupV2Controllers() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPrivilegedPorts(t *testing.T) {
+	var tests = []struct {
+		specs []string
+		want  []int
+	}{
+		{nil, []int{}},
+		{[]string{"8080:80", "80", "30000-30010:30000-30010"}, []int{}},
+		{[]string{"80:80", "127.0.0.1:443:8443/tcp", "0.0.0.0:8443:443", "53:53/udp"}, []int{80, 443, 53}},
+		{[]string{"127.0.0.1::80"}, []int{}},
+	}
+	for _, tc := range tests {
+		got := PrivilegedPorts(tc.specs)
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("PrivilegedPorts(%v) mismatch (-want +got):\n%s", tc.specs, diff)
+		}
+	}
+}
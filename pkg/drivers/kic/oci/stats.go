@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	units "github.com/docker/go-units"
+	"github.com/pkg/errors"
+)
+
+// ContainerStats holds the cgroup resource usage of a container
+type ContainerStats struct {
+	CPUPercent  float64 // CPUPercent is the CPU usage, where 100 means one CPU is fully used
+	MemoryUsed  int64   // MemoryUsed is the memory used in bytes
+	MemoryLimit int64   // MemoryLimit is the memory available to the container in bytes
+}
+
+// Stats returns the resource usage of a container, as reported by docker/podman stats
+func Stats(ociBin string, name string) (ContainerStats, error) {
+	// podman and docker both support these fields, although podman reports sizes in SI units
+	rr, err := runCmd(exec.Command(ociBin, "stats", "--no-stream", "--format", "{{.CPUPerc}};{{.MemUsage}}", name))
+	if err != nil {
+		return ContainerStats{}, errors.Wrapf(err, "%s stats", ociBin)
+	}
+	return parseStats(rr.Stdout.String())
+}
+
+// parseStats parses output such as "12.34%;512MiB / 1.944GiB"
+func parseStats(s string) (ContainerStats, error) {
+	var cs ContainerStats
+	fields := strings.Split(strings.TrimSpace(s), ";")
+	if len(fields) != 2 {
+		return cs, fmt.Errorf("unexpected stats output: %q", s)
+	}
+
+	cpu, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(fields[0]), "%"), 64)
+	if err != nil {
+		return cs, errors.Wrap(err, "cpu")
+	}
+	cs.CPUPercent = cpu
+
+	mem := strings.Split(fields[1], "/")
+	if len(mem) != 2 {
+		return cs, fmt.Errorf("unexpected memory usage: %q", fields[1])
+	}
+	if cs.MemoryUsed, err = parseSize(mem[0]); err != nil {
+		return cs, errors.Wrap(err, "memory used")
+	}
+	if cs.MemoryLimit, err = parseSize(mem[1]); err != nil {
+		return cs, errors.Wrap(err, "memory limit")
+	}
+	return cs, nil
+}
+
+// parseSize parses binary sizes such as "1.944GiB" and SI sizes such as "2.1GB"
+func parseSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if strings.Contains(strings.ToLower(s), "i") {
+		return units.RAMInBytes(s)
+	}
+	return units.FromHumanSize(s)
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"testing"
+)
+
+func TestParseStats(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		Output      string
+		ShouldError bool
+		Want        ContainerStats
+	}{
+		{
+			Name:   "docker",
+			Output: "12.50%;512MiB / 2GiB\n",
+			Want:   ContainerStats{CPUPercent: 12.5, MemoryUsed: 512 * 1024 * 1024, MemoryLimit: 2 * 1024 * 1024 * 1024},
+		},
+		{
+			Name:   "podman",
+			Output: "150.00%;500MB / 2GB",
+			Want:   ContainerStats{CPUPercent: 150, MemoryUsed: 500 * 1000 * 1000, MemoryLimit: 2 * 1000 * 1000 * 1000},
+		},
+		{
+			Name:        "not running",
+			Output:      "--;-- / --",
+			ShouldError: true,
+		},
+		{
+			Name:        "empty",
+			Output:      "",
+			ShouldError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			got, err := parseStats(tc.Output)
+			if tc.ShouldError {
+				if err == nil {
+					t.Errorf("parseStats(%q) = %+v, want error", tc.Output, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseStats(%q) error: %v", tc.Output, err)
+			}
+			if got != tc.Want {
+				t.Errorf("parseStats(%q) = %+v, want %+v", tc.Output, got, tc.Want)
+			}
+		})
+	}
+}
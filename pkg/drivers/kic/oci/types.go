@@ -31,6 +31,8 @@ const (
 	Docker = "docker"
 	// Podman is podman
 	Podman = "podman"
+	// Nerdctl is nerdctl, the docker-compatible CLI for containerd
+	Nerdctl = "nerdctl"
 	// ProfileLabelKey is applied to any container or volume created by a specific minikube profile name.minikube.sigs.k8s.io=PROFILE_NAME
 	ProfileLabelKey = "name.minikube.sigs.k8s.io"
 	// NodeLabelKey is applied to each volume so it can be referred to by name
@@ -59,6 +61,7 @@ type CreateParams struct {
 	OCIBinary     string            // docker or podman
 	Network       string            // network name that the container will attach to
 	IP            string            // static IP to assign for th container in the cluster network
+	ExtraDisks    int               // number of extra volumes to mount, each backing a raw disk
 }
 
 // createOpt is an option for Create
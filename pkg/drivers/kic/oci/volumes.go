@@ -151,6 +151,39 @@ func createVolume(ociBin string, profile string, nodeName string) error {
 	return nil
 }
 
+// ExtraDiskVolume returns the name of the volume backing an extra disk of a node
+func ExtraDiskVolume(nodeName string, index int) string {
+	return fmt.Sprintf("%s-disk%d", nodeName, index)
+}
+
+// ExtraDiskMountPath returns where the volume of an extra disk is mounted inside the node
+func ExtraDiskMountPath(index int) string {
+	return fmt.Sprintf("/extra-disks/disk%d", index)
+}
+
+// extraDiskMounts returns the run arguments mounting the volumes of the extra disks into a node
+func extraDiskMounts(nodeName string, count int) []string {
+	var args []string
+	for i := 1; i <= count; i++ {
+		args = append(args, "--volume", fmt.Sprintf("%s:%s", ExtraDiskVolume(nodeName, i), ExtraDiskMountPath(i)))
+	}
+	return args
+}
+
+// RemoveExtraDiskVolumes deletes the volumes of the extra disks of a node
+func RemoveExtraDiskVolumes(ociBin string, nodeName string, count int) error {
+	var errs []string
+	for i := 1; i <= count; i++ {
+		if _, err := runCmd(exec.Command(ociBin, "volume", "rm", "--force", ExtraDiskVolume(nodeName, i))); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("removing extra disk volumes: %s", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
 // prepareVolume will copy the initial content of the mount point by starting a container to check the expected content
 func prepareVolume(ociBin string, imageName string, nodeName string) error {
 	cmdArgs := []string{"run", "--rm", "--entrypoint", "/usr/bin/test"}
@@ -0,0 +1,39 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtraDiskMounts(t *testing.T) {
+	tests := []struct {
+		name  string
+		count int
+		want  []string
+	}{
+		{"minikube", 0, nil},
+		{"minikube", 1, []string{"--volume", "minikube-disk1:/extra-disks/disk1"}},
+		{"p-m02", 2, []string{"--volume", "p-m02-disk1:/extra-disks/disk1", "--volume", "p-m02-disk2:/extra-disks/disk2"}},
+	}
+	for _, tc := range tests {
+		if got := extraDiskMounts(tc.name, tc.count); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("extraDiskMounts(%q, %d) = %q, want %q", tc.name, tc.count, got, tc.want)
+		}
+	}
+}
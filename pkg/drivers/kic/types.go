@@ -63,4 +63,9 @@ type Config struct {
 	ContainerRuntime  string            // container runtime kic is running
 	Network           string            //  network to run with kic
 	ExtraArgs         []string          // a list of any extra option to pass to oci binary during creation time, for example --expose 8080...
+	ExtraDisks        int               // number of extra disks, attached to the node as loop devices
+	ExtraDiskSize     int               // size of each extra disk in MB
+	Subnet            string            // subnet of the network created for the cluster, picked automatically if empty
+	StaticIP          string            // IP of the container, computed from its index in the cluster if empty
+	ReservedIPs       []string          // static IPs of the other nodes of the cluster, which the computed IP must not take
 }
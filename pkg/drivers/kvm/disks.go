@@ -0,0 +1,63 @@
+// +build linux
+
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvm
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/docker/machine/libmachine/log"
+	"github.com/pkg/errors"
+)
+
+// extraDisk is an extra disk attached to the domain
+type extraDisk struct {
+	Path   string
+	Target string
+}
+
+// extraDisks returns the extra disks of the domain, attached after the boot disk as vdb, vdc...
+func (d *Driver) extraDisks() []extraDisk {
+	disks := []extraDisk{}
+	for i := 1; i <= d.ExtraDisks; i++ {
+		disks = append(disks, extraDisk{
+			Path:   d.ResolveStorePath(fmt.Sprintf("%s-%d.qcow2", d.MachineName, i)),
+			Target: fmt.Sprintf("vd%c", 'a'+i),
+		})
+	}
+	return disks
+}
+
+// createExtraDisks creates the qcow2 images of the extra disks, which live in the machine directory
+func (d *Driver) createExtraDisks() error {
+	for _, disk := range d.extraDisks() {
+		if _, err := os.Stat(disk.Path); err == nil {
+			log.Infof("Extra disk %s already exists", disk.Path)
+			continue
+		}
+		log.Infof("Creating extra disk %s", disk.Path)
+		cmd := exec.Command("qemu-img", "create", "-f", "qcow2", disk.Path, fmt.Sprintf("%dM", d.ExtraDiskSize))
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return errors.Wrapf(err, "%s: %s", strings.Join(cmd.Args, " "), out)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+// +build linux
+
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvm
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtraDisks(t *testing.T) {
+	tests := []struct {
+		count   int
+		targets []string
+	}{
+		{0, []string{}},
+		{1, []string{"vdb"}},
+		{3, []string{"vdb", "vdc", "vdd"}},
+		{25, nil},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%d disks", tc.count), func(t *testing.T) {
+			d := NewDriver("minikube", "/store")
+			d.ExtraDisks = tc.count
+
+			disks := d.extraDisks()
+			if len(disks) != tc.count {
+				t.Fatalf("extraDisks() returned %d disks, want %d", len(disks), tc.count)
+			}
+			targets := []string{}
+			for i, disk := range disks {
+				if want := filepath.Join("/store", "machines", "minikube", fmt.Sprintf("minikube-%d.qcow2", i+1)); disk.Path != want {
+					t.Errorf("path of disk %d = %q, want %q", i+1, disk.Path, want)
+				}
+				targets = append(targets, disk.Target)
+			}
+			if tc.targets != nil && !reflect.DeepEqual(targets, tc.targets) {
+				t.Errorf("extraDisks() targets = %v, want %v", targets, tc.targets)
+			}
+			// the boot disk is hda, the extra disks must not go past vdz
+			if tc.count == 25 && targets[24] != "vdz" {
+				t.Errorf("target of the last disk = %q, want vdz", targets[24])
+			}
+		})
+	}
+}
+
+func TestDomainXMLExtraDisks(t *testing.T) {
+	tests := []struct {
+		count int
+		want  []string
+	}{
+		{0, nil},
+		{2, []string{
+			"<source file='/store/machines/minikube/minikube-1.qcow2'/>\n      <target dev='vdb' bus='virtio'/>",
+			"<source file='/store/machines/minikube/minikube-2.qcow2'/>\n      <target dev='vdc' bus='virtio'/>",
+		}},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%d disks", tc.count), func(t *testing.T) {
+			d := NewDriver("minikube", "/store")
+			d.ExtraDisks = tc.count
+			d.DiskPath = "/store/machines/minikube/minikube.rawdisk"
+
+			xml, err := d.domainXML()
+			if err != nil {
+				t.Fatalf("domainXML: %v", err)
+			}
+			if got := strings.Count(xml, "type='qcow2'"); got != tc.count {
+				t.Errorf("domain has %d qcow2 disks, want %d:\n%s", got, tc.count, xml)
+			}
+			for _, w := range tc.want {
+				if !strings.Contains(xml, w) {
+					t.Errorf("domain lacks %q:\n%s", w, xml)
+				}
+			}
+			if !strings.Contains(xml, "<target dev='hda' bus='virtio'/>") {
+				t.Errorf("domain lacks the boot disk:\n%s", xml)
+			}
+		})
+	}
+}
+
+func TestCreateExtraDisksKeepsExisting(t *testing.T) {
+	store, err := ioutil.TempDir("", "kvm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(store)
+
+	d := NewDriver("minikube", store)
+	d.ExtraDisks = 2
+	d.ExtraDiskSize = 1000
+	if err := os.MkdirAll(d.ResolveStorePath("."), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// the existing images hold data, they must not be recreated by qemu-img
+	for _, disk := range d.extraDisks() {
+		if err := ioutil.WriteFile(disk.Path, []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := d.createExtraDisks(); err != nil {
+		t.Fatalf("createExtraDisks: %v", err)
+	}
+	for _, disk := range d.extraDisks() {
+		b, err := ioutil.ReadFile(disk.Path)
+		if err != nil || string(b) != "data" {
+			t.Errorf("%s was overwritten: %q, %v", disk.Path, b, err)
+		}
+	}
+}
@@ -62,6 +62,13 @@ const domainTmpl = `
       <source file='{{.DiskPath}}'/>
       <target dev='hda' bus='virtio'/>
     </disk>
+    {{range extraDisks}}
+    <disk type='file' device='disk'>
+      <driver name='qemu' type='qcow2' cache='default' io='threads' />
+      <source file='{{.Path}}'/>
+      <target dev='{{.Target}}' bus='virtio'/>
+    </disk>
+    {{end}}
     <interface type='network'>
       <source network='{{.Network}}'/>
       <mac address='{{.MAC}}'/>
@@ -159,11 +166,9 @@ func (d *Driver) createDomain() (*libvirt.Domain, error) {
 		d.PrivateMAC = mac.String()
 	}
 
-	// create the XML for the domain using our domainTmpl template
-	tmpl := template.Must(template.New("domain").Parse(domainTmpl))
-	var domainXML bytes.Buffer
-	if err := tmpl.Execute(&domainXML, d); err != nil {
-		return nil, errors.Wrap(err, "executing domain xml")
+	domainXML, err := d.domainXML()
+	if err != nil {
+		return nil, err
 	}
 
 	conn, err := getConnection(d.ConnectionURI)
@@ -173,10 +178,20 @@ func (d *Driver) createDomain() (*libvirt.Domain, error) {
 	defer conn.Close()
 
 	// define the domain in libvirt using the generated XML
-	dom, err := conn.DomainDefineXML(domainXML.String())
+	dom, err := conn.DomainDefineXML(domainXML)
 	if err != nil {
-		return nil, errors.Wrapf(err, "error defining domain xml: %s", domainXML.String())
+		return nil, errors.Wrapf(err, "error defining domain xml: %s", domainXML)
 	}
 
 	return dom, nil
 }
+
+// domainXML creates the XML for the domain using our domainTmpl template
+func (d *Driver) domainXML() (string, error) {
+	tmpl := template.Must(template.New("domain").Funcs(template.FuncMap{"extraDisks": d.extraDisks}).Parse(domainTmpl))
+	var domainXML bytes.Buffer
+	if err := tmpl.Execute(&domainXML, d); err != nil {
+		return "", errors.Wrap(err, "executing domain xml")
+	}
+	return domainXML.String(), nil
+}
@@ -81,6 +81,23 @@ type Driver struct {
 
 	// QEMU Connection URI
 	ConnectionURI string
+
+	// The number of extra qcow2 disks attached to the VM as raw block devices
+	ExtraDisks int
+
+	// The size of each extra disk, in MB
+	ExtraDiskSize int
+
+	// The subnet of the private network, 192.168.39.0/24 if empty
+	Subnet string
+
+	// The IP assigned to the VM by a static DHCP host entry in the private network.
+	// If empty, the VM gets any free IP of the private network.
+	StaticIP string
+
+	// ReserveVirtualIPs leaves the addresses reserved for the virtual IPs of HA clusters out of the DHCP range
+	// of the private network, when minikube creates it
+	ReserveVirtualIPs bool
 }
 
 const (
@@ -260,6 +277,15 @@ func (d *Driver) Start() (err error) {
 		}
 	}()
 
+	if err := d.setStaticIP(conn); err != nil {
+		return errors.Wrap(err, "setting static IP")
+	}
+
+	log.Info("Reconciling domain resources...")
+	if err := d.setDomainResources(dom); err != nil {
+		return errors.Wrap(err, "setting domain resources")
+	}
+
 	log.Info("Creating domain...")
 	if err := dom.Create(); err != nil {
 		return errors.Wrap(err, "error creating VM")
@@ -297,6 +323,43 @@ func (d *Driver) Start() (err error) {
 	return nil
 }
 
+// setDomainResources updates the definition of a stopped domain if the configured CPUs or memory have changed since it was created
+func (d *Driver) setDomainResources(dom *libvirt.Domain) error {
+	info, err := dom.GetInfo()
+	if err != nil {
+		return errors.Wrap(err, "getting domain info")
+	}
+
+	if info.NrVirtCpu != uint(d.CPU) {
+		log.Infof("Changing CPUs from %d to %d", info.NrVirtCpu, d.CPU)
+		// The maximum must be raised before the current count, and lowered after it
+		flags := []libvirt.DomainVcpuFlags{libvirt.DOMAIN_VCPU_CONFIG | libvirt.DOMAIN_VCPU_MAXIMUM, libvirt.DOMAIN_VCPU_CONFIG}
+		if uint(d.CPU) < info.NrVirtCpu {
+			flags[0], flags[1] = flags[1], flags[0]
+		}
+		for _, f := range flags {
+			if err := dom.SetVcpusFlags(uint(d.CPU), f); err != nil {
+				return errors.Wrap(err, "setting CPUs")
+			}
+		}
+	}
+
+	mem := uint64(d.Memory) * 1024 // KiB
+	if info.MaxMem != mem {
+		log.Infof("Changing memory from %dMiB to %dMiB", info.MaxMem/1024, d.Memory)
+		flags := []libvirt.DomainMemoryModFlags{libvirt.DOMAIN_MEM_CONFIG | libvirt.DOMAIN_MEM_MAXIMUM, libvirt.DOMAIN_MEM_CONFIG}
+		if mem < info.MaxMem {
+			flags[0], flags[1] = flags[1], flags[0]
+		}
+		for _, f := range flags {
+			if err := dom.SetMemoryFlags(mem, f); err != nil {
+				return errors.Wrap(err, "setting memory")
+			}
+		}
+	}
+	return nil
+}
+
 // Create a host using the driver's config
 func (d *Driver) Create() (err error) {
 	log.Info("Creating KVM machine...")
@@ -326,6 +389,10 @@ func (d *Driver) Create() (err error) {
 		return errors.Wrap(err, "error creating disk")
 	}
 
+	if err := d.createExtraDisks(); err != nil {
+		return errors.Wrap(err, "creating extra disks")
+	}
+
 	if err := ensureDirPermissions(store); err != nil {
 		log.Errorf("unable to ensure permissions on %s: %v", store, err)
 	}
@@ -420,6 +487,10 @@ func (d *Driver) Remove() error {
 	}
 	defer conn.Close()
 
+	if err := d.unsetStaticIP(conn); err != nil {
+		log.Warnf("Removing the static IP %s failed: %v", d.StaticIP, err)
+	}
+
 	// Tear down network if it exists and is not in use by another minikube instance
 	log.Debug("Trying to delete the networks (if possible)")
 	if err := d.deleteNetwork(); err != nil {
@@ -34,15 +34,13 @@ import (
 	"k8s.io/minikube/pkg/util/retry"
 )
 
-// Replace with hardcoded range with CIDR
-// https://play.golang.org/p/m8TNTtygK0
 const networkTmpl = `
 <network>
-  <name>{{.PrivateNetwork}}</name>
+  <name>{{.Name}}</name>
   <dns enable='no'/>
-  <ip address='192.168.39.1' netmask='255.255.255.0'>
+  <ip address='{{.Gateway}}' netmask='{{.Netmask}}'>
     <dhcp>
-      <range start='192.168.39.2' end='192.168.39.254'/>
+      <range start='{{.ClientMin}}' end='{{.ClientMax}}'/>
     </dhcp>
   </ip>
 </network>
@@ -146,9 +144,13 @@ func (d *Driver) createNetwork() error {
 	netp, err := conn.LookupNetworkByName(d.PrivateNetwork)
 	if err != nil {
 		// create the XML for the private network from our networkTmpl
+		params, err := privateNetworkParams(d.PrivateNetwork, d.Subnet, d.ReserveVirtualIPs)
+		if err != nil {
+			return err
+		}
 		tmpl := template.Must(template.New("network").Parse(networkTmpl))
 		var networkXML bytes.Buffer
-		if err := tmpl.Execute(&networkXML, d); err != nil {
+		if err := tmpl.Execute(&networkXML, params); err != nil {
 			return errors.Wrap(err, "executing network template")
 		}
 
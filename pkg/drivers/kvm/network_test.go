@@ -95,3 +95,60 @@ func TestParseStatusAndReturnIp(t *testing.T) {
 		})
 	}
 }
+
+func TestPrivateNetworkParams(t *testing.T) {
+	var tests = []struct {
+		subnet      string
+		reserveVIPs bool
+		want        privateNetwork
+	}{
+		{"", false, privateNetwork{Name: "net", Gateway: "192.168.39.1", Netmask: "255.255.255.0", ClientMin: "192.168.39.2", ClientMax: "192.168.39.254"}},
+		{"", true, privateNetwork{Name: "net", Gateway: "192.168.39.1", Netmask: "255.255.255.0", ClientMin: "192.168.39.2", ClientMax: "192.168.39.191"}},
+		{"10.10.0.0/16", false, privateNetwork{Name: "net", Gateway: "10.10.0.1", Netmask: "255.255.0.0", ClientMin: "10.10.0.2", ClientMax: "10.10.255.254"}},
+		{"10.10.0.0/16", true, privateNetwork{Name: "net", Gateway: "10.10.0.1", Netmask: "255.255.0.0", ClientMin: "10.10.0.2", ClientMax: "10.10.191.255"}},
+		{"172.16.5.64/26", true, privateNetwork{Name: "net", Gateway: "172.16.5.65", Netmask: "255.255.255.192", ClientMin: "172.16.5.66", ClientMax: "172.16.5.111"}},
+		{"172.16.5.64/30", false, privateNetwork{Name: "net", Gateway: "172.16.5.65", Netmask: "255.255.255.252", ClientMin: "172.16.5.66", ClientMax: "172.16.5.66"}},
+	}
+	for _, tc := range tests {
+		got, err := privateNetworkParams("net", tc.subnet, tc.reserveVIPs)
+		if err != nil {
+			t.Fatalf("privateNetworkParams(%q, %v): %v", tc.subnet, tc.reserveVIPs, err)
+		}
+		if got != tc.want {
+			t.Errorf("privateNetworkParams(%q, %v) = %+v, want %+v", tc.subnet, tc.reserveVIPs, got, tc.want)
+		}
+	}
+
+	for _, subnet := range []string{"192.168.39.0", "fd00::/64", "192.168.39.0/31"} {
+		if _, err := privateNetworkParams("net", subnet, false); err == nil {
+			t.Errorf("privateNetworkParams(%q) returned no error", subnet)
+		}
+	}
+	// a /30 has no room for a virtual IP
+	if _, err := privateNetworkParams("net", "172.16.5.64/30", true); err == nil {
+		t.Errorf("privateNetworkParams(%q, true) returned no error", "172.16.5.64/30")
+	}
+}
+
+func TestDHCPHosts(t *testing.T) {
+	hosts, err := dhcpHosts(`<network>
+  <name>mk-p1</name>
+  <ip address='192.168.50.1' netmask='255.255.255.0'>
+    <dhcp>
+      <range start='192.168.50.2' end='192.168.50.254'/>
+      <host mac='52:54:00:aa:bb:cc' name='p1' ip='192.168.50.10'/>
+      <host mac='52:54:00:aa:bb:dd' name='p1-m02' ip='192.168.50.11'/>
+    </dhcp>
+  </ip>
+</network>`)
+	if err != nil {
+		t.Fatalf("dhcpHosts: %v", err)
+	}
+	want := []dhcpHost{
+		{MAC: "52:54:00:aa:bb:cc", Name: "p1", IP: "192.168.50.10"},
+		{MAC: "52:54:00:aa:bb:dd", Name: "p1-m02", IP: "192.168.50.11"},
+	}
+	if len(hosts) != len(want) || hosts[0] != want[0] || hosts[1] != want[1] {
+		t.Errorf("dhcpHosts() = %+v, want %+v", hosts, want)
+	}
+}
@@ -0,0 +1,165 @@
+// +build linux
+
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvm
+
+import (
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"net"
+
+	"github.com/docker/machine/libmachine/log"
+	libvirt "github.com/libvirt/libvirt-go"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/util"
+)
+
+// defaultPrivateSubnet is the subnet of the private network when none is requested
+const defaultPrivateSubnet = "192.168.39.0/24"
+
+// privateNetwork holds the parameters of the private network template
+type privateNetwork struct {
+	Name      string
+	Gateway   string
+	Netmask   string
+	ClientMin string
+	ClientMax string
+}
+
+// privateNetworkParams returns the parameters of the private network in subnet: the gateway takes the first address,
+// and the DHCP range the rest of the subnet, but for the addresses reserved for the virtual IPs of HA clusters if reserveVIPs is set.
+func privateNetworkParams(name string, subnet string, reserveVIPs bool) (privateNetwork, error) {
+	if subnet == "" {
+		subnet = defaultPrivateSubnet
+	}
+	_, ipnet, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return privateNetwork{}, errors.Wrapf(err, "parsing subnet %s", subnet)
+	}
+	base := ipnet.IP.To4()
+	if base == nil {
+		return privateNetwork{}, fmt.Errorf("subnet %s is not an IPv4 subnet", subnet)
+	}
+	ones, bits := ipnet.Mask.Size()
+	if bits-ones < 2 {
+		return privateNetwork{}, fmt.Errorf("subnet %s is too small", subnet)
+	}
+
+	first := binary.BigEndian.Uint32(base)
+	broadcast := first | ^binary.BigEndian.Uint32(net.IP(ipnet.Mask).To4())
+	addr := func(n uint32) string {
+		ip := make(net.IP, 4)
+		binary.BigEndian.PutUint32(ip, n)
+		return ip.String()
+	}
+	clientMax := addr(broadcast - 1)
+	if reserveVIPs {
+		vip, _, err := util.VirtualIPRange(ipnet)
+		if err != nil {
+			return privateNetwork{}, err
+		}
+		clientMax = addr(binary.BigEndian.Uint32(vip) - 1)
+	}
+	return privateNetwork{
+		Name:      name,
+		Gateway:   addr(first + 1),
+		Netmask:   net.IP(ipnet.Mask).String(),
+		ClientMin: addr(first + 2),
+		ClientMax: clientMax,
+	}, nil
+}
+
+// dhcpHost is a static DHCP host entry of a libvirt network
+type dhcpHost struct {
+	MAC  string `xml:"mac,attr"`
+	Name string `xml:"name,attr"`
+	IP   string `xml:"ip,attr"`
+}
+
+// dhcpHosts returns the static DHCP host entries of the XML description of a libvirt network
+func dhcpHosts(networkXML string) ([]dhcpHost, error) {
+	var n struct {
+		Hosts []dhcpHost `xml:"ip>dhcp>host"`
+	}
+	if err := xml.Unmarshal([]byte(networkXML), &n); err != nil {
+		return nil, errors.Wrap(err, "parsing network xml")
+	}
+	return n.Hosts, nil
+}
+
+// staticHostXML returns the static DHCP host entry pinning the IP of the VM
+func (d *Driver) staticHostXML() string {
+	return fmt.Sprintf("<host mac='%s' name='%s' ip='%s'/>", d.PrivateMAC, d.MachineName, d.StaticIP)
+}
+
+// setStaticIP adds a static DHCP host entry for the VM to the private network, which libvirt keeps across restarts
+func (d *Driver) setStaticIP(conn *libvirt.Connect) error {
+	if d.StaticIP == "" {
+		return nil
+	}
+	network, err := conn.LookupNetworkByName(d.PrivateNetwork)
+	if err != nil {
+		return errors.Wrapf(err, "looking up network %s", d.PrivateNetwork)
+	}
+	defer func() { _ = network.Free() }()
+
+	desc, err := network.GetXMLDesc(0)
+	if err != nil {
+		return errors.Wrapf(err, "getting xml of network %s", d.PrivateNetwork)
+	}
+	hosts, err := dhcpHosts(desc)
+	if err != nil {
+		return err
+	}
+
+	cmd := libvirt.NETWORK_UPDATE_COMMAND_ADD_LAST
+	for _, h := range hosts {
+		switch {
+		case h.MAC == d.PrivateMAC && h.IP == d.StaticIP:
+			log.Debugf("Network %s already assigns %s to %s", d.PrivateNetwork, d.StaticIP, d.MachineName)
+			return nil
+		case h.MAC == d.PrivateMAC:
+			cmd = libvirt.NETWORK_UPDATE_COMMAND_MODIFY
+		case h.IP == d.StaticIP:
+			return fmt.Errorf("%s is already the static IP of %s", d.StaticIP, h.Name)
+		}
+	}
+
+	log.Infof("Assigning static IP %s to %s in network %s", d.StaticIP, d.MachineName, d.PrivateNetwork)
+	flags := libvirt.NETWORK_UPDATE_AFFECT_LIVE | libvirt.NETWORK_UPDATE_AFFECT_CONFIG
+	return network.Update(cmd, libvirt.NETWORK_SECTION_IP_DHCP_HOST, -1, d.staticHostXML(), flags)
+}
+
+// unsetStaticIP removes the static DHCP host entry of the VM from the private network
+func (d *Driver) unsetStaticIP(conn *libvirt.Connect) error {
+	if d.StaticIP == "" {
+		return nil
+	}
+	network, err := conn.LookupNetworkByName(d.PrivateNetwork)
+	if err != nil {
+		return errors.Wrapf(err, "looking up network %s", d.PrivateNetwork)
+	}
+	defer func() { _ = network.Free() }()
+
+	flags := libvirt.NETWORK_UPDATE_AFFECT_CONFIG
+	if active, err := network.IsActive(); err == nil && active {
+		flags |= libvirt.NETWORK_UPDATE_AFFECT_LIVE
+	}
+	return network.Update(libvirt.NETWORK_UPDATE_COMMAND_DELETE, libvirt.NETWORK_SECTION_IP_DHCP_HOST, -1, d.staticHostXML(), flags)
+}
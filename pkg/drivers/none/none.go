@@ -47,9 +47,11 @@ var cleanupPaths = []string{
 type Driver struct {
 	*drivers.BaseDriver
 	*pkgdrivers.CommonDriver
-	URL     string
-	runtime cruntime.Manager
-	exec    command.Runner
+	URL string
+	// KubeconfigPath is the kubeconfig holding the endpoint of the cluster, the one of the environment if empty
+	KubeconfigPath string
+	runtime        cruntime.Manager
+	exec           command.Runner
 }
 
 // Config is configuration for the None driver
@@ -57,6 +59,7 @@ type Config struct {
 	MachineName      string
 	StorePath        string
 	ContainerRuntime string
+	KubeconfigPath   string
 }
 
 // NewDriver returns a fully configured None driver
@@ -72,8 +75,9 @@ func NewDriver(c Config) *Driver {
 			MachineName: c.MachineName,
 			StorePath:   c.StorePath,
 		},
-		runtime: runtime,
-		exec:    runner,
+		KubeconfigPath: c.KubeconfigPath,
+		runtime:        runtime,
+		exec:           runner,
 	}
 }
 
@@ -125,7 +129,11 @@ func (d *Driver) GetURL() (string, error) {
 
 // GetState returns the state that the host is in (running, stopped, etc)
 func (d *Driver) GetState() (state.State, error) {
-	hostname, port, err := kubeconfig.Endpoint(d.BaseDriver.MachineName)
+	var paths []string
+	if d.KubeconfigPath != "" {
+		paths = append(paths, d.KubeconfigPath)
+	}
+	hostname, port, err := kubeconfig.Endpoint(d.BaseDriver.MachineName, paths...)
 	if err != nil {
 		klog.Warningf("unable to get port: %v", err)
 		port = constants.APIServerPort
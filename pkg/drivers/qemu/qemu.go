@@ -0,0 +1,385 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qemu
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/log"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	pkgdrivers "k8s.io/minikube/pkg/drivers"
+	"k8s.io/minikube/pkg/util/retry"
+)
+
+const (
+	isoFilename     = "boot2docker.iso"
+	pidFileName     = "qemu.pid"
+	monitorFileName = "monitor"
+	serialFileName  = "serial.log"
+
+	// NetworkUser is the qemu user mode network stack, which needs no privileges
+	// but only reaches the VM through ports forwarded from the host loopback
+	NetworkUser = "user"
+	// NetworkSocketVMnet attaches the VM to a vmnet bridge shared by socket_vmnet, so that it gets a routable IP
+	NetworkSocketVMnet = "socket_vmnet"
+
+	// UserNetworkGuestIP is the address of the VM on the user mode network
+	UserNetworkGuestIP = "10.0.2.15"
+	// UserNetworkHostIP is the address of the host as seen by the VM on the user mode network
+	UserNetworkHostIP = "10.0.2.2"
+
+	// DefaultSocketVMnetClientPath is where the socket_vmnet installer puts the client
+	DefaultSocketVMnetClientPath = "/opt/socket_vmnet/bin/socket_vmnet_client"
+	// DefaultSocketVMnetPath is where the socket_vmnet daemon listens by default
+	DefaultSocketVMnetPath = "/var/run/socket_vmnet"
+)
+
+// Driver is the machine driver running qemu-system directly, without libvirt
+type Driver struct {
+	*drivers.BaseDriver
+	*pkgdrivers.CommonDriver
+	Boot2DockerURL        string
+	DiskSize              int
+	CPU                   int
+	Memory                int
+	Network               string
+	MACAddress            string
+	SocketVMnetClientPath string
+	SocketVMnetPath       string
+	// APIServerPort is forwarded from the host loopback to the same port of the VM on the user mode network
+	APIServerPort int
+}
+
+// NewDriver creates a new driver for a host
+func NewDriver(hostName, storePath string) *Driver {
+	return &Driver{
+		BaseDriver: &drivers.BaseDriver{
+			MachineName: hostName,
+			StorePath:   storePath,
+			SSHUser:     "docker",
+		},
+		CommonDriver: &pkgdrivers.CommonDriver{},
+		Network:      NetworkUser,
+	}
+}
+
+// Binary returns the qemu-system binary running the VMs, which boot the x86_64 ISO
+func Binary() string {
+	return "qemu-system-x86_64"
+}
+
+// Accelerator returns the hypervisor qemu can use on this host, or tcg for software emulation
+func Accelerator() string {
+	switch runtime.GOOS {
+	case "linux":
+		f, err := os.OpenFile("/dev/kvm", os.O_RDWR, 0)
+		if err != nil {
+			log.Debugf("/dev/kvm is not usable, falling back to tcg: %v", err)
+			return "tcg"
+		}
+		f.Close()
+		return "kvm"
+	case "darwin":
+		return "hvf"
+	}
+	return "tcg"
+}
+
+// DriverName returns the name of the driver
+func (d *Driver) DriverName() string {
+	return "qemu"
+}
+
+// PreCreateCheck is called to enforce pre-creation steps
+func (d *Driver) PreCreateCheck() error {
+	if _, err := exec.LookPath(Binary()); err != nil {
+		return errors.Wrapf(err, "%s is required by the qemu driver", Binary())
+	}
+	switch d.Network {
+	case NetworkUser:
+		return nil
+	case NetworkSocketVMnet:
+		if _, err := os.Stat(d.SocketVMnetClientPath); err != nil {
+			return errors.Wrap(err, "socket_vmnet client")
+		}
+		if _, err := os.Stat(d.SocketVMnetPath); err != nil {
+			return errors.Wrap(err, "socket_vmnet socket, is the socket_vmnet daemon running?")
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported qemu network %q, use %q or %q", d.Network, NetworkUser, NetworkSocketVMnet)
+}
+
+// Create a host using the driver's config
+func (d *Driver) Create() error {
+	if err := pkgdrivers.MakeDiskImage(d.BaseDriver, d.Boot2DockerURL, d.DiskSize); err != nil {
+		return errors.Wrap(err, "making disk image")
+	}
+
+	mac, err := generateMACAddress()
+	if err != nil {
+		return errors.Wrap(err, "generating mac address")
+	}
+	d.MACAddress = mac
+
+	if d.Network == NetworkUser {
+		// the port has to be persisted, as the ssh runner of minikube connects to it
+		d.SSHPort, err = freePort()
+		if err != nil {
+			return errors.Wrap(err, "ssh port")
+		}
+		d.IPAddress = UserNetworkGuestIP
+	}
+
+	return d.Start()
+}
+
+// GetSSHHostname returns hostname for use with ssh
+func (d *Driver) GetSSHHostname() (string, error) {
+	if d.Network == NetworkUser {
+		return "127.0.0.1", nil
+	}
+	return d.GetIP()
+}
+
+// GetIP returns the IP of the VM, as seen by the VM itself on the user mode network
+func (d *Driver) GetIP() (string, error) {
+	if d.Network == NetworkUser {
+		return UserNetworkGuestIP, nil
+	}
+	if d.IPAddress == "" {
+		return "", errors.New("IP address is not set")
+	}
+	return d.IPAddress, nil
+}
+
+// GetURL returns a Docker URL inside this host
+func (d *Driver) GetURL() (string, error) {
+	ip, err := d.GetIP()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("tcp://%s", net.JoinHostPort(ip, "2376")), nil
+}
+
+// GetState returns the state that the host is in (running, stopped, etc)
+func (d *Driver) GetState() (state.State, error) {
+	socket := d.ResolveStorePath(monitorFileName)
+	if _, err := os.Stat(socket); os.IsNotExist(err) {
+		return state.Stopped, nil
+	}
+	s, err := qmpQueryStatus(socket)
+	if err != nil {
+		// qemu leaves the socket behind when it exits
+		log.Debugf("qmp query-status: %v", err)
+		return state.Stopped, nil
+	}
+	return vmState(s), nil
+}
+
+// vmState maps the run state reported by qemu to the machine state
+func vmState(s qmpStatus) state.State {
+	switch s.Status {
+	case "running":
+		return state.Running
+	case "paused", "suspended":
+		return state.Paused
+	case "inmigrate", "prelaunch", "restore-vm":
+		return state.Starting
+	case "shutdown":
+		return state.Stopping
+	case "internal-error", "io-error", "guest-panicked":
+		return state.Error
+	}
+	if s.Running {
+		return state.Running
+	}
+	return state.Stopped
+}
+
+// Start a host
+func (d *Driver) Start() error {
+	name, args := d.command(Accelerator())
+	log.Debugf("Starting qemu: %s %s", name, strings.Join(args, " "))
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		return errors.Wrapf(err, "%s: %s", name, strings.TrimSpace(string(out)))
+	}
+
+	if d.Network == NetworkUser {
+		return nil
+	}
+
+	getIP := func() error {
+		ip, err := vmnetIPAddress(d.MACAddress)
+		if err != nil {
+			return &retry.RetriableError{Err: err}
+		}
+		d.IPAddress = ip
+		return nil
+	}
+	if err := retry.Expo(getIP, 2*time.Second, 2*time.Minute); err != nil {
+		return errors.Wrap(err, "IP address never found in dhcp leases file")
+	}
+	log.Debugf("IP: %s", d.IPAddress)
+	return nil
+}
+
+// command returns the command line which runs the VM in the background
+func (d *Driver) command(accel string) (string, []string) {
+	args := []string{
+		"-name", d.MachineName,
+		"-accel", accel,
+		"-smp", strconv.Itoa(d.CPU),
+		"-m", strconv.Itoa(d.Memory),
+		"-boot", "d",
+		"-cdrom", d.ResolveStorePath(isoFilename),
+		"-drive", fmt.Sprintf("file=%s,format=raw,if=virtio", pkgdrivers.GetDiskPath(d.BaseDriver)),
+		"-qmp", fmt.Sprintf("unix:%s,server,nowait", d.ResolveStorePath(monitorFileName)),
+		"-pidfile", d.ResolveStorePath(pidFileName),
+		"-serial", "file:" + d.ResolveStorePath(serialFileName),
+		"-display", "none",
+		"-daemonize",
+	}
+	if accel == "tcg" {
+		args = append(args, "-cpu", "max")
+	} else {
+		args = append(args, "-cpu", "host")
+	}
+
+	if d.Network == NetworkSocketVMnet {
+		// socket_vmnet_client connects to the daemon and passes the connection to qemu as fd 3
+		args = append(args,
+			"-netdev", "socket,id=net0,fd=3",
+			"-device", fmt.Sprintf("virtio-net-pci,netdev=net0,mac=%s", d.MACAddress))
+		return d.SocketVMnetClientPath, append([]string{d.SocketVMnetPath, Binary()}, args...)
+	}
+
+	forwards := []string{
+		fmt.Sprintf("hostfwd=tcp:127.0.0.1:%d-:22", d.SSHPort),
+		fmt.Sprintf("hostfwd=tcp:127.0.0.1:%d-:%d", d.APIServerPort, d.APIServerPort),
+	}
+	args = append(args, "-nic", fmt.Sprintf("user,model=virtio,mac=%s,%s", d.MACAddress, strings.Join(forwards, ",")))
+	return Binary(), args
+}
+
+// Stop a host gracefully
+func (d *Driver) Stop() error {
+	s, err := d.GetState()
+	if err != nil {
+		return err
+	}
+	if s == state.Stopped {
+		return nil
+	}
+
+	if _, err := qmpCommand(d.ResolveStorePath(monitorFileName), "system_powerdown"); err != nil {
+		return errors.Wrap(err, "system_powerdown")
+	}
+
+	for i := 0; i < 60; i++ {
+		log.Debug("waiting for graceful shutdown")
+		time.Sleep(time.Second)
+		s, err := d.GetState()
+		if err != nil {
+			return errors.Wrap(err, "waiting for graceful shutdown")
+		}
+		if s == state.Stopped {
+			return nil
+		}
+	}
+
+	log.Debug("qemu did not power down, killing it")
+	return d.Kill()
+}
+
+// Kill stops a host forcefully
+func (d *Driver) Kill() error {
+	if _, err := qmpCommand(d.ResolveStorePath(monitorFileName), "quit"); err == nil {
+		return nil
+	}
+
+	// the monitor may be gone while qemu is still running
+	b, err := ioutil.ReadFile(d.ResolveStorePath(pidFileName))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "reading pidfile")
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(b)))
+	if err != nil {
+		return errors.Wrap(err, "parsing pidfile")
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return nil
+	}
+	if err := p.Kill(); err != nil {
+		// most likely, the process is gone already
+		log.Debugf("killing qemu pid %d: %v", pid, err)
+	}
+	return nil
+}
+
+// Remove a host
+func (d *Driver) Remove() error {
+	s, err := d.GetState()
+	if err != nil {
+		return errors.Wrap(err, "get state")
+	}
+	if s == state.Stopped {
+		return nil
+	}
+	return d.Kill()
+}
+
+// Restart a host
+func (d *Driver) Restart() error {
+	return pkgdrivers.Restart(d)
+}
+
+// generateMACAddress returns a random locally administered unicast MAC address
+func generateMACAddress() (string, error) {
+	buf := make([]byte, 6)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	buf[0] = (buf[0] | 2) & 0xfe
+	return net.HardwareAddr(buf).String(), nil
+}
+
+// freePort asks the kernel for a free port on the host loopback
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
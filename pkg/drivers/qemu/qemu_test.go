@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qemu
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	d := NewDriver("minikube", "/home/user/.minikube")
+	d.CPU = 2
+	d.Memory = 2200
+	d.SSHPort = 40022
+	d.APIServerPort = 40443
+	d.MACAddress = "52:54:00:12:34:56"
+
+	name, args := d.command("tcg")
+	if name != Binary() {
+		t.Errorf("command() runs %s, want %s", name, Binary())
+	}
+	cmdline := strings.Join(args, " ")
+	for _, want := range []string{
+		"-accel tcg -smp 2 -m 2200",
+		"-cpu max",
+		"-cdrom /home/user/.minikube/machines/minikube/boot2docker.iso",
+		"-drive file=/home/user/.minikube/machines/minikube/minikube.rawdisk,format=raw,if=virtio",
+		"-qmp unix:/home/user/.minikube/machines/minikube/monitor,server,nowait",
+		"-nic user,model=virtio,mac=52:54:00:12:34:56,hostfwd=tcp:127.0.0.1:40022-:22,hostfwd=tcp:127.0.0.1:40443-:40443",
+		"-daemonize",
+	} {
+		if !strings.Contains(cmdline, want) {
+			t.Errorf("command() = %q, missing %q", cmdline, want)
+		}
+	}
+
+	d.Network = NetworkSocketVMnet
+	d.SocketVMnetClientPath = DefaultSocketVMnetClientPath
+	d.SocketVMnetPath = DefaultSocketVMnetPath
+	name, args = d.command("hvf")
+	if name != DefaultSocketVMnetClientPath {
+		t.Errorf("command() runs %s, want %s", name, DefaultSocketVMnetClientPath)
+	}
+	cmdline = strings.Join(args, " ")
+	if !strings.HasPrefix(cmdline, DefaultSocketVMnetPath+" "+Binary()+" ") {
+		t.Errorf("command() = %q, want the socket and qemu first", cmdline)
+	}
+	for _, want := range []string{"-cpu host", "-netdev socket,id=net0,fd=3", "-device virtio-net-pci,netdev=net0,mac=52:54:00:12:34:56"} {
+		if !strings.Contains(cmdline, want) {
+			t.Errorf("command() = %q, missing %q", cmdline, want)
+		}
+	}
+	if strings.Contains(cmdline, "hostfwd") {
+		t.Errorf("command() = %q, forwards ports on a bridged network", cmdline)
+	}
+}
+
+func TestGenerateMACAddress(t *testing.T) {
+	mac, err := generateMACAddress()
+	if err != nil {
+		t.Fatalf("generateMACAddress: %v", err)
+	}
+	hw, err := net.ParseMAC(mac)
+	if err != nil {
+		t.Fatalf("ParseMAC(%q): %v", mac, err)
+	}
+	if hw[0]&1 != 0 || hw[0]&2 == 0 {
+		t.Errorf("%s is not a locally administered unicast address", mac)
+	}
+}
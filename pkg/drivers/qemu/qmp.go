@@ -0,0 +1,120 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qemu
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// qmpTimeout is how long to wait for qemu to answer a QMP command
+const qmpTimeout = 10 * time.Second
+
+// qmpResponse is a message sent by qemu over the QEMU Machine Protocol
+// https://qemu.readthedocs.io/en/latest/interop/qmp-spec.html
+type qmpResponse struct {
+	Greeting *json.RawMessage `json:"QMP"`
+	Return   *json.RawMessage `json:"return"`
+	Error    *struct {
+		Class string `json:"class"`
+		Desc  string `json:"desc"`
+	} `json:"error"`
+	Event string `json:"event"`
+}
+
+// qmpStatus is the return value of the query-status command
+type qmpStatus struct {
+	Running bool   `json:"running"`
+	Status  string `json:"status"`
+}
+
+// qmpCommand runs a QMP command against the monitor socket of a running qemu and returns its result
+func qmpCommand(socket string, command string) (json.RawMessage, error) {
+	conn, err := net.DialTimeout("unix", socket, qmpTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(qmpTimeout)); err != nil {
+		return nil, errors.Wrap(err, "deadline")
+	}
+
+	dec := json.NewDecoder(conn)
+	enc := json.NewEncoder(conn)
+
+	var greeting qmpResponse
+	if err := dec.Decode(&greeting); err != nil {
+		return nil, errors.Wrap(err, "reading greeting")
+	}
+	if greeting.Greeting == nil {
+		return nil, fmt.Errorf("%s is not a QMP socket", socket)
+	}
+
+	// the capabilities negotiation must happen before any other command is accepted
+	if err := enc.Encode(map[string]string{"execute": "qmp_capabilities"}); err != nil {
+		return nil, errors.Wrap(err, "sending qmp_capabilities")
+	}
+	if _, err := qmpReturn(dec); err != nil {
+		return nil, errors.Wrap(err, "qmp_capabilities")
+	}
+
+	if err := enc.Encode(map[string]string{"execute": command}); err != nil {
+		return nil, errors.Wrapf(err, "sending %s", command)
+	}
+	ret, err := qmpReturn(dec)
+	if err != nil {
+		return nil, errors.Wrap(err, command)
+	}
+	return ret, nil
+}
+
+// qmpReturn reads the answer to the last command, skipping the events qemu sends meanwhile
+func qmpReturn(dec *json.Decoder) (json.RawMessage, error) {
+	for {
+		var r qmpResponse
+		if err := dec.Decode(&r); err != nil {
+			return nil, err
+		}
+		if r.Event != "" {
+			continue
+		}
+		if r.Error != nil {
+			return nil, fmt.Errorf("%s: %s", r.Error.Class, r.Error.Desc)
+		}
+		if r.Return == nil {
+			return nil, nil
+		}
+		return *r.Return, nil
+	}
+}
+
+// qmpQueryStatus returns the run state of the VM behind the monitor socket
+func qmpQueryStatus(socket string) (qmpStatus, error) {
+	var s qmpStatus
+	ret, err := qmpCommand(socket, "query-status")
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(ret, &s); err != nil {
+		return s, errors.Wrapf(err, "parsing %s", string(ret))
+	}
+	return s, nil
+}
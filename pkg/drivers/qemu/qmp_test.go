@@ -0,0 +1,114 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qemu
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/machine/libmachine/state"
+)
+
+// fakeQMP serves a single QMP session, answering each command from replies
+func fakeQMP(t *testing.T, replies map[string]string) string {
+	dir, err := ioutil.TempDir("", "qmp")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socket := filepath.Join(dir, monitorFileName)
+
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		fmt.Fprintln(conn, `{"QMP": {"version": {"qemu": {"micro": 0, "minor": 2, "major": 5}}, "capabilities": []}}`)
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			var cmd map[string]string
+			if err := json.Unmarshal(scanner.Bytes(), &cmd); err != nil {
+				return
+			}
+			// events may arrive before any answer
+			fmt.Fprintln(conn, `{"event": "RTC_CHANGE", "data": {"offset": 0}}`)
+			fmt.Fprintln(conn, replies[cmd["execute"]])
+		}
+	}()
+	return socket
+}
+
+func TestQMPQueryStatus(t *testing.T) {
+	socket := fakeQMP(t, map[string]string{
+		"qmp_capabilities": `{"return": {}}`,
+		"query-status":     `{"return": {"status": "paused", "singlestep": false, "running": false}}`,
+	})
+
+	s, err := qmpQueryStatus(socket)
+	if err != nil {
+		t.Fatalf("qmpQueryStatus: %v", err)
+	}
+	if s.Status != "paused" || s.Running {
+		t.Errorf("qmpQueryStatus() = %+v, want paused", s)
+	}
+	if got := vmState(s); got != state.Paused {
+		t.Errorf("vmState(%+v) = %s, want %s", s, got, state.Paused)
+	}
+}
+
+func TestQMPCommandError(t *testing.T) {
+	socket := fakeQMP(t, map[string]string{
+		"qmp_capabilities": `{"return": {}}`,
+		"system_powerdown": `{"error": {"class": "GenericError", "desc": "not running"}}`,
+	})
+
+	if _, err := qmpCommand(socket, "system_powerdown"); err == nil {
+		t.Errorf("qmpCommand() returned no error, want the GenericError")
+	}
+}
+
+func TestVMState(t *testing.T) {
+	var tests = []struct {
+		status qmpStatus
+		want   state.State
+	}{
+		{qmpStatus{Running: true, Status: "running"}, state.Running},
+		{qmpStatus{Status: "prelaunch"}, state.Starting},
+		{qmpStatus{Status: "shutdown"}, state.Stopping},
+		{qmpStatus{Status: "guest-panicked"}, state.Error},
+		{qmpStatus{Running: true, Status: "debug"}, state.Running},
+		{qmpStatus{Status: "save-vm"}, state.Stopped},
+	}
+	for _, tc := range tests {
+		if got := vmState(tc.status); got != tc.want {
+			t.Errorf("vmState(%+v) = %s, want %s", tc.status, got, tc.want)
+		}
+	}
+}
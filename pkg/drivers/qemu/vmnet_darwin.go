@@ -0,0 +1,33 @@
+// +build darwin
+
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qemu
+
+import (
+	"regexp"
+
+	"k8s.io/minikube/pkg/drivers/hyperkit"
+)
+
+var leadingZeroRegexp = regexp.MustCompile(`0([A-Fa-f0-9](:|$))`)
+
+// vmnetIPAddress looks up the address the vmnet DHCP server leased to mac
+func vmnetIPAddress(mac string) (string, error) {
+	// the leases file strips the leading zeros of each byte
+	return hyperkit.GetIPAddressByMACAddress(leadingZeroRegexp.ReplaceAllString(mac, "$1"))
+}
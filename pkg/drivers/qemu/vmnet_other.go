@@ -1,5 +1,7 @@
+// +build !darwin
+
 /*
-Copyright 2018 The Kubernetes Authors All rights reserved.
+Copyright 2021 The Kubernetes Authors All rights reserved.
 
 Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
@@ -14,18 +16,14 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package main
+package qemu
 
 import (
-	"log"
-	"os"
-
-	"k8s.io/minikube/pkg/gvisor"
+	"fmt"
+	"runtime"
 )
 
-func main() {
-	if err := gvisor.Enable(); err != nil {
-		log.Print(err)
-		os.Exit(1)
-	}
+// vmnetIPAddress looks up the address the vmnet DHCP server leased to mac
+func vmnetIPAddress(mac string) (string, error) {
+	return "", fmt.Errorf("socket_vmnet is not supported on %s", runtime.GOOS)
 }
@@ -23,7 +23,9 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"runtime"
 	"strconv"
+	"strings"
 	"time"
 
 	"golang.org/x/crypto/ssh"
@@ -141,6 +143,10 @@ func (d *Driver) Create() error {
 		}
 	}
 
+	if err := d.checkPrerequisites(); err != nil {
+		return err
+	}
+
 	if d.runtime.Name() == "Docker" {
 		if _, err := d.exec.RunCmd(exec.Command("sudo", "usermod", "-aG", "docker", d.GetSSHUsername())); err != nil {
 			return errors.Wrap(err, "usermod")
@@ -249,6 +255,56 @@ func (d *Driver) Remove() error {
 	return nil
 }
 
+// runtimeBinaries are the executables the container runtimes need on the host
+var runtimeBinaries = map[string]string{
+	"Docker":     "docker",
+	"containerd": "containerd",
+	"CRI-O":      "crio",
+}
+
+// machineArchs are the architectures reported by uname, by GOARCH
+var machineArchs = map[string][]string{
+	"amd64":   {"x86_64"},
+	"arm64":   {"aarch64", "arm64"},
+	"ppc64le": {"ppc64le"},
+	"s390x":   {"s390x"},
+}
+
+// checkPrerequisites checks that the host can run a node: minikube copies its own binaries there, and needs sudo and the container runtime
+func (d *Driver) checkPrerequisites() error {
+	rr, err := d.exec.RunCmd(exec.Command("uname", "-s", "-m"))
+	if err != nil {
+		return errors.Wrap(err, "uname")
+	}
+	fields := strings.Fields(rr.Stdout.String())
+	if len(fields) != 2 {
+		return fmt.Errorf("unexpected uname output: %q", rr.Stdout.String())
+	}
+	if fields[0] != "Linux" {
+		return fmt.Errorf("%s runs %s, only Linux hosts are supported", d.IPAddress, fields[0])
+	}
+	supported := false
+	for _, arch := range machineArchs[runtime.GOARCH] {
+		if fields[1] == arch {
+			supported = true
+		}
+	}
+	if !supported {
+		return fmt.Errorf("%s is a %s host, its architecture has to match the one of minikube (%s)", d.IPAddress, fields[1], runtime.GOARCH)
+	}
+
+	if _, err := d.exec.RunCmd(exec.Command("sudo", "-n", "true")); err != nil {
+		return errors.Wrapf(err, "%s has to be allowed to run sudo without a password on %s", d.GetSSHUsername(), d.IPAddress)
+	}
+
+	if bin, ok := runtimeBinaries[d.runtime.Name()]; ok {
+		if _, err := d.exec.RunCmd(exec.Command("sudo", "sh", "-c", "command -v "+bin)); err != nil {
+			return errors.Wrapf(err, "%s is not installed on %s", d.runtime.Name(), d.IPAddress)
+		}
+	}
+	return nil
+}
+
 func copySSHKey(src, dst string) error {
 	if err := mcnutils.CopyFile(src, dst); err != nil {
 		return fmt.Errorf("unable to copy ssh key: %s", err)
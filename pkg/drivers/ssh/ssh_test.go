@@ -0,0 +1,90 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssh
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+)
+
+func TestCheckPrerequisites(t *testing.T) {
+	arch := machineArchs[runtime.GOARCH]
+	if len(arch) == 0 {
+		t.Skipf("no machine architecture known for %s", runtime.GOARCH)
+	}
+
+	var tests = []struct {
+		description string
+		commands    map[string]string
+		wantErr     bool
+	}{
+		{
+			description: "ready",
+			commands: map[string]string{
+				"uname -s -m":                        "Linux " + arch[0] + "\n",
+				"sudo -n true":                       "",
+				`sudo sh -c "command -v containerd"`: "/usr/bin/containerd\n",
+			},
+		},
+		{
+			description: "not linux",
+			commands:    map[string]string{"uname -s -m": "Darwin " + arch[0] + "\n"},
+			wantErr:     true,
+		},
+		{
+			description: "other architecture",
+			commands:    map[string]string{"uname -s -m": "Linux mips\n"},
+			wantErr:     true,
+		},
+		{
+			description: "sudo asks for a password",
+			commands:    map[string]string{"uname -s -m": "Linux " + arch[0] + "\n"},
+			wantErr:     true,
+		},
+		{
+			description: "missing runtime",
+			commands: map[string]string{
+				"uname -s -m":  "Linux " + arch[0] + "\n",
+				"sudo -n true": "",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			runner := command.NewFakeCommandRunner()
+			runner.SetCommandToOutput(tc.commands)
+			cr, err := cruntime.New(cruntime.Config{Type: "containerd", Runner: runner})
+			if err != nil {
+				t.Fatalf("runtime: %v", err)
+			}
+			d := &Driver{
+				BaseDriver: &drivers.BaseDriver{IPAddress: "192.168.1.10", SSHUser: "root"},
+				runtime:    cr,
+				exec:       runner,
+			}
+			err = d.checkPrerequisites()
+			if (err != nil) != tc.wantErr {
+				t.Errorf("checkPrerequisites() = %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -36,6 +36,7 @@ import (
 	watchtools "k8s.io/client-go/tools/watch"
 	"k8s.io/klog/v2"
 	kconst "k8s.io/kubernetes/cmd/kubeadm/app/constants"
+	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/proxy"
 	"k8s.io/minikube/pkg/minikube/vmpath"
 )
@@ -50,6 +51,10 @@ var (
 // ClientConfig returns the client configuration for a kubectl context
 func ClientConfig(context string) (*rest.Config, error) {
 	loader := clientcmd.NewDefaultClientConfigLoadingRules()
+	// profiles started with --kubeconfig keep their context out of the default kubeconfig
+	if cc, err := config.Load(context); err == nil && cc.KubeconfigPath != "" {
+		loader.ExplicitPath = cc.KubeconfigPath
+	}
 	cc := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loader, &clientcmd.ConfigOverrides{CurrentContext: context})
 	c, err := cc.ClientConfig()
 	if err != nil {
@@ -23,7 +23,6 @@ import (
 
 	"github.com/spf13/viper"
 	"k8s.io/minikube/pkg/minikube/config"
-	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/style"
 	"k8s.io/minikube/pkg/minikube/vmpath"
@@ -381,27 +380,8 @@ var Addons = map[string]*Addon{
 	}, false, "logviewer", map[string]string{
 		"LogViewer": "ivans3/minikube-log-viewer:latest",
 	}, nil),
-	"gvisor": NewAddon([]*BinAsset{
-		MustBinAsset(
-			"deploy/addons/gvisor/gvisor-pod.yaml.tmpl",
-			vmpath.GuestAddonsDir,
-			"gvisor-pod.yaml",
-			"0640"),
-		MustBinAsset(
-			"deploy/addons/gvisor/gvisor-runtimeclass.yaml.tmpl",
-			vmpath.GuestAddonsDir,
-			"gvisor-runtimeclass.yaml",
-			"0640"),
-		MustBinAsset(
-			"deploy/addons/gvisor/gvisor-config.toml",
-			vmpath.GuestGvisorDir,
-			constants.GvisorConfigTomlTargetName,
-			"0640"),
-	}, false, "gvisor", map[string]string{
-		"GvisorAddon": "k8s-minikube/gvisor-addon:3",
-	}, map[string]string{
-		"GvisorAddon": "gcr.io",
-	}),
+	// installed as the runsc runtime handler, see minikube runtime enable
+	"gvisor": NewAddon([]*BinAsset{}, false, "gvisor", nil, nil),
 	"helm-tiller": NewAddon([]*BinAsset{
 		MustBinAsset(
 			"deploy/addons/helm-tiller/helm-tiller-dp.tmpl",
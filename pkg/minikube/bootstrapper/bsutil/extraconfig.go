@@ -147,7 +147,7 @@ func defaultOptionsForComponentAndVersion(component string, version semver.Versi
 }
 
 // newComponentOptions creates a new componentOptions
-func newComponentOptions(opts config.ExtraOptionSlice, version semver.Version, featureGates string, cp config.Node) ([]componentOptions, error) {
+func newComponentOptions(opts config.ExtraOptionSlice, version semver.Version, featureGates string, certSANs []string) ([]componentOptions, error) {
 	if invalidOpts := FindInvalidExtraConfigFlags(opts); len(invalidOpts) > 0 {
 		return nil, fmt.Errorf("unknown components %v. valid components are: %v", invalidOpts, KubeadmExtraConfigOpts)
 	}
@@ -169,7 +169,7 @@ func newComponentOptions(opts config.ExtraOptionSlice, version semver.Version, f
 			kubeadmExtraArgs = append(kubeadmExtraArgs, componentOptions{
 				Component: kubeadmComponentKey,
 				ExtraArgs: extraConfig,
-				Pairs:     optionPairsForComponent(component, version, cp),
+				Pairs:     optionPairsForComponent(component, version, certSANs),
 			})
 		}
 	}
@@ -178,11 +178,11 @@ func newComponentOptions(opts config.ExtraOptionSlice, version semver.Version, f
 }
 
 // optionPairsForComponent generates a map of value pairs for a k8s component
-func optionPairsForComponent(component string, version semver.Version, cp config.Node) map[string]string {
+func optionPairsForComponent(component string, version semver.Version, certSANs []string) map[string]string {
 	// For the ktmpl.V1Beta1 users
 	if component == Apiserver && version.GTE(semver.MustParse("1.14.0-alpha.0")) {
 		return map[string]string{
-			"certSANs": fmt.Sprintf(`["127.0.0.1", "localhost", "%s"]`, cp.IP),
+			"certSANs": fmt.Sprintf(`["%s"]`, strings.Join(certSANs, `", "`)),
 		}
 	}
 	return nil
@@ -193,8 +193,8 @@ func optionPairsForComponent(component string, version semver.Version, cp config
 // kubeadm extra args from the slice
 // etcd must also not be included in that section, as those extra args exist in the `etcd` section
 // createExtraComponentConfig generates a map of component to extra args for all of the components except kubeadm
-func createExtraComponentConfig(extraOptions config.ExtraOptionSlice, version semver.Version, componentFeatureArgs string, cp config.Node) ([]componentOptions, error) {
-	extraArgsSlice, err := newComponentOptions(extraOptions, version, componentFeatureArgs, cp)
+func createExtraComponentConfig(extraOptions config.ExtraOptionSlice, version semver.Version, componentFeatureArgs string, certSANs []string) ([]componentOptions, error) {
+	extraArgsSlice, err := newComponentOptions(extraOptions, version, componentFeatureArgs, certSANs)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,78 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ktmpl
+
+import "text/template"
+
+// KubeVipTemplate is the static pod of kube-vip, which holds the virtual IP of the control plane nodes of HA clusters.
+// It talks to the local API server, as the virtual IP is not up before a leader is elected.
+var KubeVipTemplate = template.Must(template.New("kubeVipTemplate").Parse(`apiVersion: v1
+kind: Pod
+metadata:
+  name: kube-vip
+  namespace: kube-system
+spec:
+  containers:
+  - name: kube-vip
+    image: {{.Image}}
+    imagePullPolicy: IfNotPresent
+    args:
+    - manager
+    env:
+    - name: vip_arp
+      value: "true"
+    - name: vip_interface
+      value: {{.Interface}}
+    - name: vip_address
+      value: {{.VIP}}
+    - name: vip_cidr
+      value: "32"
+    - name: port
+      value: "{{.Port}}"
+    - name: cp_enable
+      value: "true"
+    - name: cp_namespace
+      value: kube-system
+    - name: vip_leaderelection
+      value: "true"
+    - name: vip_leaseduration
+      value: "5"
+    - name: vip_renewdeadline
+      value: "3"
+    - name: vip_retryperiod
+      value: "1"
+    securityContext:
+      capabilities:
+        add:
+        - NET_ADMIN
+        - NET_RAW
+    volumeMounts:
+    - mountPath: /etc/kubernetes/admin.conf
+      name: kubeconfig
+      readOnly: true
+    - mountPath: {{.CertDir}}
+      name: certs
+      readOnly: true
+  hostNetwork: true
+  volumes:
+  - name: kubeconfig
+    hostPath:
+      path: {{.KubeconfigPath}}
+  - name: certs
+    hostPath:
+      path: {{.CertDir}}
+`))
@@ -94,6 +94,13 @@ apiVersion: kubeproxy.config.k8s.io/v1alpha1
 kind: KubeProxyConfiguration
 clusterCIDR: "{{.PodSubnet }}"
 metricsBindAddress: {{.AdvertiseAddress}}:10249
+{{- if .SkipConntrackSysctls}}
+# kube-proxy can't set the conntrack sysctls of the host from a rootless container, leave them to the host
+conntrack:
+  maxPerCore: 0
+  tcpEstablishedTimeout: 0s
+  tcpCloseWaitTimeout: 0s
+{{- end}}
 {{- range $i, $val := printMapInOrder .KubeProxyOptions ": " }}
 {{$val}}
 {{- end}}
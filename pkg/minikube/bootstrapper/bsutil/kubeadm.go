@@ -66,7 +66,11 @@ func GenerateKubeadmYAML(cc config.ClusterConfig, n config.Node, r cruntime.Mana
 		return nil, errors.Wrap(err, "getting cgroup driver")
 	}
 
-	componentOpts, err := createExtraComponentConfig(k8s.ExtraOptions, version, componentFeatureArgs, cp)
+	certSANs := []string{"127.0.0.1", "localhost", cp.IP}
+	if k8s.APIServerHAVIP != "" {
+		certSANs = append(certSANs, k8s.APIServerHAVIP)
+	}
+	componentOpts, err := createExtraComponentConfig(k8s.ExtraOptions, version, componentFeatureArgs, certSANs)
 	if err != nil {
 		return nil, errors.Wrap(err, "generating extra component config for kubeadm")
 	}
@@ -106,6 +110,8 @@ func GenerateKubeadmYAML(cc config.ClusterConfig, n config.Node, r cruntime.Mana
 		StaticPodPath       string
 		ControlPlaneAddress string
 		KubeProxyOptions    map[string]string
+		// SkipConntrackSysctls leaves the conntrack table of the host alone, which rootless container nodes can't tune
+		SkipConntrackSysctls bool
 	}{
 		CertDir:           vmpath.GuestKubernetesCertsDir,
 		ServiceCIDR:       constants.DefaultServiceCIDR,
@@ -117,19 +123,20 @@ func GenerateKubeadmYAML(cc config.ClusterConfig, n config.Node, r cruntime.Mana
 		EtcdExtraArgs:     etcdExtraArgs(k8s.ExtraOptions),
 		ClusterName:       cc.Name,
 		// kubeadm uses NodeName as the --hostname-override parameter, so this needs to be the name of the machine
-		NodeName:            KubeNodeName(cc, n),
-		CRISocket:           r.SocketPath(),
-		ImageRepository:     k8s.ImageRepository,
-		ComponentOptions:    componentOpts,
-		FeatureArgs:         kubeadmFeatureArgs,
-		NoTaintMaster:       false, // That does not work with k8s 1.12+
-		DNSDomain:           k8s.DNSDomain,
-		NodeIP:              n.IP,
-		CgroupDriver:        cgroupDriver,
-		ClientCAFile:        path.Join(vmpath.GuestKubernetesCertsDir, "ca.crt"),
-		StaticPodPath:       vmpath.GuestManifestsDir,
-		ControlPlaneAddress: constants.ControlPlaneAlias,
-		KubeProxyOptions:    createKubeProxyOptions(k8s.ExtraOptions),
+		NodeName:             KubeNodeName(cc, n),
+		CRISocket:            r.SocketPath(),
+		ImageRepository:      k8s.ImageRepository,
+		ComponentOptions:     componentOpts,
+		FeatureArgs:          kubeadmFeatureArgs,
+		NoTaintMaster:        false, // That does not work with k8s 1.12+
+		DNSDomain:            k8s.DNSDomain,
+		NodeIP:               n.IP,
+		CgroupDriver:         cgroupDriver,
+		ClientCAFile:         path.Join(vmpath.GuestKubernetesCertsDir, "ca.crt"),
+		StaticPodPath:        vmpath.GuestManifestsDir,
+		ControlPlaneAddress:  constants.ControlPlaneAlias,
+		KubeProxyOptions:     createKubeProxyOptions(k8s.ExtraOptions),
+		SkipConntrackSysctls: cc.Rootless,
 	}
 
 	if k8s.ServiceCIDR != "" {
@@ -19,9 +19,12 @@ package bsutil
 
 import (
 	"bytes"
+	"fmt"
 	"os"
 	"path"
+	"strings"
 
+	"github.com/blang/semver"
 	"github.com/pkg/errors"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/ktmpl"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
@@ -32,6 +35,9 @@ import (
 	"k8s.io/minikube/pkg/util"
 )
 
+// kubeletInUserNamespace is the feature gate which lets the kubelet ignore errors from setting sysctls and rlimits in a user namespace
+const kubeletInUserNamespace = "KubeletInUserNamespace"
+
 func extraKubeletOpts(mc config.ClusterConfig, nc config.Node, r cruntime.Manager) (map[string]string, error) {
 	k8s := mc.KubernetesConfig
 	version, err := util.ParseKubernetesVersion(k8s.KubernetesVersion)
@@ -68,12 +74,22 @@ func extraKubeletOpts(mc config.ClusterConfig, nc config.Node, r cruntime.Manage
 		extraOpts["pod-infra-container-image"] = pauseImage
 	}
 
+	// the reservation follows the CPUs and memory of the node, which resizing the cluster changes
+	if _, ok := extraOpts["kube-reserved"]; !ok && mc.ReserveResources && !driver.BareMetal(mc.Driver) && mc.CPUs > 0 && mc.Memory > 0 {
+		extraOpts["kube-reserved"] = kubeReserved(mc.CPUs, mc.Memory)
+	}
+
 	// parses a map of the feature gates for kubelet
 	_, kubeletFeatureArgs, err := parseFeatureArgs(k8s.FeatureGates)
 	if err != nil {
 		return nil, errors.Wrap(err, "parses feature gate config for kubelet")
 	}
 
+	// lets the kubelet run within the user namespace of a rootless container
+	if mc.Rootless && version.GTE(semver.MustParse("1.22.0-alpha.0")) && !strings.Contains(k8s.FeatureGates, kubeletInUserNamespace) {
+		kubeletFeatureArgs = strings.TrimLeft(kubeletFeatureArgs+","+kubeletInUserNamespace+"=true", ",")
+	}
+
 	if kubeletFeatureArgs != "" {
 		extraOpts["feature-gates"] = kubeletFeatureArgs
 	}
@@ -81,6 +97,16 @@ func extraKubeletOpts(mc config.ClusterConfig, nc config.Node, r cruntime.Manage
 	return extraOpts, nil
 }
 
+// kubeReserved returns the CPUs and memory of a node the kubelet reserves for the system: 6% of the first CPU
+// and 1% of the others, and 5% of the memory, at least 100MB. The eviction thresholds are the ones of the kubelet config.
+func kubeReserved(cpus int, memoryMB int) string {
+	mem := memoryMB * 5 / 100
+	if mem < 100 {
+		mem = 100
+	}
+	return fmt.Sprintf("cpu=%dm,memory=%dMi", 60+10*(cpus-1), mem)
+}
+
 // NewKubeletConfig generates a new systemd unit containing a configured kubelet
 // based on the options present in the KubernetesConfig.
 func NewKubeletConfig(mc config.ClusterConfig, nc config.Node, r cruntime.Manager) ([]byte, error) {
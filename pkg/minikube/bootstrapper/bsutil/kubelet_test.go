@@ -203,3 +203,99 @@ ExecStart=/var/lib/minikube/binaries/v1.18.2/kubelet --authorization-mode=Webhoo
 		})
 	}
 }
+
+func TestExtraKubeletOptsUserNamespace(t *testing.T) {
+	tests := []struct {
+		description  string
+		driver       string
+		rootless     bool
+		version      string
+		featureGates string
+		expected     string
+	}{
+		{"vm", "kvm2", false, "v1.22.0", "", ""},
+		{"rootful kic", "docker", false, "v1.22.0", "", ""},
+		{"rootless before the feature gate", "docker", true, constants.DefaultKubernetesVersion, "", ""},
+		{"rootless", "podman", true, "v1.22.0", "", "KubeletInUserNamespace=true"},
+		{"rootless with other gates", "docker", true, "v1.22.0", "EphemeralContainers=true", "EphemeralContainers=true,KubeletInUserNamespace=true"},
+		{"rootless with gate disabled", "docker", true, "v1.22.0", "KubeletInUserNamespace=false", "KubeletInUserNamespace=false"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			cfg := config.ClusterConfig{
+				Name:     "minikube",
+				Driver:   tc.driver,
+				Rootless: tc.rootless,
+				KubernetesConfig: config.KubernetesConfig{
+					KubernetesVersion: tc.version,
+					ContainerRuntime:  "docker",
+					FeatureGates:      tc.featureGates,
+				},
+				Nodes: []config.Node{{IP: "192.168.49.2", Name: "minikube", ControlPlane: true}},
+			}
+			runtime, err := cruntime.New(cruntime.Config{Type: "docker"})
+			if err != nil {
+				t.Fatalf("runtime: %v", err)
+			}
+			opts, err := extraKubeletOpts(cfg, cfg.Nodes[0], runtime)
+			if err != nil {
+				t.Fatalf("extraKubeletOpts: %v", err)
+			}
+			if opts["feature-gates"] != tc.expected {
+				t.Errorf("feature-gates = %q, want %q", opts["feature-gates"], tc.expected)
+			}
+		})
+	}
+}
+
+func TestExtraKubeletOptsReservations(t *testing.T) {
+	tests := []struct {
+		description  string
+		driver       string
+		cpus         int
+		memory       int
+		reserve      bool
+		extra        config.ExtraOptionSlice
+		kubeReserved string
+	}{
+		{"default", "docker", 2, 2200, false, nil, ""},
+		{"opted in", "docker", 2, 2200, true, nil, "cpu=70m,memory=110Mi"},
+		{"resized", "docker", 8, 16384, true, nil, "cpu=130m,memory=819Mi"},
+		{"small", "kvm2", 1, 1024, true, nil, "cpu=60m,memory=100Mi"},
+		{"extra config", "docker", 2, 4096, true, config.ExtraOptionSlice{{Component: Kubelet, Key: "kube-reserved", Value: "cpu=1"}}, "cpu=1"},
+		{"bare metal", "none", 2, 4096, true, nil, ""},
+		{"unknown size", "docker", 0, 0, true, nil, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			cfg := config.ClusterConfig{
+				Name:             "minikube",
+				Driver:           tc.driver,
+				CPUs:             tc.cpus,
+				Memory:           tc.memory,
+				ReserveResources: tc.reserve,
+				KubernetesConfig: config.KubernetesConfig{
+					KubernetesVersion: constants.DefaultKubernetesVersion,
+					ContainerRuntime:  "docker",
+					ExtraOptions:      tc.extra,
+				},
+				Nodes: []config.Node{{IP: "192.168.49.2", Name: "minikube", ControlPlane: true}},
+			}
+			runtime, err := cruntime.New(cruntime.Config{Type: "docker"})
+			if err != nil {
+				t.Fatalf("runtime: %v", err)
+			}
+			opts, err := extraKubeletOpts(cfg, cfg.Nodes[0], runtime)
+			if err != nil {
+				t.Fatalf("extraKubeletOpts: %v", err)
+			}
+			if opts["kube-reserved"] != tc.kubeReserved {
+				t.Errorf("kube-reserved = %q, want %q", opts["kube-reserved"], tc.kubeReserved)
+			}
+			// the eviction thresholds of the kubelet config are left alone
+			if v, ok := opts["eviction-hard"]; ok {
+				t.Errorf("eviction-hard = %q, want the one of the kubelet config", v)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bsutil
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/ktmpl"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+	"k8s.io/minikube/pkg/util"
+)
+
+// KubeVipManifestPath is the path to the static pod manifest of kube-vip
+var KubeVipManifestPath = path.Join(vmpath.GuestManifestsDir, "kube-vip.yaml")
+
+// VirtualIP allocates the virtual IP of the control plane nodes of an HA cluster: the last free address of the
+// range of the subnet reserved for virtual IPs, or of the /24 network of the primary control plane if there is no subnet.
+// taken are the addresses used by other clusters, which may share the network.
+func VirtualIP(nodeIP string, subnet string, taken []string) (string, error) {
+	ip := net.ParseIP(nodeIP).To4()
+	if ip == nil {
+		return "", fmt.Errorf("failed to parse IPv4 address %q", nodeIP)
+	}
+	network := &net.IPNet{IP: ip.Mask(net.CIDRMask(24, 32)), Mask: net.CIDRMask(24, 32)}
+	if subnet != "" {
+		_, n, err := net.ParseCIDR(subnet)
+		if err != nil {
+			return "", errors.Wrapf(err, "parsing subnet %q", subnet)
+		}
+		network = n
+	}
+	if !network.Contains(ip) {
+		return "", fmt.Errorf("%s is not in subnet %s", nodeIP, network)
+	}
+	first, last, err := util.VirtualIPRange(network)
+	if err != nil {
+		return "", err
+	}
+
+	used := map[string]bool{ip.String(): true}
+	for _, t := range taken {
+		used[t] = true
+	}
+	for n := binary.BigEndian.Uint32(last); n >= binary.BigEndian.Uint32(first); n-- {
+		vip := make(net.IP, 4)
+		binary.BigEndian.PutUint32(vip, n)
+		if !used[vip.String()] {
+			return vip.String(), nil
+		}
+	}
+	return "", fmt.Errorf("all the virtual IPs of subnet %s, %s to %s, are taken", network, first, last)
+}
+
+// GenerateKubeVipManifest generates the static pod manifest of kube-vip, announcing the virtual IP on iface
+func GenerateKubeVipManifest(cc config.ClusterConfig, iface string) ([]byte, error) {
+	if cc.KubernetesConfig.APIServerHAVIP == "" {
+		return nil, errors.New("the cluster has no virtual IP")
+	}
+	cp, err := config.PrimaryControlPlane(&cc)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting control plane")
+	}
+	port := cp.Port
+	if port <= 0 {
+		port = constants.APIServerPort
+	}
+
+	opts := struct {
+		Image          string
+		Interface      string
+		VIP            string
+		Port           int
+		KubeconfigPath string
+		CertDir        string
+	}{
+		Image:          images.KubeVip(cc.KubernetesConfig.ImageRepository),
+		Interface:      iface,
+		VIP:            cc.KubernetesConfig.APIServerHAVIP,
+		Port:           port,
+		KubeconfigPath: path.Join(vmpath.GuestPersistentDir, "kubeconfig"),
+		CertDir:        vmpath.GuestKubernetesCertsDir,
+	}
+
+	var b bytes.Buffer
+	if err := ktmpl.KubeVipTemplate.Execute(&b, opts); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// InterfaceForIP returns the interface holding ip from the output of "ip -o -4 addr show"
+func InterfaceForIP(addrs string, ip string) (string, error) {
+	for _, line := range strings.Split(addrs, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[2] != "inet" {
+			continue
+		}
+		if strings.Split(fields[3], "/")[0] == ip {
+			return strings.Split(fields[1], "@")[0], nil
+		}
+	}
+	return "", fmt.Errorf("no interface has the address %s", ip)
+}
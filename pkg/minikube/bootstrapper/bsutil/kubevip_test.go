@@ -0,0 +1,102 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bsutil
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestVirtualIP(t *testing.T) {
+	tests := []struct {
+		description string
+		nodeIP      string
+		subnet      string
+		taken       []string
+		expected    string
+		err         bool
+	}{
+		{description: "default network", nodeIP: "192.168.49.2", expected: "192.168.49.254"},
+		{description: "custom subnet", nodeIP: "10.10.0.5", subnet: "10.10.0.0/16", expected: "10.10.255.254"},
+		{description: "node at the end of the subnet", nodeIP: "172.16.5.126", subnet: "172.16.5.64/26", expected: "172.16.5.125"},
+		{description: "taken by another cluster", nodeIP: "192.168.39.10", taken: []string{"192.168.39.254", "192.168.39.253"}, expected: "192.168.39.252"},
+		{description: "all taken", nodeIP: "172.16.5.66", subnet: "172.16.5.64/29", taken: []string{"172.16.5.70"}, err: true},
+		{description: "node outside of the subnet", nodeIP: "192.168.49.2", subnet: "10.10.0.0/16", err: true},
+		{description: "subnet too small", nodeIP: "10.0.0.1", subnet: "10.0.0.0/30", err: true},
+		{description: "invalid IP", nodeIP: "", err: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			got, err := VirtualIP(tc.nodeIP, tc.subnet, tc.taken)
+			if (err != nil) != tc.err {
+				t.Fatalf("VirtualIP(%q, %q) error = %v, want error %v", tc.nodeIP, tc.subnet, err, tc.err)
+			}
+			if got != tc.expected {
+				t.Errorf("VirtualIP(%q, %q) = %q, want %q", tc.nodeIP, tc.subnet, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestInterfaceForIP(t *testing.T) {
+	addrs := `1: lo    inet 127.0.0.1/8 scope host lo\       valid_lft forever preferred_lft forever
+2: eth0    inet 192.168.122.10/24 brd 192.168.122.255 scope global dynamic eth0\       valid_lft 3570sec preferred_lft 3570sec
+3: eth1    inet 192.168.39.12/24 brd 192.168.39.255 scope global dynamic eth1\       valid_lft 3570sec preferred_lft 3570sec
+`
+	got, err := InterfaceForIP(addrs, "192.168.39.12")
+	if err != nil {
+		t.Fatalf("InterfaceForIP() error = %v", err)
+	}
+	if got != "eth1" {
+		t.Errorf("InterfaceForIP() = %q, want %q", got, "eth1")
+	}
+	if _, err := InterfaceForIP(addrs, "192.168.39.1"); err == nil {
+		t.Errorf("InterfaceForIP() of a missing address returned no error")
+	}
+}
+
+func TestGenerateKubeVipManifest(t *testing.T) {
+	cc := config.ClusterConfig{
+		Nodes:            []config.Node{{ControlPlane: true, IP: "192.168.49.2", Port: 8443}},
+		KubernetesConfig: config.KubernetesConfig{APIServerHAVIP: "192.168.49.254"},
+	}
+	got, err := GenerateKubeVipManifest(cc, "eth0")
+	if err != nil {
+		t.Fatalf("GenerateKubeVipManifest() error = %v", err)
+	}
+	for _, want := range []string{"image: docker.io/plndr/kube-vip:0.3.1", "value: eth0", "value: 192.168.49.254", `value: "8443"`, "path: /var/lib/minikube/kubeconfig"} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("manifest does not contain %q:\n%s", want, got)
+		}
+	}
+
+	cc.KubernetesConfig.ImageRepository = "mirror.k8s.io"
+	got, err = GenerateKubeVipManifest(cc, "eth0")
+	if err != nil {
+		t.Fatalf("GenerateKubeVipManifest() error = %v", err)
+	}
+	if want := "image: mirror.k8s.io/kube-vip:0.3.1"; !strings.Contains(string(got), want) {
+		t.Errorf("manifest does not contain %q:\n%s", want, got)
+	}
+
+	cc.KubernetesConfig.APIServerHAVIP = ""
+	if _, err := GenerateKubeVipManifest(cc, "eth0"); err == nil {
+		t.Errorf("GenerateKubeVipManifest() without a virtual IP returned no error")
+	}
+}
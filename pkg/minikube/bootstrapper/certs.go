@@ -202,6 +202,9 @@ func generateProfileCerts(k8s config.KubernetesConfig, n config.Node, ccs CACert
 	if v := oci.DaemonHost(k8s.ContainerRuntime); v != oci.DefaultBindIPV4 {
 		apiServerIPs = append(apiServerIPs, net.ParseIP(v))
 	}
+	if k8s.APIServerHAVIP != "" {
+		apiServerIPs = append(apiServerIPs, net.ParseIP(k8s.APIServerHAVIP))
+	}
 
 	apiServerNames := append(k8s.APIServerNames, k8s.APIServerName, constants.ControlPlaneAlias)
 	apiServerAlternateNames := append(
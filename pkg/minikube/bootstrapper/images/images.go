@@ -34,7 +34,7 @@ func Pause(v semver.Version, mirror string) string {
 	if semver.MustParseRange("<1.18.0-alpha.0")(v) {
 		pv = "3.1"
 	}
-	return path.Join(kubernetesRepo(mirror), "pause:"+pv)
+	return path.Join(KubernetesRepo(mirror), "pause:"+pv)
 }
 
 // essentials returns images needed too bootstrap a Kubernetes
@@ -53,7 +53,7 @@ func essentials(mirror string, v semver.Version) []string {
 
 // componentImage returns a Kubernetes component image to pull
 func componentImage(name string, v semver.Version, mirror string) string {
-	return fmt.Sprintf("%s:v%s", path.Join(kubernetesRepo(mirror), name), v)
+	return fmt.Sprintf("%s:v%s", path.Join(KubernetesRepo(mirror), name), v)
 }
 
 // coreDNS returns the images used for CoreDNS
@@ -77,7 +77,7 @@ func coreDNS(v semver.Version, mirror string) string {
 	case 11:
 		cv = "1.1.3"
 	}
-	return path.Join(kubernetesRepo(mirror), "coredns:"+cv)
+	return path.Join(KubernetesRepo(mirror), "coredns:"+cv)
 }
 
 // etcd returns the image used for etcd
@@ -104,7 +104,7 @@ func etcd(v semver.Version, mirror string) string {
 		ev = "3.4.9-1"
 	}
 
-	return path.Join(kubernetesRepo(mirror), "etcd:"+ev)
+	return path.Join(KubernetesRepo(mirror), "etcd:"+ev)
 }
 
 // auxiliary returns images that are helpful for running minikube
@@ -113,6 +113,7 @@ func auxiliary(mirror string) []string {
 		storageProvisioner(mirror),
 		dashboardFrontend(mirror),
 		dashboardMetrics(mirror),
+		KubeVip(mirror),
 		// NOTE: kindnet is also used when the Docker driver is used with a non-Docker runtime
 	}
 }
@@ -148,6 +149,14 @@ func KindNet(repo string) string {
 	return path.Join(repo, "kindnetd:0.5.4")
 }
 
+// KubeVip returns the image of kube-vip, which holds the virtual IP of the control plane nodes of HA clusters
+func KubeVip(repo string) string {
+	if repo == "" {
+		repo = "docker.io/plndr"
+	}
+	return path.Join(repo, "kube-vip:0.3.1")
+}
+
 // CalicoDaemonSet returns the image used for calicoDaemonSet
 func CalicoDaemonSet(repo string) string {
 	if repo == "" {
@@ -27,6 +27,7 @@ func TestAuxiliary(t *testing.T) {
 		"gcr.io/k8s-minikube/storage-provisioner:v4",
 		"docker.io/kubernetesui/dashboard:v2.1.0",
 		"docker.io/kubernetesui/metrics-scraper:v1.0.4",
+		"docker.io/plndr/kube-vip:0.3.1",
 	}
 	got := auxiliary("")
 	if diff := cmp.Diff(want, got); diff != "" {
@@ -39,6 +40,7 @@ func TestAuxiliaryMirror(t *testing.T) {
 		"test.mirror/storage-provisioner:v4",
 		"test.mirror/dashboard:v2.1.0",
 		"test.mirror/metrics-scraper:v1.0.4",
+		"test.mirror/kube-vip:0.3.1",
 	}
 	got := auxiliary("test.mirror")
 	if diff := cmp.Diff(want, got); diff != "" {
@@ -19,8 +19,8 @@ package images
 // DefaultKubernetesRepo is the default Kubernetes repository
 const DefaultKubernetesRepo = "k8s.gcr.io"
 
-// kubernetesRepo returns the official Kubernetes repository, or an alternate
-func kubernetesRepo(mirror string) string {
+// KubernetesRepo returns the official Kubernetes repository, or an alternate such as the image repository of a cluster
+func KubernetesRepo(mirror string) string {
 	if mirror != "" {
 		return mirror
 	}
@@ -230,6 +230,14 @@ func (k *Bootstrapper) init(cfg config.ClusterConfig) error {
 		return errors.Wrap(err, "clearing stale configs")
 	}
 
+	cp, err := config.PrimaryControlPlane(&cfg)
+	if err != nil {
+		return errors.Wrap(err, "primary control plane")
+	}
+	if err := k.applyKubeVip(cfg, cp); err != nil {
+		return errors.Wrap(err, "kube-vip")
+	}
+
 	conf := bsutil.KubeadmYamlPath
 	ctx, cancel := context.WithTimeout(context.Background(), initTimeoutMinutes*time.Minute)
 	defer cancel()
@@ -604,7 +612,7 @@ func (k *Bootstrapper) restartControlPlane(cfg config.ClusterConfig) error {
 	}
 
 	// Save the costly tax of reinstalling Kubernetes if the only issue is a missing kube context
-	_, err = kubeconfig.UpdateEndpoint(cfg.Name, hostname, port, kubeconfig.PathFromEnv(), kubeconfig.NewExtension())
+	_, err = kubeconfig.UpdateEndpoint(cfg.Name, hostname, port, kubeconfig.PathFromConfig(&cfg), kubeconfig.NewExtension())
 	if err != nil {
 		klog.Warningf("unable to update kubeconfig (cluster will likely require a reset): %v", err)
 	}
@@ -658,6 +666,10 @@ func (k *Bootstrapper) restartControlPlane(cfg config.ClusterConfig) error {
 		}
 	}
 
+	if err := k.applyKubeVip(cfg, cp); err != nil {
+		return errors.Wrap(err, "kube-vip")
+	}
+
 	cr, err := cruntime.New(cruntime.Config{Type: cfg.KubernetesConfig.ContainerRuntime, Runner: k.c})
 	if err != nil {
 		return errors.Wrap(err, "runtime")
@@ -709,8 +721,21 @@ func (k *Bootstrapper) JoinCluster(cc config.ClusterConfig, n config.Node, joinC
 		klog.Infof("JoinCluster complete in %s", time.Since(start))
 	}()
 
+	if n.ControlPlane && k.joinedControlPlane() {
+		// resetting it would remove its etcd member, which the cluster may need for a quorum
+		klog.Infof("%s already is a control plane node, restarting kubelet", config.MachineName(cc, n))
+		return k.startKubelet()
+	}
+
 	// Join the master by specifying its token
 	joinCmd = fmt.Sprintf("%s --node-name=%s", joinCmd, config.MachineName(cc, n))
+	if n.ControlPlane {
+		port := n.Port
+		if port <= 0 {
+			port = constants.APIServerPort
+		}
+		joinCmd = fmt.Sprintf("%s --apiserver-advertise-address=%s --apiserver-bind-port=%d", joinCmd, n.IP, port)
+	}
 
 	join := func() error {
 		// reset first to clear any possibly existing state
@@ -719,6 +744,13 @@ func (k *Bootstrapper) JoinCluster(cc config.ClusterConfig, n config.Node, joinC
 			klog.Infof("kubeadm reset failed, continuing anyway: %v", err)
 		}
 
+		if n.ControlPlane {
+			// kubeadm reset cleans up the certificates directory, the API server of the node needs them back
+			if err := k.SetupCerts(cc.KubernetesConfig, n); err != nil {
+				return errors.Wrap(err, "setting up certs")
+			}
+		}
+
 		_, err = k.c.RunCmd(exec.Command("/bin/bash", "-c", joinCmd))
 		if err != nil {
 			if strings.Contains(err.Error(), "status \"Ready\" already exists in the cluster") {
@@ -733,15 +765,54 @@ func (k *Bootstrapper) JoinCluster(cc config.ClusterConfig, n config.Node, joinC
 		return errors.Wrap(err, "joining cp")
 	}
 
+	if n.ControlPlane {
+		if err := k.applyKubeVip(cc, n); err != nil {
+			return errors.Wrap(err, "kube-vip")
+		}
+	}
+
+	return k.startKubelet()
+}
+
+// startKubelet enables and starts kubelet
+func (k *Bootstrapper) startKubelet() error {
 	if _, err := k.c.RunCmd(exec.Command("/bin/bash", "-c", "sudo systemctl daemon-reload && sudo systemctl enable kubelet && sudo systemctl start kubelet")); err != nil {
 		return errors.Wrap(err, "starting kubelet")
 	}
-
 	return nil
 }
 
-// GenerateToken creates a token and returns the appropriate kubeadm join command to run, or the already existing token
-func (k *Bootstrapper) GenerateToken(cc config.ClusterConfig) (string, error) {
+// joinedControlPlane returns true if kubeadm already joined the node as a control plane
+func (k *Bootstrapper) joinedControlPlane() bool {
+	_, err := k.c.RunCmd(exec.Command("sudo", "test", "-f", path.Join(vmpath.GuestManifestsDir, "etcd.yaml"), "-a", "-f", "/etc/kubernetes/kubelet.conf"))
+	return err == nil
+}
+
+// applyKubeVip writes the static pod of kube-vip on the control plane nodes of HA clusters, to hold the virtual IP of their API servers
+func (k *Bootstrapper) applyKubeVip(cfg config.ClusterConfig, n config.Node) error {
+	if cfg.KubernetesConfig.APIServerHAVIP == "" {
+		return nil
+	}
+
+	rr, err := k.c.RunCmd(exec.Command("ip", "-o", "-4", "addr", "show"))
+	if err != nil {
+		return errors.Wrap(err, "listing addresses")
+	}
+	iface, err := bsutil.InterfaceForIP(rr.Stdout.String(), n.IP)
+	if err != nil {
+		return err
+	}
+	manifest, err := bsutil.GenerateKubeVipManifest(cfg, iface)
+	if err != nil {
+		return errors.Wrap(err, "generating kube-vip manifest")
+	}
+	klog.Infof("kube-vip will hold %s on %s of %s", cfg.KubernetesConfig.APIServerHAVIP, iface, n.IP)
+	return bsutil.CopyFiles(k.c, []assets.CopyableFile{assets.NewMemoryAssetTarget(manifest, bsutil.KubeVipManifestPath, "0600")})
+}
+
+// GenerateToken creates a token and returns the appropriate kubeadm join command to run, or the already existing token.
+// The command joins n as a control plane if it is one, along with the key of the cluster certificates uploaded for it.
+func (k *Bootstrapper) GenerateToken(cc config.ClusterConfig, n config.Node) (string, error) {
 	// Take that generated token and use it to get a kubeadm join command
 	tokenCmd := exec.Command("/bin/bash", "-c", fmt.Sprintf("%s token create --print-join-command --ttl=0", bsutil.InvokeKubeadm(cc.KubernetesConfig.KubernetesVersion)))
 	r, err := k.c.RunCmd(tokenCmd)
@@ -756,9 +827,32 @@ func (k *Bootstrapper) GenerateToken(cc config.ClusterConfig) (string, error) {
 		joinCmd = fmt.Sprintf("%s --cri-socket %s", joinCmd, cc.KubernetesConfig.CRISocket)
 	}
 
+	if n.ControlPlane {
+		key, err := k.uploadCerts(cc)
+		if err != nil {
+			return "", errors.Wrap(err, "uploading certs")
+		}
+		joinCmd = fmt.Sprintf("%s --control-plane --certificate-key=%s", joinCmd, key)
+	}
+
 	return joinCmd, nil
 }
 
+// uploadCerts uploads the cluster certificates for control plane nodes to join, and returns the key they are encrypted with
+func (k *Bootstrapper) uploadCerts(cc config.ClusterConfig) (string, error) {
+	rr, err := k.c.RunCmd(exec.Command("/bin/bash", "-c", fmt.Sprintf("%s init phase upload-certs --upload-certs --config %s", bsutil.InvokeKubeadm(cc.KubernetesConfig.KubernetesVersion), bsutil.KubeadmYamlPath)))
+	if err != nil {
+		return "", err
+	}
+	// the key is the last line of the output
+	lines := strings.Split(strings.TrimSpace(rr.Stdout.String()), "\n")
+	key := strings.TrimSpace(lines[len(lines)-1])
+	if key == "" {
+		return "", errors.New("no certificate key in the kubeadm output")
+	}
+	return key, nil
+}
+
 // DeleteCluster removes the components that were started earlier
 func (k *Bootstrapper) DeleteCluster(k8s config.KubernetesConfig) error {
 	cr, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Runner: k.c, Socket: k8s.CRISocket})
@@ -899,7 +993,11 @@ func (k *Bootstrapper) UpdateNode(cfg config.ClusterConfig, n config.Node, r cru
 		return errors.Wrap(err, "control plane")
 	}
 
-	if err := machine.AddHostAlias(k.c, constants.ControlPlaneAlias, net.ParseIP(cp.IP)); err != nil {
+	cpIP := cp.IP
+	if cfg.KubernetesConfig.APIServerHAVIP != "" {
+		cpIP = cfg.KubernetesConfig.APIServerHAVIP
+	}
+	if err := machine.AddHostAlias(k.c, constants.ControlPlaneAlias, net.ParseIP(cpIP)); err != nil {
 		return errors.Wrap(err, "host alias")
 	}
 
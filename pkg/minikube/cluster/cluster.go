@@ -27,7 +27,9 @@ import (
 	"k8s.io/minikube/pkg/minikube/bootstrapper/kubeadm"
 	"k8s.io/minikube/pkg/minikube/command"
 	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/util"
 )
 
 // This init function is used to set the logtostderr variable to false so that INFO level log info does not clutter the CLI
@@ -72,3 +74,28 @@ func ControlPlaneBootstrapper(mAPI libmachine.API, cc *config.ClusterConfig, boo
 	bs, err := Bootstrapper(mAPI, bootstrapperName, *cc, cpr)
 	return bs, cpr, err
 }
+
+// KubeletReconfigurer returns how resizing a cluster regenerates the kubelet configuration of its nodes with a bootstrapper,
+// as the reservations of the kubelet follow the CPUs and memory of the nodes
+func KubeletReconfigurer(api libmachine.API, bootstrapperName string) machine.KubeletReconfigurer {
+	return func(cc config.ClusterConfig, n config.Node, r command.Runner) error {
+		bs, err := Bootstrapper(api, bootstrapperName, cc, r)
+		if err != nil {
+			return errors.Wrap(err, "bootstrapper")
+		}
+		kv, err := util.ParseKubernetesVersion(n.KubernetesVersion)
+		if err != nil {
+			return errors.Wrap(err, "parse kubernetes version")
+		}
+		cr, err := cruntime.New(cruntime.Config{
+			Type:              cc.KubernetesConfig.ContainerRuntime,
+			Runner:            r,
+			ImageRepository:   cc.KubernetesConfig.ImageRepository,
+			KubernetesVersion: kv,
+		})
+		if err != nil {
+			return errors.Wrap(err, "runtime")
+		}
+		return bs.UpdateNode(cc, n, cr)
+	}
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/drivers/qemu"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/machine"
 )
@@ -40,6 +41,8 @@ func HostIP(host *host.Host, clusterName string) (net.IP, error) {
 		return oci.RoutableHostIPFromInside(oci.Docker, clusterName, host.Name)
 	case driver.Podman:
 		return oci.RoutableHostIPFromInside(oci.Podman, clusterName, host.Name)
+	case driver.Nerdctl:
+		return oci.RoutableHostIPFromInside(oci.Nerdctl, clusterName, host.Name)
 	case driver.SSH:
 		ip, err := host.Driver.GetIP()
 		if err != nil {
@@ -48,6 +51,24 @@ func HostIP(host *host.Host, clusterName string) (net.IP, error) {
 		return net.ParseIP(ip), nil
 	case driver.KVM2:
 		return net.ParseIP("192.168.39.1"), nil
+	case driver.QEMU:
+		d, ok := host.Driver.(*qemu.Driver)
+		if !ok {
+			return []byte{}, fmt.Errorf("unexpected driver type %T", host.Driver)
+		}
+		if d.Network != qemu.NetworkSocketVMnet {
+			return net.ParseIP(qemu.UserNetworkHostIP), nil
+		}
+		ip, err := d.GetIP()
+		if err != nil {
+			return []byte{}, errors.Wrap(err, "Error getting VM IP address")
+		}
+		// the vmnet gateway is the host
+		vmIP := net.ParseIP(ip).To4()
+		if vmIP == nil {
+			return []byte{}, fmt.Errorf("unable to parse the VM IP address %q", ip)
+		}
+		return net.IPv4(vmIP[0], vmIP[1], vmIP[2], byte(1)), nil
 	case driver.HyperV:
 		v := reflect.ValueOf(host.Driver).Elem()
 		var hypervVirtualSwitch string
@@ -234,8 +234,11 @@ func isSnapBinary() bool {
 
 func (k *kicRunner) copy(src string, dst string) error {
 	fullDest := fmt.Sprintf("%s:%s", k.nameOrID, dst)
-	if k.ociBin == oci.Podman {
+	switch k.ociBin {
+	case oci.Podman:
 		return copyToPodman(src, fullDest)
+	case oci.Nerdctl:
+		return copyToNerdctl(src, fullDest)
 	}
 	return copyToDocker(src, fullDest)
 }
@@ -279,6 +282,16 @@ func copyToDocker(src string, dest string) error {
 	return nil
 }
 
+// Nerdctl cp command doesn't have -a, the copied file keeps the host ownership
+func copyToNerdctl(src string, dest string) error {
+	cmd := oci.PrefixCmd(exec.Command(oci.Nerdctl, "cp", src, dest))
+	klog.Infof("Run: %v", cmd)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "nerdctl copy %s into %s, output: %s", src, dest, string(out))
+	}
+	return nil
+}
+
 // Remove removes a file
 func (k *kicRunner) Remove(f assets.CopyableFile) error {
 	dst := path.Join(f.GetTargetDir(), f.GetTargetName())
@@ -54,6 +54,8 @@ const (
 	AddonImages = "addon-images"
 	// AddonRegistries stores custom addon images config
 	AddonRegistries = "addon-registries"
+	// Rootless is the key for running the podman driver against a rootless podman
+	Rootless = "rootless"
 )
 
 var (
@@ -217,9 +219,27 @@ func (c *simpleConfigLoader) LoadConfigFromFile(profileName string, miniHome ...
 	if err := json.Unmarshal(data, &cc); err != nil {
 		return nil, errors.Wrap(err, "unmarshal")
 	}
+	migrateSSHConfig(&cc)
 	return &cc, nil
 }
 
+// migrateSSHConfig moves the ssh driver settings of profiles from 1.17 or earlier to their only node
+func migrateSSHConfig(cc *ClusterConfig) {
+	if cc.SSHIPAddress == "" || len(cc.Nodes) != 1 || cc.Nodes[0].SSHIPAddress != "" {
+		return
+	}
+	cc.Nodes[0].SSHIPAddress = cc.SSHIPAddress
+	cc.Nodes[0].SSHUser = cc.SSHUser
+	cc.Nodes[0].SSHKey = cc.SSHKey
+	cc.Nodes[0].SSHPort = cc.SSHPort
+
+	// Remove old style attribute to avoid confusion
+	cc.SSHIPAddress = ""
+	cc.SSHUser = ""
+	cc.SSHKey = ""
+	cc.SSHPort = 0
+}
+
 func (c *simpleConfigLoader) WriteConfigToFile(profileName string, cc *ClusterConfig, miniHome ...string) error {
 	path := profileFilePath(profileName, miniHome...)
 	contents, err := json.MarshalIndent(cc, "", "	")
@@ -233,6 +233,30 @@ func TestMachineName(t *testing.T) {
 			},
 			Want: "p2-m2",
 		},
+
+		{
+			ClusterConfig: ClusterConfig{Name: "ha",
+				Nodes: []Node{
+					{
+						Name:              "",
+						IP:                "172.17.0.3",
+						Port:              8443,
+						KubernetesVersion: "v1.19.2",
+						ControlPlane:      true,
+						Worker:            true,
+					},
+					{
+						Name:              "m02",
+						IP:                "172.17.0.4",
+						Port:              8443,
+						KubernetesVersion: "v1.19.2",
+						ControlPlane:      true,
+						Worker:            true,
+					},
+				},
+			},
+			Want: "ha-m02",
+		},
 	}
 
 	for _, tc := range testsCases {
@@ -242,3 +266,30 @@ func TestMachineName(t *testing.T) {
 		}
 	}
 }
+
+func TestMigrateSSHConfig(t *testing.T) {
+	cc := ClusterConfig{
+		Name:         "p1",
+		Driver:       "ssh",
+		SSHIPAddress: "192.168.1.10",
+		SSHUser:      "root",
+		SSHKey:       "/home/user/.ssh/id_rsa",
+		SSHPort:      22,
+		Nodes:        []Node{{Name: "", ControlPlane: true, Worker: true}},
+	}
+	migrateSSHConfig(&cc)
+
+	want := Node{Name: "", ControlPlane: true, Worker: true, SSHIPAddress: "192.168.1.10", SSHUser: "root", SSHKey: "/home/user/.ssh/id_rsa", SSHPort: 22}
+	if !reflect.DeepEqual(cc.Nodes[0], want) {
+		t.Errorf("Expected node to be %+v but got %+v", want, cc.Nodes[0])
+	}
+	if cc.SSHIPAddress != "" || cc.SSHUser != "" || cc.SSHKey != "" || cc.SSHPort != 0 {
+		t.Errorf("Expected the cluster ssh settings to be cleared but got %+v", cc)
+	}
+
+	// migrating again doesn't change anything
+	migrateSSHConfig(&cc)
+	if !reflect.DeepEqual(cc.Nodes[0], want) {
+		t.Errorf("Expected node to be %+v but got %+v", want, cc.Nodes[0])
+	}
+}
@@ -82,6 +82,52 @@ func PrimaryControlPlane(cc *ClusterConfig) (Node, error) {
 	return cp, nil
 }
 
+// ControlPlanes returns the control plane nodes of the cluster, the primary one first
+func ControlPlanes(cc ClusterConfig) []Node {
+	var cps []Node
+	for _, n := range cc.Nodes {
+		if n.ControlPlane {
+			cps = append(cps, n)
+		}
+	}
+	return cps
+}
+
+// IsPrimaryControlPlane returns true if n is the node the cluster was initialized on
+func IsPrimaryControlPlane(cc ClusterConfig, n Node) bool {
+	for _, cp := range cc.Nodes {
+		if cp.ControlPlane {
+			return cp.Name == n.Name
+		}
+	}
+	return false
+}
+
+// isSecondaryControlPlane returns true if n is a control plane joined to the primary one of an HA cluster
+func isSecondaryControlPlane(cc ClusterConfig, n Node) bool {
+	cps := ControlPlanes(cc)
+	return n.ControlPlane && len(cps) > 0 && cps[0].Name != n.Name
+}
+
+// StopOrder returns the nodes in the order to stop them: the workers first and the primary control plane last,
+// so that the API server stays reachable while the other nodes shut down
+func StopOrder(cc ClusterConfig) []Node {
+	var workers, cps []Node
+	for _, n := range cc.Nodes {
+		if n.ControlPlane {
+			cps = append([]Node{n}, cps...)
+		} else {
+			workers = append(workers, n)
+		}
+	}
+	return append(workers, cps...)
+}
+
+// Quorum returns the number of control plane nodes that must be up for etcd to accept writes
+func Quorum(cc ClusterConfig) int {
+	return len(ControlPlanes(cc))/2 + 1
+}
+
 // ProfileNameValid checks if the profile name is container name and DNS hostname/label friendly.
 func ProfileNameValid(name string) bool {
 	// RestrictedNamePattern describes the characters allowed to represent a profile's name
@@ -328,8 +374,19 @@ func ProfileFolderPath(profile string, miniHome ...string) string {
 // MachineName returns the name of the machine, as seen by the hypervisor given the cluster and node names
 func MachineName(cc ClusterConfig, n Node) string {
 	// For single node cluster, default to back to old naming
-	if (len(cc.Nodes) == 1 && cc.Nodes[0].Name == n.Name) || n.ControlPlane {
+	if (len(cc.Nodes) == 1 && cc.Nodes[0].Name == n.Name) || (n.ControlPlane && !isSecondaryControlPlane(cc, n)) {
 		return cc.Name
 	}
 	return fmt.Sprintf("%s-%s", cc.Name, n.Name)
 }
+
+// ReservedIPs returns the static IPs of the nodes of a cluster other than n, which the IP computed for n must not take
+func ReservedIPs(cc ClusterConfig, n Node) []string {
+	var ips []string
+	for _, o := range cc.Nodes {
+		if o.Name != n.Name && o.StaticIP != "" {
+			ips = append(ips, o.StaticIP)
+		}
+	}
+	return ips
+}
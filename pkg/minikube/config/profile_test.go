@@ -20,6 +20,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/spf13/viper"
@@ -339,3 +340,50 @@ func TestGetPrimaryControlPlane(t *testing.T) {
 		}()
 	}
 }
+
+func TestStopOrder(t *testing.T) {
+	cc := ClusterConfig{Nodes: []Node{
+		{Name: "", ControlPlane: true},
+		{Name: "m02", ControlPlane: true},
+		{Name: "m03", ControlPlane: true},
+		{Name: "m04"},
+	}}
+
+	var names []string
+	for _, n := range StopOrder(cc) {
+		names = append(names, n.Name)
+	}
+	want := []string{"m04", "m03", "m02", ""}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("StopOrder() = %q, want %q", names, want)
+	}
+
+	if q := Quorum(cc); q != 2 {
+		t.Errorf("Quorum() = %d, want 2", q)
+	}
+	if !IsPrimaryControlPlane(cc, cc.Nodes[0]) || IsPrimaryControlPlane(cc, cc.Nodes[1]) {
+		t.Errorf("IsPrimaryControlPlane() does not match the first control plane only")
+	}
+}
+
+func TestReservedIPs(t *testing.T) {
+	cc := ClusterConfig{Nodes: []Node{
+		{Name: "", ControlPlane: true, StaticIP: "192.168.50.10"},
+		{Name: "m02"},
+		{Name: "m03", StaticIP: "192.168.50.3"},
+	}}
+
+	tests := []struct {
+		node Node
+		want []string
+	}{
+		{cc.Nodes[0], []string{"192.168.50.3"}},
+		{cc.Nodes[1], []string{"192.168.50.10", "192.168.50.3"}},
+		{cc.Nodes[2], []string{"192.168.50.10"}},
+	}
+	for _, tc := range tests {
+		if got := ReservedIPs(cc, tc.node); strings.Join(got, ",") != strings.Join(tc.want, ",") {
+			t.Errorf("ReservedIPs(%q) = %q, want %q", tc.node.Name, got, tc.want)
+		}
+	}
+}
@@ -35,6 +35,7 @@ type ClusterConfig struct {
 	Name                    string
 	KeepContext             bool   // used by start and profile command to or not to switch kubectl's current context
 	EmbedCerts              bool   // used by kubeconfig.Setup
+	KubeconfigPath          string // kubeconfig to write to instead of $KUBECONFIG or ~/.kube/config
 	MinikubeISO             string // ISO used for VM-drivers.
 	KicBaseImage            string // base-image used for docker/podman drivers.
 	Memory                  int
@@ -48,6 +49,8 @@ type ClusterConfig struct {
 	ContainerVolumeMounts   []string // Only used by container drivers: Docker, Podman
 	InsecureRegistry        []string
 	RegistryMirror          []string
+	RegistryCA              []string // Each entry is formatted as REGISTRY=PATH.
+	RegistryAuthFromHost    bool
 	HostOnlyCIDR            string // Only used by the virtualbox driver
 	HypervVirtualSwitch     string
 	HypervUseExternalSwitch bool
@@ -66,10 +69,10 @@ type ClusterConfig struct {
 	HostDNSResolver         bool   // Only used by virtualbox
 	HostOnlyNicType         string // Only used by virtualbox
 	NatNicType              string // Only used by virtualbox
-	SSHIPAddress            string // Only used by ssh driver
-	SSHUser                 string // Only used by ssh driver
-	SSHKey                  string // Only used by ssh driver
-	SSHPort                 int    // Only used by ssh driver
+	SSHIPAddress            string // Deprecated: moved to Node, only read from older profiles
+	SSHUser                 string // Deprecated: moved to Node, only read from older profiles
+	SSHKey                  string // Deprecated: moved to Node, only read from older profiles
+	SSHPort                 int    // Deprecated: moved to Node, only read from older profiles
 	KubernetesConfig        KubernetesConfig
 	Nodes                   []Node
 	Addons                  map[string]bool
@@ -77,8 +80,17 @@ type ClusterConfig struct {
 	StartHostTimeout        time.Duration
 	ScheduledStop           *ScheduledStopConfig
 	ExposedPorts            []string // Only used by the docker and podman driver
-	Network                 string   // only used by docker driver
+	Network                 string   // only used by docker, podman and qemu drivers
+	SocketVMnetClientPath   string   // Only used by the qemu driver
+	SocketVMnetPath         string   // Only used by the qemu driver
 	MultiNodeRequested      bool
+	DriverOptions           map[string]string // Only used by driver plugins, keyed by the name of their start flags
+	ExtraDisks              int               // Only used by kvm2 and the docker, podman and nerdctl drivers
+	ExtraDiskSize           int               // in MB, only used along with ExtraDisks
+	Subnet                  string            // Only used by kvm2 and the docker, podman and nerdctl drivers
+	HA                      bool              // several control plane nodes behind a virtual IP
+	Rootless                bool              // Only used by the docker and podman drivers, whether their daemon runs as an unprivileged user
+	ReserveResources        bool              // whether the kubelet reserves CPUs and memory for the system, set once the cluster is resized
 }
 
 // KubernetesConfig contains the parameters used to configure the VM Kubernetes.
@@ -98,8 +110,10 @@ type KubernetesConfig struct {
 	ImageRepository     string
 	LoadBalancerStartIP string // currently only used by MetalLB addon
 	LoadBalancerEndIP   string // currently only used by MetalLB addon
+	APIServerHAVIP      string // virtual IP of the control plane nodes of HA clusters
 	CustomIngressCert   string // used by Ingress addon
 	ExtraOptions        ExtraOptionSlice
+	RuntimeHandlers     []string // OCI runtimes installed alongside the default one, see minikube runtime
 
 	ShouldLoadCachedImages bool
 
@@ -120,6 +134,11 @@ type Node struct {
 	KubernetesVersion string
 	ControlPlane      bool
 	Worker            bool
+	SSHIPAddress      string // Only used by ssh driver
+	SSHUser           string // Only used by ssh driver
+	SSHKey            string // Only used by ssh driver
+	SSHPort           int    // Only used by ssh driver
+	StaticIP          string // Only used by kvm2 and the docker, podman and nerdctl drivers
 }
 
 // VersionedExtraOption holds information on flags to apply to a specific range
@@ -78,8 +78,19 @@ const (
 	// MinikubeActivePodmanEnv holds the podman service that the user's shell is pointing at
 	// value would be profile or empty if pointing to the user's host.
 	MinikubeActivePodmanEnv = "MINIKUBE_ACTIVE_PODMAN"
+	// ContainerdAddressEnv is used for containerd client settings, such as of nerdctl
+	ContainerdAddressEnv = "CONTAINERD_ADDRESS"
+	// ContainerdNamespaceEnv is used for containerd client settings, such as of nerdctl
+	ContainerdNamespaceEnv = "CONTAINERD_NAMESPACE"
+	// BuildkitHostEnv is used for buildkit client settings, such as of buildctl and nerdctl build
+	BuildkitHostEnv = "BUILDKIT_HOST"
+	// MinikubeActiveContainerdEnv holds the containerd that the user's shell is pointing at
+	// value would be profile or empty if pointing to the user's host.
+	MinikubeActiveContainerdEnv = "MINIKUBE_ACTIVE_CONTAINERD"
 	// MinikubeForceSystemdEnv is used to force systemd as cgroup manager for the container runtime
 	MinikubeForceSystemdEnv = "MINIKUBE_FORCE_SYSTEMD"
+	// MinikubeRootlessEnv is used to run podman without sudo, against a rootless podman
+	MinikubeRootlessEnv = "MINIKUBE_ROOTLESS"
 	// TestDiskUsedEnv is used in integration tests for insufficient storage with 'minikube status'
 	TestDiskUsedEnv = "MINIKUBE_TEST_STORAGE_CAPACITY"
 
@@ -103,6 +114,13 @@ const (
 	// ExistingContainerHostEnv is used to save original podman environment
 	ExistingContainerHostEnv = MinikubeExistingPrefix + "CONTAINER_HOST"
 
+	// ExistingContainerdAddressEnv is used to save original containerd environment
+	ExistingContainerdAddressEnv = MinikubeExistingPrefix + "CONTAINERD_ADDRESS"
+	// ExistingContainerdNamespaceEnv is used to save original containerd environment
+	ExistingContainerdNamespaceEnv = MinikubeExistingPrefix + "CONTAINERD_NAMESPACE"
+	// ExistingBuildkitHostEnv is used to save original containerd environment
+	ExistingBuildkitHostEnv = MinikubeExistingPrefix + "BUILDKIT_HOST"
+
 	// TimeFormat is the format that should be used when outputting time
 	TimeFormat = time.RFC1123
 )
@@ -110,8 +128,6 @@ const (
 var (
 	// IsMinikubeChildProcess is the name of "is minikube child process" variable
 	IsMinikubeChildProcess = "IS_MINIKUBE_CHILD_PROCESS"
-	// GvisorConfigTomlTargetName is the go-bindata target name for the gvisor config.toml
-	GvisorConfigTomlTargetName = "gvisor-config.toml"
 	// MountProcessFileName is the filename of the mount process
 	MountProcessFileName = ".mount-process"
 
@@ -126,6 +142,9 @@ var (
 	// PodmanRemoteEnvs is list of podman-remote related environment variables.
 	PodmanRemoteEnvs = [2]string{PodmanVarlinkBridgeEnv, PodmanContainerHostEnv}
 
+	// ContainerdEnvs is list of containerd and buildkit related environment variables.
+	ContainerdEnvs = [3]string{ContainerdAddressEnv, ContainerdNamespaceEnv, BuildkitHostEnv}
+
 	// DefaultMinipath is the default minikube path (under the home directory)
 	DefaultMinipath = filepath.Join(homedir.HomeDir(), ".minikube")
 
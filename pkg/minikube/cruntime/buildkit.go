@@ -0,0 +1,135 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cruntime
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/sysinit"
+)
+
+const (
+	// ContainerdSocket is the containerd socket within the node
+	ContainerdSocket = "/run/containerd/containerd.sock"
+	// BuildkitSocket is the buildkitd socket within the node
+	BuildkitSocket = "/run/buildkit/buildkitd.sock"
+	// ContainerdNamespace is the containerd namespace used by the kubelet
+	ContainerdNamespace = "k8s.io"
+
+	buildkitService = "buildkit"
+	// buildkitUnitFile runs buildkitd with the containerd worker, so builds land in the kubelet namespace
+	buildkitUnitFile = "/etc/systemd/system/buildkit.service"
+	// containerdEnvDropIn keeps the containerd socket accessible to the ssh user across restarts
+	containerdEnvDropIn = "/etc/systemd/system/containerd.service.d/10-minikube-env.conf"
+)
+
+var buildkitUnitTmpl = template.Must(template.New("buildkitUnit").Parse(`[Unit]
+Description=BuildKit
+Documentation=https://github.com/moby/buildkit
+After=containerd.service
+Requires=containerd.service
+
+[Service]
+ExecStart={{.Binary}} --addr unix://{{.Socket}} --group {{.Group}} --oci-worker=false --containerd-worker=true --containerd-worker-namespace={{.Namespace}}
+
+[Install]
+WantedBy=multi-user.target
+`))
+
+// socketAccessCmd returns the shell command granting group access to the containerd socket
+func socketAccessCmd(group string) string {
+	return fmt.Sprintf("chgrp %s %s && chmod g+rw %s", group, ContainerdSocket, ContainerdSocket)
+}
+
+// containerdEnvConfig generates the containerd drop-in granting group access to its socket
+func containerdEnvConfig(group string) []byte {
+	return []byte(fmt.Sprintf("[Service]\nExecStartPost=/bin/sh -c \"%s\"\n", socketAccessCmd(group)))
+}
+
+// buildkitUnit generates the systemd unit for buildkitd
+func buildkitUnit(binary string, group string) ([]byte, error) {
+	var b bytes.Buffer
+	opts := struct {
+		Binary    string
+		Socket    string
+		Group     string
+		Namespace string
+	}{
+		Binary:    binary,
+		Socket:    BuildkitSocket,
+		Group:     group,
+		Namespace: ContainerdNamespace,
+	}
+	if err := buildkitUnitTmpl.Execute(&b, opts); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// EnableContainerdEnv grants the ssh user access to the containerd socket, and starts buildkitd on top of containerd
+func EnableContainerdEnv(cr CommandRunner, user string) error {
+	init := sysinit.New(cr)
+	if !init.Active("containerd") {
+		return errors.New("containerd is not running")
+	}
+
+	rr, err := cr.RunCmd(exec.Command("id", "-gn", user))
+	if err != nil {
+		return errors.Wrapf(err, "group of %s", user)
+	}
+	group := strings.TrimSpace(rr.Stdout.String())
+
+	if _, err := updateFile(cr, containerdEnvDropIn, containerdEnvConfig(group), "0644"); err != nil {
+		return errors.Wrap(err, "containerd drop-in")
+	}
+	// the drop-in only applies on the next restart, so avoid disrupting the cluster and apply it now
+	if _, err := cr.RunCmd(exec.Command("sudo", "/bin/sh", "-c", socketAccessCmd(group))); err != nil {
+		return errors.Wrap(err, "containerd socket")
+	}
+
+	rr, err = cr.RunCmd(exec.Command("sudo", "which", "buildkitd"))
+	if err != nil {
+		return errors.Wrap(err, "buildkitd is not available")
+	}
+	unit, err := buildkitUnit(strings.TrimSpace(rr.Stdout.String()), group)
+	if err != nil {
+		return errors.Wrap(err, "buildkit unit")
+	}
+	changed, err := updateFile(cr, buildkitUnitFile, unit, "0644")
+	if err != nil {
+		return errors.Wrap(err, "buildkit unit")
+	}
+
+	if changed {
+		err = init.Restart(buildkitService)
+	} else {
+		err = init.Start(buildkitService)
+	}
+	if err != nil {
+		return errors.Wrap(err, "buildkit")
+	}
+	if err := init.Enable(buildkitService); err != nil {
+		klog.Warningf("unable to enable %s: %v", buildkitService, err)
+	}
+	return nil
+}
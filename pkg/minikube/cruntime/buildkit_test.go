@@ -0,0 +1,54 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cruntime
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBuildkitUnit(t *testing.T) {
+	got, err := buildkitUnit("/usr/sbin/buildkitd", "docker")
+	if err != nil {
+		t.Fatalf("buildkitUnit: %v", err)
+	}
+	want := `[Unit]
+Description=BuildKit
+Documentation=https://github.com/moby/buildkit
+After=containerd.service
+Requires=containerd.service
+
+[Service]
+ExecStart=/usr/sbin/buildkitd --addr unix:///run/buildkit/buildkitd.sock --group docker --oci-worker=false --containerd-worker=true --containerd-worker-namespace=k8s.io
+
+[Install]
+WantedBy=multi-user.target
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("buildkitUnit mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestContainerdEnvConfig(t *testing.T) {
+	want := `[Service]
+ExecStartPost=/bin/sh -c "chgrp docker /run/containerd/containerd.sock && chmod g+rw /run/containerd/containerd.sock"
+`
+	if diff := cmp.Diff(want, string(containerdEnvConfig("docker"))); diff != "" {
+		t.Errorf("containerdEnvConfig mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -18,7 +18,6 @@ package cruntime
 
 import (
 	"bytes"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"os/exec"
@@ -42,7 +41,9 @@ import (
 const (
 	containerdNamespaceRoot = "/run/containerd/runc/k8s.io"
 	// ContainerdConfFile is the path to the containerd configuration
-	containerdConfigFile     = "/etc/containerd/config.toml"
+	containerdConfigFile = "/etc/containerd/config.toml"
+	// containerdCertsDir is where the CAs of registries are copied to for containerd
+	containerdCertsDir       = "/etc/containerd/certs.d"
 	containerdConfigTemplate = `root = "/var/lib/containerd"
 state = "/run/containerd"
 oom_score = 0
@@ -90,19 +91,25 @@ oom_score = 0
         runtime_type = ""
         runtime_engine = ""
         runtime_root = ""
-    [plugins.cri.cni]
+{{ range .RuntimeHandlers }}      [plugins.cri.containerd.runtimes.{{ .Name }}]
+        runtime_type = "{{ .ContainerdRuntimeType }}"
+{{ if .RuncCompatible }}        [plugins.cri.containerd.runtimes.{{ .Name }}.options]
+          BinaryName = "{{ .Path }}"
+{{ end }}{{ end }}    [plugins.cri.cni]
       bin_dir = "/opt/cni/bin"
       conf_dir = "/etc/cni/net.d"
       conf_template = ""
     [plugins.cri.registry]
       [plugins.cri.registry.mirrors]
-        [plugins.cri.registry.mirrors."docker.io"]
-          endpoint = ["https://registry-1.docker.io"]
-        {{ range .InsecureRegistry -}}
-        [plugins.cri.registry.mirrors."{{. -}}"]
-          endpoint = ["http://{{. -}}"]
-        {{ end -}}
-  [plugins.diff-service]
+{{ range .Mirrors }}        [plugins.cri.registry.mirrors."{{ .Host }}"]
+          endpoint = [{{ range $i, $e := .Endpoints }}{{ if $i }}, {{ end }}"{{ $e }}"{{ end }}]
+{{ end }}      [plugins.cri.registry.configs]
+{{ range .Configs }}{{ if or .CAFile .InsecureSkipVerify }}        [plugins.cri.registry.configs."{{ .Host }}".tls]
+{{ if .CAFile }}          ca_file = "{{ .CAFile }}"
+{{ end }}          insecure_skip_verify = {{ .InsecureSkipVerify }}
+{{ end }}{{ if .Auth }}        [plugins.cri.registry.configs."{{ .Host }}".auth]
+          auth = "{{ .Auth }}"
+{{ end }}{{ end }}  [plugins.diff-service]
     default = ["walking"]
   [plugins.linux]
     shim = "containerd-shim"
@@ -126,7 +133,52 @@ type Containerd struct {
 	ImageRepository   string
 	KubernetesVersion semver.Version
 	Init              sysinit.Manager
-	InsecureRegistry  []string
+	Registries        []Registry
+	RuntimeHandlers   []RuntimeHandler
+}
+
+// containerdMirror is the list of endpoints containerd pulls the images of a registry from
+type containerdMirror struct {
+	Host      string
+	Endpoints []string
+}
+
+// containerdRegistryConfig is the TLS and auth configuration of a registry for containerd
+type containerdRegistryConfig struct {
+	Host               string
+	CAFile             string
+	InsecureSkipVerify bool
+	Auth               string
+}
+
+// containerdRegistries returns the mirrors and configs sections of the containerd config.toml for the registries
+func containerdRegistries(regs []Registry) ([]containerdMirror, []containerdRegistryConfig) {
+	mirrors := []containerdMirror{}
+	configs := []containerdRegistryConfig{}
+	dockerHub := containerdMirror{Host: DockerHub, Endpoints: []string{"https://registry-1.docker.io"}}
+	for _, r := range regs {
+		// containerd doesn't support ranges of insecure registries
+		if r.IsCIDR() {
+			continue
+		}
+		if r.Host == DockerHub {
+			dockerHub.Endpoints = append(append([]string{}, r.Mirrors...), dockerHub.Endpoints...)
+		} else if len(r.Mirrors) > 0 || r.Insecure {
+			scheme := "https"
+			if r.Insecure {
+				scheme = "http"
+			}
+			mirrors = append(mirrors, containerdMirror{Host: r.Host, Endpoints: append(append([]string{}, r.Mirrors...), scheme+"://"+r.Host)})
+		}
+		if len(r.CA) > 0 || r.Insecure || r.Auth != "" {
+			c := containerdRegistryConfig{Host: r.Host, InsecureSkipVerify: r.Insecure, Auth: r.Auth}
+			if len(r.CA) > 0 {
+				c.CAFile = registryCAPath(containerdCertsDir, r.Host)
+			}
+			configs = append(configs, c)
+		}
+	}
+	return append([]containerdMirror{dockerHub}, mirrors...), configs
 }
 
 // Name is a human readable name for containerd
@@ -176,34 +228,63 @@ func (r *Containerd) Available() error {
 	return nil
 }
 
-// generateContainerdConfig sets up /etc/containerd/config.toml
-func generateContainerdConfig(cr CommandRunner, imageRepository string, kv semver.Version, forceSystemd bool, insecureRegistry []string) error {
-	cPath := containerdConfigFile
+// containerdConfig returns the content of /etc/containerd/config.toml
+func containerdConfig(imageRepository string, kv semver.Version, forceSystemd bool, regs []Registry, hs []RuntimeHandler) ([]byte, error) {
 	t, err := template.New("containerd.config.toml").Parse(containerdConfigTemplate)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	pauseImage := images.Pause(kv, imageRepository)
+	mirrors, configs := containerdRegistries(regs)
 	opts := struct {
 		PodInfraContainerImage string
 		SystemdCgroup          bool
-		InsecureRegistry       []string
+		Mirrors                []containerdMirror
+		Configs                []containerdRegistryConfig
+		RuntimeHandlers        []RuntimeHandler
 	}{
-		PodInfraContainerImage: pauseImage,
+		PodInfraContainerImage: images.Pause(kv, imageRepository),
 		SystemdCgroup:          forceSystemd,
-		InsecureRegistry:       insecureRegistry,
+		Mirrors:                mirrors,
+		Configs:                configs,
+		RuntimeHandlers:        hs,
 	}
 	var b bytes.Buffer
 	if err := t.Execute(&b, opts); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// generateContainerdConfig sets up /etc/containerd/config.toml
+func generateContainerdConfig(cr CommandRunner, imageRepository string, kv semver.Version, forceSystemd bool, regs []Registry, hs []RuntimeHandler) error {
+	cPath := containerdConfigFile
+	b, err := containerdConfig(imageRepository, kv, forceSystemd, regs, hs)
+	if err != nil {
+		return err
+	}
+	if _, err := updateRegistryCAs(cr, containerdCertsDir, regs); err != nil {
 		return err
 	}
-	c := exec.Command("/bin/bash", "-c", fmt.Sprintf("sudo mkdir -p %s && printf %%s \"%s\" | base64 -d | sudo tee %s", path.Dir(cPath), base64.StdEncoding.EncodeToString(b.Bytes()), cPath))
-	if _, err := cr.RunCmd(c); err != nil {
+	// the config holds the registry credentials: it is only readable by root, and never goes through a command line
+	if _, err := cr.RunCmd(exec.Command("sudo", "mkdir", "-p", path.Dir(cPath))); err != nil {
+		return errors.Wrap(err, "generate containerd cfg.")
+	}
+	if err := cr.Copy(assets.NewMemoryAssetTarget(b, cPath, "0600")); err != nil {
+		return errors.Wrap(err, "generate containerd cfg.")
+	}
+	// scp keeps the mode of an existing file
+	if _, err := cr.RunCmd(exec.Command("sudo", "chmod", "0600", cPath)); err != nil {
 		return errors.Wrap(err, "generate containerd cfg.")
 	}
 	return nil
 }
 
+// ContainerdSystemdCgroup returns whether containerd was configured with the systemd cgroup driver, as --force-systemd does
+func ContainerdSystemdCgroup(cr CommandRunner) bool {
+	_, err := cr.RunCmd(exec.Command("sudo", "grep", "-q", "systemd_cgroup = true", containerdConfigFile))
+	return err == nil
+}
+
 // Enable idempotently enables containerd on a host
 func (r *Containerd) Enable(disOthers, forceSystemd bool) error {
 	if disOthers {
@@ -214,7 +295,7 @@ func (r *Containerd) Enable(disOthers, forceSystemd bool) error {
 	if err := populateCRIConfig(r.Runner, r.SocketPath()); err != nil {
 		return err
 	}
-	if err := generateContainerdConfig(r.Runner, r.ImageRepository, r.KubernetesVersion, forceSystemd, r.InsecureRegistry); err != nil {
+	if err := generateContainerdConfig(r.Runner, r.ImageRepository, r.KubernetesVersion, forceSystemd, r.Registries, r.RuntimeHandlers); err != nil {
 		return err
 	}
 	if err := enableIPForwarding(r.Runner); err != nil {
@@ -249,6 +330,21 @@ func (r *Containerd) LoadImage(path string) error {
 	return nil
 }
 
+// ListImages returns the tagged images of the runtime
+func (r *Containerd) ListImages() ([]string, error) {
+	return listCRIImages(r.Runner)
+}
+
+// SaveImage saves an image of this runtime into an archive
+func (r *Containerd) SaveImage(name string, path string) error {
+	klog.Infof("Saving image %s: %s", name, path)
+	c := exec.Command("sudo", "ctr", "-n=k8s.io", "images", "export", path, name)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrapf(err, "ctr images export")
+	}
+	return nil
+}
+
 // CGroupDriver returns cgroup driver ("cgroupfs" or "systemd")
 func (r *Containerd) CGroupDriver() (string, error) {
 	info, err := getCRIInfo(r.Runner)
@@ -360,6 +456,7 @@ func (r *Containerd) Preload(cfg config.KubernetesConfig) error {
 	t = time.Now()
 	// extract the tarball to /var in the VM
 	if rr, err := r.Runner.RunCmd(exec.Command("sudo", "tar", "-I", "lz4", "-C", "/var", "-xf", dest)); err != nil {
+		download.VerifyFailedPreload(k8sVersion, cRuntime)
 		return errors.Wrapf(err, "extracting tarball: %s", rr.Output())
 	}
 	klog.Infof("Took %f seconds t extract the tarball", time.Since(t).Seconds())
@@ -271,3 +271,24 @@ func addRepoTagToImageName(imgName string) string {
 	} // else it already has repo name dont add anything
 	return imgName
 }
+
+// listCRIImages returns the tagged images of a CRI runtime
+func listCRIImages(cr CommandRunner) ([]string, error) {
+	rr, err := cr.RunCmd(exec.Command("sudo", "crictl", "images", "--output", "json"))
+	if err != nil {
+		return nil, errors.Wrap(err, "crictl images")
+	}
+	var list struct {
+		Images []struct {
+			RepoTags []string `json:"repoTags"`
+		} `json:"images"`
+	}
+	if err := json.Unmarshal(rr.Stdout.Bytes(), &list); err != nil {
+		return nil, errors.Wrap(err, "unmarshal crictl images")
+	}
+	images := []string{}
+	for _, img := range list.Images {
+		images = append(images, img.RepoTags...)
+	}
+	return images, nil
+}
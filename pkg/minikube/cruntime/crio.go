@@ -17,12 +17,15 @@ limitations under the License.
 package cruntime
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"net"
+	"net/url"
 	"os/exec"
 	"path"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/blang/semver"
@@ -40,8 +43,28 @@ import (
 const (
 	// CRIOConfFile is the path to the CRI-O configuration
 	crioConfigFile = "/etc/crio/crio.conf"
+	// crioRegistriesFile is the path to the registries configuration of CRI-O
+	crioRegistriesFile = "/etc/containers/registries.conf"
+	// crioCertsDir is where CRI-O looks up the CAs of registries
+	crioCertsDir = "/etc/containers/certs.d"
 )
 
+// crioRegistriesTemplate is a containers-registries.conf(5) in the version 2 format
+var crioRegistriesTemplate = template.Must(template.New("registries.conf").Parse(`# generated by minikube, see the --registry-mirror, --insecure-registry and --registry-ca flags of minikube start
+unqualified-search-registries = ["docker.io"]
+{{ range . }}
+[[registry]]
+prefix = "{{ .Host }}"
+location = "{{ .Host }}"
+insecure = {{ .Insecure }}
+{{ range .Mirrors }}
+[[registry.mirror]]
+location = "{{ .Location }}"
+insecure = {{ .Insecure }}
+{{ end -}}
+{{ end -}}
+`))
+
 // CRIO contains CRIO runtime state
 type CRIO struct {
 	Socket            string
@@ -49,6 +72,79 @@ type CRIO struct {
 	ImageRepository   string
 	KubernetesVersion semver.Version
 	Init              sysinit.Manager
+	Registries        []Registry
+	RuntimeHandlers   []RuntimeHandler
+}
+
+// crioMirror is a mirror in registries.conf, which has no scheme: plain HTTP mirrors are insecure
+type crioMirror struct {
+	Location string
+	Insecure bool
+}
+
+// generateCRIORegistries returns the registries.conf configuring the registries
+func generateCRIORegistries(regs []Registry) ([]byte, error) {
+	type registry struct {
+		Host     string
+		Insecure bool
+		Mirrors  []crioMirror
+	}
+	rs := []registry{}
+	for _, r := range regs {
+		// ranges of insecure registries are passed to crio by the provisioner, in /etc/sysconfig/crio.minikube
+		if r.IsCIDR() || (len(r.Mirrors) == 0 && !r.Insecure) {
+			continue
+		}
+		cr := registry{Host: r.Host, Insecure: r.Insecure}
+		for _, m := range r.Mirrors {
+			u, err := url.Parse(m)
+			if err != nil {
+				return nil, errors.Wrapf(err, "parsing mirror %s", m)
+			}
+			cr.Mirrors = append(cr.Mirrors, crioMirror{Location: u.Host, Insecure: u.Scheme == "http"})
+		}
+		rs = append(rs, cr)
+	}
+	var b bytes.Buffer
+	if err := crioRegistriesTemplate.Execute(&b, rs); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// configureRegistries writes the registries.conf, registry CAs and credentials, and reports whether CRI-O needs a restart
+func (r *CRIO) configureRegistries() (bool, error) {
+	if len(r.Registries) == 0 {
+		return false, nil
+	}
+	rc, err := generateCRIORegistries(r.Registries)
+	if err != nil {
+		return false, errors.Wrap(err, "generating registries.conf")
+	}
+	changed, err := updateFile(r.Runner, crioRegistriesFile, rc, "0644")
+	if err != nil {
+		return false, err
+	}
+	// CRI-O reads the registry CAs on each pull, they don't need a restart
+	if _, err := updateRegistryCAs(r.Runner, crioCertsDir, r.Registries); err != nil {
+		return false, err
+	}
+	if err := updateKubeletCredentials(r.Runner, r.Registries); err != nil {
+		return false, err
+	}
+	return changed, nil
+}
+
+// configureRuntimeHandlers writes the drop-in registering the runtime handlers, and reports whether CRI-O needs a restart
+func (r *CRIO) configureRuntimeHandlers() (bool, error) {
+	if len(r.RuntimeHandlers) == 0 {
+		return false, nil
+	}
+	b, err := crioRuntimeHandlersConfig(r.RuntimeHandlers)
+	if err != nil {
+		return false, err
+	}
+	return updateFile(r.Runner, crioRuntimeHandlersFile, b, "0644")
 }
 
 // generateCRIOConfig sets up /etc/crio/crio.conf
@@ -145,6 +241,17 @@ func (r *CRIO) Enable(disOthers, _ bool) error {
 	if err := enableIPForwarding(r.Runner); err != nil {
 		return err
 	}
+	changed, err := r.configureRegistries()
+	if err != nil {
+		return err
+	}
+	handlersChanged, err := r.configureRuntimeHandlers()
+	if err != nil {
+		return err
+	}
+	if changed || handlersChanged {
+		return r.Init.Restart("crio")
+	}
 	return r.Init.Start("crio")
 }
 
@@ -177,6 +284,21 @@ func (r *CRIO) LoadImage(path string) error {
 	return nil
 }
 
+// ListImages returns the tagged images of the runtime
+func (r *CRIO) ListImages() ([]string, error) {
+	return listCRIImages(r.Runner)
+}
+
+// SaveImage saves an image of this runtime into an archive
+func (r *CRIO) SaveImage(name string, path string) error {
+	klog.Infof("Saving image %s: %s", name, path)
+	c := exec.Command("sudo", "podman", "save", "-o", path, name)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrap(err, "crio save image")
+	}
+	return nil
+}
+
 // CGroupDriver returns cgroup driver ("cgroupfs" or "systemd")
 func (r *CRIO) CGroupDriver() (string, error) {
 	c := exec.Command("crio", "config")
@@ -285,6 +407,7 @@ func (r *CRIO) Preload(cfg config.KubernetesConfig) error {
 	t = time.Now()
 	// extract the tarball to /var in the VM
 	if rr, err := r.Runner.RunCmd(exec.Command("sudo", "tar", "-I", "lz4", "-C", "/var", "-xf", dest)); err != nil {
+		download.VerifyFailedPreload(k8sVersion, cRuntime)
 		return errors.Wrapf(err, "extracting tarball: %s", rr.Output())
 	}
 	klog.Infof("Took %f seconds t extract the tarball", time.Since(t).Seconds())
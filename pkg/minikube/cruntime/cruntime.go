@@ -97,6 +97,10 @@ type Manager interface {
 
 	// ImageExists takes image name and image sha checks if an it exists
 	ImageExists(string, string) bool
+	// ListImages returns the tagged images of the runtime
+	ListImages() ([]string, error)
+	// SaveImage saves an image of the runtime into an archive on the host
+	SaveImage(string, string) error
 
 	// ListContainers returns a list of managed by this container runtime
 	ListContainers(ListOptions) ([]string, error)
@@ -130,8 +134,10 @@ type Config struct {
 	ImageRepository string
 	// KubernetesVersion Kubernetes version
 	KubernetesVersion semver.Version
-	// InsecureRegistry list of insecure registries
-	InsecureRegistry []string
+	// Registries is the configuration of the registries to pull images from
+	Registries []Registry
+	// RuntimeHandlers are the OCI runtimes to register alongside the default one
+	RuntimeHandlers []RuntimeHandler
 }
 
 // ListOptions are the options to use for listing containers
@@ -151,9 +157,10 @@ func New(c Config) (Manager, error) {
 	switch c.Type {
 	case "", "docker":
 		return &Docker{
-			Socket: c.Socket,
-			Runner: c.Runner,
-			Init:   sm,
+			Socket:     c.Socket,
+			Runner:     c.Runner,
+			Init:       sm,
+			Registries: c.Registries,
 		}, nil
 	case "crio", "cri-o":
 		return &CRIO{
@@ -162,6 +169,8 @@ func New(c Config) (Manager, error) {
 			ImageRepository:   c.ImageRepository,
 			KubernetesVersion: c.KubernetesVersion,
 			Init:              sm,
+			Registries:        c.Registries,
+			RuntimeHandlers:   c.RuntimeHandlers,
 		}, nil
 	case "containerd":
 		return &Containerd{
@@ -170,7 +179,8 @@ func New(c Config) (Manager, error) {
 			ImageRepository:   c.ImageRepository,
 			KubernetesVersion: c.KubernetesVersion,
 			Init:              sm,
-			InsecureRegistry:  c.InsecureRegistry,
+			Registries:        c.Registries,
+			RuntimeHandlers:   c.RuntimeHandlers,
 		}, nil
 	default:
 		return nil, fmt.Errorf("unknown runtime type: %q", c.Type)
@@ -83,6 +83,26 @@ func TestImageExists(t *testing.T) {
 	}
 }
 
+func TestListImages(t *testing.T) {
+	want := []string{"k8s.gcr.io/pause:3.2", "docker.io/library/busybox:1.32"}
+	for _, runtime := range []string{"docker", "crio", "containerd"} {
+		t.Run(runtime, func(t *testing.T) {
+			r, err := New(Config{Type: runtime, Runner: NewFakeRunner(t)})
+			if err != nil {
+				t.Fatalf("New(%s): %v", runtime, err)
+			}
+
+			got, err := r.ListImages()
+			if err != nil {
+				t.Fatalf("ListImages(): %v", err)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("ListImages(%s) returned diff (-want +got):\n%s", runtime, diff)
+			}
+		})
+	}
+}
+
 func TestCGroupDriver(t *testing.T) {
 	var tests = []struct {
 		runtime string
@@ -311,6 +331,9 @@ func (f *FakeRunner) docker(args []string, _ bool) (string, error) {
 	case "inspect":
 		return f.dockerInspect(args)
 
+	case "images":
+		return "k8s.gcr.io/pause:3.2\ndocker.io/library/busybox:1.32\n<none>:<none>\n", nil
+
 	case "info":
 
 		if args[1] == "--format" && args[2] == "{{.CgroupDriver}}" {
@@ -365,6 +388,14 @@ func (f *FakeRunner) containerd(args []string, _ bool) (string, error) {
 func (f *FakeRunner) crictl(args []string, _ bool) (string, error) {
 	f.t.Logf("crictl args: %s", args)
 	switch cmd := args[0]; cmd {
+	case "images":
+		return `{
+		  "images": [
+		    {"id": "sha256:80d2", "repoTags": ["k8s.gcr.io/pause:3.2"]},
+		    {"id": "sha256:a9d5", "repoTags": ["docker.io/library/busybox:1.32"]},
+		    {"id": "sha256:0c1f", "repoTags": []}
+		  ]
+		}`, nil
 	case "info":
 		return `{
 		  "status": {
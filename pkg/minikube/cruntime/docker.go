@@ -17,6 +17,8 @@ limitations under the License.
 package cruntime
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"os/exec"
 	"path"
@@ -35,8 +37,14 @@ import (
 	"k8s.io/minikube/pkg/minikube/sysinit"
 )
 
-// KubernetesContainerPrefix is the prefix of each Kubernetes container
-const KubernetesContainerPrefix = "k8s_"
+const (
+	// KubernetesContainerPrefix is the prefix of each Kubernetes container
+	KubernetesContainerPrefix = "k8s_"
+	// dockerDaemonConfigFile is the path to the docker daemon configuration
+	dockerDaemonConfigFile = "/etc/docker/daemon.json"
+	// dockerCertsDir is where docker looks up the CAs of registries
+	dockerCertsDir = "/etc/docker/certs.d"
+)
 
 // ErrISOFeature is the error returned when disk image is missing features
 type ErrISOFeature struct {
@@ -56,9 +64,10 @@ func (e *ErrISOFeature) Error() string {
 
 // Docker contains Docker runtime state
 type Docker struct {
-	Socket string
-	Runner CommandRunner
-	Init   sysinit.Manager
+	Socket     string
+	Runner     CommandRunner
+	Init       sysinit.Manager
+	Registries []Registry
 }
 
 // Name is a human readable name for Docker
@@ -115,10 +124,11 @@ func (r *Docker) Enable(disOthers, forceSystemd bool) error {
 		return err
 	}
 
-	if forceSystemd {
-		if err := r.forceSystemd(); err != nil {
-			return err
-		}
+	changed, err := r.configureDaemon(forceSystemd)
+	if err != nil {
+		return err
+	}
+	if forceSystemd || changed {
 		return r.Init.Restart("docker")
 	}
 
@@ -158,6 +168,31 @@ func (r *Docker) ImageExists(name string, sha string) bool {
 	return true
 }
 
+// ListImages returns the tagged images of the runtime
+func (r *Docker) ListImages() ([]string, error) {
+	rr, err := r.Runner.RunCmd(exec.Command("docker", "images", "--format", "{{.Repository}}:{{.Tag}}"))
+	if err != nil {
+		return nil, errors.Wrap(err, "docker images")
+	}
+	images := []string{}
+	for _, img := range strings.Fields(rr.Stdout.String()) {
+		if !strings.Contains(img, "<none>") {
+			images = append(images, img)
+		}
+	}
+	return images, nil
+}
+
+// SaveImage saves an image of this runtime into an archive
+func (r *Docker) SaveImage(name string, path string) error {
+	klog.Infof("Saving image %s: %s", name, path)
+	c := exec.Command("docker", "save", "-o", path, name)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrap(err, "saveimage docker.")
+	}
+	return nil
+}
+
 // LoadImage loads an image into this runtime
 func (r *Docker) LoadImage(path string) error {
 	klog.Infof("Loading image: %s", path)
@@ -294,20 +329,81 @@ func (r *Docker) SystemLogCmd(len int) string {
 	return fmt.Sprintf("sudo journalctl -u docker -n %d", len)
 }
 
-// ForceSystemd forces the docker daemon to use systemd as cgroup manager
-func (r *Docker) forceSystemd() error {
-	klog.Infof("Forcing docker to use systemd as cgroup manager...")
-	daemonConfig := `{
-"exec-opts": ["native.cgroupdriver=systemd"],
-"log-driver": "json-file",
-"log-opts": {
-	"max-size": "100m"
-},
-"storage-driver": "overlay2"
+// generateDockerDaemonConfig merges into the existing daemon.json the keys forcing systemd as cgroup manager
+// and configuring the registries, leaving the other keys, such as the defaults of the ISO, alone
+func generateDockerDaemonConfig(existing []byte, forceSystemd bool, regs []Registry) ([]byte, error) {
+	dc := map[string]interface{}{}
+	if len(bytes.TrimSpace(existing)) > 0 {
+		if err := json.Unmarshal(existing, &dc); err != nil {
+			klog.Warningf("ignoring invalid daemon.json: %v", err)
+			dc = map[string]interface{}{}
+		}
+	}
+	if forceSystemd {
+		dc["exec-opts"] = []string{"native.cgroupdriver=systemd"}
+		dc["log-driver"] = "json-file"
+		dc["log-opts"] = map[string]string{"max-size": "100m"}
+		dc["storage-driver"] = "overlay2"
+	}
+
+	var mirrors, insecure []string
+	for _, reg := range regs {
+		if len(reg.Mirrors) > 0 {
+			// docker only supports mirrors of Docker Hub
+			if reg.Host == DockerHub {
+				mirrors = append(mirrors, reg.Mirrors...)
+			} else {
+				klog.Warningf("docker doesn't support mirrors of %s, ignoring %v", reg.Host, reg.Mirrors)
+			}
+		}
+		if reg.Insecure {
+			insecure = append(insecure, reg.Host)
+		}
+	}
+	// the registry keys are owned by minikube, so that removed registries are removed from them too
+	for key, values := range map[string][]string{"registry-mirrors": mirrors, "insecure-registries": insecure} {
+		if len(values) > 0 {
+			dc[key] = values
+		} else {
+			delete(dc, key)
+		}
+	}
+
+	b, err := json.MarshalIndent(dc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
 }
-`
-	ma := assets.NewMemoryAsset([]byte(daemonConfig), "/etc/docker", "daemon.json", "0644")
-	return r.Runner.Copy(ma)
+
+// configureDaemon writes the docker daemon.json, registry CAs and credentials, and reports whether docker needs a restart
+func (r *Docker) configureDaemon(forceSystemd bool) (bool, error) {
+	changed := false
+	if forceSystemd || len(r.Registries) > 0 {
+		if forceSystemd {
+			klog.Infof("Forcing docker to use systemd as cgroup manager...")
+		}
+		var existing []byte
+		if rr, err := r.Runner.RunCmd(exec.Command("sudo", "cat", dockerDaemonConfigFile)); err == nil {
+			existing = rr.Stdout.Bytes()
+		}
+		dc, err := generateDockerDaemonConfig(existing, forceSystemd, r.Registries)
+		if err != nil {
+			return false, errors.Wrap(err, "generating daemon.json")
+		}
+		changed, err = updateFile(r.Runner, dockerDaemonConfigFile, dc, "0644")
+		if err != nil {
+			return false, err
+		}
+	}
+	// docker reads the registry CAs on each pull, they don't need a restart
+	if _, err := updateRegistryCAs(r.Runner, dockerCertsDir, r.Registries); err != nil {
+		return false, err
+	}
+	if err := updateKubeletCredentials(r.Runner, r.Registries); err != nil {
+		return false, err
+	}
+	return changed, nil
 }
 
 // Preload preloads docker with k8s images:
@@ -359,6 +455,7 @@ func (r *Docker) Preload(cfg config.KubernetesConfig) error {
 
 	// extract the tarball to /var in the VM
 	if rr, err := r.Runner.RunCmd(exec.Command("sudo", "tar", "-I", "lz4", "-C", "/var", "-xf", dest)); err != nil {
+		download.VerifyFailedPreload(k8sVersion, cRuntime)
 		return errors.Wrapf(err, "extracting tarball: %s", rr.Output())
 	}
 
@@ -0,0 +1,282 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cruntime
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/util/homedir"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+const (
+	// DockerHub is the name container runtimes use for the default registry
+	DockerHub = "docker.io"
+	// dockerHubAuthKey is the key of Docker Hub in a docker config.json
+	dockerHubAuthKey = "https://index.docker.io/v1/"
+	// kubeletCredentialsFile is where the kubelet looks up registry credentials for image pulls
+	kubeletCredentialsFile = "/var/lib/kubelet/config.json"
+)
+
+// Registry is the runtime-agnostic configuration of how to pull from an image registry
+type Registry struct {
+	// Host is the registry, such as "docker.io" or "myregistry:5000", or a CIDR for insecure registries
+	Host string
+	// Mirrors are the URLs of the mirrors to try before the registry itself
+	Mirrors []string
+	// Insecure allows plain HTTP or unverified HTTPS connections to the registry
+	Insecure bool
+	// CA is the PEM encoded certificate authority the registry certificate is signed by
+	CA []byte
+	// Auth is the base64 encoded "user:password" to log in to the registry with
+	Auth string
+}
+
+// IsCIDR returns whether the registry is a range of insecure registries, rather than a single one
+func (r Registry) IsCIDR() bool {
+	_, _, err := net.ParseCIDR(r.Host)
+	return err == nil
+}
+
+// dockerConfig is the subset of a docker config.json used for registry credentials
+type dockerConfig struct {
+	Auths      map[string]dockerAuth `json:"auths"`
+	CredsStore string                `json:"credsStore,omitempty"`
+}
+
+type dockerAuth struct {
+	Auth string `json:"auth"`
+}
+
+// Registries returns the configuration of the registries of a cluster:
+// --registry-mirror, --insecure-registry and --registry-ca entries, and the
+// registry credentials of the host if --registry-auth-from-host is set.
+func Registries(cc config.ClusterConfig) ([]Registry, error) {
+	regs := map[string]*Registry{}
+	get := func(host string) *Registry {
+		if regs[host] == nil {
+			regs[host] = &Registry{Host: host}
+		}
+		return regs[host]
+	}
+
+	for _, m := range cc.RegistryMirror {
+		host, mirror, err := ParseRegistryMirror(m)
+		if err != nil {
+			return nil, err
+		}
+		r := get(host)
+		r.Mirrors = append(r.Mirrors, mirror)
+	}
+
+	serviceCIDR := cc.KubernetesConfig.ServiceCIDR
+	if serviceCIDR == "" {
+		serviceCIDR = constants.DefaultServiceCIDR
+	}
+	for _, host := range append([]string{serviceCIDR}, cc.InsecureRegistry...) {
+		get(host).Insecure = true
+	}
+
+	for _, c := range cc.RegistryCA {
+		host, p, err := parseRegistryCA(c)
+		if err != nil {
+			return nil, err
+		}
+		ca, err := ioutil.ReadFile(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading CA of registry %s", host)
+		}
+		get(host).CA = ca
+	}
+
+	if cc.RegistryAuthFromHost {
+		auths, err := hostRegistryAuths()
+		if err != nil {
+			return nil, err
+		}
+		for host, auth := range auths {
+			get(host).Auth = auth
+		}
+	}
+
+	hosts := []string{}
+	for host := range regs {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	result := []Registry{}
+	for _, host := range hosts {
+		result = append(result, *regs[host])
+	}
+	return result, nil
+}
+
+// ParseRegistryMirror parses a --registry-mirror entry, either the URL of a Docker Hub mirror
+// or "<registry>=<url>" for the mirror of another registry, and returns the registry and the mirror URL
+func ParseRegistryMirror(s string) (string, string, error) {
+	host, mirror := DockerHub, s
+	if i := strings.Index(s, "="); i > 0 {
+		host, mirror = s[:i], s[i+1:]
+	}
+	u, err := url.Parse(mirror)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || strings.Trim(u.Path, "/") != "" {
+		return "", "", errors.Errorf("invalid registry mirror %q, expected a URL such as https://mirror.example.com", s)
+	}
+	return host, strings.TrimSuffix(mirror, "/"), nil
+}
+
+// parseRegistryCA parses a --registry-ca entry, formatted as "<registry>=<path>"
+func parseRegistryCA(s string) (string, string, error) {
+	i := strings.Index(s, "=")
+	if i <= 0 || i == len(s)-1 {
+		return "", "", errors.Errorf("invalid registry CA %q, expected <registry>=<path>", s)
+	}
+	return s[:i], s[i+1:], nil
+}
+
+// hostDockerConfigPath returns the path of the docker config.json of the host
+func hostDockerConfigPath() string {
+	if d := os.Getenv("DOCKER_CONFIG"); d != "" {
+		return filepath.Join(d, "config.json")
+	}
+	return filepath.Join(homedir.HomeDir(), ".docker", "config.json")
+}
+
+// hostRegistryAuths returns the registry credentials of the docker config.json of the host, by registry
+func hostRegistryAuths() (map[string]string, error) {
+	p := hostDockerConfigPath()
+	data, err := ioutil.ReadFile(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			klog.Warningf("no docker config at %s, no registry credentials to copy", p)
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "reading %s", p)
+	}
+	return parseRegistryAuths(data)
+}
+
+// parseRegistryAuths returns the credentials of a docker config.json, by registry
+func parseRegistryAuths(data []byte) (map[string]string, error) {
+	var dc dockerConfig
+	if err := json.Unmarshal(data, &dc); err != nil {
+		return nil, errors.Wrap(err, "parsing docker config")
+	}
+	if dc.CredsStore != "" {
+		klog.Warningf("credentials in the %q credential store can't be copied, only the ones in the docker config", dc.CredsStore)
+	}
+	auths := map[string]string{}
+	for key, a := range dc.Auths {
+		if a.Auth == "" {
+			continue
+		}
+		auths[registryHost(key)] = a.Auth
+	}
+	return auths, nil
+}
+
+// registryHost returns the registry of a docker config.json auths key, such as "https://index.docker.io/v1/"
+func registryHost(key string) string {
+	if key == dockerHubAuthKey {
+		return DockerHub
+	}
+	host := key
+	if u, err := url.Parse(key); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	host = strings.TrimSuffix(host, "/")
+	if host == "index.docker.io" || host == "registry-1.docker.io" {
+		return DockerHub
+	}
+	return host
+}
+
+// generateKubeletCredentials returns the config.json the kubelet passes registry credentials from to the runtime
+func generateKubeletCredentials(regs []Registry) ([]byte, error) {
+	auths := map[string]dockerAuth{}
+	for _, r := range regs {
+		if r.Auth == "" {
+			continue
+		}
+		key := r.Host
+		if key == DockerHub {
+			key = dockerHubAuthKey
+		}
+		auths[key] = dockerAuth{Auth: r.Auth}
+	}
+	if len(auths) == 0 {
+		return nil, nil
+	}
+	return json.MarshalIndent(dockerConfig{Auths: auths}, "", "  ")
+}
+
+// updateKubeletCredentials writes the registry credentials for the kubelet, for runtimes without their own
+func updateKubeletCredentials(cr CommandRunner, regs []Registry) error {
+	data, err := generateKubeletCredentials(regs)
+	if err != nil || data == nil {
+		return err
+	}
+	_, err = updateFile(cr, kubeletCredentialsFile, data, "0600")
+	return err
+}
+
+// updateRegistryCAs copies the CAs of the registries to <dir>/<registry>/ca.crt, and reports whether any changed
+func updateRegistryCAs(cr CommandRunner, dir string, regs []Registry) (bool, error) {
+	changed := false
+	for _, r := range regs {
+		if len(r.CA) == 0 {
+			continue
+		}
+		c, err := updateFile(cr, registryCAPath(dir, r.Host), r.CA, "0644")
+		if err != nil {
+			return false, err
+		}
+		changed = changed || c
+	}
+	return changed, nil
+}
+
+// registryCAPath returns the path of the CA of a registry in a certs.d directory
+func registryCAPath(dir string, host string) string {
+	return path.Join(dir, host, "ca.crt")
+}
+
+// updateFile writes data to a file of the host if its content differs, and reports whether it changed
+func updateFile(cr CommandRunner, dst string, data []byte, perm string) (bool, error) {
+	rr, err := cr.RunCmd(exec.Command("sudo", "cat", dst))
+	if err == nil && bytes.Equal(rr.Stdout.Bytes(), data) {
+		return false, nil
+	}
+	if err := cr.Copy(assets.NewMemoryAsset(data, path.Dir(dst), path.Base(dst), perm)); err != nil {
+		return false, errors.Wrapf(err, "copy %s", dst)
+	}
+	return true, nil
+}
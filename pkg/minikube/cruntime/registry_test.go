@@ -0,0 +1,247 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cruntime
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestParseRegistryMirror(t *testing.T) {
+	var tests = []struct {
+		in      string
+		host    string
+		mirror  string
+		wantErr bool
+	}{
+		{"https://mirror.example.com", DockerHub, "https://mirror.example.com", false},
+		{"http://localhost:5000/", DockerHub, "http://localhost:5000", false},
+		{"quay.io=https://quay-mirror.example.com", "quay.io", "https://quay-mirror.example.com", false},
+		{"mirror.example.com", "", "", true},
+		{"https://mirror.example.com/v2", "", "", true},
+		{"quay.io=ftp://mirror.example.com", "", "", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			host, mirror, err := ParseRegistryMirror(tc.in)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseRegistryMirror(%q) error = %v, wantErr %v", tc.in, err, tc.wantErr)
+			}
+			if host != tc.host || mirror != tc.mirror {
+				t.Errorf("ParseRegistryMirror(%q) = %q, %q, want %q, %q", tc.in, host, mirror, tc.host, tc.mirror)
+			}
+		})
+	}
+}
+
+func TestRegistries(t *testing.T) {
+	dir, err := ioutil.TempDir("", "registries")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	ca := filepath.Join(dir, "ca.crt")
+	if err := ioutil.WriteFile(ca, []byte("CA"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dc := `{"auths": {"https://index.docker.io/v1/": {"auth": "aHViOnB3"}, "registry.example.com": {"auth": "cmVnOnB3"}}}`
+	if err := ioutil.WriteFile(filepath.Join(dir, "config.json"), []byte(dc), 0600); err != nil {
+		t.Fatal(err)
+	}
+	old := os.Getenv("DOCKER_CONFIG")
+	os.Setenv("DOCKER_CONFIG", dir)
+	t.Cleanup(func() { os.Setenv("DOCKER_CONFIG", old) })
+
+	cc := config.ClusterConfig{
+		RegistryMirror:       []string{"https://mirror.example.com", "registry.example.com=https://reg-mirror.example.com"},
+		InsecureRegistry:     []string{"10.0.0.1:5000"},
+		RegistryCA:           []string{"registry.example.com=" + ca},
+		RegistryAuthFromHost: true,
+	}
+	got, err := Registries(cc)
+	if err != nil {
+		t.Fatalf("Registries: %v", err)
+	}
+	want := []Registry{
+		{Host: "10.0.0.1:5000", Insecure: true},
+		{Host: "10.96.0.0/12", Insecure: true},
+		{Host: DockerHub, Mirrors: []string{"https://mirror.example.com"}, Auth: "aHViOnB3"},
+		{Host: "registry.example.com", Mirrors: []string{"https://reg-mirror.example.com"}, CA: []byte("CA"), Auth: "cmVnOnB3"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Registries diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateDockerDaemonConfig(t *testing.T) {
+	regs := []Registry{
+		{Host: "10.96.0.0/12", Insecure: true},
+		{Host: DockerHub, Mirrors: []string{"https://mirror.example.com"}},
+		{Host: "quay.io", Mirrors: []string{"https://quay-mirror.example.com"}},
+	}
+	got, err := generateDockerDaemonConfig(nil, false, regs)
+	if err != nil {
+		t.Fatalf("generateDockerDaemonConfig: %v", err)
+	}
+	want := `{
+  "insecure-registries": [
+    "10.96.0.0/12"
+  ],
+  "registry-mirrors": [
+    "https://mirror.example.com"
+  ]
+}
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("daemon.json diff (-want +got):\n%s", diff)
+	}
+
+	got, err = generateDockerDaemonConfig(nil, true, nil)
+	if err != nil {
+		t.Fatalf("generateDockerDaemonConfig: %v", err)
+	}
+	if !strings.Contains(string(got), `"native.cgroupdriver=systemd"`) {
+		t.Errorf("daemon.json doesn't force systemd:\n%s", got)
+	}
+}
+
+func TestGenerateDockerDaemonConfigKeepsDefaults(t *testing.T) {
+	// the daemon.json of the ISO
+	existing := `{
+  "exec-opts": ["native.cgroupdriver=systemd"],
+  "log-driver": "json-file",
+  "log-opts": {"max-size": "100m"},
+  "registry-mirrors": ["https://removed.example.com"],
+  "storage-driver": "overlay2"
+}
+`
+	got, err := generateDockerDaemonConfig([]byte(existing), false, []Registry{{Host: "10.96.0.0/12", Insecure: true}})
+	if err != nil {
+		t.Fatalf("generateDockerDaemonConfig: %v", err)
+	}
+	want := `{
+  "exec-opts": [
+    "native.cgroupdriver=systemd"
+  ],
+  "insecure-registries": [
+    "10.96.0.0/12"
+  ],
+  "log-driver": "json-file",
+  "log-opts": {
+    "max-size": "100m"
+  },
+  "storage-driver": "overlay2"
+}
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("daemon.json diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestContainerdConfigRegistries(t *testing.T) {
+	regs := []Registry{
+		{Host: "10.96.0.0/12", Insecure: true},
+		{Host: "192.168.39.1:5000", Insecure: true},
+		{Host: DockerHub, Mirrors: []string{"https://mirror.example.com"}, Auth: "aHViOnB3"},
+		{Host: "registry.example.com", CA: []byte("CA")},
+	}
+	got, err := containerdConfig("", semver.MustParse("1.20.0"), false, regs, nil)
+	if err != nil {
+		t.Fatalf("containerdConfig: %v", err)
+	}
+	want := `    [plugins.cri.registry]
+      [plugins.cri.registry.mirrors]
+        [plugins.cri.registry.mirrors."docker.io"]
+          endpoint = ["https://mirror.example.com", "https://registry-1.docker.io"]
+        [plugins.cri.registry.mirrors."192.168.39.1:5000"]
+          endpoint = ["http://192.168.39.1:5000"]
+      [plugins.cri.registry.configs]
+        [plugins.cri.registry.configs."192.168.39.1:5000".tls]
+          insecure_skip_verify = true
+        [plugins.cri.registry.configs."docker.io".auth]
+          auth = "aHViOnB3"
+        [plugins.cri.registry.configs."registry.example.com".tls]
+          ca_file = "/etc/containerd/certs.d/registry.example.com/ca.crt"
+          insecure_skip_verify = false
+  [plugins.diff-service]
+`
+	if !strings.Contains(string(got), want) {
+		t.Errorf("config.toml doesn't contain:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestGenerateCRIORegistries(t *testing.T) {
+	regs := []Registry{
+		{Host: "10.96.0.0/12", Insecure: true},
+		{Host: "192.168.39.1:5000", Insecure: true},
+		{Host: DockerHub, Mirrors: []string{"https://mirror.example.com", "http://localhost:5000"}},
+		{Host: "registry.example.com", CA: []byte("CA")},
+	}
+	got, err := generateCRIORegistries(regs)
+	if err != nil {
+		t.Fatalf("generateCRIORegistries: %v", err)
+	}
+	want := `# generated by minikube, see the --registry-mirror, --insecure-registry and --registry-ca flags of minikube start
+unqualified-search-registries = ["docker.io"]
+
+[[registry]]
+prefix = "192.168.39.1:5000"
+location = "192.168.39.1:5000"
+insecure = true
+
+[[registry]]
+prefix = "docker.io"
+location = "docker.io"
+insecure = false
+
+[[registry.mirror]]
+location = "mirror.example.com"
+insecure = false
+
+[[registry.mirror]]
+location = "localhost:5000"
+insecure = true
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("registries.conf diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateKubeletCredentials(t *testing.T) {
+	got, err := generateKubeletCredentials([]Registry{{Host: DockerHub, Auth: "aHViOnB3"}, {Host: "quay.io"}})
+	if err != nil {
+		t.Fatalf("generateKubeletCredentials: %v", err)
+	}
+	want := `{
+  "auths": {
+    "https://index.docker.io/v1/": {
+      "auth": "aHViOnB3"
+    }
+  }
+}`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("config.json diff (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cruntime
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"sort"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// RuntimeHandlerBinDir is where the binaries of the runtime handlers are installed within the node
+	RuntimeHandlerBinDir = "/usr/local/bin"
+	// crioRuntimeHandlersFile registers the runtime handlers with CRI-O, next to crio.conf
+	crioRuntimeHandlersFile = "/etc/crio/crio.conf.d/10-minikube-runtime-handlers.conf"
+
+	// containerdRuncShim runs OCI runtimes compatible with the command line of runc
+	containerdRuncShim = "io.containerd.runc.v2"
+)
+
+// RuntimeHandler is an OCI runtime installed alongside the default one, which pods select with a RuntimeClass
+type RuntimeHandler struct {
+	// Name is the name of the handler in the configuration of the runtimes
+	Name string
+	// RuntimeClass is the name of the RuntimeClass selecting the handler, when it isn't the name of the handler
+	RuntimeClass string
+	// Description is a human readable description of the sandbox
+	Description string
+	// Version is the version of the binaries
+	Version string
+	// Binaries are the binaries installed into the node, starting with the OCI runtime
+	Binaries []string
+	// ContainerdRuntimeType is the containerd shim running the handler
+	ContainerdRuntimeType string
+
+	// locations returns where to download a binary for an architecture from, and the go-getter checksum verifying it
+	locations func(version, binary, arch string) (string, string, error)
+}
+
+// crunChecksums are the sha256 sums of the released crun binaries, by version and architecture
+var crunChecksums = map[string]string{}
+
+// RuntimeHandlers are the runtime handlers minikube can install, by name
+var RuntimeHandlers = map[string]RuntimeHandler{
+	"runsc": {
+		Name:                  "runsc",
+		RuntimeClass:          "gvisor",
+		Description:           "gVisor, an application kernel intercepting the system calls of the containers",
+		Version:               "20210201",
+		Binaries:              []string{"runsc", "containerd-shim-runsc-v1"},
+		ContainerdRuntimeType: "io.containerd.runsc.v1",
+		locations: func(version, binary, arch string) (string, string, error) {
+			a, ok := map[string]string{"amd64": "x86_64", "arm64": "aarch64"}[arch]
+			if !ok {
+				return "", "", fmt.Errorf("gVisor isn't released for %s", arch)
+			}
+			u := fmt.Sprintf("https://storage.googleapis.com/gvisor/releases/release/%s/%s/%s", version, a, binary)
+			return u, "file:" + u + ".sha512", nil
+		},
+	},
+	"crun": {
+		Name:                  "crun",
+		Description:           "crun, a fast and lightweight OCI runtime written in C",
+		Version:               "0.17",
+		Binaries:              []string{"crun"},
+		ContainerdRuntimeType: containerdRuncShim,
+		locations: func(version, binary, arch string) (string, string, error) {
+			if arch != "amd64" && arch != "arm64" {
+				return "", "", fmt.Errorf("crun isn't released for %s", arch)
+			}
+			// crun publishes no checksum files, the binaries are only downloaded when their checksum is pinned
+			sum, ok := crunChecksums[version+"/"+arch]
+			if !ok {
+				return "", "", fmt.Errorf("no checksum of crun %s is pinned for %s, build it and pass it with --binary", version, arch)
+			}
+			return fmt.Sprintf("https://github.com/containers/crun/releases/download/%s/crun-%s-linux-%s", version, version, arch), "sha256:" + sum, nil
+		},
+	},
+	"youki": {
+		Name:                  "youki",
+		Description:           "youki, an OCI runtime written in Rust",
+		Version:               "local",
+		Binaries:              []string{"youki"},
+		ContainerdRuntimeType: containerdRuncShim,
+		locations: func(version, binary, arch string) (string, string, error) {
+			return "", "", errors.New("youki publishes no binaries, build it and pass it with --binary")
+		},
+	},
+}
+
+// LookupRuntimeHandler returns the runtime handler of a name
+func LookupRuntimeHandler(name string) (RuntimeHandler, error) {
+	h, ok := RuntimeHandlers[name]
+	if !ok {
+		return RuntimeHandler{}, fmt.Errorf("unknown runtime handler %q, expected one of %v", name, RuntimeHandlerNames())
+	}
+	return h, nil
+}
+
+// RuntimeHandlerNames returns the sorted names of the runtime handlers
+func RuntimeHandlerNames() []string {
+	var names []string
+	for name := range RuntimeHandlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RuntimeHandlersOf returns the runtime handlers of names, such as the ones of a cluster
+func RuntimeHandlersOf(names []string) ([]RuntimeHandler, error) {
+	var hs []RuntimeHandler
+	for _, name := range names {
+		h, err := LookupRuntimeHandler(name)
+		if err != nil {
+			return nil, err
+		}
+		hs = append(hs, h)
+	}
+	return hs, nil
+}
+
+// Path returns the path to the OCI runtime of the handler within the node
+func (h RuntimeHandler) Path() string {
+	return path.Join(RuntimeHandlerBinDir, h.Binaries[0])
+}
+
+// ClassName returns the name of the RuntimeClass selecting the handler
+func (h RuntimeHandler) ClassName() string {
+	if h.RuntimeClass != "" {
+		return h.RuntimeClass
+	}
+	return h.Name
+}
+
+// Locations returns where to download a binary of the handler for an architecture from, and the go-getter checksum
+// verifying it, such as "sha256:<sum>" or "file:<url>"
+func (h RuntimeHandler) Locations(binary, arch string) (string, string, error) {
+	return h.locations(h.Version, binary, arch)
+}
+
+// RuncCompatible returns whether the OCI runtime of the handler is run by the runc shim of containerd
+func (h RuntimeHandler) RuncCompatible() bool {
+	return h.ContainerdRuntimeType == containerdRuncShim
+}
+
+// SupportsRuntimeHandlers returns whether runtime handlers can be registered with a container runtime
+func SupportsRuntimeHandlers(containerRuntime string) bool {
+	switch containerRuntime {
+	case "containerd", "crio", "cri-o":
+		return true
+	}
+	return false
+}
+
+var crioRuntimeHandlersTmpl = template.Must(template.New("crioRuntimeHandlers").Parse(`# generated by minikube, see minikube runtime enable
+{{ range . }}[crio.runtime.runtimes.{{ .Name }}]
+runtime_path = "{{ .Path }}"
+runtime_type = "oci"
+runtime_root = "/run/{{ .Name }}"
+
+{{ end }}`))
+
+// crioRuntimeHandlersConfig generates the CRI-O drop-in registering the runtime handlers
+func crioRuntimeHandlersConfig(hs []RuntimeHandler) ([]byte, error) {
+	var b bytes.Buffer
+	if err := crioRuntimeHandlersTmpl.Execute(&b, hs); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cruntime
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRuntimeHandlerLocations(t *testing.T) {
+	var tests = []struct {
+		handler  string
+		binary   string
+		arch     string
+		loc      string
+		checksum string
+		err      bool
+	}{
+		{handler: "runsc", binary: "runsc", arch: "amd64", loc: "https://storage.googleapis.com/gvisor/releases/release/20210201/x86_64/runsc", checksum: "file:https://storage.googleapis.com/gvisor/releases/release/20210201/x86_64/runsc.sha512"},
+		{handler: "runsc", binary: "containerd-shim-runsc-v1", arch: "arm64", loc: "https://storage.googleapis.com/gvisor/releases/release/20210201/aarch64/containerd-shim-runsc-v1", checksum: "file:https://storage.googleapis.com/gvisor/releases/release/20210201/aarch64/containerd-shim-runsc-v1.sha512"},
+		{handler: "runsc", binary: "runsc", arch: "s390x", err: true},
+		{handler: "crun", binary: "crun", arch: "s390x", err: true},
+		{handler: "youki", binary: "youki", arch: "amd64", err: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.handler+"/"+tc.binary+"/"+tc.arch, func(t *testing.T) {
+			h, err := LookupRuntimeHandler(tc.handler)
+			if err != nil {
+				t.Fatalf("LookupRuntimeHandler: %v", err)
+			}
+			loc, checksum, err := h.Locations(tc.binary, tc.arch)
+			if (err != nil) != tc.err {
+				t.Fatalf("Locations() error = %v, want error %v", err, tc.err)
+			}
+			if loc != tc.loc || checksum != tc.checksum {
+				t.Errorf("Locations() = %q, %q, want %q, %q", loc, checksum, tc.loc, tc.checksum)
+			}
+		})
+	}
+
+	// the crun binaries are downloaded only with a pinned checksum
+	crun := RuntimeHandlers["crun"]
+	if _, _, err := crun.Locations("crun", "amd64"); err == nil && crunChecksums[crun.Version+"/amd64"] == "" {
+		t.Errorf("Locations() of crun succeeded without a pinned checksum")
+	}
+	defer func(sums map[string]string) { crunChecksums = sums }(crunChecksums)
+	crunChecksums = map[string]string{crun.Version + "/amd64": "0123abcd"}
+	loc, checksum, err := crun.Locations("crun", "amd64")
+	if err != nil || loc != "https://github.com/containers/crun/releases/download/0.17/crun-0.17-linux-amd64" || checksum != "sha256:0123abcd" {
+		t.Errorf("Locations() of crun = %q, %q, %v, want the pinned sha256", loc, checksum, err)
+	}
+
+	if _, err := LookupRuntimeHandler("kata"); err == nil {
+		t.Errorf("LookupRuntimeHandler(kata) succeeded")
+	}
+}
+
+func TestContainerdConfigRuntimeHandlers(t *testing.T) {
+	hs, err := RuntimeHandlersOf([]string{"runsc", "crun"})
+	if err != nil {
+		t.Fatalf("RuntimeHandlersOf: %v", err)
+	}
+	got, err := containerdConfig("", semver.MustParse("1.20.0"), false, nil, hs)
+	if err != nil {
+		t.Fatalf("containerdConfig: %v", err)
+	}
+	want := `        runtime_root = ""
+      [plugins.cri.containerd.runtimes.runsc]
+        runtime_type = "io.containerd.runsc.v1"
+      [plugins.cri.containerd.runtimes.crun]
+        runtime_type = "io.containerd.runc.v2"
+        [plugins.cri.containerd.runtimes.crun.options]
+          BinaryName = "/usr/local/bin/crun"
+    [plugins.cri.cni]
+`
+	if !strings.Contains(string(got), want) {
+		t.Errorf("config.toml doesn't contain:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestCRIORuntimeHandlersConfig(t *testing.T) {
+	hs, err := RuntimeHandlersOf([]string{"runsc", "youki"})
+	if err != nil {
+		t.Fatalf("RuntimeHandlersOf: %v", err)
+	}
+	got, err := crioRuntimeHandlersConfig(hs)
+	if err != nil {
+		t.Fatalf("crioRuntimeHandlersConfig: %v", err)
+	}
+	want := `# generated by minikube, see minikube runtime enable
+[crio.runtime.runtimes.runsc]
+runtime_path = "/usr/local/bin/runsc"
+runtime_type = "oci"
+runtime_root = "/run/runsc"
+
+[crio.runtime.runtimes.youki]
+runtime_path = "/usr/local/bin/youki"
+runtime_type = "oci"
+runtime_root = "/run/youki"
+
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("runtime handlers drop-in diff (-want +got):\n%s", diff)
+	}
+}
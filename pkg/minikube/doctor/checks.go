@@ -0,0 +1,275 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package doctor
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/kverify"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/kubeconfig"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/reason"
+)
+
+const (
+	// certExpiryWarning is how long before expiry a certificate is reported
+	certExpiryWarning = 30 * 24 * time.Hour
+	// systemPodsTimeout is how long to wait for kube-system pods, well under the time kverify starts logging problems
+	systemPodsTimeout = 10 * time.Second
+	// minCPUs is the number of CPUs Kubernetes requires
+	minCPUs = 2
+)
+
+// checkDaemon checks that the docker or podman daemon is reachable and has the resources the cluster was configured with
+func checkDaemon(cc *config.ClusterConfig) Result {
+	si, err := oci.DaemonInfo(cc.Driver)
+	if err != nil {
+		k := reason.EnvDockerUnavailable
+		if cc.Driver == oci.Podman {
+			k = reason.EnvPodmanUnavailable
+		}
+		if cc.Driver == oci.Nerdctl {
+			k = reason.EnvNerdctlUnavailable
+		}
+		return problem(Fail, k, "daemon", "", err.Error())
+	}
+
+	if si.CPUs < minCPUs {
+		return problem(Fail, reason.RsrcInsufficientCores, "daemon", "", fmt.Sprintf("%s has %d CPUs available, but Kubernetes requires at least %d", cc.Driver, si.CPUs, minCPUs))
+	}
+
+	memMB := int(si.TotalMemory / 1024 / 1024)
+	if memMB < cc.Memory {
+		return problem(Warn, reason.RsrcInsufficientContainerMemory, "daemon", "", fmt.Sprintf("%s has %dMB of memory, but the cluster was configured with %dMB", cc.Driver, memMB, cc.Memory))
+	}
+
+	if len(si.Errors) > 0 {
+		return problem(Warn, reason.ProviderUnavailable, "daemon", "", fmt.Sprintf("%s reported errors: %v", cc.Driver, si.Errors))
+	}
+	return pass("daemon", "", fmt.Sprintf("%s has %d CPUs and %dMB of memory", cc.Driver, si.CPUs, memMB))
+}
+
+// checkCerts checks the expiry of the shared CA certificates and the profile certificates
+func checkCerts(cc *config.ClusterConfig) []Result {
+	paths := []string{localpath.CACert(), filepath.Join(localpath.MiniPath(), "proxy-client-ca.crt")}
+	profile, err := filepath.Glob(filepath.Join(localpath.Profile(cc.Name), "*.crt"))
+	if err != nil {
+		klog.Warningf("unable to list profile certificates: %v", err)
+	}
+	paths = append(paths, profile...)
+
+	results := []Result{}
+	for _, p := range paths {
+		if _, err := os.Stat(p); err != nil {
+			continue
+		}
+		results = append(results, checkCertExpiry(p, time.Now()))
+	}
+	return results
+}
+
+// checkCertExpiry checks whether a PEM certificate has expired, or is about to
+func checkCertExpiry(path string, now time.Time) Result {
+	name := filepath.Base(path)
+	notAfter, err := certNotAfter(path)
+	if err != nil {
+		return problem(Fail, reason.HostCertExpired, "certificate", "", fmt.Sprintf("%s: %v", name, err))
+	}
+	if now.After(notAfter) {
+		return problem(Fail, reason.HostCertExpired, "certificate", "", fmt.Sprintf("%s expired on %s", name, notAfter.Format(time.RFC3339)))
+	}
+	if notAfter.Sub(now) < certExpiryWarning {
+		return problem(Warn, reason.HostCertExpired, "certificate", "", fmt.Sprintf("%s expires on %s", name, notAfter.Format(time.RFC3339)))
+	}
+	return pass("certificate", "", fmt.Sprintf("%s is valid until %s", name, notAfter.Format(time.RFC3339)))
+}
+
+// certNotAfter returns the expiry of the first certificate in a PEM file
+func certNotAfter(path string) (time.Time, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "read")
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return time.Time{}, fmt.Errorf("no PEM data found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "parse")
+	}
+	return cert.NotAfter, nil
+}
+
+// checkDisk checks how full /var is within the machine
+func checkDisk(cr command.Runner, drvName string, machineName string) Result {
+	k := reason.RsrcInsufficientStorage
+	if drvName == oci.Docker {
+		k = reason.RsrcInsufficientDockerStorage
+	}
+	if drvName == oci.Podman {
+		k = reason.RsrcInsufficientPodmanStorage
+	}
+	if drvName == oci.Nerdctl {
+		k = reason.RsrcInsufficientNerdctlStorage
+	}
+
+	p, err := machine.DiskUsed(cr, "/var")
+	if err != nil {
+		return problem(Warn, k, "disk", machineName, fmt.Sprintf("unable to measure /var: %v", err))
+	}
+	return diskResult(p, k, machineName)
+}
+
+// diskResult classifies the capacity of /var, using the thresholds minikube start warns and exits at
+func diskResult(percentageFull int, k reason.Kind, machineName string) Result {
+	msg := fmt.Sprintf("/var is at %d%% of capacity", percentageFull)
+	if percentageFull >= 99 {
+		return problem(Fail, k, "disk", machineName, msg)
+	}
+	if percentageFull >= 85 {
+		return problem(Warn, k, "disk", machineName, msg)
+	}
+	return pass("disk", machineName, msg)
+}
+
+// checkClock checks the skew between the host and guest clocks
+func checkClock(h *host.Host, machineName string) Result {
+	d, skewed, err := machine.GuestClockSkew(h)
+	if err != nil {
+		return problem(Warn, reason.GuestClockSkew, "clock", machineName, fmt.Sprintf("unable to measure clock skew: %v", err))
+	}
+	if skewed {
+		return problem(Warn, reason.GuestClockSkew, "clock", machineName, fmt.Sprintf("guest clock is %s away from the host", d))
+	}
+	return pass("clock", machineName, fmt.Sprintf("guest clock is within %s of the host", d))
+}
+
+// checkKubelet checks that the kubelet service is running
+func checkKubelet(cr command.Runner, machineName string) Result {
+	st := kverify.ServiceStatus(cr, "kubelet")
+	if st != state.Running {
+		return problem(Fail, reason.GuestStatus, "kubelet", machineName, "kubelet is "+st.String())
+	}
+	return pass("kubelet", machineName, "kubelet is Running")
+}
+
+// checkRegistry checks DNS resolution and access to the image repository from within the machine
+func checkRegistry(cr command.Runner, imageRepository string, machineName string) []Result {
+	err := node.CheckRegistryAccess(cr, imageRepository)
+	if errors.Is(err, node.ErrRegistryDNS) {
+		return []Result{
+			problem(Fail, reason.InetRepo, "dns", machineName, err.Error()),
+			problem(Warn, reason.InetRepo, "registry", machineName, "skipped, as DNS resolution failed"),
+		}
+	}
+
+	results := []Result{pass("dns", machineName, "registry host name resolved")}
+	if err != nil {
+		return append(results, problem(Warn, reason.InetRepo, "registry", machineName, err.Error()))
+	}
+	return append(results, pass("registry", machineName, "image repository is reachable"))
+}
+
+// checkKubeconfig checks that the kubeconfig points at the control plane
+func checkKubeconfig(cc *config.ClusterConfig, hostname string, port int) Result {
+	if err := kubeconfig.VerifyEndpoint(cc.Name, hostname, port, kubeconfig.PathFromConfig(cc)); err != nil {
+		k := reason.HostKubeconfigUpdate
+		k.Advice = "Run 'minikube update-context' to point kubectl at the cluster"
+		return problem(Warn, k, "kubeconfig", "", err.Error())
+	}
+	return pass("kubeconfig", "", fmt.Sprintf("context %q points at %s:%d", cc.Name, hostname, port))
+}
+
+// checkAPIServer checks that the apiserver is running and healthy
+func checkAPIServer(cr command.Runner, hostname string, port int, machineName string) Result {
+	st, err := kverify.APIServerStatus(cr, hostname, port)
+	if err != nil {
+		return problem(Fail, reason.GuestStatus, "apiserver", machineName, err.Error())
+	}
+	if st == state.Paused {
+		k := reason.GuestStatus
+		k.Advice = "Run 'minikube unpause' to resume the cluster"
+		return problem(Warn, k, "apiserver", machineName, "apiserver is Paused")
+	}
+	if st != state.Running {
+		return problem(Fail, reason.GuestStatus, "apiserver", machineName, "apiserver is "+st.String())
+	}
+	return pass("apiserver", machineName, "apiserver is Running")
+}
+
+// checkKubernetes checks node conditions and system pods through the Kubernetes API
+func checkKubernetes(api libmachine.API, cc *config.ClusterConfig, bsName string) []Result {
+	client, err := kapi.Client(cc.Name)
+	if err != nil {
+		return []Result{problem(Fail, reason.InternalKubernetesClient, "kubernetes", "", err.Error())}
+	}
+
+	results := []Result{pressureResult(kverify.NodePressure(client))}
+
+	bs, cr, err := cluster.ControlPlaneBootstrapper(api, cc, bsName)
+	if err != nil {
+		return append(results, problem(Fail, reason.InternalBootstrapper, "system-pods", "", err.Error()))
+	}
+	r, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: cr})
+	if err != nil {
+		return append(results, problem(Fail, reason.InternalNewRuntime, "system-pods", "", err.Error()))
+	}
+	if err := kverify.WaitForSystemPods(r, bs, *cc, cr, client, time.Now(), systemPodsTimeout); err != nil {
+		return append(results, problem(Fail, reason.GuestStatus, "system-pods", "", err.Error()))
+	}
+	if err := kverify.ExpectAppsRunning(client, kverify.AppsRunningList); err != nil {
+		return append(results, problem(Warn, reason.GuestStatus, "system-pods", "", err.Error()))
+	}
+	return append(results, pass("system-pods", "", "kube-system pods are running"))
+}
+
+// pressureResult converts the outcome of kverify.NodePressure into a Result
+func pressureResult(err error) Result {
+	if err == nil {
+		return pass("node-pressure", "", "no nodes are under disk, memory, PID or network pressure")
+	}
+	switch err.(type) {
+	case *kverify.ErrDiskPressure:
+		return problem(Fail, reason.RsrcInsufficientStorage, "node-pressure", "", err.Error())
+	case *kverify.ErrMemoryPressure:
+		return problem(Fail, reason.RsrcInsufficientSysMemory, "node-pressure", "", err.Error())
+	case *kverify.ErrPIDPressure, *kverify.ErrNetworkNotReady:
+		return problem(Fail, reason.GuestStatus, "node-pressure", "", err.Error())
+	default:
+		return problem(Warn, reason.InternalKubernetesClient, "node-pressure", "", err.Error())
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package doctor
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/kverify"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/util"
+)
+
+func TestCheckCertExpiry(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "doctor")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	certPath := filepath.Join(tempDir, "ca.crt")
+	if err := util.GenerateCACert(certPath, filepath.Join(tempDir, "ca.key"), "minikubeCA"); err != nil {
+		t.Fatalf("generate cert: %v", err)
+	}
+	notAfter, err := certNotAfter(certPath)
+	if err != nil {
+		t.Fatalf("certNotAfter: %v", err)
+	}
+
+	garbage := filepath.Join(tempDir, "garbage.crt")
+	if err := ioutil.WriteFile(garbage, []byte("not a certificate"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	tests := []struct {
+		description string
+		path        string
+		now         time.Time
+		want        Status
+	}{
+		{"valid", certPath, time.Now(), Pass},
+		{"expiring", certPath, notAfter.Add(-24 * time.Hour), Warn},
+		{"expired", certPath, notAfter.Add(time.Hour), Fail},
+		{"not PEM", garbage, time.Now(), Fail},
+		{"missing", filepath.Join(tempDir, "missing.crt"), time.Now(), Fail},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			got := checkCertExpiry(tc.path, tc.now)
+			if got.Status != tc.want {
+				t.Errorf("checkCertExpiry(%s) = %s (%s), want %s", tc.path, got.Status, got.Message, tc.want)
+			}
+			if got.Status != Pass && got.Reason != reason.HostCertExpired.ID {
+				t.Errorf("checkCertExpiry(%s) reason = %q, want %q", tc.path, got.Reason, reason.HostCertExpired.ID)
+			}
+		})
+	}
+}
+
+func TestDiskResult(t *testing.T) {
+	tests := []struct {
+		percentageFull int
+		want           Status
+	}{
+		{10, Pass},
+		{84, Pass},
+		{85, Warn},
+		{98, Warn},
+		{99, Fail},
+		{100, Fail},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%d%%", tc.percentageFull), func(t *testing.T) {
+			got := diskResult(tc.percentageFull, reason.RsrcInsufficientStorage, "minikube")
+			if got.Status != tc.want {
+				t.Errorf("diskResult(%d) = %s, want %s", tc.percentageFull, got.Status, tc.want)
+			}
+			if got.Node != "minikube" {
+				t.Errorf("diskResult(%d) node = %q, want %q", tc.percentageFull, got.Node, "minikube")
+			}
+		})
+	}
+}
+
+func TestPressureResult(t *testing.T) {
+	tests := []struct {
+		description string
+		err         error
+		want        Status
+		wantReason  string
+	}{
+		{"none", nil, Pass, ""},
+		{"disk", &kverify.ErrDiskPressure{}, Fail, reason.RsrcInsufficientStorage.ID},
+		{"memory", &kverify.ErrMemoryPressure{}, Fail, reason.RsrcInsufficientSysMemory.ID},
+		{"pid", &kverify.ErrPIDPressure{}, Fail, reason.GuestStatus.ID},
+		{"network", &kverify.ErrNetworkNotReady{}, Fail, reason.GuestStatus.ID},
+		{"other", fmt.Errorf("connection refused"), Warn, reason.InternalKubernetesClient.ID},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			got := pressureResult(tc.err)
+			if got.Status != tc.want || got.Reason != tc.wantReason {
+				t.Errorf("pressureResult(%v) = (%s, %q), want (%s, %q)", tc.err, got.Status, got.Reason, tc.want, tc.wantReason)
+			}
+		})
+	}
+}
+
+func TestHealthy(t *testing.T) {
+	if !Healthy([]Result{pass("host", "", ""), {Check: "disk", Status: Warn}}) {
+		t.Errorf("Healthy() = false for warnings only, want true")
+	}
+	if Healthy([]Result{pass("host", "", ""), {Check: "kubelet", Status: Fail}}) {
+		t.Errorf("Healthy() = true with a failure, want false")
+	}
+}
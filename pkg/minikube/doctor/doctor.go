@@ -0,0 +1,147 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package doctor runs structured health checks against a minikube cluster
+package doctor
+
+import (
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/reason"
+)
+
+// Status is the outcome of a check
+type Status string
+
+const (
+	// Pass means the check found no problem
+	Pass Status = "pass"
+	// Warn means the check found a problem which may degrade the cluster
+	Warn Status = "warn"
+	// Fail means the check found a problem which breaks the cluster
+	Fail Status = "fail"
+)
+
+// Result holds the outcome of a single check
+type Result struct {
+	// Check is the name of the check, such as "apiserver" or "clock"
+	Check string
+	// Node is the machine the check ran against, empty for cluster-wide checks
+	Node string `json:",omitempty"`
+	// Status is the outcome of the check
+	Status Status
+	// Message is a human-readable description of what was found
+	Message string
+	// Reason is the ID of the reason.Kind describing the problem, if any
+	Reason string `json:",omitempty"`
+	// Advice is actionable text that the user should follow
+	Advice string `json:",omitempty"`
+	// URL is a reference URL for more information
+	URL string `json:",omitempty"`
+}
+
+func pass(check string, node string, msg string) Result {
+	return Result{Check: check, Node: node, Status: Pass, Message: msg}
+}
+
+func problem(st Status, k reason.Kind, check string, node string, msg string) Result {
+	return Result{Check: check, Node: node, Status: st, Message: msg, Reason: k.ID, Advice: k.Advice, URL: k.URL}
+}
+
+// Healthy returns whether none of the results failed
+func Healthy(results []Result) bool {
+	for _, r := range results {
+		if r.Status == Fail {
+			return false
+		}
+	}
+	return true
+}
+
+// Run runs all checks against a cluster, skipping the checks which depend on a component that failed
+func Run(api libmachine.API, cc *config.ClusterConfig, bsName string) []Result {
+	results := []Result{}
+	if driver.IsKIC(cc.Driver) {
+		results = append(results, checkDaemon(cc))
+	}
+	results = append(results, checkCerts(cc)...)
+
+	apiServerRunning := false
+	for _, n := range cc.Nodes {
+		machineName := config.MachineName(*cc, n)
+		klog.Infof("running checks against %s ...", machineName)
+
+		hr := checkHost(api, machineName)
+		results = append(results, hr)
+		if hr.Status != Pass {
+			continue
+		}
+
+		h, err := machine.LoadHost(api, machineName)
+		if err != nil {
+			results = append(results, problem(Fail, reason.GuestLoadHost, "host", machineName, err.Error()))
+			continue
+		}
+		cr, err := machine.CommandRunner(h)
+		if err != nil {
+			results = append(results, problem(Fail, reason.InternalCommandRunner, "host", machineName, err.Error()))
+			continue
+		}
+
+		results = append(results, checkDisk(cr, cc.Driver, machineName))
+		if driver.IsVM(cc.Driver) {
+			results = append(results, checkClock(h, machineName))
+		}
+		results = append(results, checkKubelet(cr, machineName))
+		results = append(results, checkRegistry(cr, cc.KubernetesConfig.ImageRepository, machineName)...)
+
+		if n.ControlPlane {
+			hostname, _, port, err := driver.ControlPlaneEndpoint(cc, &n, h.DriverName)
+			if err != nil {
+				results = append(results, problem(Fail, reason.DrvCPEndpoint, "apiserver", machineName, err.Error()))
+				continue
+			}
+			results = append(results, checkKubeconfig(cc, hostname, port))
+			ar := checkAPIServer(cr, hostname, port, machineName)
+			results = append(results, ar)
+			apiServerRunning = apiServerRunning || ar.Status == Pass
+		}
+	}
+
+	if apiServerRunning {
+		results = append(results, checkKubernetes(api, cc, bsName)...)
+	}
+	return results
+}
+
+// checkHost checks that a machine exists and is running
+func checkHost(api libmachine.API, machineName string) Result {
+	hs, err := machine.Status(api, machineName)
+	if err != nil {
+		return problem(Fail, reason.GuestStatus, "host", machineName, err.Error())
+	}
+	if hs == state.None.String() {
+		return problem(Fail, reason.GuestNodeRetrieve, "host", machineName, "the machine does not exist")
+	}
+	if hs != state.Running.String() {
+		return problem(Fail, reason.GuestStatus, "host", machineName, "the machine is "+hs)
+	}
+	return pass("host", machineName, "the machine is Running")
+}
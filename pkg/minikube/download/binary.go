@@ -21,25 +21,44 @@ import (
 	"os"
 	"path"
 	"runtime"
+	"strings"
 
 	"github.com/blang/semver"
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/out"
 )
 
-// binaryWithChecksumURL gets the location of a Kubernetes binary
-func binaryWithChecksumURL(binaryName, version, osName, archName string) (string, error) {
-	base := fmt.Sprintf("https://storage.googleapis.com/kubernetes-release/release/%s/bin/%s/%s/%s", version, osName, archName, binaryName)
-	v, err := semver.Make(version[1:])
+// binaryLocations returns where to get a Kubernetes binary, in fallback order
+func binaryLocations(binaryName, version, osName, archName string) []string {
+	return binaryArtifact.locations(fmt.Sprintf("%s/bin/%s/%s/%s", version, osName, archName, binaryName))
+}
+
+// binaryChecksumExt returns the extension of the checksum files of the binaries of a Kubernetes version
+func binaryChecksumExt(version string) (string, error) {
+	v, err := semver.Make(strings.TrimPrefix(version, "v"))
 	if err != nil {
 		return "", err
 	}
 
 	if v.GTE(semver.MustParse("1.17.0")) {
-		return fmt.Sprintf("%s?checksum=file:%s.sha256", base, base), nil
+		return ".sha256", nil
 	}
-	return fmt.Sprintf("%s?checksum=file:%s.sha1", base, base), nil
+	return ".sha1", nil
+}
+
+// binaryWithChecksumURLs gets the locations of a Kubernetes binary, verified by the checksum file next to it
+func binaryWithChecksumURLs(binaryName, version, osName, archName string) ([]string, error) {
+	ext, err := binaryChecksumExt(version)
+	if err != nil {
+		return nil, err
+	}
+	var urls []string
+	for _, loc := range binaryLocations(binaryName, version, osName, archName) {
+		urls = append(urls, fmt.Sprintf("%s?checksum=file:%s%s", loc, loc, ext))
+	}
+	return urls, nil
 }
 
 // Binary will download a binary onto the host
@@ -47,18 +66,30 @@ func Binary(binary, version, osName, archName string) (string, error) {
 	targetDir := localpath.MakeMiniPath("cache", osName, version)
 	targetFilepath := path.Join(targetDir, binary)
 
-	url, err := binaryWithChecksumURL(binary, version, osName, archName)
+	urls, err := binaryWithChecksumURLs(binary, version, osName, archName)
 	if err != nil {
 		return "", err
 	}
 
 	if _, err := os.Stat(targetFilepath); err == nil {
-		klog.Infof("Not caching binary, using %s", url)
-		return targetFilepath, nil
+		if err := verifyIfChanged(targetFilepath, verifyBinaryChecksum); err == nil || errors.Is(err, ErrNoChecksum) {
+			klog.Infof("Not caching binary, using %s", targetFilepath)
+			touch(targetFilepath)
+			return targetFilepath, nil
+		}
+		out.WarningT("The cached binary {{.name}} is corrupted, downloading it again: {{.error}}", out.V{"name": targetFilepath, "error": err})
+		if err := RemoveBinary(targetFilepath); err != nil {
+			return "", errors.Wrap(err, "removing corrupted binary")
+		}
+	}
+
+	if _, err := downloadFirst(urls, targetFilepath); err != nil {
+		return "", errors.Wrapf(err, "binary %s", binary)
 	}
 
-	if err := download(url, targetFilepath); err != nil {
-		return "", errors.Wrapf(err, "download failed: %s", url)
+	// the download verified the binary against the remote checksum, keep it to verify the cached binary
+	if err := saveBinaryChecksum(targetFilepath); err != nil {
+		klog.Warningf("saving checksum of %s: %v", targetFilepath, err)
 	}
 
 	if osName == runtime.GOOS && archName == runtime.GOARCH {
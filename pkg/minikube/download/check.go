@@ -0,0 +1,109 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"runtime"
+
+	"github.com/blang/semver"
+)
+
+// LocationCheck is the result of checking one location of an artifact
+type LocationCheck struct {
+	Location string
+	// Mirror is whether the location is in one of the configured mirrors
+	Mirror bool
+	Err    error
+}
+
+// ArtifactCheck is the result of checking every location of an artifact, in fallback order
+type ArtifactCheck struct {
+	Name      string
+	Locations []LocationCheck
+}
+
+// OK returns whether the artifact can be downloaded from at least one location
+func (a ArtifactCheck) OK() bool {
+	for _, l := range a.Locations {
+		if l.Err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// MirrorFailed returns whether a mirror of the artifact failed, so that it is downloaded from further down the fallback chain
+func (a ArtifactCheck) MirrorFailed() bool {
+	for _, l := range a.Locations {
+		if l.Mirror && l.Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// isMirrorLocation returns whether a location is in one of the configured mirrors, remote or local
+func isMirrorLocation(loc string) bool {
+	_, local := localPath(loc)
+	return local || fromMirror(loc)
+}
+
+// withExt returns the locations of the files next to locs with the extension ext, such as their checksum files
+func withExt(locs []string, ext string) []string {
+	var r []string
+	for _, loc := range locs {
+		r = append(r, loc+ext)
+	}
+	return r
+}
+
+// checkLocations checks that every location of an artifact serves it
+func checkLocations(name string, locs []string) ArtifactCheck {
+	c := ArtifactCheck{Name: name}
+	for _, loc := range locs {
+		c.Locations = append(c.Locations, LocationCheck{Location: loc, Mirror: isMirrorLocation(loc), Err: locationExists(loc)})
+	}
+	return c
+}
+
+// Check checks that the artifacts needed to start a cluster, and their checksum files,
+// are served by the configured mirrors or upstream, without downloading them
+func Check(k8sVersion, containerRuntime, driverName string, driverVersion semver.Version) []ArtifactCheck {
+	isoURLs := DefaultISOURLs()
+	checks := []ArtifactCheck{
+		checkLocations("ISO", isoURLs),
+		checkLocations("ISO checksum", withExt(isoURLs, ".sha256")),
+		checkLocations("preload", preloadLocations(k8sVersion, containerRuntime)),
+	}
+	// upstream preload checksums are object attributes, mirrors hold checksum files
+	if locs := preloadArtifact.mirrorLocations(TarballName(k8sVersion, containerRuntime)); len(locs) > 0 {
+		checks = append(checks, checkLocations("preload checksum", withExt(locs, ".checksum")))
+	}
+
+	if ext, err := binaryChecksumExt(k8sVersion); err == nil {
+		for _, b := range []string{"kubelet", "kubeadm", "kubectl"} {
+			locs := binaryLocations(b, k8sVersion, "linux", runtime.GOARCH)
+			checks = append(checks, checkLocations(b, locs), checkLocations(b+" checksum", withExt(locs, ext)))
+		}
+	}
+
+	if driverName != "" {
+		locs := driverLocations(driverName, driverVersion)
+		checks = append(checks, checkLocations(driverName, locs), checkLocations(driverName+" checksum", withExt(locs, ".sha256")))
+	}
+	return checks
+}
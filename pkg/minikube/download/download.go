@@ -44,7 +44,12 @@ func download(src string, dst string) error {
 	if out.JSON {
 		progress = getter.WithProgress(DefaultJSONOutput)
 	}
+	// partially downloaded artifacts are resumed
 	tmpDst := dst + ".download"
+	httpGetter, err := newHTTPGetter()
+	if err != nil {
+		return err
+	}
 	client := &getter.Client{
 		Src:     src,
 		Dst:     tmpDst,
@@ -52,9 +57,10 @@ func download(src string, dst string) error {
 		Mode:    getter.ClientModeFile,
 		Options: []getter.ClientOption{progress},
 		Getters: map[string]getter.Getter{
-			"file":  &getter.FileGetter{Copy: false},
-			"http":  &getter.HttpGetter{Netrc: false},
-			"https": &getter.HttpGetter{Netrc: false},
+			// copy rather than link to files, which may be in a mirror
+			"file":  &getter.FileGetter{Copy: true},
+			"http":  httpGetter,
+			"https": httpGetter,
 		},
 	}
 
@@ -77,11 +83,32 @@ func download(src string, dst string) error {
 
 	klog.Infof("Downloading: %s -> %s", src, dst)
 	if err := client.Get(); err != nil {
+		var cerr *getter.ChecksumError
+		if errors.As(err, &cerr) {
+			// don't resume from corrupted data
+			if err := os.Remove(tmpDst); err != nil && !os.IsNotExist(err) {
+				klog.Warningf("unable to remove %s: %v", tmpDst, err)
+			}
+		}
 		return errors.Wrapf(err, "getter: %+v", client)
 	}
 	return os.Rename(tmpDst, dst)
 }
 
+// downloadFirst downloads the first of the sources that succeeds, which are in fallback order, and returns it
+func downloadFirst(srcs []string, dst string) (string, error) {
+	var errs []string
+	for _, src := range srcs {
+		err := download(src, dst)
+		if err == nil {
+			return src, nil
+		}
+		klog.Warningf("Unable to download %s: %v", src, err)
+		errs = append(errs, fmt.Sprintf("%s: %v", src, err))
+	}
+	return "", fmt.Errorf("download failed:\n  %s", strings.Join(errs, "\n  "))
+}
+
 // withinUnitTset detects if we are in running within a unit-test
 func withinUnitTest() bool {
 	// Nope, it's the integration test
@@ -26,15 +26,24 @@ import (
 	"k8s.io/minikube/pkg/minikube/style"
 )
 
-func driverWithChecksumURL(name string, v semver.Version) string {
-	base := fmt.Sprintf("https://github.com/kubernetes/minikube/releases/download/v%s/%s", v, name)
-	return fmt.Sprintf("%s?checksum=file:%s.sha256", base, base)
+// driverLocations returns where to get a driver, in fallback order
+func driverLocations(name string, v semver.Version) []string {
+	return driverArtifact.locations(fmt.Sprintf("v%s/%s", v, name))
+}
+
+// driverWithChecksumURLs returns the locations of a driver, verified by the checksum file next to it
+func driverWithChecksumURLs(name string, v semver.Version) []string {
+	var urls []string
+	for _, loc := range driverLocations(name, v) {
+		urls = append(urls, fmt.Sprintf("%s?checksum=file:%s.sha256", loc, loc))
+	}
+	return urls
 }
 
 // Driver downloads an arbitrary driver
 func Driver(name string, destination string, v semver.Version) error {
 	out.Step(style.FileDownload, "Downloading driver {{.driver}}:", out.V{"driver": name})
-	if err := download(driverWithChecksumURL(name, v), destination); err != nil {
+	if _, err := downloadFirst(driverWithChecksumURLs(name, v), destination); err != nil {
 		return errors.Wrap(err, "download")
 	}
 
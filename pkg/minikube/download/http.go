@@ -0,0 +1,413 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"github.com/docker/go-units"
+	"github.com/hashicorp/go-getter"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/time/rate"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/util/retry"
+)
+
+const (
+	// DownloadRateLimitKey is the setting of the maximum download bandwidth per second, such as 2MB, shared by every connection
+	DownloadRateLimitKey = "download-rate-limit"
+	// DownloadConnectionsKey is the setting of the number of parallel connections to download each artifact over
+	DownloadConnectionsKey = "download-connections"
+
+	// downloadRetries is the number of times a chunk is requested again, from where it stopped, after a transfer error
+	downloadRetries = 5
+)
+
+// minChunkSize is the smallest chunk of an artifact downloaded over a connection of its own
+var minChunkSize int64 = 16 << 20
+
+// httpGetter is a go-getter Getter for http and https resuming partial downloads with range requests,
+// optionally over parallel connections and with a bandwidth limit
+type httpGetter struct {
+	client      *getter.Client
+	connections int
+	limiter     *rate.Limiter
+}
+
+// chunk is a range of an artifact, downloaded into a file of its own
+type chunk struct {
+	path  string
+	start int64
+	// end is the offset after the chunk, or -1 if the size of the artifact is unknown
+	end int64
+}
+
+// newHTTPGetter returns a getter configured by the download settings
+func newHTTPGetter() (*httpGetter, error) {
+	g := &httpGetter{connections: viper.GetInt(DownloadConnectionsKey)}
+	if g.connections < 1 {
+		g.connections = 1
+	}
+	if s := viper.GetString(DownloadRateLimitKey); s != "" {
+		limit, err := units.RAMInBytes(s)
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("invalid %s %q, expected a size per second such as 2MB", DownloadRateLimitKey, s)
+		}
+		g.limiter = rate.NewLimiter(rate.Limit(limit), int(limit))
+	}
+	return g, nil
+}
+
+// ClientMode returns the mode of artifacts, which are files
+func (g *httpGetter) ClientMode(*url.URL) (getter.ClientMode, error) {
+	return getter.ClientModeFile, nil
+}
+
+// SetClient sets the client, whose progress listener is reported to
+func (g *httpGetter) SetClient(c *getter.Client) {
+	g.client = c
+}
+
+// Get is only implemented by getters of directories
+func (g *httpGetter) Get(dst string, src *url.URL) error {
+	return fmt.Errorf("downloading directories is not supported: %s", src)
+}
+
+// GetFile downloads src into dst, resuming from the data already in dst or its chunk files
+func (g *httpGetter) GetFile(dst string, src *url.URL) error {
+	loc := src.String()
+	size, ranges := probe(loc)
+	chunks := g.chunks(dst, size, ranges)
+	if err := removeStaleChunks(dst, chunks); err != nil {
+		return err
+	}
+
+	current := int64(0)
+	for _, c := range chunks {
+		current += c.downloaded()
+	}
+	if current > 0 {
+		klog.Infof("Resuming %s from %d bytes", loc, current)
+	}
+
+	counter := newProgressCounter()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if g.client == nil || g.client.ProgressListener == nil {
+			_, _ = io.Copy(ioutil.Discard, counter)
+			return
+		}
+		total := size
+		if total < 0 {
+			total = 0
+		}
+		tracked := g.client.ProgressListener.TrackProgress(filepath.Base(src.EscapedPath()), current, total, counter)
+		_, _ = io.Copy(ioutil.Discard, tracked)
+		tracked.Close()
+	}()
+
+	var eg errgroup.Group
+	for _, c := range chunks {
+		c := c
+		eg.Go(func() error {
+			return g.fetchChunk(loc, c, ranges, counter)
+		})
+	}
+	err := eg.Wait()
+	counter.Close()
+	<-done
+	if err != nil {
+		return err
+	}
+
+	if len(chunks) == 1 {
+		return nil
+	}
+	return joinChunks(dst, chunks)
+}
+
+// probe returns the size of the artifact at loc, or -1 if unknown, and whether it can be requested by range
+func probe(loc string) (int64, bool) {
+	req, err := newRequest(http.MethodHead, loc)
+	if err != nil {
+		return -1, false
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		klog.Infof("HEAD %s: %v", loc, err)
+		return -1, false
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return -1, false
+	}
+	return resp.ContentLength, resp.ContentLength >= 0 && resp.Header.Get("Accept-Ranges") == "bytes"
+}
+
+// chunks splits an artifact into the chunks downloaded in parallel, which are in dst itself for a single connection
+func (g *httpGetter) chunks(dst string, size int64, ranges bool) []chunk {
+	n := int64(g.connections)
+	if !ranges || n == 1 || size < 2*minChunkSize {
+		return []chunk{{path: dst, start: 0, end: size}}
+	}
+	if size/n < minChunkSize {
+		n = size / minChunkSize
+	}
+
+	var chunks []chunk
+	for i := int64(0); i < n; i++ {
+		start, end := i*size/n, (i+1)*size/n
+		// the range is in the name, so that chunks of another number of connections aren't resumed
+		chunks = append(chunks, chunk{path: fmt.Sprintf("%s.part-%d-%d", dst, start, end), start: start, end: end})
+	}
+	return chunks
+}
+
+// removeStaleChunks removes the chunk files of downloads of dst split in other chunks
+func removeStaleChunks(dst string, chunks []chunk) error {
+	matches, err := filepath.Glob(dst + ".part-*")
+	if err != nil {
+		return err
+	}
+	current := map[string]bool{}
+	for _, c := range chunks {
+		current[c.path] = true
+	}
+	for _, m := range matches {
+		if !current[m] {
+			if err := os.Remove(m); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// downloaded returns the size of the data of the chunk already downloaded, discarding data beyond the chunk
+func (c chunk) downloaded() int64 {
+	fi, err := os.Stat(c.path)
+	if err != nil {
+		return 0
+	}
+	if c.end >= 0 && fi.Size() > c.end-c.start {
+		klog.Warningf("%s is larger than expected, downloading it again", c.path)
+		os.Remove(c.path)
+		return 0
+	}
+	return fi.Size()
+}
+
+// fetchChunk downloads the rest of a chunk, retrying with backoff from where transfer errors stopped it
+func (g *httpGetter) fetchChunk(loc string, c chunk, ranges bool, counter *progressCounter) error {
+	ctx := context.Background()
+	if g.client != nil && g.client.Ctx != nil {
+		ctx = g.client.Ctx
+	}
+
+	fetch := func() error {
+		f, err := os.OpenFile(c.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+		if err != nil {
+			return &backoff.PermanentError{Err: err}
+		}
+		defer f.Close()
+		fi, err := f.Stat()
+		if err != nil {
+			return &backoff.PermanentError{Err: err}
+		}
+		offset := fi.Size()
+		if c.end >= 0 && c.start+offset >= c.end {
+			return nil
+		}
+
+		req, err := newRequest(http.MethodGet, loc)
+		if err != nil {
+			return &backoff.PermanentError{Err: err}
+		}
+		req = req.WithContext(ctx)
+		if ranges {
+			rng := fmt.Sprintf("bytes=%d-", c.start+offset)
+			if c.end >= 0 {
+				rng += fmt.Sprint(c.end - 1)
+			}
+			req.Header.Set("Range", rng)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		var body io.Reader = resp.Body
+		switch {
+		case resp.StatusCode == http.StatusPartialContent:
+		case resp.StatusCode == http.StatusOK:
+			// the whole artifact was sent, skip the data before the rest of the chunk
+			if _, err := io.CopyN(ioutil.Discard, body, c.start+offset); err != nil {
+				return errors.Wrap(err, "skipping downloaded data")
+			}
+		case resp.StatusCode >= 400 && resp.StatusCode < 500:
+			return &backoff.PermanentError{Err: fmt.Errorf("bad response code: %d", resp.StatusCode)}
+		default:
+			return fmt.Errorf("bad response code: %d", resp.StatusCode)
+		}
+		if c.end >= 0 {
+			body = io.LimitReader(body, c.end-c.start-offset)
+		}
+		if g.limiter != nil {
+			body = &limitedReader{Reader: body, limiter: g.limiter, ctx: ctx}
+		}
+
+		n, err := io.Copy(f, &countingReader{Reader: body, counter: counter})
+		if err != nil {
+			return err
+		}
+		if c.end >= 0 && c.start+offset+n < c.end {
+			return io.ErrUnexpectedEOF
+		}
+		return nil
+	}
+	return retry.Expo(fetch, time.Second, 10*time.Minute, downloadRetries)
+}
+
+// joinChunks concatenates the chunk files into dst, and removes them
+func joinChunks(dst string, chunks []chunk) error {
+	w, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	for _, c := range chunks {
+		f, err := os.Open(c.path)
+		if err != nil {
+			w.Close()
+			return err
+		}
+		_, err = io.Copy(w, f)
+		f.Close()
+		if err != nil {
+			w.Close()
+			return errors.Wrapf(err, "joining %s", c.path)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	for _, c := range chunks {
+		if err := os.Remove(c.path); err != nil {
+			klog.Warningf("unable to remove %s: %v", c.path, err)
+		}
+	}
+	return nil
+}
+
+// limitedReader is a reader waiting for a rate limiter shared by every connection
+type limitedReader struct {
+	io.Reader
+	limiter *rate.Limiter
+	ctx     context.Context
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	// the limiter doesn't allow waiting for more than its burst at once
+	if len(p) > r.limiter.Burst() {
+		p = p[:r.limiter.Burst()]
+	}
+	n, err := r.Reader.Read(p)
+	if n > 0 {
+		if werr := r.limiter.WaitN(r.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
+
+// countingReader is a reader reporting the size of the data read to a progress counter
+type countingReader struct {
+	io.Reader
+	counter *progressCounter
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.counter.Add(int64(n))
+	return n, err
+}
+
+// progressCounter is a stream of as many bytes as were downloaded, so that a single progress tracker
+// reports the progress of parallel and retried requests
+type progressCounter struct {
+	lock    sync.Mutex
+	cond    *sync.Cond
+	pending int64
+	closed  bool
+}
+
+func newProgressCounter() *progressCounter {
+	c := &progressCounter{}
+	c.cond = sync.NewCond(&c.lock)
+	return c
+}
+
+// Add reports n more bytes downloaded
+func (c *progressCounter) Add(n int64) {
+	if n <= 0 {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.pending += n
+	c.cond.Broadcast()
+}
+
+// Read blocks until more bytes are downloaded, and returns as many bytes
+func (c *progressCounter) Read(p []byte) (int, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for c.pending == 0 && !c.closed {
+		c.cond.Wait()
+	}
+	if c.pending == 0 {
+		return 0, io.EOF
+	}
+	n := len(p)
+	if int64(n) > c.pending {
+		n = int(c.pending)
+	}
+	c.pending -= int64(n)
+	return n, nil
+}
+
+// Close ends the stream once the downloaded bytes are read
+func (c *progressCounter) Close() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.closed = true
+	c.cond.Broadcast()
+	return nil
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// artifactServer serves data, with or without range requests, and records the ranges requested
+type artifactServer struct {
+	data   []byte
+	ranges bool
+	// failures is the number of requests cut short before the rest of the data
+	failures int
+
+	lock      sync.Mutex
+	requested []string
+}
+
+func (s *artifactServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		s.lock.Lock()
+		s.requested = append(s.requested, r.Header.Get("Range"))
+		fail := s.failures > 0
+		s.failures--
+		s.lock.Unlock()
+		if fail {
+			// promise the whole data, and send half of it
+			w.Header().Set("Content-Length", strconv.Itoa(len(s.data)))
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(s.data[:len(s.data)/2])
+			return
+		}
+	}
+	if s.ranges {
+		http.ServeContent(w, r, "artifact", time.Time{}, bytes.NewReader(s.data))
+		return
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(s.data)))
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(s.data)
+	}
+}
+
+func randomData(t *testing.T, size int) []byte {
+	data := make([]byte, size)
+	if _, err := rand.New(rand.NewSource(1)).Read(data); err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func getFile(t *testing.T, g *httpGetter, srv *httptest.Server, dst string) {
+	u, err := url.Parse(srv.URL + "/artifact")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.GetFile(dst, u); err != nil {
+		t.Fatalf("GetFile: %v", err)
+	}
+}
+
+func TestGetFileResumes(t *testing.T) {
+	tests := []struct {
+		description string
+		ranges      bool
+		wantRange   string
+	}{
+		{description: "range requests", ranges: true, wantRange: "bytes=1000-4095"},
+		{description: "no range requests", ranges: false, wantRange: ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			s := &artifactServer{data: randomData(t, 4096), ranges: tc.ranges}
+			srv := httptest.NewServer(s)
+			defer srv.Close()
+
+			dir, err := ioutil.TempDir("", "download")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			dst := filepath.Join(dir, "artifact.download")
+			if err := ioutil.WriteFile(dst, s.data[:1000], 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			getFile(t, &httpGetter{connections: 1}, srv, dst)
+
+			got, err := ioutil.ReadFile(dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, s.data) {
+				t.Errorf("resumed download differs from the artifact")
+			}
+			if len(s.requested) != 1 || s.requested[0] != tc.wantRange {
+				t.Errorf("requested ranges = %q, want [%q]", s.requested, tc.wantRange)
+			}
+		})
+	}
+}
+
+func TestGetFileRetries(t *testing.T) {
+	s := &artifactServer{data: randomData(t, 4096), ranges: true, failures: 1}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "download")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	dst := filepath.Join(dir, "artifact.download")
+
+	getFile(t, &httpGetter{connections: 1}, srv, dst)
+
+	got, err := ioutil.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, s.data) {
+		t.Errorf("retried download differs from the artifact")
+	}
+	want := []string{"bytes=0-4095", "bytes=2048-4095"}
+	if strings.Join(s.requested, ",") != strings.Join(want, ",") {
+		t.Errorf("requested ranges = %q, want %q", s.requested, want)
+	}
+}
+
+func TestGetFileChunks(t *testing.T) {
+	defer func(size int64) { minChunkSize = size }(minChunkSize)
+	minChunkSize = 1024
+
+	s := &artifactServer{data: randomData(t, 4096+7), ranges: true}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "download")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	dst := filepath.Join(dir, "artifact.download")
+	// a chunk left by an interrupted download, and one of a download over another number of connections
+	if err := ioutil.WriteFile(dst+".part-0-1025", s.data[:100], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(dst+".part-0-2051", []byte("stale"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	getFile(t, &httpGetter{connections: 4}, srv, dst)
+
+	got, err := ioutil.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, s.data) {
+		t.Errorf("chunked download differs from the artifact")
+	}
+	if len(s.requested) != 4 {
+		t.Errorf("requested ranges = %q, want 4 chunks", s.requested)
+	}
+	for _, r := range s.requested {
+		if r == "bytes=0-1024" {
+			t.Errorf("the interrupted chunk was requested from the start")
+		}
+	}
+	if parts, _ := filepath.Glob(dst + ".part-*"); len(parts) != 0 {
+		t.Errorf("chunk files left behind: %v", parts)
+	}
+}
+
+func TestProgressCounter(t *testing.T) {
+	c := newProgressCounter()
+	go func() {
+		c.Add(3000)
+		c.Add(96)
+		c.Close()
+	}()
+	n, err := ioutil.ReadAll(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(n) != 3096 {
+		t.Errorf("read %d bytes, want 3096", len(n))
+	}
+}
+
+func TestNewHTTPGetter(t *testing.T) {
+	setMirrors(t, map[string]string{DownloadRateLimitKey: "2MB"})
+	g, err := newHTTPGetter()
+	if err != nil {
+		t.Fatalf("newHTTPGetter: %v", err)
+	}
+	if g.connections != 1 {
+		t.Errorf("connections = %d, want 1 by default", g.connections)
+	}
+	if g.limiter == nil || g.limiter.Limit() != 2<<20 {
+		t.Errorf("limiter = %+v, want 2MB per second", g.limiter)
+	}
+
+	viper.Set(DownloadRateLimitKey, "fast")
+	if _, err := newHTTPGetter(); err == nil {
+		t.Errorf("newHTTPGetter() with an invalid rate limit succeeded")
+	}
+}
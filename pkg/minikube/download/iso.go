@@ -37,14 +37,14 @@ import (
 
 const fileScheme = "file"
 
-// DefaultISOURLs returns a list of ISO URL's to consult by default, in priority order
+// DefaultISOURLs returns a list of ISO URL's to consult by default, in priority order, starting with the mirrors
 func DefaultISOURLs() []string {
 	v := version.GetISOVersion()
-	return []string{
+	return append(isoArtifact.mirrorLocations(fmt.Sprintf("minikube-%s.iso", v)),
 		fmt.Sprintf("https://storage.googleapis.com/minikube/iso/minikube-%s.iso", v),
 		fmt.Sprintf("https://github.com/kubernetes/minikube/releases/download/%s/minikube-%s.iso", v, v),
 		fmt.Sprintf("https://kubernetes.oss-cn-hangzhou.aliyuncs.com/minikube/iso/minikube-%s.iso", v),
-	}
+	)
 }
 
 // LocalISOResource returns a local file:// URI equivalent for a local or remote ISO path
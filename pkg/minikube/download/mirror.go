@@ -0,0 +1,219 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/bgentry/go-netrc/netrc"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"k8s.io/client-go/util/homedir"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// ArtifactMirrorKey is the setting of the mirrors to get every artifact from before upstream, comma separated.
+	// A mirror is a base URL or a local directory holding the upstream paths, such as <mirror>/minikube/iso/minikube-v1.17.0.iso
+	ArtifactMirrorKey = "artifact-mirror"
+	// ArtifactMirrorTokenKey is the setting of a bearer token sent to the mirrors, and never upstream
+	ArtifactMirrorTokenKey = "artifact-mirror-token"
+	// ArtifactMirrorNetrcKey is the setting to authenticate to the hosts in the netrc file, $NETRC or ~/.netrc
+	ArtifactMirrorNetrcKey = "artifact-mirror-netrc"
+
+	// ISOMirrorKey is the setting of a mirror holding the ISOs, such as <mirror>/minikube-v1.17.0.iso
+	ISOMirrorKey = "iso-mirror"
+	// BinaryMirrorKey is the setting of a mirror holding the Kubernetes binaries, such as <mirror>/v1.20.2/bin/linux/amd64/kubelet
+	BinaryMirrorKey = "binary-mirror"
+	// DriverMirrorKey is the setting of a mirror holding the drivers, such as <mirror>/v1.17.0/docker-machine-driver-kvm2
+	DriverMirrorKey = "driver-mirror"
+	// PreloadMirrorKey is the setting of a mirror holding the preloaded tarballs and their checksum files,
+	// such as <mirror>/preloaded-images-k8s-v8-v1.20.2-docker-overlay2-amd64.tar.lz4
+	PreloadMirrorKey = "preload-mirror"
+)
+
+// artifact is a kind of file downloaded by minikube
+type artifact struct {
+	// mirrorKey is the setting of the mirror of this kind of artifact only
+	mirrorKey string
+	// upstream is the base URL the artifacts are published at
+	upstream string
+}
+
+var (
+	isoArtifact     = artifact{mirrorKey: ISOMirrorKey, upstream: "https://storage.googleapis.com/minikube/iso"}
+	binaryArtifact  = artifact{mirrorKey: BinaryMirrorKey, upstream: "https://storage.googleapis.com/kubernetes-release/release"}
+	driverArtifact  = artifact{mirrorKey: DriverMirrorKey, upstream: "https://github.com/kubernetes/minikube/releases/download"}
+	preloadArtifact = artifact{mirrorKey: PreloadMirrorKey, upstream: "https://storage.googleapis.com/" + PreloadBucket}
+)
+
+// artifactMirrors returns the mirrors of every artifact, in fallback order
+func artifactMirrors() []string {
+	var mirrors []string
+	for _, m := range strings.Split(viper.GetString(ArtifactMirrorKey), ",") {
+		if m = strings.TrimSpace(m); m != "" {
+			mirrors = append(mirrors, m)
+		}
+	}
+	return mirrors
+}
+
+// mirrorLocations returns where the mirrors hold the file at rel of the artifact, in fallback order:
+// the mirror of the artifact, then the mirrors of every artifact
+func (a artifact) mirrorLocations(rel string) []string {
+	var locs []string
+	if m := viper.GetString(a.mirrorKey); m != "" {
+		locs = append(locs, mirrorLocation(m, rel))
+	}
+	u, err := url.Parse(a.upstream)
+	if err != nil {
+		klog.Errorf("invalid upstream %s: %v", a.upstream, err)
+		return locs
+	}
+	for _, m := range artifactMirrors() {
+		locs = append(locs, mirrorLocation(m, path.Join(u.Path, rel)))
+	}
+	return locs
+}
+
+// locations returns where to get the file at rel of the artifact, in fallback order, ending with upstream
+func (a artifact) locations(rel string) []string {
+	return append(a.mirrorLocations(rel), a.upstream+"/"+rel)
+}
+
+// isUpstream returns whether a location is the upstream of the artifact
+func (a artifact) isUpstream(loc string) bool {
+	return strings.HasPrefix(loc, a.upstream+"/")
+}
+
+// mirrorLocation returns the location of rel in a mirror, which is either a base URL or a local directory
+func mirrorLocation(mirror, rel string) string {
+	rel = strings.TrimPrefix(rel, "/")
+	if dir, ok := localMirror(mirror); ok {
+		return fileURI(filepath.Join(dir, filepath.FromSlash(rel)))
+	}
+	return strings.TrimSuffix(mirror, "/") + "/" + rel
+}
+
+// localMirror returns the directory of a mirror that is a file path rather than a URL
+func localMirror(mirror string) (string, bool) {
+	if mirror == "" || strings.HasPrefix(mirror, "http://") || strings.HasPrefix(mirror, "https://") {
+		return "", false
+	}
+	return strings.TrimPrefix(mirror, "file://"), true
+}
+
+// localPath returns the path of a file:// location, and false for remote locations
+func localPath(loc string) (string, bool) {
+	if !strings.HasPrefix(loc, "file://") {
+		return "", false
+	}
+	return filepath.FromSlash(strings.TrimPrefix(loc, "file://")), true
+}
+
+// fromMirror returns whether a location is in one of the configured mirrors
+func fromMirror(loc string) bool {
+	mirrors := artifactMirrors()
+	for _, a := range []artifact{isoArtifact, binaryArtifact, driverArtifact, preloadArtifact} {
+		if m := viper.GetString(a.mirrorKey); m != "" {
+			mirrors = append(mirrors, m)
+		}
+	}
+	for _, m := range mirrors {
+		if _, ok := localMirror(m); ok {
+			continue
+		}
+		if strings.HasPrefix(loc, strings.TrimSuffix(m, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// mirrorHeader returns the headers of requests to a location, which carry the mirror token to the mirrors only
+func mirrorHeader(loc string) http.Header {
+	token := viper.GetString(ArtifactMirrorTokenKey)
+	if token == "" || !fromMirror(loc) {
+		return nil
+	}
+	h := http.Header{}
+	h.Set("Authorization", "Bearer "+token)
+	return h
+}
+
+// netrcPath returns the path of the netrc file, like go-getter looks it up
+func netrcPath() string {
+	if p := os.Getenv("NETRC"); p != "" {
+		return p
+	}
+	name := ".netrc"
+	if runtime.GOOS == "windows" {
+		name = "_netrc"
+	}
+	return filepath.Join(homedir.HomeDir(), name)
+}
+
+// newRequest returns a request to a location, authenticated like the downloads
+func newRequest(method, loc string) (*http.Request, error) {
+	req, err := http.NewRequest(method, loc, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range mirrorHeader(loc) {
+		req.Header[k] = v
+	}
+	if viper.GetBool(ArtifactMirrorNetrcKey) && req.URL.User == nil {
+		if _, err := os.Stat(netrcPath()); err == nil {
+			n, err := netrc.ParseFile(netrcPath())
+			if err != nil {
+				return nil, errors.Wrap(err, "netrc")
+			}
+			if m := n.FindMachine(req.URL.Hostname()); m != nil {
+				req.SetBasicAuth(m.Login, m.Password)
+			}
+		}
+	}
+	return req, nil
+}
+
+// locationExists returns an error if a location doesn't serve a file, without downloading it
+func locationExists(loc string) error {
+	if p, ok := localPath(loc); ok {
+		_, err := os.Stat(p)
+		return err
+	}
+	req, err := newRequest(http.MethodHead, loc)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %s", resp.Status)
+	}
+	return nil
+}
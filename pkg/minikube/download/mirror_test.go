@@ -0,0 +1,115 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/version"
+)
+
+func setMirrors(t *testing.T, settings map[string]string) {
+	for k, v := range settings {
+		viper.Set(k, v)
+	}
+	t.Cleanup(func() {
+		for k := range settings {
+			viper.Set(k, "")
+		}
+	})
+}
+
+func TestLocations(t *testing.T) {
+	tests := []struct {
+		description string
+		settings    map[string]string
+		want        []string
+	}{
+		{
+			description: "upstream only",
+			want: []string{
+				"https://storage.googleapis.com/kubernetes-release/release/v1.20.2/bin/linux/amd64/kubelet",
+			},
+		},
+		{
+			description: "artifact mirrors keep the upstream path",
+			settings:    map[string]string{ArtifactMirrorKey: "https://a.example.com/, /srv/mirror"},
+			want: []string{
+				"https://a.example.com/kubernetes-release/release/v1.20.2/bin/linux/amd64/kubelet",
+				"file:///srv/mirror/kubernetes-release/release/v1.20.2/bin/linux/amd64/kubelet",
+				"https://storage.googleapis.com/kubernetes-release/release/v1.20.2/bin/linux/amd64/kubelet",
+			},
+		},
+		{
+			description: "the artifact mirror comes first",
+			settings: map[string]string{
+				ArtifactMirrorKey: "https://a.example.com",
+				BinaryMirrorKey:   "https://b.example.com/k8s",
+			},
+			want: []string{
+				"https://b.example.com/k8s/v1.20.2/bin/linux/amd64/kubelet",
+				"https://a.example.com/kubernetes-release/release/v1.20.2/bin/linux/amd64/kubelet",
+				"https://storage.googleapis.com/kubernetes-release/release/v1.20.2/bin/linux/amd64/kubelet",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			setMirrors(t, tc.settings)
+			got := binaryLocations("kubelet", "v1.20.2", "linux", "amd64")
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("binaryLocations() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMirrorHeader(t *testing.T) {
+	setMirrors(t, map[string]string{
+		ArtifactMirrorKey:      "https://a.example.com",
+		ArtifactMirrorTokenKey: "secret",
+	})
+
+	if h := mirrorHeader("https://a.example.com/minikube/iso/minikube-v1.17.0.iso"); h.Get("Authorization") != "Bearer secret" {
+		t.Errorf("mirror Authorization = %q, want the token", h.Get("Authorization"))
+	}
+	for _, loc := range []string{
+		"https://storage.googleapis.com/minikube/iso/minikube-v1.17.0.iso",
+		"https://a.example.com.evil.com/minikube/iso/minikube-v1.17.0.iso",
+	} {
+		if h := mirrorHeader(loc); h != nil {
+			t.Errorf("mirrorHeader(%q) = %v, want no token sent outside the mirrors", loc, h)
+		}
+	}
+}
+
+func TestDefaultISOURLs(t *testing.T) {
+	setMirrors(t, map[string]string{ISOMirrorKey: "/srv/iso"})
+
+	urls := DefaultISOURLs()
+	if len(urls) != 4 {
+		t.Fatalf("DefaultISOURLs() = %v, want the mirror and 3 upstream URLs", urls)
+	}
+	want := fileURI(filepath.Join("/srv/iso", fmt.Sprintf("minikube-%s.iso", version.GetISOVersion())))
+	if urls[0] != want {
+		t.Errorf("DefaultISOURLs()[0] = %s, want %s", urls[0], want)
+	}
+}
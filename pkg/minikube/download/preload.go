@@ -20,11 +20,13 @@ import (
 	"context"
 	"crypto/md5"
 	"fmt"
+	"io"
 	"io/ioutil"
-	"net/http"
 	"os"
+	"path"
 	"path/filepath"
 	"runtime"
+	"strings"
 
 	"cloud.google.com/go/storage"
 	"google.golang.org/api/option"
@@ -80,9 +82,9 @@ func TarballPath(k8sVersion, containerRuntime string) string {
 	return filepath.Join(targetDir(), TarballName(k8sVersion, containerRuntime))
 }
 
-// remoteTarballURL returns the URL for the remote tarball in GCS
-func remoteTarballURL(k8sVersion, containerRuntime string) string {
-	return fmt.Sprintf("https://storage.googleapis.com/%s/%s", PreloadBucket, TarballName(k8sVersion, containerRuntime))
+// preloadLocations returns where to get the tarball, in fallback order
+func preloadLocations(k8sVersion, containerRuntime string) []string {
+	return preloadArtifact.locations(TarballName(k8sVersion, containerRuntime))
 }
 
 // PreloadExists returns true if there is a preloaded tarball that can be used
@@ -106,21 +108,15 @@ func PreloadExists(k8sVersion, containerRuntime string, forcePreload ...bool) bo
 		return true
 	}
 
-	url := remoteTarballURL(k8sVersion, containerRuntime)
-	resp, err := http.Head(url)
-	if err != nil {
-		klog.Warningf("%s fetch error: %v", url, err)
-		return false
-	}
-
-	// note: err won't be set if it's a 404
-	if resp.StatusCode != 200 {
-		klog.Warningf("%s status code: %d", url, resp.StatusCode)
-		return false
+	for _, loc := range preloadLocations(k8sVersion, containerRuntime) {
+		if err := locationExists(loc); err != nil {
+			klog.Warningf("%s: %v", loc, err)
+			continue
+		}
+		klog.Infof("Found remote preload: %s", loc)
+		return true
 	}
-
-	klog.Infof("Found remote preload: %s", url)
-	return true
+	return false
 }
 
 // Preload caches the preloaded images tarball on the host machine
@@ -128,8 +124,15 @@ func Preload(k8sVersion, containerRuntime string) error {
 	targetPath := TarballPath(k8sVersion, containerRuntime)
 
 	if _, err := os.Stat(targetPath); err == nil {
-		klog.Infof("Found %s in cache, skipping download", targetPath)
-		return nil
+		if err := verifyIfChanged(targetPath, VerifyPreload); err == nil || errors.Is(err, ErrNoChecksum) {
+			klog.Infof("Found %s in cache, skipping download", targetPath)
+			touch(targetPath)
+			return nil
+		}
+		out.WarningT("The cached preload {{.name}} is corrupted, downloading it again: {{.error}}", out.V{"name": filepath.Base(targetPath), "error": err})
+		if err := RemovePreload(targetPath); err != nil {
+			return errors.Wrap(err, "removing corrupted preload")
+		}
 	}
 
 	// Make sure we support this k8s version
@@ -139,57 +142,165 @@ func Preload(k8sVersion, containerRuntime string) error {
 	}
 
 	out.Step(style.FileDownload, "Downloading Kubernetes {{.version}} preload ...", out.V{"version": k8sVersion})
-	url := remoteTarballURL(k8sVersion, containerRuntime)
+	return fetchPreload(filepath.Base(targetPath), targetPath)
+}
+
+// VerifyFailedPreload verifies the cached preload tarball of a Kubernetes version and container runtime which failed
+// to load, and removes it if it's corrupted for the next start to download it again
+func VerifyFailedPreload(k8sVersion, containerRuntime string) {
+	path := TarballPath(k8sVersion, containerRuntime)
+	err := VerifyPreload(path)
+	if !errors.Is(err, ErrCorrupted) {
+		klog.Infof("verifying %s after it failed to load: %v", path, err)
+		return
+	}
+	out.WarningT("The cached preload {{.name}} is corrupted, the next start will download it again: {{.error}}", out.V{"name": filepath.Base(path), "error": err})
+	if err := RemovePreload(path); err != nil {
+		klog.Warningf("removing corrupted preload: %v", err)
+	}
+}
+
+// fetchPreload downloads a preload tarball of a name from the first location that has it, and saves its checksum
+func fetchPreload(name, targetPath string) error {
+	var errs []string
+	for _, loc := range preloadArtifact.locations(name) {
+		err := download(loc, targetPath)
+		if err == nil {
+			err = savePreloadChecksum(loc, targetPath)
+		}
+		if err == nil {
+			return nil
+		}
+		klog.Warningf("Unable to get %s: %v", loc, err)
+		errs = append(errs, fmt.Sprintf("%s: %v", loc, err))
+		if err := RemovePreload(targetPath); err != nil {
+			return errors.Wrap(err, "removing failed download")
+		}
+	}
+	return fmt.Errorf("download failed:\n  %s", strings.Join(errs, "\n  "))
+}
+
+// AddPreload moves a locally built preload tarball into the cache, and saves its checksum
+func AddPreload(src, k8sVersion, containerRuntime string) (string, error) {
+	targetPath := TarballPath(k8sVersion, containerRuntime)
+	if err := RemovePreload(targetPath); err != nil {
+		return "", errors.Wrap(err, "removing cached preload")
+	}
+	if err := os.MkdirAll(targetDir(), 0755); err != nil {
+		return "", errors.Wrap(err, "mkdir")
+	}
+	if err := os.Rename(src, targetPath); err != nil {
+		// src may be on another filesystem
+		if err := copyFile(src, targetPath); err != nil {
+			return "", errors.Wrap(err, "copy")
+		}
+		if err := os.Remove(src); err != nil {
+			klog.Warningf("unable to remove %s: %v", src, err)
+		}
+	}
 
-	if err := download(url, targetPath); err != nil {
-		return errors.Wrapf(err, "download failed: %s", url)
+	checksum, err := fileChecksum(md5.New(), targetPath)
+	if err != nil {
+		return "", errors.Wrap(err, "checksum")
+	}
+	if err := ioutil.WriteFile(targetPath+".checksum", checksum, 0o644); err != nil {
+		return "", err
 	}
+	markVerified(targetPath)
+	return targetPath, nil
+}
 
-	if err := saveChecksumFile(k8sVersion, containerRuntime); err != nil {
+// copyFile atomically copies a file, like download does
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return errors.Wrap(err, "mkdir")
+	}
+	tmpDst := dst + ".download"
+	w, err := os.Create(tmpDst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, in); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpDst, dst)
+}
+
+// savePreloadChecksum saves the checksum of a preload tarball downloaded from loc and verifies it
+func savePreloadChecksum(loc, targetPath string) error {
+	if err := saveChecksumFile(loc, targetPath+".checksum"); err != nil {
 		return errors.Wrap(err, "saving checksum file")
 	}
 
-	if err := verifyChecksum(k8sVersion, containerRuntime, targetPath); err != nil {
+	if err := verifyChecksum(targetPath, targetPath+".checksum"); err != nil {
 		return errors.Wrap(err, "verify")
 	}
-
+	markVerified(targetPath)
 	return nil
 }
 
-func saveChecksumFile(k8sVersion, containerRuntime string) error {
-	klog.Infof("saving checksum for %s ...", TarballName(k8sVersion, containerRuntime))
+// saveChecksumFile saves the checksum of a preload tarball downloaded from loc. Mirrors keep the checksum next to
+// the tarball, in the format of the cache, and the ones which don't fall back to the checksum of the upstream tarball.
+func saveChecksumFile(loc, checksumPath string) error {
+	klog.Infof("saving checksum for %s ...", loc)
+	if !preloadArtifact.isUpstream(loc) {
+		err := download(loc+".checksum", checksumPath)
+		if err == nil {
+			klog.Infof("using the checksum of %s from the mirror", loc)
+			return nil
+		}
+		klog.Warningf("the mirror has no checksum for %s, falling back to the upstream checksum: %v", loc, err)
+	}
+
+	checksum, err := upstreamPreloadChecksum(path.Base(loc))
+	if err != nil {
+		return errors.Wrap(err, "upstream checksum")
+	}
+	klog.Infof("using the upstream checksum of %s", path.Base(loc))
+	return ioutil.WriteFile(checksumPath, checksum, 0o644)
+}
+
+// upstreamPreloadChecksum returns the md5 checksum of a preload tarball of the minikube bucket
+var upstreamPreloadChecksum = func(tarballName string) ([]byte, error) {
 	ctx := context.Background()
 	client, err := storage.NewClient(ctx, option.WithoutAuthentication())
 	if err != nil {
-		return errors.Wrap(err, "getting storage client")
+		return nil, errors.Wrap(err, "getting storage client")
 	}
-	attrs, err := client.Bucket(PreloadBucket).Object(TarballName(k8sVersion, containerRuntime)).Attrs(ctx)
+	attrs, err := client.Bucket(PreloadBucket).Object(tarballName).Attrs(ctx)
 	if err != nil {
-		return errors.Wrap(err, "getting storage object")
+		return nil, errors.Wrap(err, "getting storage object")
 	}
-	checksum := attrs.MD5
-	return ioutil.WriteFile(PreloadChecksumPath(k8sVersion, containerRuntime), checksum, 0o644)
+	return attrs.MD5, nil
 }
 
-// verifyChecksum returns true if the checksum of the local binary matches
-// the checksum of the remote binary
-func verifyChecksum(k8sVersion, containerRuntime, path string) error {
-	klog.Infof("verifying checksumm of %s ...", path)
-	// get md5 checksum of tarball path
-	contents, err := ioutil.ReadFile(path)
+// verifyChecksum returns an error wrapping ErrCorrupted if the md5 checksum of the local tarball
+// doesn't match the checksum of the remote tarball saved in checksumPath
+func verifyChecksum(path, checksumPath string) error {
+	klog.Infof("verifying checksum of %s ...", path)
+	remoteChecksum, err := ioutil.ReadFile(checksumPath)
 	if err != nil {
-		return errors.Wrap(err, "reading tarball")
+		return errors.Wrap(err, "reading checksum file")
 	}
-	checksum := md5.Sum(contents)
 
-	remoteChecksum, err := ioutil.ReadFile(PreloadChecksumPath(k8sVersion, containerRuntime))
+	// get md5 checksum of tarball path
+	checksum, err := fileChecksum(md5.New(), path)
 	if err != nil {
-		return errors.Wrap(err, "reading checksum file")
+		return errors.Wrap(err, "reading tarball")
 	}
 
-	// create a slice of checksum, which is [16]byte
-	if string(remoteChecksum) != string(checksum[:]) {
-		return fmt.Errorf("checksum of %s does not match remote checksum (%s != %s)", path, string(remoteChecksum), string(checksum[:]))
+	if string(remoteChecksum) != string(checksum) {
+		return errors.Wrapf(ErrCorrupted, "checksum of %s does not match remote checksum (%x != %x)", path, remoteChecksum, checksum)
 	}
 	return nil
 }
@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveChecksumFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "preload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(f func(string) ([]byte, error)) { upstreamPreloadChecksum = f }(upstreamPreloadChecksum)
+
+	name := TarballName("v1.20.2", "docker")
+	tests := []struct {
+		description string
+		mirror      string
+		upstream    map[string]string
+		want        string
+	}{
+		{"upstream", "", map[string]string{name: "upstream md5"}, "upstream md5"},
+		// the mirror can't be downloaded from within unit tests, like a mirror without the checksum file
+		{"mirror without checksum", "/srv/mirror", map[string]string{name: "upstream md5"}, "upstream md5"},
+		{"mirror of a tarball which isn't upstream", "/srv/mirror", map[string]string{}, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			setMirrors(t, map[string]string{PreloadMirrorKey: tc.mirror})
+			upstreamPreloadChecksum = func(tarballName string) ([]byte, error) {
+				c, ok := tc.upstream[tarballName]
+				if !ok {
+					return nil, fmt.Errorf("%s: not found", tarballName)
+				}
+				return []byte(c), nil
+			}
+
+			checksumPath := filepath.Join(dir, name+".checksum")
+			os.Remove(checksumPath)
+			err := saveChecksumFile(preloadArtifact.locations(name)[0], checksumPath)
+			if tc.want == "" {
+				if err == nil {
+					t.Errorf("saveChecksumFile succeeded without a checksum")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("saveChecksumFile: %v", err)
+			}
+			if got, err := ioutil.ReadFile(checksumPath); err != nil || string(got) != tc.want {
+				t.Errorf("saved checksum = %q, %v, want %q", got, err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+// runtimeBinaryPath returns where a binary of a runtime handler is cached
+func runtimeBinaryPath(handler, version, binary string) string {
+	return filepath.Join(localpath.MakeMiniPath("cache", "runtimes", handler, version), binary)
+}
+
+// RuntimeBinary downloads a binary of a runtime handler onto the host, verified by a go-getter checksum
+func RuntimeBinary(handler, version, binary, loc, checksum string) (string, error) {
+	dst := runtimeBinaryPath(handler, version, binary)
+	if _, err := os.Stat(dst); err == nil {
+		if err := verifyBinaryChecksum(dst); err == nil || errors.Is(err, ErrNoChecksum) {
+			klog.Infof("Not caching binary, using %s", dst)
+			touch(dst)
+			return dst, nil
+		}
+		out.WarningT("The cached binary {{.name}} is corrupted, downloading it again: {{.error}}", out.V{"name": dst, "error": err})
+		if err := RemoveBinary(dst); err != nil {
+			return "", errors.Wrap(err, "removing corrupted binary")
+		}
+	}
+	if loc == "" {
+		return "", errors.Errorf("%s of %s isn't cached, and has no download location", binary, handler)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return "", errors.Wrap(err, "mkdir")
+	}
+	if checksum == "" {
+		return "", errors.Errorf("%s of %s has no checksum to verify its download", binary, handler)
+	}
+	if err := download(loc+"?checksum="+checksum, dst); err != nil {
+		return "", errors.Wrapf(err, "binary %s", binary)
+	}
+	if err := saveBinaryChecksum(dst); err != nil {
+		klog.Warningf("saving checksum of %s: %v", dst, err)
+	}
+	return dst, nil
+}
+
+// CacheRuntimeBinary caches a local binary of a runtime handler, such as one built from source, in place of its download
+func CacheRuntimeBinary(handler, version, binary, src string) (string, error) {
+	dst := runtimeBinaryPath(handler, version, binary)
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return "", errors.Wrap(err, "mkdir")
+	}
+
+	r, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	w, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(w, r); err != nil {
+		w.Close()
+		return "", errors.Wrapf(err, "copying %s", src)
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	if err := saveBinaryChecksum(dst); err != nil {
+		klog.Warningf("saving checksum of %s: %v", dst, err)
+	}
+	return dst, nil
+}
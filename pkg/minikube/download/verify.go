@@ -0,0 +1,315 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+var (
+	// ErrCorrupted is returned when a cached artifact doesn't match its checksum
+	ErrCorrupted = errors.New("corrupted")
+	// ErrNoChecksum is returned when there is no checksum to verify a cached artifact with
+	ErrNoChecksum = errors.New("no checksum")
+)
+
+// binaryOSes are the operating systems binaries are cached for, in $MINIKUBE_HOME/cache/<os>/<version>
+var binaryOSes = []string{"linux", "darwin", "windows"}
+
+// touch marks a cached artifact as used, for the eviction of the least recently used ones
+func touch(path string) {
+	verified := unchangedSinceVerified(path)
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err != nil {
+		klog.Warningf("touching %s: %v", path, err)
+		return
+	}
+	if verified {
+		markVerified(path)
+	}
+}
+
+// verifiedPath is where the size and modification time of a cached artifact are recorded when its checksum is verified
+func verifiedPath(path string) string {
+	return path + ".verified"
+}
+
+// fileStamp identifies the content of a file by its size and modification time
+func fileStamp(fi os.FileInfo) string {
+	return fmt.Sprintf("%d %d", fi.Size(), fi.ModTime().UnixNano())
+}
+
+// markVerified records the size and modification time of a cached artifact whose checksum was verified
+func markVerified(path string) {
+	fi, err := os.Stat(path)
+	if err == nil {
+		err = ioutil.WriteFile(verifiedPath(path), []byte(fileStamp(fi)), 0o644)
+	}
+	if err != nil {
+		klog.Warningf("unable to mark %s as verified: %v", path, err)
+	}
+}
+
+// unchangedSinceVerified returns whether a cached artifact has the size and modification time it had when its checksum was last verified
+func unchangedSinceVerified(path string) bool {
+	stamp, err := ioutil.ReadFile(verifiedPath(path))
+	if err != nil {
+		return false
+	}
+	fi, err := os.Stat(path)
+	return err == nil && string(stamp) == fileStamp(fi)
+}
+
+// verifyIfChanged verifies a cached artifact, unless it didn't change since it was last verified.
+// This spares hashing the preload and the binaries on every start, 'minikube cache verify' hashes them regardless.
+func verifyIfChanged(path string, verify func(string) error) error {
+	if unchangedSinceVerified(path) {
+		klog.Infof("%s is unchanged since it was verified", path)
+		return nil
+	}
+	return verify(path)
+}
+
+// fileChecksum returns the checksum of a file
+func fileChecksum(h hash.Hash, path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// Preloads returns the paths of the cached preload tarballs
+func Preloads() ([]string, error) {
+	return filepath.Glob(filepath.Join(targetDir(), "*.tar.lz4"))
+}
+
+// VerifyPreload verifies a cached preload tarball with the checksum saved when it was downloaded
+func VerifyPreload(path string) error {
+	if _, err := os.Stat(path + ".checksum"); err != nil {
+		return ErrNoChecksum
+	}
+	if err := verifyChecksum(path, path+".checksum"); err != nil {
+		return err
+	}
+	markVerified(path)
+	return nil
+}
+
+// RemovePreload removes a cached preload tarball and its checksum
+func RemovePreload(path string) error {
+	for _, p := range []string{path, path + ".checksum", verifiedPath(path)} {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// RepairPreload downloads a cached preload tarball again, and replaces it once the download is verified
+func RepairPreload(path string) error {
+	tmp := path + ".repair"
+	if err := fetchPreload(filepath.Base(path), tmp); err != nil {
+		return err
+	}
+	for _, ext := range []string{"", ".checksum"} {
+		if err := os.Rename(tmp+ext, path+ext); err != nil {
+			return errors.Wrap(err, "replacing corrupted preload")
+		}
+	}
+	if err := RemovePreload(tmp); err != nil {
+		klog.Warningf("removing %s: %v", tmp, err)
+	}
+	markVerified(path)
+	return nil
+}
+
+// Binaries returns the paths of the cached Kubernetes binaries
+func Binaries() ([]string, error) {
+	paths := []string{}
+	for _, osName := range binaryOSes {
+		matches, err := filepath.Glob(localpath.MakeMiniPath("cache", osName, "v*", "*"))
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range matches {
+			if strings.HasSuffix(p, ".sha256") || strings.HasSuffix(p, ".verified") || strings.Contains(p, ".download") {
+				continue
+			}
+			if fi, err := os.Stat(p); err == nil && !fi.IsDir() {
+				paths = append(paths, p)
+			}
+		}
+	}
+	return paths, nil
+}
+
+// binaryFromPath returns the name, version and operating system of a cached binary
+func binaryFromPath(path string) (string, string, string) {
+	return filepath.Base(path), filepath.Base(filepath.Dir(path)), filepath.Base(filepath.Dir(filepath.Dir(path)))
+}
+
+// saveBinaryChecksum saves the sha256 checksum of a cached binary next to it
+func saveBinaryChecksum(path string) error {
+	sum, err := fileChecksum(sha256.New(), path)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path+".sha256", []byte(hex.EncodeToString(sum)), 0o644); err != nil {
+		return err
+	}
+	markVerified(path)
+	return nil
+}
+
+// verifyBinaryChecksum verifies a cached binary with the checksum saved when it was downloaded
+func verifyBinaryChecksum(path string) error {
+	want, err := ioutil.ReadFile(path + ".sha256")
+	if err != nil {
+		return ErrNoChecksum
+	}
+	if err := compareChecksum(sha256.New(), path, string(want)); err != nil {
+		return err
+	}
+	markVerified(path)
+	return nil
+}
+
+// compareChecksum returns an error wrapping ErrCorrupted if the checksum of a file isn't the hex checksum want
+func compareChecksum(h hash.Hash, path string, want string) error {
+	sum, err := fileChecksum(h, path)
+	if err != nil {
+		return errors.Wrapf(err, "reading %s", path)
+	}
+	if got := hex.EncodeToString(sum); got != strings.TrimSpace(want) {
+		return errors.Wrapf(ErrCorrupted, "checksum of %s does not match (%s != %s)", path, got, strings.TrimSpace(want))
+	}
+	return nil
+}
+
+// VerifyBinary verifies a cached binary with the checksum saved when it was downloaded,
+// or with the remote checksum for binaries cached by older minikube versions
+func VerifyBinary(path string) error {
+	err := verifyBinaryChecksum(path)
+	if !errors.Is(err, ErrNoChecksum) {
+		return err
+	}
+	if mockMode || withinUnitTest() {
+		return ErrNoChecksum
+	}
+
+	binary, version, osName := binaryFromPath(path)
+	ext, err := binaryChecksumExt(version)
+	if err != nil {
+		return err
+	}
+	for _, loc := range binaryLocations(binary, version, osName, runtime.GOARCH) {
+		checksum, err := remoteChecksum(loc + ext)
+		if err != nil {
+			klog.Warningf("%s%s: %v", loc, ext, err)
+			continue
+		}
+
+		var h hash.Hash = sha256.New()
+		if ext == ".sha1" {
+			h = sha1.New()
+		}
+		if err := compareChecksum(h, path, checksum); err != nil {
+			return err
+		}
+		return saveBinaryChecksum(path)
+	}
+	return ErrNoChecksum
+}
+
+// remoteChecksum fetches the hex checksum in a checksum file
+func remoteChecksum(loc string) (string, error) {
+	if p, ok := localPath(loc); ok {
+		body, err := ioutil.ReadFile(p)
+		if err != nil {
+			return "", err
+		}
+		return firstField(loc, body)
+	}
+
+	req, err := newRequest(http.MethodGet, loc)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("status %s", resp.Status)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return firstField(loc, body)
+}
+
+// firstField returns the checksum of a checksum file, which may be followed by the file name
+func firstField(loc string, body []byte) (string, error) {
+	fields := strings.Fields(string(body))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("empty checksum at %s", loc)
+	}
+	return fields[0], nil
+}
+
+// RemoveBinary removes a cached binary and its checksum
+func RemoveBinary(path string) error {
+	for _, p := range []string{path, path + ".sha256", verifiedPath(path)} {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// RepairBinary downloads a cached binary again
+func RepairBinary(path string) error {
+	if err := RemoveBinary(path); err != nil {
+		return err
+	}
+	binary, version, osName := binaryFromPath(path)
+	_, err := Binary(binary, version, osName, runtime.GOARCH)
+	return err
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestVerifyIfChanged(t *testing.T) {
+	dir, err := ioutil.TempDir("", "verify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "kubelet")
+	if err := ioutil.WriteFile(path, []byte("kubelet"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	verified := 0
+	verify := func(p string) error {
+		verified++
+		return verifyBinaryChecksum(p)
+	}
+	steps := []struct {
+		description string
+		change      func() error
+		want        int
+		wantErr     bool
+	}{
+		{"never verified", func() error { return nil }, 1, true},
+		{"unchanged", func() error { return nil }, 1, false},
+		{"touched", func() error { touch(path); return nil }, 1, false},
+		{"rewritten", func() error { return ioutil.WriteFile(path, []byte("kubelet, corrupted"), 0o644) }, 2, true},
+		{"modified", func() error { return os.Chtimes(path, time.Now(), time.Now().Add(time.Hour)) }, 3, true},
+	}
+	if err := ioutil.WriteFile(path+".sha256", []byte("not the checksum"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for i, s := range steps {
+		if i == 1 {
+			// the checksum is saved, as when the binary is downloaded
+			if err := saveBinaryChecksum(path); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := s.change(); err != nil {
+			t.Fatalf("%s: %v", s.description, err)
+		}
+		err := verifyIfChanged(path, verify)
+		if verified != s.want {
+			t.Errorf("%s: verified %d times, want %d", s.description, verified, s.want)
+		}
+		if (err != nil) != s.wantErr {
+			t.Errorf("%s: verifyIfChanged = %v, want error: %t", s.description, err, s.wantErr)
+		}
+	}
+}
+
+func TestRepairPreloadKeepsTarball(t *testing.T) {
+	home, err := ioutil.TempDir("", "minikube")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+	defer os.Setenv("MINIKUBE_HOME", os.Getenv("MINIKUBE_HOME"))
+	os.Setenv("MINIKUBE_HOME", home)
+
+	path := TarballPath("v1.20.2", "docker")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{path, path + ".checksum"} {
+		if err := ioutil.WriteFile(p, []byte("corrupted"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// downloads fail within unit tests
+	if err := RepairPreload(path); err == nil {
+		t.Fatalf("RepairPreload succeeded without a download")
+	}
+	for _, p := range []string{path, path + ".checksum"} {
+		if b, err := ioutil.ReadFile(p); err != nil || string(b) != "corrupted" {
+			t.Errorf("%s = %q, %v after a failed repair, want it kept", p, b, err)
+		}
+	}
+	if leftovers, _ := filepath.Glob(path + ".repair*"); len(leftovers) != 0 {
+		t.Errorf("failed repair left %v", leftovers)
+	}
+}
@@ -26,6 +26,7 @@ import (
 
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/drivers/qemu"
 	"k8s.io/minikube/pkg/minikube/registry"
 )
 
@@ -34,6 +35,8 @@ const (
 	Podman = "podman"
 	// Docker is Kubernetes in container using docker driver
 	Docker = "docker"
+	// Nerdctl is Kubernetes in container using containerd, through the nerdctl driver
+	Nerdctl = "nerdctl"
 	// Mock driver
 	Mock = "mock"
 	// None driver
@@ -42,6 +45,8 @@ const (
 	SSH = "ssh"
 	// KVM2 driver
 	KVM2 = "kvm2"
+	// QEMU driver, running qemu-system without libvirt
+	QEMU = "qemu"
 	// VirtualBox driver
 	VirtualBox = "virtualbox"
 	// HyperKit driver
@@ -91,7 +96,12 @@ func Supported(name string) bool {
 			return true
 		}
 	}
-	return false
+	return pluginSupported(registry.Driver(name))
+}
+
+// pluginSupported returns whether a driver is a plugin which reports being installed on this host
+func pluginSupported(d registry.DriverDef) bool {
+	return d.Plugin != "" && d.Status != nil && d.Status().Installed
 }
 
 // MachineType returns appropriate machine name for the driver
@@ -114,7 +124,17 @@ func MachineType(name string) string {
 
 // IsKIC checks if the driver is a Kubernetes in container
 func IsKIC(name string) bool {
-	return name == Docker || name == Podman
+	return name == Docker || name == Podman || name == Nerdctl
+}
+
+// IsQEMU checks if the driver is qemu
+func IsQEMU(name string) bool {
+	return name == QEMU
+}
+
+// IsQEMUUserNetwork checks if the driver is qemu on the user mode network, which is only reachable through forwarded ports
+func IsQEMUUserNetwork(name string, network string) bool {
+	return IsQEMU(name) && network != qemu.NetworkSocketVMnet
 }
 
 // IsDocker checks if the driver docker
@@ -156,6 +176,29 @@ func IsSSH(name string) bool {
 	return name == SSH
 }
 
+// ResizeMode describes when a driver can apply a changed CPU or memory allocation to an existing machine
+type ResizeMode int
+
+const (
+	// ResizeUnsupported means the machine must be deleted and recreated
+	ResizeUnsupported ResizeMode = iota
+	// ResizeLive means the machine is resized while it is running
+	ResizeLive
+	// ResizeOnRestart means the machine is resized the next time it starts
+	ResizeOnRestart
+)
+
+// Resize returns when a driver can apply a changed CPU or memory allocation to an existing machine
+func Resize(name string) ResizeMode {
+	switch name {
+	case Docker:
+		return ResizeLive
+	case KVM2:
+		return ResizeOnRestart
+	}
+	return ResizeUnsupported
+}
+
 // NeedsPortForward returns true if driver is unable provide direct IP connectivity
 func NeedsPortForward(name string) bool {
 	if !IsKIC(name) {
@@ -35,6 +35,7 @@ var supportedDrivers []string = func() []string {
 		Parallels,
 		VMwareFusion,
 		HyperKit,
+		QEMU,
 		VMware,
 		Docker,
 		SSH,
@@ -25,10 +25,12 @@ var supportedDrivers = []string{
 	VirtualBox,
 	VMwareFusion,
 	KVM2,
+	QEMU,
 	VMware,
 	None,
 	Docker,
 	Podman,
+	Nerdctl,
 	SSH,
 }
 
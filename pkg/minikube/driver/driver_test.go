@@ -48,6 +48,18 @@ func TestSupported(t *testing.T) {
 	if Supported("yabba?") {
 		t.Errorf("Supported(yabba?) is true")
 	}
+
+	// driver plugins are supported where they report being installed
+	for _, installed := range []bool{true, false} {
+		installed := installed
+		def := registry.DriverDef{Name: "plugin", Plugin: "/bin/minikube-driver-plugin", Status: func() registry.State { return registry.State{Installed: installed} }}
+		if got := pluginSupported(def); got != installed {
+			t.Errorf("pluginSupported() of a plugin installed=%v = %v", installed, got)
+		}
+	}
+	if pluginSupported(registry.DriverDef{Name: "builtin", Status: func() registry.State { return registry.State{Installed: true} }}) {
+		t.Errorf("pluginSupported() of a built-in driver is true")
+	}
 }
 
 func TestBareMetal(t *testing.T) {
@@ -59,14 +71,31 @@ func TestBareMetal(t *testing.T) {
 	}
 }
 
+func TestResize(t *testing.T) {
+	tests := map[string]ResizeMode{
+		Docker:     ResizeLive,
+		KVM2:       ResizeOnRestart,
+		Podman:     ResizeUnsupported,
+		VirtualBox: ResizeUnsupported,
+		None:       ResizeUnsupported,
+	}
+	for name, want := range tests {
+		if got := Resize(name); got != want {
+			t.Errorf("Resize(%s) = %d, want %d", name, got, want)
+		}
+	}
+}
+
 func TestMachineType(t *testing.T) {
 	types := map[string]string{
 		Podman:       "container",
 		Docker:       "container",
+		Nerdctl:      "container",
 		Mock:         "bare metal machine",
 		None:         "bare metal machine",
 		SSH:          "bare metal machine",
 		KVM2:         "VM",
+		QEMU:         "VM",
 		VirtualBox:   "VM",
 		HyperKit:     "VM",
 		VMware:       "VM",
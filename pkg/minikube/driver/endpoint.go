@@ -25,10 +25,30 @@ import (
 	"k8s.io/minikube/pkg/minikube/constants"
 )
 
-// ControlPlaneEndpoint returns the location where callers can reach this cluster
+// ControlPlaneEndpoint returns the location where callers can reach this cluster. Where the API server is reached through
+// a forwarded port, the virtual IP of HA clusters is out of reach of the host, which uses the port of the primary control plane.
 func ControlPlaneEndpoint(cc *config.ClusterConfig, cp *config.Node, driverName string) (string, net.IP, int, error) {
+	vip := cc.KubernetesConfig.APIServerHAVIP
+	if vip == "" || NeedsPortForward(driverName) || IsQEMUUserNetwork(driverName, cc.Network) {
+		return APIServerEndpoint(cc, cp, driverName)
+	}
+
+	// the control plane nodes of HA clusters share a virtual IP, held by any of them
+	hostname := vip
+	if cc.KubernetesConfig.APIServerName != constants.APIServerName {
+		hostname = cc.KubernetesConfig.APIServerName
+	}
+	ip := net.ParseIP(vip)
+	if ip == nil {
+		return hostname, ip, cp.Port, fmt.Errorf("failed to parse ip for %q", vip)
+	}
+	return hostname, ip, cp.Port, nil
+}
+
+// APIServerEndpoint returns the location where callers can reach the API server of the given control plane node
+func APIServerEndpoint(cc *config.ClusterConfig, cp *config.Node, driverName string) (string, net.IP, int, error) {
 	if NeedsPortForward(driverName) {
-		port, err := oci.ForwardedPort(cc.Driver, cc.Name, cp.Port)
+		port, err := oci.ForwardedPort(cc.Driver, config.MachineName(*cc, *cp), cp.Port)
 		hostname := oci.DaemonHost(driverName)
 
 		ip := net.ParseIP(hostname)
@@ -43,6 +63,16 @@ func ControlPlaneEndpoint(cc *config.ClusterConfig, cp *config.Node, driverName
 		return hostname, ip, port, err
 	}
 
+	if IsQEMUUserNetwork(driverName, cc.Network) {
+		// the apiserver port is forwarded to the same port of the host loopback
+		hostname := oci.DefaultBindIPV4
+		ip := net.ParseIP(hostname)
+		if cc.KubernetesConfig.APIServerName != constants.APIServerName {
+			hostname = cc.KubernetesConfig.APIServerName
+		}
+		return hostname, ip, cp.Port, nil
+	}
+
 	// https://github.com/kubernetes/minikube/issues/3878
 	hostname := cp.IP
 	if cc.KubernetesConfig.APIServerName != constants.APIServerName {
@@ -17,53 +17,109 @@ limitations under the License.
 package image
 
 import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/match"
 	"github.com/google/go-containerregistry/pkg/v1/tarball"
 	"github.com/juju/mutex"
 	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/minikube/constants"
-	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/util/lock"
 )
 
-// DeleteFromCacheDir deletes tar files stored in cache dir
+const (
+	// layoutDir is the OCI image layout of the image cache, in the image cache directory.
+	// Images share their blobs there, stored once by digest.
+	layoutDir = "oci"
+	// refNameAnnotation is the annotation with the name of an image in an OCI image layout
+	refNameAnnotation = "org.opencontainers.image.ref.name"
+)
+
+// CachedBlob is a blob of an image of the image cache
+type CachedBlob struct {
+	// Digest is the digest of the blob
+	Digest v1.Hash
+	// Path is the path of the blob on the host
+	Path string
+}
+
+// CachedArchive is an image of the image cache as the blobs of a docker archive
+type CachedArchive struct {
+	// Blobs are the config and layers of the image
+	Blobs []CachedBlob
+	// Manifest is the manifest.json of the docker archive, which refers to the blobs as <algorithm>/<hex>
+	Manifest []byte
+}
+
+// DeleteFromCacheDir deletes images from the image cache, and the blobs no other image uses
 func DeleteFromCacheDir(images []string) error {
+	return deleteFromCache(constants.ImageCacheDir, images)
+}
+
+// deleteFromCache deletes images from an image cache, and the blobs no other image uses
+func deleteFromCache(cacheDir string, images []string) error {
+	lp, release, err := lockLayout(cacheDir)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	for _, image := range images {
-		path := filepath.Join(constants.ImageCacheDir, image)
-		path = localpath.SanitizeCacheDir(path)
-		klog.Infoln("Deleting image in cache at ", path)
-		if err := os.Remove(path); err != nil {
+		key, err := cacheKey(image)
+		if err != nil {
 			return err
 		}
+		klog.Infof("Deleting image %s in cache at %s", key, lp)
+		if err := lp.RemoveDescriptors(match.Annotation(refNameAnnotation, key)); err != nil {
+			return errors.Wrapf(err, "removing %s", key)
+		}
+	}
+	if err := removeUnusedBlobs(lp); err != nil {
+		return err
 	}
-	return cleanImageCacheDir()
+	return cleanImageCacheDir(cacheDir)
 }
 
 // SaveToDir will cache images on the host
 //
-// The cache directory currently caches images using the imagename_tag
-// For example, k8s.gcr.io/kube-addon-manager:v6.5 would be
-// stored at $CACHE_DIR/k8s.gcr.io/kube-addon-manager_v6.5
+// The cache directory holds an OCI image layout, where images are stored as blobs by digest
+// and share their layers. The layout names each image with its full reference:
+// k8s.gcr.io/kube-addon-manager:v6.5 is the image annotated with "k8s.gcr.io/kube-addon-manager:v6.5"
+// in $CACHE_DIR/oci/index.json
 func SaveToDir(images []string, cacheDir string) error {
+	// create the layout before writing to it concurrently
+	_, release, err := lockLayout(cacheDir)
+	if err != nil {
+		return err
+	}
+	release()
+	if err := migrateTarballs(cacheDir); err != nil {
+		klog.Warningf("migrating cached tarballs: %v", err)
+	}
 	var g errgroup.Group
 	for _, image := range images {
 		image := image
 		g.Go(func() error {
-			dst := filepath.Join(cacheDir, image)
-			dst = localpath.SanitizeCacheDir(dst)
-			if err := saveToTarFile(image, dst); err != nil {
-				klog.Errorf("save image to file %q -> %q failed: %v", image, dst, err)
-				return errors.Wrapf(err, "caching image %q", dst)
+			if err := saveToLayout(image, cacheDir); err != nil {
+				klog.Errorf("save image to cache %q -> %q failed: %v", image, cacheDir, err)
+				return errors.Wrapf(err, "caching image %q", image)
 			}
-			klog.Infof("save to tar file %s -> %s succeeded", image, dst)
+			klog.Infof("save to cache %s -> %s succeeded", image, cacheDir)
 			return nil
 		})
 	}
@@ -74,44 +130,110 @@ func SaveToDir(images []string, cacheDir string) error {
 	return nil
 }
 
-// saveToTarFile caches an image
-func saveToTarFile(iname, rawDest string) error {
-	iname = normalizeTagName(iname)
-	start := time.Now()
-	defer func() {
-		klog.Infof("cache image %q -> %q took %s", iname, rawDest, time.Since(start))
-	}()
+// ExistsInCache returns whether an image is in the image cache
+func ExistsInCache(cacheDir string, image string) bool {
+	_, err := cachedImage(cacheDir, image)
+	return err == nil
+}
 
-	// OS-specific mangling of destination path
-	dst, err := localpath.DstPath(rawDest)
+// cacheKey returns the name of an image in the image cache, its normalized full reference
+func cacheKey(image string) (string, error) {
+	ref, err := name.ParseReference(normalizeTagName(image), name.WeakValidation)
 	if err != nil {
-		return errors.Wrap(err, "getting destination path")
+		return "", errors.Wrapf(err, "parsing image ref name for %s", image)
+	}
+	return ref.Name(), nil
+}
+
+// openLayout opens the OCI image layout of the image cache, creating it if needed
+func openLayout(cacheDir string) (layout.Path, error) {
+	p := filepath.Join(cacheDir, layoutDir)
+	if lp, err := layout.FromPath(p); err == nil {
+		return lp, nil
+	}
+	if err := os.MkdirAll(p, 0777); err != nil {
+		return "", errors.Wrapf(err, "making image cache directory: %s", p)
 	}
+	return layout.Write(p, empty.Index)
+}
 
-	spec := lock.PathMutexSpec(dst)
+// lockLayout opens the OCI image layout of the image cache, locked for changes to its index
+func lockLayout(cacheDir string) (layout.Path, func(), error) {
+	p := filepath.Join(cacheDir, layoutDir)
+	spec := lock.PathMutexSpec(p)
 	spec.Timeout = 10 * time.Minute
 	klog.Infof("acquiring lock: %+v", spec)
 	releaser, err := mutex.Acquire(spec)
 	if err != nil {
-		return errors.Wrapf(err, "unable to acquire lock for %+v", spec)
+		return "", nil, errors.Wrapf(err, "unable to acquire lock for %+v", spec)
+	}
+	lp, err := openLayout(cacheDir)
+	if err != nil {
+		releaser.Release()
+		return "", nil, err
 	}
-	defer releaser.Release()
+	return lp, releaser.Release, nil
+}
 
-	if _, err := os.Stat(dst); err == nil {
-		klog.Infof("%s exists", dst)
-		return nil
+// cachedImage returns an image of the image cache
+func cachedImage(cacheDir string, image string) (v1.Image, error) {
+	lp, d, err := cachedDescriptor(cacheDir, image)
+	if err != nil {
+		return nil, err
 	}
+	return lp.Image(d.Digest)
+}
 
-	if err := os.MkdirAll(filepath.Dir(dst), 0777); err != nil {
-		return errors.Wrapf(err, "making cache image directory: %s", dst)
+// cachedDescriptor returns the descriptor of an image in the index of the image cache
+func cachedDescriptor(cacheDir string, image string) (layout.Path, v1.Descriptor, error) {
+	key, err := cacheKey(image)
+	if err != nil {
+		return "", v1.Descriptor{}, err
+	}
+	lp, err := layout.FromPath(filepath.Join(cacheDir, layoutDir))
+	if err != nil {
+		return "", v1.Descriptor{}, errors.Wrap(err, "image cache")
+	}
+	ii, err := lp.ImageIndex()
+	if err != nil {
+		return "", v1.Descriptor{}, errors.Wrap(err, "image cache index")
+	}
+	im, err := ii.IndexManifest()
+	if err != nil {
+		return "", v1.Descriptor{}, errors.Wrap(err, "image cache index")
+	}
+	for _, d := range im.Manifests {
+		if d.Annotations[refNameAnnotation] == key {
+			// the time of the manifest is when the image was last used, for the eviction of the least recently used images
+			now := time.Now()
+			if err := os.Chtimes(blobPath(lp, d.Digest), now, now); err != nil {
+				klog.Warningf("touching %s: %v", key, err)
+			}
+			return lp, d, nil
+		}
+	}
+	return "", v1.Descriptor{}, fmt.Errorf("%s isn't in the image cache", key)
+}
+
+// saveToLayout caches an image
+func saveToLayout(iname string, cacheDir string) error {
+	start := time.Now()
+	defer func() {
+		klog.Infof("cache image %q -> %q took %s", iname, cacheDir, time.Since(start))
+	}()
+
+	if ExistsInCache(cacheDir, iname) {
+		klog.Infof("%s exists in the image cache", iname)
+		return nil
 	}
 
-	ref, err := name.ParseReference(iname, name.WeakValidation)
+	key, err := cacheKey(iname)
 	if err != nil {
-		return errors.Wrapf(err, "parsing image ref name for %s", iname)
+		return err
 	}
-	if ref == nil {
-		return errors.Wrapf(err, "nil reference for %s", iname)
+	ref, err := name.ParseReference(key, name.WeakValidation)
+	if err != nil {
+		return errors.Wrapf(err, "parsing image ref name for %s", iname)
 	}
 
 	img, err := retrieveImage(ref)
@@ -122,43 +244,330 @@ func saveToTarFile(iname, rawDest string) error {
 		return errors.Wrapf(err, "nil image for %s", iname)
 	}
 
-	err = writeImage(img, dst, ref)
+	lp, err := openLayout(cacheDir)
+	if err != nil {
+		return err
+	}
+	// the blobs are written without the lock, so that images download concurrently
+	if err := writeBlobs(lp, img); err != nil {
+		return err
+	}
+	return addToIndex(cacheDir, img, key)
+}
+
+// addToIndex names an image whose blobs are in the image cache in its index
+func addToIndex(cacheDir string, img v1.Image, key string) error {
+	lp, release, err := lockLayout(cacheDir)
 	if err != nil {
 		return err
 	}
+	defer release()
+	return lp.ReplaceImage(img, match.Annotation(refNameAnnotation, key), layout.WithAnnotations(map[string]string{refNameAnnotation: key}))
+}
 
-	klog.Infof("%s exists", dst)
-	return nil
+// blobPath returns the path of a blob of the image cache
+func blobPath(lp layout.Path, h v1.Hash) string {
+	return filepath.Join(string(lp), "blobs", h.Algorithm, h.Hex)
 }
 
-func writeImage(img v1.Image, dst string, ref name.Reference) error {
-	klog.Infoln("opening: ", dst)
-	f, err := ioutil.TempFile(filepath.Dir(dst), filepath.Base(dst)+".*.tmp")
+// writeBlobs writes the layers, config and manifest of an image to the image cache, verifying their digests
+func writeBlobs(lp layout.Path, img v1.Image) error {
+	layers, err := img.Layers()
+	if err != nil {
+		return errors.Wrap(err, "layers")
+	}
+	var g errgroup.Group
+	for _, l := range layers {
+		l := l
+		g.Go(func() error {
+			d, err := l.Digest()
+			if err != nil {
+				return err
+			}
+			return writeBlob(lp, d, l.Compressed)
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	cfgName, err := img.ConfigName()
+	if err != nil {
+		return errors.Wrap(err, "config name")
+	}
+	cfg, err := img.RawConfigFile()
+	if err != nil {
+		return errors.Wrap(err, "config")
+	}
+	if err := writeBlob(lp, cfgName, bytesOpener(cfg)); err != nil {
+		return err
+	}
+	d, err := img.Digest()
+	if err != nil {
+		return errors.Wrap(err, "digest")
+	}
+	m, err := img.RawManifest()
+	if err != nil {
+		return errors.Wrap(err, "manifest")
+	}
+	return writeBlob(lp, d, bytesOpener(m))
+}
+
+func bytesOpener(b []byte) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(b)), nil
+	}
+}
+
+// writeBlob writes a blob to the image cache unless it's there, through a temp file so that
+// an interrupted write doesn't leave a truncated blob behind
+func writeBlob(lp layout.Path, h v1.Hash, open func() (io.ReadCloser, error)) error {
+	dst := blobPath(lp, h)
+	if _, err := os.Stat(dst); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0777); err != nil {
+		return errors.Wrapf(err, "making blob directory: %s", dst)
+	}
+	r, err := open()
+	if err != nil {
+		return errors.Wrapf(err, "opening blob %s", h)
+	}
+	defer r.Close()
+
+	f, err := ioutil.TempFile(filepath.Dir(dst), h.Hex+".*.tmp")
 	if err != nil {
 		return err
 	}
 	defer func() {
 		// If we left behind a temp file, remove it.
-		_, err := os.Stat(f.Name())
-		if err == nil {
-			err = os.Remove(f.Name())
-			if err != nil {
+		if _, err := os.Stat(f.Name()); err == nil {
+			if err := os.Remove(f.Name()); err != nil {
 				klog.Warningf("failed to clean up the temp file %s: %v", f.Name(), err)
 			}
 		}
 	}()
 
-	err = tarball.Write(ref, img, f)
+	got, _, err := v1.SHA256(io.TeeReader(r, f))
 	if err != nil {
-		return errors.Wrap(err, "write")
+		f.Close()
+		return errors.Wrapf(err, "writing blob %s", h)
 	}
-	err = f.Close()
-	if err != nil {
+	if err := f.Close(); err != nil {
 		return errors.Wrap(err, "close")
 	}
-	err = os.Rename(f.Name(), dst)
+	if h.Algorithm == got.Algorithm && got != h {
+		return fmt.Errorf("blob %s has digest %s", h, got)
+	}
+	return os.Rename(f.Name(), dst)
+}
+
+// Archive returns an image of the image cache as the blobs of a docker archive
+func Archive(cacheDir string, image string) (*CachedArchive, error) {
+	img, err := cachedImage(cacheDir, image)
 	if err != nil {
-		return errors.Wrap(err, "rename")
+		return nil, err
+	}
+	key, err := cacheKey(image)
+	if err != nil {
+		return nil, err
+	}
+	ref, err := name.ParseReference(key, name.WeakValidation)
+	if err != nil {
+		return nil, err
+	}
+	m, err := tarball.ComputeManifest(map[name.Reference]v1.Image{ref: img})
+	if err != nil || len(m) != 1 {
+		return nil, errors.Wrapf(err, "docker archive manifest of %s", key)
+	}
+
+	lp := layout.Path(filepath.Join(cacheDir, layoutDir))
+	ca := &CachedArchive{}
+	cfgName, err := img.ConfigName()
+	if err != nil {
+		return nil, errors.Wrap(err, "config name")
+	}
+	ca.Blobs = append(ca.Blobs, CachedBlob{Digest: cfgName, Path: blobPath(lp, cfgName)})
+	m[0].Config = cfgName.Algorithm + "/" + cfgName.Hex
+
+	layers, err := img.Layers()
+	if err != nil {
+		return nil, errors.Wrap(err, "layers")
+	}
+	for i, l := range layers {
+		d, err := l.Digest()
+		if err != nil {
+			return nil, err
+		}
+		ca.Blobs = append(ca.Blobs, CachedBlob{Digest: d, Path: blobPath(lp, d)})
+		m[0].Layers[i] = d.Algorithm + "/" + d.Hex
+	}
+
+	if ca.Manifest, err = json.Marshal(m); err != nil {
+		return nil, errors.Wrap(err, "marshal manifest")
+	}
+	return ca, nil
+}
+
+// removeUnusedBlobs removes the blobs of the image cache that no image of its index uses
+func removeUnusedBlobs(lp layout.Path) error {
+	digests, err := usedBlobs(lp)
+	if err != nil {
+		return err
+	}
+	used := map[string]bool{}
+	for _, h := range digests {
+		used[blobPath(lp, h)] = true
+	}
+	return filepath.Walk(filepath.Join(string(lp), "blobs"), func(p string, info os.FileInfo, err error) error {
+		// temp files are blobs being written
+		if err != nil || info.IsDir() || used[p] || strings.HasSuffix(p, ".tmp") {
+			return nil
+		}
+		klog.Infof("removing unused blob %s", p)
+		return os.Remove(p)
+	})
+}
+
+// usedBlobs returns the digests of the manifests, configs and layers of the images of the index of the image cache
+func usedBlobs(lp layout.Path) ([]v1.Hash, error) {
+	ii, err := lp.ImageIndex()
+	if err != nil {
+		return nil, errors.Wrap(err, "image cache index")
+	}
+	im, err := ii.IndexManifest()
+	if err != nil {
+		return nil, errors.Wrap(err, "image cache index")
+	}
+	var used []v1.Hash
+	for _, d := range im.Manifests {
+		used = append(used, d.Digest)
+		img, err := lp.Image(d.Digest)
+		if err != nil {
+			return nil, errors.Wrapf(err, "image %s", d.Digest)
+		}
+		m, err := img.Manifest()
+		if err != nil {
+			// the blobs of an image with a corrupted manifest are cached again when it's repaired
+			klog.Warningf("manifest of %s: %v", d.Digest, err)
+			continue
+		}
+		used = append(used, m.Config.Digest)
+		for _, l := range m.Layers {
+			used = append(used, l.Digest)
+		}
+	}
+	return used, nil
+}
+
+// UsedBlobs returns the digests of the blobs the images of the image cache use, such as "sha256:<hex>"
+func UsedBlobs(cacheDir string) (map[string]bool, error) {
+	lp, err := layout.FromPath(filepath.Join(cacheDir, layoutDir))
+	if err != nil {
+		// there is no image cache before the first image is cached
+		return map[string]bool{}, nil
+	}
+	digests, err := usedBlobs(lp)
+	if err != nil {
+		return nil, err
+	}
+	used := map[string]bool{}
+	for _, h := range digests {
+		used[h.String()] = true
+	}
+	return used, nil
+}
+
+// migrateTarballs moves the images of the image cache of older versions, one tarball per image,
+// to the OCI image layout
+func migrateTarballs(cacheDir string) error {
+	tarballs := []string{}
+	err := filepath.Walk(cacheDir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if p == filepath.Join(cacheDir, layoutDir) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(p, ".tmp") {
+			tarballs = append(tarballs, p)
+		}
+		return nil
+	})
+	if err != nil || len(tarballs) == 0 {
+		return err
+	}
+
+	for _, p := range tarballs {
+		if err := migrateTarball(cacheDir, p); err != nil {
+			// the tarball is kept for the next run to migrate it again, the image is downloaded again when needed
+			klog.Warningf("unable to migrate cached image %s: %v", p, err)
+			continue
+		}
+		if err := os.Remove(p); err != nil {
+			return err
+		}
+	}
+	return cleanImageCacheDir(cacheDir)
+}
+
+// migrateTarball adds the image of a tarball to the OCI image layout of the image cache
+func migrateTarball(cacheDir string, p string) error {
+	tags, err := tarballTags(p)
+	if err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return fmt.Errorf("%s has no tag", p)
+	}
+	tag, err := name.NewTag(tags[0], name.WeakValidation)
+	if err != nil {
+		return err
+	}
+	img, err := tarball.ImageFromPath(p, &tag)
+	if err != nil {
+		return err
+	}
+	lp, err := openLayout(cacheDir)
+	if err != nil {
+		return err
+	}
+	if err := writeBlobs(lp, img); err != nil {
+		return err
+	}
+	klog.Infof("migrated cached image %s to %s", p, lp)
+	return addToIndex(cacheDir, img, tag.Name())
+}
+
+// tarballTags returns the tags of the image of a docker archive
+func tarballTags(p string) ([]string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%s has no manifest.json", p)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Name != "manifest.json" {
+			continue
+		}
+		var m tarball.Manifest
+		if err := json.NewDecoder(tr).Decode(&m); err != nil {
+			return nil, errors.Wrap(err, "manifest.json")
+		}
+		if len(m) != 1 {
+			return nil, fmt.Errorf("%s has %d images", p, len(m))
+		}
+		return m[0].RepoTags, nil
 	}
-	return nil
 }
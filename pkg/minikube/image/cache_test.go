@@ -0,0 +1,263 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+)
+
+func cacheDirForTest(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "image-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+// addForTest adds an image to the image cache as SaveToDir does, without retrieving it
+func addForTest(t *testing.T, cacheDir string, img v1.Image, image string) {
+	lp, err := openLayout(cacheDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writeBlobs(lp, img); err != nil {
+		t.Fatalf("writeBlobs: %v", err)
+	}
+	key, err := cacheKey(image)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := addToIndex(cacheDir, img, key); err != nil {
+		t.Fatalf("addToIndex: %v", err)
+	}
+}
+
+func countBlobs(t *testing.T, cacheDir string) int {
+	files, err := ioutil.ReadDir(filepath.Join(cacheDir, layoutDir, "blobs", "sha256"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(files)
+}
+
+func TestCacheSharesLayers(t *testing.T) {
+	cacheDir := cacheDirForTest(t)
+	base, err := random.Image(1024, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	extra, err := random.Layer(1024, "application/vnd.docker.image.rootfs.diff.tar.gzip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	derived, err := mutate.AppendLayers(base, extra)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addForTest(t, cacheDir, base, "example.com/base:v1")
+	// 2 layers, config and manifest
+	if got := countBlobs(t, cacheDir); got != 4 {
+		t.Errorf("blobs = %d, want 4", got)
+	}
+	addForTest(t, cacheDir, derived, "example.com/derived:v1")
+	// the layers of base are shared, 1 more layer, config and manifest
+	if got := countBlobs(t, cacheDir); got != 7 {
+		t.Errorf("blobs = %d, want 7", got)
+	}
+
+	if !ExistsInCache(cacheDir, "example.com/base:v1") {
+		t.Errorf("example.com/base:v1 isn't in the image cache")
+	}
+	if ExistsInCache(cacheDir, "example.com/base:v2") {
+		t.Errorf("example.com/base:v2 is in the image cache")
+	}
+	img, err := cachedImage(cacheDir, "example.com/derived:v1")
+	if err != nil {
+		t.Fatalf("cachedImage: %v", err)
+	}
+	want, _ := derived.Digest()
+	if got, _ := img.Digest(); got != want {
+		t.Errorf("digest = %s, want %s", got, want)
+	}
+}
+
+func TestRemoveUnusedBlobs(t *testing.T) {
+	cacheDir := cacheDirForTest(t)
+	base, err := random.Image(1024, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := random.Image(1024, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addForTest(t, cacheDir, base, "example.com/base:v1")
+	addForTest(t, cacheDir, other, "example.com/other:v1")
+
+	lp, release, err := lockLayout(cacheDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+	if err := lp.RemoveDescriptors(func(d v1.Descriptor) bool { return d.Annotations[refNameAnnotation] == "example.com/other:v1" }); err != nil {
+		t.Fatal(err)
+	}
+	if err := removeUnusedBlobs(lp); err != nil {
+		t.Fatalf("removeUnusedBlobs: %v", err)
+	}
+	if got := countBlobs(t, cacheDir); got != 4 {
+		t.Errorf("blobs = %d, want 4", got)
+	}
+	if _, err := cachedImage(cacheDir, "example.com/base:v1"); err != nil {
+		t.Errorf("cachedImage: %v", err)
+	}
+}
+
+func TestArchive(t *testing.T) {
+	cacheDir := cacheDirForTest(t)
+	img, err := random.Image(1024, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addForTest(t, cacheDir, img, "example.com/image:v1")
+
+	ca, err := Archive(cacheDir, "example.com/image:v1")
+	if err != nil {
+		t.Fatalf("Archive: %v", err)
+	}
+	if len(ca.Blobs) != 3 {
+		t.Fatalf("blobs = %v, want config and 2 layers", ca.Blobs)
+	}
+	for _, b := range ca.Blobs {
+		if _, err := os.Stat(b.Path); err != nil {
+			t.Errorf("blob %s: %v", b.Digest, err)
+		}
+	}
+	var m tarball.Manifest
+	if err := json.Unmarshal(ca.Manifest, &m); err != nil {
+		t.Fatalf("manifest: %v", err)
+	}
+	cfgName, _ := img.ConfigName()
+	if m[0].Config != "sha256/"+cfgName.Hex {
+		t.Errorf("config = %s, want sha256/%s", m[0].Config, cfgName.Hex)
+	}
+	if len(m[0].RepoTags) != 1 || m[0].RepoTags[0] != "example.com/image:v1" {
+		t.Errorf("repo tags = %v, want example.com/image:v1", m[0].RepoTags)
+	}
+	for i, l := range m[0].Layers {
+		if l != "sha256/"+ca.Blobs[i+1].Digest.Hex {
+			t.Errorf("layer %d = %s, want sha256/%s", i, l, ca.Blobs[i+1].Digest.Hex)
+		}
+	}
+}
+
+func TestMigrateTarballs(t *testing.T) {
+	cacheDir := cacheDirForTest(t)
+	img, err := random.Image(1024, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag, err := name.NewTag("example.com/legacy:v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := filepath.Join(cacheDir, "example.com", "legacy_v1")
+	if err := os.MkdirAll(filepath.Dir(p), 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := tarball.WriteToFile(p, tag, img); err != nil {
+		t.Fatal(err)
+	}
+	broken := filepath.Join(cacheDir, "example.org", "broken_v1")
+	if err := os.MkdirAll(filepath.Dir(broken), 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(broken, []byte("truncated"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := migrateTarballs(cacheDir); err != nil {
+		t.Fatalf("migrateTarballs: %v", err)
+	}
+	if _, err := os.Stat(p); !os.IsNotExist(err) {
+		t.Errorf("%s wasn't removed: %v", p, err)
+	}
+	if _, err := os.Stat(filepath.Dir(p)); !os.IsNotExist(err) {
+		t.Errorf("%s wasn't removed: %v", filepath.Dir(p), err)
+	}
+	// the tarballs which fail to migrate are kept for the next run
+	if _, err := os.Stat(broken); err != nil {
+		t.Errorf("%s was removed: %v", broken, err)
+	}
+	cached, err := cachedImage(cacheDir, "example.com/legacy:v1")
+	if err != nil {
+		t.Fatalf("cachedImage: %v", err)
+	}
+	want, _ := img.Digest()
+	if got, _ := cached.Digest(); got != want {
+		t.Errorf("digest = %s, want %s", got, want)
+	}
+}
+
+func TestUsedBlobs(t *testing.T) {
+	cacheDir := cacheDirForTest(t)
+	if used, err := UsedBlobs(cacheDir); err != nil || len(used) != 0 {
+		t.Errorf("UsedBlobs of an empty cache = %v, %v, want none", used, err)
+	}
+
+	img, err := random.Image(1024, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addForTest(t, cacheDir, img, "example.com/image:v1")
+	used, err := UsedBlobs(cacheDir)
+	if err != nil {
+		t.Fatalf("UsedBlobs: %v", err)
+	}
+	ca, err := Archive(cacheDir, "example.com/image:v1")
+	if err != nil {
+		t.Fatalf("Archive: %v", err)
+	}
+	// the manifest, which the archive leaves out, the config and the layers
+	if len(used) != len(ca.Blobs)+1 {
+		t.Errorf("UsedBlobs = %v, want %d blobs", used, len(ca.Blobs)+1)
+	}
+	for _, b := range ca.Blobs {
+		if !used[b.Digest.String()] {
+			t.Errorf("UsedBlobs lacks %s", b.Digest)
+		}
+	}
+
+	if err := deleteFromCache(cacheDir, []string{"example.com/image:v1"}); err != nil {
+		t.Fatalf("deleteFromCache: %v", err)
+	}
+	if used, err := UsedBlobs(cacheDir); err != nil || len(used) != 0 {
+		t.Errorf("UsedBlobs after deleting the image = %v, %v, want none", used, err)
+	}
+}
@@ -36,9 +36,9 @@ import (
 	"github.com/google/go-containerregistry/pkg/v1/tarball"
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/driver"
-	"k8s.io/minikube/pkg/minikube/localpath"
 )
 
 var defaultPlatform = v1.Platform{
@@ -97,30 +97,47 @@ func ExistsImageInDaemon(img string) bool {
 	return false
 }
 
-// LoadFromTarball checks if the image exists as a tarball and tries to load it to the local daemon
-// TODO: Pass in if we are loading to docker or podman so this function can also be used for podman
-func LoadFromTarball(binary, img string) error {
-	p := filepath.Join(constants.ImageCacheDir, img)
-	p = localpath.SanitizeCacheDir(p)
-
-	switch binary {
-	case driver.Podman:
+// LoadFromCache checks if the image exists in the image cache and tries to load it into the engine of the host,
+// the docker daemon or containerd through nerdctl
+func LoadFromCache(binary, img string) error {
+	if binary == driver.Podman {
 		return fmt.Errorf("not yet implemented, see issue #8426")
-	default:
-		tag, err := name.NewTag(Tag(img))
-		if err != nil {
-			return errors.Wrap(err, "new tag")
-		}
+	}
+	tag, err := name.NewTag(Tag(img))
+	if err != nil {
+		return errors.Wrap(err, "new tag")
+	}
 
-		i, err := tarball.ImageFromPath(p, &tag)
-		if err != nil {
-			return errors.Wrap(err, "tarball")
-		}
+	i, err := cachedImage(constants.ImageCacheDir, img)
+	if err != nil {
+		return errors.Wrap(err, "image cache")
+	}
 
-		_, err = daemon.Write(tag, i)
-		return err
+	if binary == driver.Nerdctl {
+		return loadWithNerdctl(tag, i)
 	}
+	_, err = daemon.Write(tag, i)
+	return err
+}
 
+// loadWithNerdctl loads an image into containerd with nerdctl, which reads it from a docker archive
+func loadWithNerdctl(tag name.Tag, img v1.Image) error {
+	dir, err := ioutil.TempDir("", "nerdctl-load")
+	if err != nil {
+		return errors.Wrap(err, "temp dir")
+	}
+	defer os.RemoveAll(dir)
+
+	p := filepath.Join(dir, "image.tar")
+	if err := tarball.WriteToFile(p, tag, img); err != nil {
+		return errors.Wrap(err, "write tarball")
+	}
+	cmd := oci.PrefixCmd(exec.Command(oci.Nerdctl, "load", "-i", p))
+	klog.Infof("Run: %v", cmd.Args)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "nerdctl load: %s", out)
+	}
+	return nil
 }
 
 // Tag returns just the image with the tag
@@ -187,8 +204,8 @@ func retrieveImage(ref name.Reference) (v1.Image, error) {
 	return img, err
 }
 
-func cleanImageCacheDir() error {
-	err := filepath.Walk(constants.ImageCacheDir, func(path string, info os.FileInfo, err error) error {
+func cleanImageCacheDir(dir string) error {
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		// If error is not nil, it's because the path was already deleted and doesn't exist
 		// Move on to next path
 		if err != nil {
@@ -0,0 +1,143 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/pkg/errors"
+
+	"k8s.io/minikube/pkg/minikube/download"
+)
+
+// CachedImage is an image of the image cache
+type CachedImage struct {
+	// Name is the full reference of the image
+	Name string
+	// LastUsed is when the image was last cached or loaded
+	LastUsed time.Time
+}
+
+// ListCached returns the images of the image cache, sorted by name
+func ListCached(cacheDir string) ([]CachedImage, error) {
+	lp, err := layout.FromPath(filepath.Join(cacheDir, layoutDir))
+	if err != nil {
+		// there is no image cache before the first image is cached
+		return nil, nil
+	}
+	ii, err := lp.ImageIndex()
+	if err != nil {
+		return nil, errors.Wrap(err, "image cache index")
+	}
+	im, err := ii.IndexManifest()
+	if err != nil {
+		return nil, errors.Wrap(err, "image cache index")
+	}
+	images := []CachedImage{}
+	for _, d := range im.Manifests {
+		ci := CachedImage{Name: d.Annotations[refNameAnnotation]}
+		if fi, err := os.Stat(blobPath(lp, d.Digest)); err == nil {
+			ci.LastUsed = fi.ModTime()
+		}
+		images = append(images, ci)
+	}
+	sort.Slice(images, func(i, j int) bool { return images[i].Name < images[j].Name })
+	return images, nil
+}
+
+// VerifyBlob returns an error wrapping download.ErrCorrupted if a blob of the image cache doesn't match its digest
+func VerifyBlob(b CachedBlob) error {
+	f, err := os.Open(b.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return errors.Wrapf(download.ErrCorrupted, "blob %s is missing", b.Digest)
+		}
+		return err
+	}
+	defer f.Close()
+	got, _, err := v1.SHA256(f)
+	if err != nil {
+		return errors.Wrapf(err, "reading blob %s", b.Digest)
+	}
+	if got != b.Digest {
+		return errors.Wrapf(download.ErrCorrupted, "blob %s has digest %s", b.Digest, got)
+	}
+	return nil
+}
+
+// VerifyCached verifies the manifest, config and layers of an image of the image cache with their digests
+func VerifyCached(cacheDir string, image string) error {
+	blobs, err := cachedBlobs(cacheDir, image)
+	if err != nil {
+		return err
+	}
+	for _, b := range blobs {
+		if err := VerifyBlob(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cachedBlobs returns the manifest, config and layers of an image of the image cache,
+// or only the manifest if it's corrupted
+func cachedBlobs(cacheDir string, image string) ([]CachedBlob, error) {
+	lp, d, err := cachedDescriptor(cacheDir, image)
+	if err != nil {
+		return nil, err
+	}
+	blobs := []CachedBlob{{Digest: d.Digest, Path: blobPath(lp, d.Digest)}}
+	if err := VerifyBlob(blobs[0]); err != nil {
+		return blobs, nil
+	}
+	img, err := lp.Image(d.Digest)
+	if err != nil {
+		return nil, err
+	}
+	m, err := img.Manifest()
+	if err != nil {
+		return nil, errors.Wrap(err, "manifest")
+	}
+	for _, desc := range append([]v1.Descriptor{m.Config}, m.Layers...) {
+		blobs = append(blobs, CachedBlob{Digest: desc.Digest, Path: blobPath(lp, desc.Digest)})
+	}
+	return blobs, nil
+}
+
+// RepairCached removes the corrupted blobs of an image of the image cache, which other images may share, and caches it again
+func RepairCached(cacheDir string, image string) error {
+	blobs, err := cachedBlobs(cacheDir, image)
+	if err != nil {
+		return err
+	}
+	for _, b := range blobs {
+		if err := VerifyBlob(b); errors.Is(err, download.ErrCorrupted) {
+			if err := os.Remove(b.Path); err != nil && !os.IsNotExist(err) {
+				return errors.Wrapf(err, "removing corrupted blob %s", b.Digest)
+			}
+		}
+	}
+	if err := deleteFromCache(cacheDir, []string{image}); err != nil {
+		return errors.Wrapf(err, "deleting %s", image)
+	}
+	return SaveToDir([]string{image}, cacheDir)
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/pkg/errors"
+
+	"k8s.io/minikube/pkg/minikube/download"
+)
+
+func TestVerifyCached(t *testing.T) {
+	cacheDir := cacheDirForTest(t)
+	img, err := random.Image(1024, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addForTest(t, cacheDir, img, "example.com/image:v1")
+
+	if err := VerifyCached(cacheDir, "example.com/image:v1"); err != nil {
+		t.Fatalf("VerifyCached: %v", err)
+	}
+
+	cached, err := ListCached(cacheDir)
+	if err != nil {
+		t.Fatalf("ListCached: %v", err)
+	}
+	if len(cached) != 1 || cached[0].Name != "example.com/image:v1" || cached[0].LastUsed.IsZero() {
+		t.Errorf("ListCached = %+v, want example.com/image:v1", cached)
+	}
+
+	ca, err := Archive(cacheDir, "example.com/image:v1")
+	if err != nil {
+		t.Fatalf("Archive: %v", err)
+	}
+	layer := ca.Blobs[len(ca.Blobs)-1]
+	if err := ioutil.WriteFile(layer.Path, []byte("truncated"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyBlob(layer); !errors.Is(err, download.ErrCorrupted) {
+		t.Errorf("VerifyBlob = %v, want ErrCorrupted", err)
+	}
+	if err := VerifyCached(cacheDir, "example.com/image:v1"); !errors.Is(err, download.ErrCorrupted) {
+		t.Errorf("VerifyCached = %v, want ErrCorrupted", err)
+	}
+	if err := VerifyCached(cacheDir, "example.com/other:v1"); err == nil || errors.Is(err, download.ErrCorrupted) {
+		t.Errorf("VerifyCached of a missing image = %v, want an error", err)
+	}
+}
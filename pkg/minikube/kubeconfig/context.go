@@ -17,7 +17,6 @@ limitations under the License.
 package kubeconfig
 
 import (
-	"github.com/pkg/errors"
 	"k8s.io/client-go/tools/clientcmd/api"
 	"k8s.io/klog/v2"
 )
@@ -28,21 +27,15 @@ func UnsetCurrentContext(machineName string, configPath ...string) error {
 	if configPath != nil {
 		fPath = configPath[0]
 	}
-	kCfg, err := readOrNew(fPath)
-	if err != nil {
-		return errors.Wrap(err, "Error getting kubeconfig status")
-	}
-
-	// Unset current-context only if profile is the current-context
-	if kCfg.CurrentContext == machineName {
-		kCfg.CurrentContext = ""
-		if err := writeToFile(kCfg, fPath); err != nil {
-			return errors.Wrap(err, "writing kubeconfig")
+	_, err := modify(fPath, machineName, func(kcfg *api.Config) (bool, error) {
+		// Unset current-context only if profile is the current-context
+		if kcfg.CurrentContext != machineName {
+			return false, nil
 		}
-		return nil
-	}
-
-	return nil
+		kcfg.CurrentContext = ""
+		return true, nil
+	})
+	return err
 }
 
 // SetCurrentContext sets the kubectl's current-context
@@ -51,12 +44,11 @@ func SetCurrentContext(name string, configPath ...string) error {
 	if configPath != nil {
 		fPath = configPath[0]
 	}
-	kcfg, err := readOrNew(fPath)
-	if err != nil {
-		return errors.Wrap(err, "Error getting kubeconfig status")
-	}
-	kcfg.CurrentContext = name
-	return writeToFile(kcfg, fPath)
+	_, err := modify(fPath, name, func(kcfg *api.Config) (bool, error) {
+		kcfg.CurrentContext = name
+		return true, nil
+	})
+	return err
 }
 
 // DeleteContext deletes the specified machine's kubeconfig context
@@ -65,26 +57,20 @@ func DeleteContext(machineName string, configPath ...string) error {
 	if configPath != nil {
 		fPath = configPath[0]
 	}
-	kcfg, err := readOrNew(fPath)
-	if err != nil {
-		return errors.Wrap(err, "Error getting kubeconfig status")
-	}
-
-	if kcfg == nil || api.IsConfigEmpty(kcfg) {
-		klog.V(2).Info("kubeconfig is empty")
-		return nil
-	}
+	_, err := modify(fPath, machineName, func(kcfg *api.Config) (bool, error) {
+		if api.IsConfigEmpty(kcfg) {
+			klog.V(2).Info("kubeconfig is empty")
+			return false, nil
+		}
 
-	delete(kcfg.Clusters, machineName)
-	delete(kcfg.AuthInfos, machineName)
-	delete(kcfg.Contexts, machineName)
+		delete(kcfg.Clusters, machineName)
+		delete(kcfg.AuthInfos, machineName)
+		delete(kcfg.Contexts, machineName)
 
-	if kcfg.CurrentContext == machineName {
-		kcfg.CurrentContext = ""
-	}
-
-	if err := writeToFile(kcfg, fPath); err != nil {
-		return errors.Wrap(err, "writing kubeconfig")
-	}
-	return nil
+		if kcfg.CurrentContext == machineName {
+			kcfg.CurrentContext = ""
+		}
+		return true, nil
+	})
+	return err
 }
@@ -0,0 +1,41 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/tools/clientcmd/api/latest"
+)
+
+// Export returns a standalone kubeconfig which only holds the cluster, user and context
+// described by kcs, with that context as the current-context.
+// Unlike Update, it never reads or modifies the user's kubeconfig.
+func Export(kcs *Settings) ([]byte, error) {
+	kcfg := api.NewConfig()
+	if err := PopulateFromSettings(kcs, kcfg); err != nil {
+		return nil, errors.Wrap(err, "populating kubeconfig")
+	}
+	kcfg.CurrentContext = kcs.ClusterName
+
+	data, err := runtime.Encode(latest.Codec, kcfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding kubeconfig")
+	}
+	return data, nil
+}
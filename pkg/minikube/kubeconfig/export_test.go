@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error making temp directory %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	for _, f := range []string{"ca.crt", "client.crt", "client.key"} {
+		if err := ioutil.WriteFile(filepath.Join(tmpDir, f), []byte(f), 0600); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+
+	var tests = []struct {
+		description string
+		embed       bool
+	}{
+		{description: "referenced certs"},
+		{description: "embedded certs", embed: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			kcs := &Settings{
+				ClusterName:          "ci",
+				ClusterServerAddress: "https://192.168.49.2:8443",
+				ClientCertificate:    filepath.Join(tmpDir, "client.crt"),
+				ClientKey:            filepath.Join(tmpDir, "client.key"),
+				CertificateAuthority: filepath.Join(tmpDir, "ca.crt"),
+				KeepContext:          true,
+				EmbedCerts:           test.embed,
+			}
+			data, err := Export(kcs)
+			if err != nil {
+				t.Fatalf("Export: %v", err)
+			}
+			cfg, err := decode(data)
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+
+			if cfg.CurrentContext != "ci" {
+				t.Errorf("current-context = %q, want %q", cfg.CurrentContext, "ci")
+			}
+			if len(cfg.Clusters) != 1 || len(cfg.AuthInfos) != 1 || len(cfg.Contexts) != 1 {
+				t.Errorf("expected a single cluster, user and context, got %+v", cfg)
+			}
+			user := cfg.AuthInfos["ci"]
+			if user == nil {
+				t.Fatalf("user %q missing from %+v", "ci", cfg.AuthInfos)
+			}
+			if test.embed && (string(user.ClientKeyData) != "client.key" || user.ClientKey != "") {
+				t.Errorf("expected embedded client key, got %+v", user)
+			}
+			if !test.embed && (user.ClientKey != kcs.ClientKey || len(user.ClientKeyData) != 0) {
+				t.Errorf("expected client key path, got %+v", user)
+			}
+		})
+	}
+}
@@ -30,6 +30,7 @@ import (
 	"k8s.io/client-go/tools/clientcmd/api"
 	"k8s.io/client-go/tools/clientcmd/api/latest"
 	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/localpath"
 	pkgutil "k8s.io/minikube/pkg/util"
@@ -75,6 +76,15 @@ func PathFromEnv() string {
 	return constants.KubeconfigPath
 }
 
+// PathFromConfig gets the kubeconfig used by a cluster: the file given to `minikube start --kubeconfig`
+// if there was one, otherwise the first kubeconfig from the environment
+func PathFromConfig(cc *config.ClusterConfig) string {
+	if cc != nil && cc.KubeconfigPath != "" {
+		return cc.KubeconfigPath
+	}
+	return PathFromEnv()
+}
+
 // Endpoint returns the IP:port address stored for minikube in the kubeconfig specified
 func Endpoint(contextName string, configPath ...string) (string, int, error) {
 	path := PathFromEnv()
@@ -116,15 +126,13 @@ func UpdateEndpoint(contextName string, hostname string, port int, confpath stri
 	}
 	klog.Infof("verify returned: %v", err)
 
-	cfg, err := readOrNew(confpath)
-	if err != nil {
-		return false, errors.Wrap(err, "read")
-	}
-
 	address := "https://" + hostname + ":" + strconv.Itoa(port)
-
-	// if the cluster setting is missed in the kubeconfig, create new one
-	if _, ok := cfg.Clusters[contextName]; !ok {
+	return modify(confpath, contextName, func(cfg *api.Config) (bool, error) {
+		// if the cluster setting is missed in the kubeconfig, create new one
+		if _, ok := cfg.Clusters[contextName]; ok {
+			cfg.Clusters[contextName].Server = address
+			return true, nil
+		}
 		klog.Infof("%q context is missing from %s - will repair!", contextName, confpath)
 		lp := localpath.Profile(contextName)
 		gp := localpath.MiniPath()
@@ -139,20 +147,11 @@ func UpdateEndpoint(contextName string, hostname string, port int, confpath stri
 		if ext != nil {
 			kcs.ExtensionCluster = ext
 		}
-		err = PopulateFromSettings(kcs, cfg)
-		if err != nil {
+		if err := PopulateFromSettings(kcs, cfg); err != nil {
 			return false, errors.Wrap(err, "populating kubeconfig")
 		}
-	} else {
-		cfg.Clusters[contextName].Server = address
-	}
-
-	err = writeToFile(cfg, confpath)
-	if err != nil {
-		return false, errors.Wrap(err, "write")
-	}
-
-	return true, nil
+		return true, nil
+	})
 }
 
 // writeToFile encodes the configuration and writes it to the given file.
@@ -201,11 +200,9 @@ func readOrNew(configPath ...string) (*api.Config, error) {
 		fPath = configPath[0]
 	}
 
-	data, err := ioutil.ReadFile(fPath)
-	if os.IsNotExist(err) {
-		return api.NewConfig(), nil
-	} else if err != nil {
-		return nil, errors.Wrapf(err, "Error reading file %q", fPath)
+	data, err := readRaw(fPath)
+	if err != nil {
+		return nil, err
 	}
 
 	// decode config, empty if no bytes
@@ -213,8 +210,24 @@ func readOrNew(configPath ...string) (*api.Config, error) {
 	if err != nil {
 		return nil, errors.Errorf("could not read config: %v", err)
 	}
+	initMaps(kcfg)
+
+	return kcfg, nil
+}
 
-	// initialize nil maps
+// readRaw returns the contents of a kubeconfig file, or no bytes if it does not exist.
+func readRaw(fPath string) ([]byte, error) {
+	data, err := ioutil.ReadFile(fPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "Error reading file %q", fPath)
+	}
+	return data, nil
+}
+
+// initMaps initializes nil maps of a decoded config
+func initMaps(kcfg *api.Config) {
 	if kcfg.AuthInfos == nil {
 		kcfg.AuthInfos = map[string]*api.AuthInfo{}
 	}
@@ -224,8 +237,6 @@ func readOrNew(configPath ...string) (*api.Config, error) {
 	if kcfg.Contexts == nil {
 		kcfg.Contexts = map[string]*api.Context{}
 	}
-
-	return kcfg, nil
 }
 
 // decode reads a Config object from bytes.
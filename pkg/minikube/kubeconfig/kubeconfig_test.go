@@ -242,7 +242,7 @@ func TestUpdate(t *testing.T) {
 
 			test.cfg.SetPath(filepath.Join(tmpDir, "kubeconfig"))
 			if len(test.existingCfg) != 0 {
-				if err := ioutil.WriteFile(test.cfg.filePath(), test.existingCfg, 0600); err != nil {
+				if err := ioutil.WriteFile(test.cfg.FilePath(), test.existingCfg, 0600); err != nil {
 					t.Fatalf("WriteFile: %v", err)
 				}
 			}
@@ -253,7 +253,7 @@ func TestUpdate(t *testing.T) {
 			if err == nil && test.err {
 				t.Errorf("Expected error but got none")
 			}
-			config, err := readOrNew(test.cfg.filePath())
+			config, err := readOrNew(test.cfg.FilePath())
 			if err != nil {
 				t.Errorf("Error reading kubeconfig file: %v", err)
 			}
@@ -270,6 +270,90 @@ func TestUpdate(t *testing.T) {
 	}
 }
 
+func TestModifyConcurrentWrite(t *testing.T) {
+	var tests = []struct {
+		description string
+		// concurrent is what another process, such as kubectl, writes to the kubeconfig while it is being updated
+		concurrent string
+		conflict   bool
+	}{
+		{
+			description: "unrelated entries",
+			concurrent: string(kubeConfigWithoutHTTPS) + `- name: other
+  user:
+    client-key: /home/other/apiserver.key
+`,
+		},
+		{
+			description: "conflicting context",
+			concurrent: string(kubeConfigWithoutHTTPS) + `- name: test
+  user:
+    client-key: /home/other/apiserver.key
+`,
+			conflict: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			tmpDir, err := ioutil.TempDir("", "")
+			if err != nil {
+				t.Fatalf("Error making temp directory %v", err)
+			}
+			defer os.RemoveAll(tmpDir)
+
+			kcs := &Settings{
+				ClusterName:          "test",
+				ClusterServerAddress: "https://192.168.1.1:8443",
+				ClientCertificate:    "/home/apiserver.crt",
+				ClientKey:            "/home/apiserver.key",
+				CertificateAuthority: "/home/apiserver.crt",
+			}
+			kcs.SetPath(filepath.Join(tmpDir, "kubeconfig"))
+			if err := ioutil.WriteFile(kcs.FilePath(), kubeConfigWithoutHTTPS, 0600); err != nil {
+				t.Fatalf("WriteFile: %v", err)
+			}
+
+			changes := 0
+			_, err = modify(kcs.FilePath(), kcs.ClusterName, func(cfg *api.Config) (bool, error) {
+				changes++
+				if changes == 1 {
+					if err := ioutil.WriteFile(kcs.FilePath(), []byte(test.concurrent), 0600); err != nil {
+						return false, err
+					}
+				}
+				return true, PopulateFromSettings(kcs, cfg)
+			})
+			if _, ok := err.(*ErrConflict); ok != test.conflict {
+				t.Fatalf("modify() = %v, expected a conflict: %v", err, test.conflict)
+			}
+
+			cfg, err := readOrNew(kcs.FilePath())
+			if err != nil {
+				t.Fatalf("Error reading kubeconfig file: %v", err)
+			}
+			if _, ok := cfg.Clusters["la-croix"]; !ok {
+				t.Errorf("the existing cluster was lost: %+v", cfg.Clusters)
+			}
+			if test.conflict {
+				if got := cfg.AuthInfos["test"].ClientKey; got != "/home/other/apiserver.key" {
+					t.Errorf("the conflicting write was overwritten, client key = %s", got)
+				}
+				return
+			}
+			if changes != 2 {
+				t.Errorf("the change was applied %d times, expected it again after the concurrent write", changes)
+			}
+			if _, ok := cfg.AuthInfos["other"]; !ok {
+				t.Errorf("the concurrent write was lost: %+v", cfg.AuthInfos)
+			}
+			if got := cfg.Clusters["test"].Server; got != kcs.ClusterServerAddress {
+				t.Errorf("server = %s, expected %s", got, kcs.ClusterServerAddress)
+			}
+		})
+	}
+}
+
 func TestVerifyEndpoint(t *testing.T) {
 
 	var tests = []struct {
@@ -17,8 +17,11 @@ limitations under the License.
 package kubeconfig
 
 import (
+	"bytes"
+	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"reflect"
 	"sync/atomic"
 
 	"github.com/juju/mutex"
@@ -71,8 +74,8 @@ func (k *Settings) SetPath(kubeConfigFile string) {
 	k.kubeConfigFile.Store(kubeConfigFile)
 }
 
-// filePath gets the kubeconfig file
-func (k *Settings) filePath() string {
+// FilePath gets the kubeconfig file
+func (k *Settings) FilePath() string {
 	return k.kubeConfigFile.Load().(string)
 }
 
@@ -134,36 +137,93 @@ func PopulateFromSettings(cfg *Settings, apiCfg *api.Config) error {
 	return nil
 }
 
+// maxUpdateAttempts is how many times a change is applied again to a kubeconfig that was
+// rewritten by another process while minikube was updating it
+const maxUpdateAttempts = 3
+
 // Update reads config from disk, adds the minikube settings, and writes it back.
 // activeContext is true when minikube is the CurrentContext
 // If no CurrentContext is set, the given name will be used.
 func Update(kcs *Settings) error {
-	spec := lock.PathMutexSpec(filepath.Join(kcs.filePath(), "settings.Update"))
+	ext := NewExtension()
+	kcs.ExtensionCluster = ext
+	kcs.ExtensionContext = ext
+
+	klog.Infoln("Updating kubeconfig: ", kcs.FilePath())
+	_, err := modify(kcs.FilePath(), kcs.ClusterName, func(kcfg *api.Config) (bool, error) {
+		return true, PopulateFromSettings(kcs, kcfg)
+	})
+	return err
+}
+
+// modify applies change to the kubeconfig at fPath, or to an empty one if it does not exist, and writes it back
+// if change returns true. It returns whether it wrote the kubeconfig.
+//
+// Tools such as kubectl do not honour minikube's file lock, so the file is re-read before it is
+// written back: changes to unrelated entries are merged by applying change again, while a concurrent
+// change to the cluster, user or context entries of name is reported as an *ErrConflict.
+func modify(fPath string, name string, change func(*api.Config) (bool, error)) (bool, error) {
+	spec := lock.PathMutexSpec(filepath.Join(fPath, "settings.Update"))
 	klog.Infof("acquiring lock: %+v", spec)
 	releaser, err := mutex.Acquire(spec)
 	if err != nil {
-		return errors.Wrapf(err, "unable to acquire lock for %+v", spec)
+		return false, errors.Wrapf(err, "unable to acquire lock for %+v", spec)
 	}
 	defer releaser.Release()
 
-	// read existing config or create new if does not exist
-	klog.Infoln("Updating kubeconfig: ", kcs.filePath())
-	kcfg, err := readOrNew(kcs.filePath())
+	data, err := readRaw(fPath)
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	ext := NewExtension()
-	kcs.ExtensionCluster = ext
-	kcs.ExtensionContext = ext
-	err = PopulateFromSettings(kcs, kcfg)
-	if err != nil {
-		return err
-	}
+	for attempt := 1; ; attempt++ {
+		kcfg, err := decode(data)
+		if err != nil {
+			return false, errors.Errorf("could not read config: %v", err)
+		}
+		initMaps(kcfg)
+		before := entries(kcfg, name)
+
+		changed, err := change(kcfg)
+		if err != nil || !changed {
+			return false, err
+		}
+
+		current, err := readRaw(fPath)
+		if err != nil {
+			return false, err
+		}
+		if bytes.Equal(data, current) {
+			if err := writeToFile(kcfg, fPath); err != nil {
+				return false, errors.Wrap(err, "writing kubeconfig")
+			}
+			return true, nil
+		}
 
-	// write back to disk
-	if err := writeToFile(kcfg, kcs.filePath()); err != nil {
-		return errors.Wrap(err, "writing kubeconfig")
+		klog.Warningf("%s was modified by another process while it was being updated (attempt %d)", fPath, attempt)
+		latest, err := decode(current)
+		if err != nil {
+			return false, errors.Errorf("could not read config: %v", err)
+		}
+		initMaps(latest)
+		if !reflect.DeepEqual(before, entries(latest, name)) || attempt >= maxUpdateAttempts {
+			return false, &ErrConflict{Context: name, Path: fPath}
+		}
+		data = current
 	}
-	return nil
+}
+
+// ErrConflict is returned when another process modified minikube's kubeconfig entries concurrently
+type ErrConflict struct {
+	Context string
+	Path    string
+}
+
+func (e *ErrConflict) Error() string {
+	return fmt.Sprintf("the %q context in %s was modified by another process while minikube was updating it", e.Context, e.Path)
+}
+
+// entries returns the cluster, user and context entries minikube manages for name
+func entries(cfg *api.Config, name string) []interface{} {
+	return []interface{}{cfg.Clusters[name], cfg.AuthInfos[name], cfg.Contexts[name]}
 }
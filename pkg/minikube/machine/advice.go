@@ -39,7 +39,7 @@ func MaybeDisplayAdvice(err error, driver string) {
 
 	if errors.Is(err, oci.ErrExitedUnexpectedly) || errors.Is(err, oci.ErrDaemonInfo) {
 		out.Step(style.Tip, "If you are still interested to make {{.driver_name}} driver work. The following suggestions might help you get passed this issue:", out.V{"driver_name": driver})
-		if driver == oci.Docker || driver == oci.Podman {
+		if driver == oci.Docker || driver == oci.Podman || driver == oci.Nerdctl {
 			out.String("\n\t")
 			out.Step(style.Empty, `- Prune unused {{.driver_name}} images, volumes, networks and abandoned containers.
 
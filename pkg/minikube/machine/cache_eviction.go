@@ -0,0 +1,234 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/minikube/image"
+	"k8s.io/minikube/pkg/util"
+)
+
+const (
+	// CacheMaxSizeKey is the config key of the size limit of the image, preload and binary caches, such as "20g"
+	CacheMaxSizeKey = "cache-max-size"
+	// CacheMaxAgeKey is the config key of the time after which unused cached artifacts are evicted, such as "720h"
+	CacheMaxAgeKey = "cache-max-age"
+)
+
+// cacheEntry is an image, preload or binary of the cache
+type cacheEntry struct {
+	Kind     string
+	Name     string
+	LastUsed time.Time
+
+	remove func() error
+	verify func() error
+	repair func() error
+}
+
+// cacheEntries returns the images, preloads and binaries of the cache
+func cacheEntries() ([]cacheEntry, error) {
+	entries := []cacheEntry{}
+
+	images, err := image.ListCached(constants.ImageCacheDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing cached images")
+	}
+	for _, img := range images {
+		img := img
+		entries = append(entries, cacheEntry{
+			Kind:     "image",
+			Name:     img.Name,
+			LastUsed: img.LastUsed,
+			remove:   func() error { return image.DeleteFromCacheDir([]string{img.Name}) },
+			verify:   func() error { return image.VerifyCached(constants.ImageCacheDir, img.Name) },
+			repair:   func() error { return image.RepairCached(constants.ImageCacheDir, img.Name) },
+		})
+	}
+
+	preloads, err := download.Preloads()
+	if err != nil {
+		return nil, errors.Wrap(err, "listing cached preloads")
+	}
+	for _, p := range preloads {
+		p := p
+		entries = append(entries, cacheEntry{
+			Kind:     "preload",
+			Name:     filepath.Base(p),
+			LastUsed: modTime(p),
+			remove:   func() error { return download.RemovePreload(p) },
+			verify:   func() error { return download.VerifyPreload(p) },
+			repair:   func() error { return download.RepairPreload(p) },
+		})
+	}
+
+	binaries, err := download.Binaries()
+	if err != nil {
+		return nil, errors.Wrap(err, "listing cached binaries")
+	}
+	for _, p := range binaries {
+		p := p
+		name, _ := filepath.Rel(filepath.Dir(filepath.Dir(filepath.Dir(p))), p)
+		entries = append(entries, cacheEntry{
+			Kind:     "binary",
+			Name:     filepath.ToSlash(name),
+			LastUsed: modTime(p),
+			remove:   func() error { return download.RemoveBinary(p) },
+			verify:   func() error { return download.VerifyBinary(p) },
+			repair:   func() error { return download.RepairBinary(p) },
+		})
+	}
+	return entries, nil
+}
+
+// modTime returns when a cached file was last used
+func modTime(p string) time.Time {
+	fi, err := os.Stat(p)
+	if err != nil {
+		return time.Time{}
+	}
+	return fi.ModTime()
+}
+
+// cacheSize returns the disk usage of the image, preload and binary caches
+func cacheSize() (int64, error) {
+	size, err := dirSize(constants.ImageCacheDir)
+	if err != nil {
+		return 0, err
+	}
+	preloads, err := download.Preloads()
+	if err != nil {
+		return 0, err
+	}
+	binaries, err := download.Binaries()
+	if err != nil {
+		return 0, err
+	}
+	for _, p := range append(preloads, binaries...) {
+		if fi, err := os.Stat(p); err == nil {
+			size += fi.Size()
+		}
+	}
+	return size, nil
+}
+
+// dirSize returns the size of the files in a directory
+func dirSize(dir string) (int64, error) {
+	size := int64(0)
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// evict removes the entries not used for longer than maxAge, then the least recently used entries
+// until the cache fits in limit bytes, and returns the removed entries. Zero disables either policy.
+func evict(entries []cacheEntry, now time.Time, maxAge time.Duration, limit int64, size func() (int64, error)) ([]cacheEntry, error) {
+	sorted := append([]cacheEntry{}, entries...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].LastUsed.Before(sorted[j].LastUsed) })
+
+	evicted := []cacheEntry{}
+	remaining := []cacheEntry{}
+	for _, e := range sorted {
+		if maxAge > 0 && now.Sub(e.LastUsed) > maxAge {
+			klog.Infof("evicting %s %s, unused since %s", e.Kind, e.Name, e.LastUsed)
+			if err := e.remove(); err != nil {
+				return evicted, errors.Wrapf(err, "removing %s %s", e.Kind, e.Name)
+			}
+			evicted = append(evicted, e)
+			continue
+		}
+		remaining = append(remaining, e)
+	}
+	if limit <= 0 {
+		return evicted, nil
+	}
+
+	// images share layers, so measure the cache again after each removal
+	for _, e := range remaining {
+		total, err := size()
+		if err != nil {
+			return evicted, errors.Wrap(err, "cache size")
+		}
+		if total <= limit {
+			break
+		}
+		klog.Infof("evicting %s %s, the cache uses %d bytes over a limit of %d", e.Kind, e.Name, total, limit)
+		if err := e.remove(); err != nil {
+			return evicted, errors.Wrapf(err, "removing %s %s", e.Kind, e.Name)
+		}
+		evicted = append(evicted, e)
+	}
+	return evicted, nil
+}
+
+// EvictCache applies the eviction policies set with 'minikube config' to the image, preload and binary caches,
+// and returns the number of evicted entries and the bytes freed
+func EvictCache() (int, int64, error) {
+	maxAge := time.Duration(0)
+	if s := viper.GetString(CacheMaxAgeKey); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, 0, errors.Wrapf(err, "parsing %s %q", CacheMaxAgeKey, s)
+		}
+		maxAge = d
+	}
+	limit := int64(0)
+	if s := viper.GetString(CacheMaxSizeKey); s != "" {
+		mb, err := util.CalculateSizeInMB(s)
+		if err != nil {
+			return 0, 0, errors.Wrapf(err, "parsing %s %q", CacheMaxSizeKey, s)
+		}
+		limit = util.ConvertMBToBytes(mb)
+	}
+	if maxAge == 0 && limit == 0 {
+		return 0, 0, nil
+	}
+
+	before, err := cacheSize()
+	if err != nil {
+		return 0, 0, err
+	}
+	entries, err := cacheEntries()
+	if err != nil {
+		return 0, 0, err
+	}
+	evicted, err := evict(entries, time.Now(), maxAge, limit, cacheSize)
+	after, serr := cacheSize()
+	if serr != nil {
+		after = before
+	}
+	return len(evicted), before - after, err
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/download"
+)
+
+// fakeCache is a cache of entries of 10 bytes each
+type fakeCache struct {
+	entries map[string]bool
+}
+
+func (f *fakeCache) entry(name string, lastUsed time.Time, verifyErr error) cacheEntry {
+	f.entries[name] = true
+	return cacheEntry{
+		Kind:     "image",
+		Name:     name,
+		LastUsed: lastUsed,
+		remove:   func() error { f.entries[name] = false; return nil },
+		verify:   func() error { return verifyErr },
+		repair:   func() error { return nil },
+	}
+}
+
+func (f *fakeCache) size() (int64, error) {
+	size := int64(0)
+	for _, cached := range f.entries {
+		if cached {
+			size += 10
+		}
+	}
+	return size, nil
+}
+
+func names(entries []cacheEntry) []string {
+	result := []string{}
+	for _, e := range entries {
+		result = append(result, e.Name)
+	}
+	return result
+}
+
+func TestEvict(t *testing.T) {
+	now := time.Now()
+	var tests = []struct {
+		maxAge time.Duration
+		limit  int64
+		want   []string
+	}{
+		{0, 0, []string{}},
+		{48 * time.Hour, 0, []string{"old"}},
+		{0, 20, []string{"old", "mid"}},
+		{0, 25, []string{"old", "mid"}},
+		{48 * time.Hour, 20, []string{"old", "mid"}},
+		{0, 100, []string{}},
+		{time.Hour, 0, []string{"old", "mid", "new"}},
+		{time.Hour, 5, []string{"old", "mid", "new", "recent"}},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%s-%d", tc.maxAge, tc.limit), func(t *testing.T) {
+			f := &fakeCache{entries: map[string]bool{}}
+			entries := []cacheEntry{
+				f.entry("new", now.Add(-2*time.Hour), nil),
+				f.entry("old", now.Add(-72*time.Hour), nil),
+				f.entry("recent", now.Add(-time.Minute), nil),
+				f.entry("mid", now.Add(-24*time.Hour), nil),
+			}
+			evicted, err := evict(entries, now, tc.maxAge, tc.limit, f.size)
+			if err != nil {
+				t.Fatalf("evict: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, names(evicted)); diff != "" {
+				t.Errorf("evicted diff (-want +got):\n%s", diff)
+			}
+			for _, n := range tc.want {
+				if f.entries[n] {
+					t.Errorf("%s wasn't removed", n)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyEntries(t *testing.T) {
+	f := &fakeCache{entries: map[string]bool{}}
+	corrupted := errors.Wrap(download.ErrCorrupted, "checksum does not match")
+	entries := []cacheEntry{
+		f.entry("intact", time.Now(), nil),
+		f.entry("corrupted", time.Now(), corrupted),
+		f.entry("legacy", time.Now(), download.ErrNoChecksum),
+	}
+
+	want := []CacheVerifyResult{
+		{Kind: "image", Name: "intact"},
+		{Kind: "image", Name: "corrupted", Err: corrupted},
+		{Kind: "image", Name: "legacy", Unverified: true},
+	}
+	got := verifyEntries(entries, false)
+	if diff := cmp.Diff(want, got, cmp.Comparer(func(a, b error) bool { return a == b })); diff != "" {
+		t.Errorf("verifyEntries diff (-want +got):\n%s", diff)
+	}
+
+	got = verifyEntries(entries, true)
+	if !got[1].Repaired || got[0].Repaired || got[2].Repaired {
+		t.Errorf("verifyEntries repaired %+v, want only the corrupted entry", got)
+	}
+}
@@ -18,9 +18,9 @@ package machine
 
 import (
 	"fmt"
-	"os"
+	"os/exec"
 	"path"
-	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -36,8 +36,10 @@ import (
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/download"
 	"k8s.io/minikube/pkg/minikube/image"
 	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/vmpath"
 )
 
@@ -47,6 +49,12 @@ var loadRoot = path.Join(vmpath.GuestPersistentDir, "images")
 // loadImageLock is used to serialize image loads to avoid overloading the guest VM
 var loadImageLock sync.Mutex
 
+// blobRoot is where the blobs of the cached images are kept by digest within the guest VM
+var blobRoot = path.Join(loadRoot, "blobs")
+
+// transferBlobsLock is used to serialize blob transfers, so that images sharing layers transfer them once
+var transferBlobsLock sync.Mutex
+
 // CacheImagesForBootstrapper will cache images for a bootstrapper
 func CacheImagesForBootstrapper(imageRepository string, version string, clusterBootstrapper string) error {
 	images, err := bootstrapper.GetCachedImageList(imageRepository, version, clusterBootstrapper)
@@ -109,6 +117,13 @@ func LoadImages(cc *config.ClusterConfig, runner command.Runner, images []string
 		return errors.Wrap(err, "loading cached images")
 	}
 	klog.Infoln("Successfully loaded all cached images")
+	used, err := image.UsedBlobs(cacheDir)
+	if err == nil {
+		err = pruneBlobs(runner, used)
+	}
+	if err != nil {
+		klog.Warningf("unable to remove the unused blobs of the guest: %v", err)
+	}
 	return nil
 }
 
@@ -226,26 +241,53 @@ func CacheAndLoadImages(images []string, profiles []*config.Profile) error {
 	return nil
 }
 
-// transferAndLoadImage transfers and loads a single image from the cache
+// transferAndLoadImage transfers the blobs of a single image from the cache which the guest doesn't have yet,
+// and loads the image from a docker archive of them
 func transferAndLoadImage(cr command.Runner, k8s config.KubernetesConfig, imgName string, cacheDir string) error {
 	r, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Runner: cr})
 	if err != nil {
 		return errors.Wrap(err, "runtime")
 	}
-	src := filepath.Join(cacheDir, imgName)
-	src = localpath.SanitizeCacheDir(src)
-	klog.Infof("Loading image from cache: %s", src)
-	filename := filepath.Base(src)
-	if _, err := os.Stat(src); err != nil {
+	klog.Infof("Loading image from cache: %s", imgName)
+	ca, err := image.Archive(cacheDir, imgName)
+	if err != nil {
 		return err
 	}
-	dst := path.Join(loadRoot, filename)
-	f, err := assets.NewFileAsset(src, loadRoot, filename, "0644")
-	if err != nil {
-		return errors.Wrapf(err, "creating copyable file asset: %s", filename)
+	if err := transferBlobs(cr, ca.Blobs); err != nil {
+		if !errors.Is(err, download.ErrCorrupted) {
+			return errors.Wrap(err, "transferring cached image")
+		}
+		// download the image again rather than failing to load it in the guest
+		out.WarningT("The cached image {{.name}} is corrupted, downloading it again: {{.error}}", out.V{"name": imgName, "error": err})
+		if err := image.RepairCached(cacheDir, imgName); err != nil {
+			return errors.Wrap(err, "repairing cached image")
+		}
+		if ca, err = image.Archive(cacheDir, imgName); err != nil {
+			return err
+		}
+		if err := transferBlobs(cr, ca.Blobs); err != nil {
+			return errors.Wrap(err, "transferring cached image")
+		}
 	}
-	if err := cr.Copy(f); err != nil {
-		return errors.Wrap(err, "transferring cached image")
+
+	filename := path.Base(localpath.SanitizeCacheDir(imgName))
+	dir := path.Join(loadRoot, filename)
+	dst := path.Join(loadRoot, filename+".tar")
+	m := assets.NewMemoryAssetTarget(ca.Manifest, path.Join(dir, "manifest.json"), "0644")
+	if err := cr.Copy(m); err != nil {
+		return errors.Wrap(err, "transferring manifest")
+	}
+	defer func() {
+		if _, err := cr.RunCmd(exec.Command("sudo", "rm", "-rf", dir, dst)); err != nil {
+			klog.Warningf("failed to remove %s: %v", dst, err)
+		}
+	}()
+	args := []string{"tar", "-cf", dst, "-C", dir, "manifest.json", "-C", blobRoot}
+	for _, b := range ca.Blobs {
+		args = append(args, path.Join(b.Digest.Algorithm, b.Digest.Hex))
+	}
+	if _, err := cr.RunCmd(exec.Command("sudo", args...)); err != nil {
+		return errors.Wrapf(err, "archiving %s", imgName)
 	}
 
 	loadImageLock.Lock()
@@ -256,6 +298,83 @@ func transferAndLoadImage(cr command.Runner, k8s config.KubernetesConfig, imgNam
 		return errors.Wrapf(err, "%s load %s", r.Name(), dst)
 	}
 
-	klog.Infof("Transferred and loaded %s from cache", src)
+	klog.Infof("Transferred and loaded %s from cache", imgName)
 	return nil
 }
+
+// transferBlobs copies the blobs the guest doesn't have yet, so that images share their layers there too
+func transferBlobs(cr command.Runner, blobs []image.CachedBlob) error {
+	transferBlobsLock.Lock()
+	defer transferBlobsLock.Unlock()
+
+	existing, err := guestBlobs(cr)
+	if err != nil {
+		return err
+	}
+	for _, b := range blobs {
+		dst := path.Join(blobRoot, b.Digest.Algorithm, b.Digest.Hex)
+		if existing[dst] {
+			klog.Infof("%s exists in the guest, skipping", b.Digest)
+			continue
+		}
+		if err := image.VerifyBlob(b); err != nil {
+			return err
+		}
+		// copied under a temporary name, so that an interrupted copy doesn't leave a truncated blob behind
+		f, err := assets.NewFileAsset(b.Path, path.Dir(dst), b.Digest.Hex+".partial", "0644")
+		if err != nil {
+			return errors.Wrapf(err, "creating copyable file asset: %s", b.Path)
+		}
+		if err := cr.Copy(f); err != nil {
+			return errors.Wrapf(err, "transferring blob %s", b.Digest)
+		}
+		if _, err := cr.RunCmd(exec.Command("sudo", "mv", dst+".partial", dst)); err != nil {
+			return errors.Wrapf(err, "moving blob %s", b.Digest)
+		}
+		existing[dst] = true
+	}
+	return nil
+}
+
+// pruneBlobs removes the blobs of the guest which no image of the image cache uses, such as the layers of the images
+// deleted from the cache since they were transferred. used holds the digests of the blobs of the image cache.
+func pruneBlobs(cr command.Runner, used map[string]bool) error {
+	transferBlobsLock.Lock()
+	defer transferBlobsLock.Unlock()
+
+	existing, err := guestBlobs(cr)
+	if err != nil {
+		return err
+	}
+	unused := []string{}
+	for p := range existing {
+		// blobs are kept as <algorithm>/<hex>
+		if !used[path.Base(path.Dir(p))+":"+path.Base(p)] {
+			unused = append(unused, p)
+		}
+	}
+	if len(unused) == 0 {
+		return nil
+	}
+	sort.Strings(unused)
+	klog.Infof("removing %d unused blobs from the guest: %v", len(unused), unused)
+	if _, err := cr.RunCmd(exec.Command("sudo", append([]string{"rm", "-f"}, unused...)...)); err != nil {
+		return errors.Wrap(err, "removing unused blobs")
+	}
+	return nil
+}
+
+// guestBlobs returns the paths of the blobs in the guest
+func guestBlobs(cr command.Runner) (map[string]bool, error) {
+	rr, err := cr.RunCmd(exec.Command("sudo", "find", blobRoot, "-type", "f", "-not", "-name", "*.partial"))
+	existing := map[string]bool{}
+	if err != nil {
+		// there are no blobs before the first transfer
+		klog.Infof("listing blobs: %v", err)
+		return existing, nil
+	}
+	for _, p := range strings.Fields(rr.Stdout.String()) {
+		existing[p] = true
+	}
+	return existing, nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"os/exec"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+// recordingRunner records the commands run by a fake runner
+type recordingRunner struct {
+	*command.FakeCommandRunner
+	cmds []string
+}
+
+func (r *recordingRunner) RunCmd(cmd *exec.Cmd) (*command.RunResult, error) {
+	rr, err := r.FakeCommandRunner.RunCmd(cmd)
+	r.cmds = append(r.cmds, rr.Command())
+	return rr, err
+}
+
+func TestPruneBlobs(t *testing.T) {
+	find := "sudo find /var/lib/minikube/images/blobs -type f -not -name *.partial"
+	tests := []struct {
+		description string
+		guest       string
+		used        map[string]bool
+		remove      string
+	}{
+		{
+			description: "all used",
+			guest:       "/var/lib/minikube/images/blobs/sha256/aaa\n/var/lib/minikube/images/blobs/sha256/bbb\n",
+			used:        map[string]bool{"sha256:aaa": true, "sha256:bbb": true, "sha256:ccc": true},
+		},
+		{
+			description: "deleted from the cache",
+			guest:       "/var/lib/minikube/images/blobs/sha256/ccc\n/var/lib/minikube/images/blobs/sha256/aaa\n/var/lib/minikube/images/blobs/sha256/bbb\n",
+			used:        map[string]bool{"sha256:aaa": true},
+			remove:      "sudo rm -f /var/lib/minikube/images/blobs/sha256/bbb /var/lib/minikube/images/blobs/sha256/ccc",
+		},
+		{
+			description: "empty cache",
+			guest:       "/var/lib/minikube/images/blobs/sha256/aaa\n",
+			used:        map[string]bool{},
+			remove:      "sudo rm -f /var/lib/minikube/images/blobs/sha256/aaa",
+		},
+		{
+			description: "no blobs",
+			used:        map[string]bool{"sha256:aaa": true},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			runner := &recordingRunner{FakeCommandRunner: command.NewFakeCommandRunner()}
+			cmds := map[string]string{find: tc.guest}
+			if tc.remove != "" {
+				cmds[tc.remove] = ""
+			}
+			runner.SetCommandToOutput(cmds)
+			if err := pruneBlobs(runner, tc.used); err != nil {
+				t.Fatalf("pruneBlobs: %v", err)
+			}
+			removed := ""
+			for _, c := range runner.cmds {
+				if c != find {
+					removed = c
+				}
+			}
+			if removed != tc.remove {
+				t.Errorf("pruneBlobs ran %q, want %q", removed, tc.remove)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/download"
+)
+
+// CacheVerifyResult is the verification of an image, preload or binary of the cache
+type CacheVerifyResult struct {
+	Kind string
+	Name string
+	// Err is why the entry failed verification, nil if it's intact
+	Err error
+	// Unverified is set for entries without a checksum to verify them with
+	Unverified bool
+	// Repaired is set for failed entries that were downloaded again
+	Repaired bool
+	// RepairErr is why downloading a failed entry again failed
+	RepairErr error
+}
+
+// VerifyCache verifies the images of the image cache, the preloads and the binaries with their digests and checksums,
+// and downloads the ones failing verification again if repair is set
+func VerifyCache(repair bool) ([]CacheVerifyResult, error) {
+	entries, err := cacheEntries()
+	if err != nil {
+		return nil, err
+	}
+	return verifyEntries(entries, repair), nil
+}
+
+// verifyEntries verifies cache entries, and repairs the failing ones if repair is set
+func verifyEntries(entries []cacheEntry, repair bool) []CacheVerifyResult {
+	results := []CacheVerifyResult{}
+	for _, e := range entries {
+		r := CacheVerifyResult{Kind: e.Kind, Name: e.Name}
+		err := e.verify()
+		switch {
+		case err == nil:
+		case errors.Is(err, download.ErrNoChecksum):
+			r.Unverified = true
+		default:
+			klog.Warningf("%s %s failed verification: %v", e.Kind, e.Name, err)
+			r.Err = err
+			if repair {
+				if r.RepairErr = e.repair(); r.RepairErr == nil {
+					r.Repaired = true
+				}
+			}
+		}
+		results = append(results, r)
+	}
+	return results
+}
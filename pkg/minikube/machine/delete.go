@@ -37,7 +37,7 @@ import (
 // deleteOrphanedKIC attempts to delete an orphaned docker instance for machines without a config file
 // used as last effort clean up not returning errors, wont warn user.
 func deleteOrphanedKIC(ociBin string, name string) {
-	if !(ociBin == oci.Podman || ociBin == oci.Docker) {
+	if !(ociBin == oci.Podman || ociBin == oci.Docker || ociBin == oci.Nerdctl) {
 		return
 	}
 
@@ -72,6 +72,7 @@ func DeleteHost(api libmachine.API, machineName string, deleteAbandoned ...bool)
 	if err != nil && host == nil && delAbandoned {
 		deleteOrphanedKIC(oci.Docker, machineName)
 		deleteOrphanedKIC(oci.Podman, machineName)
+		deleteOrphanedKIC(oci.Nerdctl, machineName)
 		// Keep going even if minikube does not know about the host
 	}
 
@@ -76,6 +76,8 @@ func fixHost(api libmachine.API, cc *config.ClusterConfig, n *config.Node) (*hos
 	if !driver.BareMetal(h.Driver.DriverName()) {
 		e := engineOptions(*cc)
 		h.HostOptions.EngineOptions.Env = e.Env
+		h.HostOptions.EngineOptions.InsecureRegistry = e.InsecureRegistry
+		h.HostOptions.EngineOptions.RegistryMirror = e.RegistryMirror
 		err = provisionDockerMachine(h)
 		if err != nil {
 			return h, errors.Wrap(err, "provision")
@@ -200,6 +202,16 @@ func ensureSyncedGuestClock(h hostRunner, drv string) error {
 	return nil
 }
 
+// GuestClockSkew returns the approximate difference between the host and guest system clock,
+// and whether it is larger than the tolerance enforced when a VM is started
+func GuestClockSkew(h *host.Host) (time.Duration, bool, error) {
+	d, err := guestClockDelta(h, time.Now())
+	if err != nil {
+		return 0, false, err
+	}
+	return d, math.Abs(d.Seconds()) >= maxClockDesyncSeconds, nil
+}
+
 // guestClockDelta returns the approximate difference between the host and guest system clock
 // NOTE: This does not currently take into account ssh latency.
 func guestClockDelta(h hostRunner, local time.Time) (time.Duration, error) {
@@ -289,7 +301,7 @@ func machineExists(d string, s state.State, err error) (bool, error) {
 		return machineExistsState(s, err)
 	case driver.VMwareFusion:
 		return machineExistsState(s, err)
-	case driver.Docker:
+	case driver.Docker, driver.Nerdctl:
 		return machineExistsDocker(s, err)
 	case driver.Mock:
 		if s == state.Error {
@@ -0,0 +1,146 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/minikube/sysinit"
+)
+
+// KubeletReconfigurer regenerates the kubelet configuration of a running node, before its kubelet restarts
+type KubeletReconfigurer func(cc config.ClusterConfig, n config.Node, r command.Runner) error
+
+// ResizeCluster applies the CPU and memory allocation of a cluster to each of its existing machines, reporting when it takes effect.
+// From then on, the kubelet reserves CPUs and memory for the system following the size of the nodes.
+func ResizeCluster(api libmachine.API, cc *config.ClusterConfig, reconfigure KubeletReconfigurer) error {
+	if driver.Resize(cc.Driver) == driver.ResizeUnsupported {
+		return errors.Errorf("the %s driver does not support resizing existing clusters", cc.Driver)
+	}
+	cc.ReserveResources = true
+
+	for _, n := range cc.Nodes {
+		machineName := config.MachineName(*cc, n)
+		exists, err := api.Exists(machineName)
+		if err != nil {
+			return errors.Wrapf(err, "exists: %s", machineName)
+		}
+		if !exists {
+			continue
+		}
+
+		restart, err := Resize(api, *cc, n, reconfigure)
+		if err != nil {
+			return errors.Wrapf(err, "resize %s", machineName)
+		}
+
+		v := out.V{"name": machineName, "cpus": cc.CPUs, "memory": cc.Memory}
+		switch {
+		case restart:
+			out.WarningT("{{.name}} will use {{.cpus}} CPUs and {{.memory}}MB of memory once restarted with 'minikube stop' and 'minikube start'", v)
+		case driver.Resize(cc.Driver) == driver.ResizeLive:
+			out.Step(style.Check, "Resized {{.name}} to {{.cpus}} CPUs and {{.memory}}MB of memory", v)
+		default:
+			out.Step(style.Check, "{{.name}} will use {{.cpus}} CPUs and {{.memory}}MB of memory when it starts", v)
+		}
+	}
+	return nil
+}
+
+// Resize applies the CPU and memory allocation of a cluster to one of its existing machines, reconfiguring the kubelet
+// of a running one. It returns whether the machine must be restarted for the change to take effect.
+func Resize(api libmachine.API, cc config.ClusterConfig, n config.Node, reconfigure KubeletReconfigurer) (bool, error) {
+	machineName := config.MachineName(cc, n)
+	h, err := LoadHost(api, machineName)
+	if err != nil {
+		return false, errors.Wrap(err, "load host")
+	}
+	s, err := h.Driver.GetState()
+	if err != nil {
+		return false, errors.Wrap(err, "state")
+	}
+	klog.Infof("resizing %s (%s) to %d CPUs and %dMB of memory", machineName, s, cc.CPUs, cc.Memory)
+
+	switch driver.Resize(cc.Driver) {
+	case driver.ResizeLive:
+		if err := oci.UpdateContainerResources(h.DriverName, h.Name, cc.CPUs, cc.Memory); err != nil {
+			return false, err
+		}
+		if s != state.Running {
+			return false, nil
+		}
+		// The kubelet only detects the capacity of the node as it starts, and its reservations follow the capacity
+		cr, err := CommandRunner(h)
+		if err != nil {
+			return false, errors.Wrap(err, "command runner")
+		}
+		if err := reconfigure(cc, n, cr); err != nil {
+			return false, errors.Wrap(err, "reconfigure kubelet")
+		}
+		if err := sysinit.New(cr).Restart("kubelet"); err != nil {
+			return false, errors.Wrap(err, "restart kubelet")
+		}
+		return false, nil
+	case driver.ResizeOnRestart:
+		if err := setDriverResources(api, h, cc.CPUs, cc.Memory); err != nil {
+			return false, err
+		}
+		return s == state.Running, nil
+	}
+	return false, fmt.Errorf("the %s driver does not support resizing existing machines", cc.Driver)
+}
+
+// setDriverResources records CPUs and memory in the driver config of a machine, for the driver to apply when it next starts
+func setDriverResources(api libmachine.API, h *host.Host, cpus int, memoryMB int) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(h.RawDriver, &raw); err != nil {
+		return errors.Wrap(err, "unmarshal driver config")
+	}
+	if raw == nil {
+		raw = map[string]interface{}{}
+	}
+	raw["CPU"] = cpus
+	raw["Memory"] = memoryMB
+	bs, err := json.Marshal(raw)
+	if err != nil {
+		return errors.Wrap(err, "marshal driver config")
+	}
+
+	// Drivers running as plugins hold their config in the plugin process
+	if rd, ok := h.Driver.(interface{ SetConfigRaw([]byte) error }); ok {
+		err = rd.SetConfigRaw(bs)
+	} else {
+		err = json.Unmarshal(bs, h.Driver)
+	}
+	if err != nil {
+		return errors.Wrap(err, "update driver config")
+	}
+	h.RawDriver = bs
+	return api.Save(h)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"encoding/json"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestSetDriverResources(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer tests.RemoveTempDir(tempDir)
+
+	RegisterMockDriver(t)
+	api := tests.NewMockAPI(t)
+
+	h, err := createHost(api, &defaultClusterConfig, &config.Node{Name: "minikube"})
+	if err != nil {
+		t.Fatalf("Error creating host: %v", err)
+	}
+
+	if err := setDriverResources(api, h, 4, 8192); err != nil {
+		t.Fatalf("setDriverResources: %v", err)
+	}
+
+	saved, err := api.Load(h.Name)
+	if err != nil {
+		t.Fatalf("Error loading machine: %v", err)
+	}
+	got := struct {
+		CPU    int
+		Memory int
+	}{}
+	if err := json.Unmarshal(saved.RawDriver, &got); err != nil {
+		t.Fatalf("unmarshal driver config: %v", err)
+	}
+	if got.CPU != 4 || got.Memory != 8192 {
+		t.Errorf("driver config = %+v, want CPU=4 Memory=8192", got)
+	}
+}
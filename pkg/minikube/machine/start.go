@@ -61,7 +61,6 @@ var requiredDirectories = []string{
 	vmpath.GuestKubernetesCertsDir,
 	path.Join(vmpath.GuestPersistentDir, "images"),
 	path.Join(vmpath.GuestPersistentDir, "binaries"),
-	vmpath.GuestGvisorDir,
 	vmpath.GuestCertAuthDir,
 	vmpath.GuestCertStoreDir,
 }
@@ -112,10 +111,11 @@ func engineOptions(cfg config.ClusterConfig) *engine.Options {
 		}
 	}
 
+	// the container runtime configures the registries, the insecure ones here are only
+	// passed to CRI-O, for the ranges of registries its registries.conf can't express
 	o := engine.Options{
 		Env:              uniqueEnvs,
 		InsecureRegistry: append([]string{constants.DefaultServiceCIDR}, cfg.InsecureRegistry...),
-		RegistryMirror:   cfg.RegistryMirror,
 		ArbitraryFlags:   cfg.DockerOpt,
 		InstallURL:       drivers.DefaultEngineInstallURL,
 	}
@@ -228,6 +228,10 @@ func postStartValidations(h *host.Host, drvName string) {
 		kind = reason.RsrcInsufficientPodmanStorage
 		name = "Podman"
 	}
+	if drvName == oci.Nerdctl {
+		kind = reason.RsrcInsufficientNerdctlStorage
+		name = "containerd"
+	}
 	if name == "" {
 		klog.Warningf("unknown KIC driver: %v", drvName)
 		return
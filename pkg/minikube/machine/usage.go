@@ -0,0 +1,248 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/docker/machine/libmachine/host"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/util"
+)
+
+// hostpathPVDir is where the storage-provisioner addon creates persistent volumes
+const hostpathPVDir = "/tmp/hostpath-provisioner"
+
+// imageStores maps container runtimes to where they store images and containers
+var imageStores = map[string]string{
+	"":           "/var/lib/docker",
+	"docker":     "/var/lib/docker",
+	"containerd": "/var/lib/containerd",
+	"crio":       "/var/lib/containers",
+	"cri-o":      "/var/lib/containers",
+}
+
+// ResourceUsage holds the resources consumed by a node
+type ResourceUsage struct {
+	// CPUPercent is the CPU usage, where 100 means one CPU is fully used
+	CPUPercent    float64
+	MemoryUsedMB  int64
+	MemoryTotalMB int64
+	// DiskUsedMB and DiskTotalMB describe the filesystem holding /var
+	DiskUsedMB  int64
+	DiskTotalMB int64
+	// ImagesMB is the size of the container runtime's image and container store
+	ImagesMB int64
+	// VolumesMB is the size of hostpath persistent volumes
+	VolumesMB int64
+}
+
+// Usage returns the resources consumed by a running node: cgroup stats for KIC drivers, /proc for VMs
+func Usage(h *host.Host, runtime string) (*ResourceUsage, error) {
+	cr, err := CommandRunner(h)
+	if err != nil {
+		return nil, errors.Wrap(err, "command runner")
+	}
+
+	u := &ResourceUsage{}
+	if driver.IsKIC(h.DriverName) {
+		st, err := oci.Stats(h.DriverName, h.Name)
+		if err != nil {
+			return nil, errors.Wrap(err, "container stats")
+		}
+		u.CPUPercent = st.CPUPercent
+		u.MemoryUsedMB = util.ConvertUnsignedBytesToMB(uint64(st.MemoryUsed))
+		u.MemoryTotalMB = util.ConvertUnsignedBytesToMB(uint64(st.MemoryLimit))
+	} else {
+		if u.CPUPercent, err = cpuPercent(cr); err != nil {
+			return nil, errors.Wrap(err, "cpu")
+		}
+		if u.MemoryUsedMB, u.MemoryTotalMB, err = memoryUsage(cr); err != nil {
+			return nil, errors.Wrap(err, "memory")
+		}
+	}
+
+	if u.DiskUsedMB, u.DiskTotalMB, err = diskUsage(cr, "/var"); err != nil {
+		return nil, errors.Wrap(err, "disk")
+	}
+	if u.ImagesMB, err = dirSizeMB(cr, imageStores[runtime]); err != nil {
+		return nil, errors.Wrap(err, "image store")
+	}
+	if u.VolumesMB, err = dirSizeMB(cr, hostpathPVDir); err != nil {
+		return nil, errors.Wrap(err, "volumes")
+	}
+	klog.Infof("%s resource usage: %+v", h.Name, u)
+	return u, nil
+}
+
+// cpuPercent samples /proc/stat over a second
+func cpuPercent(cr command.Runner) (float64, error) {
+	rr, err := cr.RunCmd(exec.Command("sh", "-c", "nproc; head -n1 /proc/stat; sleep 1; head -n1 /proc/stat"))
+	if err != nil {
+		return 0, err
+	}
+	return parseCPUSamples(rr.Stdout.String())
+}
+
+// parseCPUSamples parses the number of CPUs followed by two samples of the aggregate line of /proc/stat
+func parseCPUSamples(out string) (float64, error) {
+	// 2
+	// cpu  10132153 290696 3084719 46828483 16683 0 25195 0 0 0
+	// cpu  10132253 290696 3084739 46828563 16683 0 25195 0 0 0
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		return 0, fmt.Errorf("unexpected output: %q", out)
+	}
+	ncpus, err := strconv.Atoi(strings.TrimSpace(lines[0]))
+	if err != nil {
+		return 0, errors.Wrap(err, "nproc")
+	}
+	busy1, total1, err := parseCPULine(lines[1])
+	if err != nil {
+		return 0, err
+	}
+	busy2, total2, err := parseCPULine(lines[2])
+	if err != nil {
+		return 0, err
+	}
+	if total2 <= total1 {
+		return 0, nil
+	}
+	return float64(busy2-busy1) / float64(total2-total1) * float64(ncpus) * 100, nil
+}
+
+// parseCPULine returns the busy and total jiffies from the aggregate line of /proc/stat
+func parseCPULine(line string) (busy uint64, total uint64, err error) {
+	fields := strings.Fields(line)
+	if len(fields) < 5 || fields[0] != "cpu" {
+		return 0, 0, fmt.Errorf("unexpected /proc/stat line: %q", line)
+	}
+	var idle uint64
+	for i, f := range fields[1:] {
+		v, err := strconv.ParseUint(f, 10, 64)
+		if err != nil {
+			return 0, 0, errors.Wrapf(err, "parse %q", f)
+		}
+		total += v
+		// idle and iowait
+		if i == 3 || i == 4 {
+			idle += v
+		}
+	}
+	return total - idle, total, nil
+}
+
+// memoryUsage returns the used and total memory in MB
+func memoryUsage(cr command.Runner) (int64, int64, error) {
+	rr, err := cr.RunCmd(exec.Command("free", "-m"))
+	if err != nil {
+		return 0, 0, err
+	}
+	return parseMemUsage(rr.Stdout.String())
+}
+
+// parseMemUsage parses the output of `free -m`, counting memory that is not available to new processes as used
+func parseMemUsage(out string) (int64, int64, error) {
+	//               total        used        free      shared  buff/cache   available
+	// Mem:           1987         706         194           1        1086        1173
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 7 || fields[0] != "Mem:" {
+			continue
+		}
+		total, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+		available, err := strconv.ParseInt(fields[6], 10, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+		return total - available, total, nil
+	}
+	return 0, 0, fmt.Errorf("no matching data found")
+}
+
+// diskUsage returns the used and total size in MB of the filesystem holding dir
+func diskUsage(cr command.Runner, dir string) (int64, int64, error) {
+	rr, err := cr.RunCmd(exec.Command("df", "-m", dir))
+	if err != nil {
+		return 0, 0, err
+	}
+	return parseDiskUsage(rr.Stdout.String())
+}
+
+// parseDiskUsage parses the output of `df -m` for a single filesystem
+func parseDiskUsage(out string) (int64, int64, error) {
+	// Filesystem     1M-blocks  Used Available Use% Mounted on
+	// /dev/sda1          39643  3705     35922  10% /var
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) < 2 {
+		return 0, 0, fmt.Errorf("unexpected output: %q", out)
+	}
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(fields) < 3 {
+		return 0, 0, fmt.Errorf("unexpected output: %q", out)
+	}
+	total, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	used, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	return used, total, nil
+}
+
+// dirSizeMB returns the size of a directory in MB, or 0 if it does not exist
+func dirSizeMB(cr command.Runner, dir string) (int64, error) {
+	if dir == "" {
+		return 0, nil
+	}
+	rr, err := cr.RunCmd(exec.Command("sudo", "du", "-sm", dir))
+	if err != nil {
+		// du exits 1 when files vanish under it, as they do in the directories of a running runtime, still printing the total
+		if rr != nil && strings.TrimSpace(rr.Stdout.String()) != "" {
+			klog.Infof("du of %s: %v, using its total anyway", dir, err)
+			return parseDu(rr.Stdout.String())
+		}
+		// du prints nothing for a directory which does not exist
+		if _, terr := cr.RunCmd(exec.Command("sudo", "test", "-e", dir)); terr != nil {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return parseDu(rr.Stdout.String())
+}
+
+// parseDu parses the output of `du -sm`, which is empty if the directory does not exist
+func parseDu(out string) (int64, error) {
+	// 1432	/var/lib/docker
+	fields := strings.Fields(out)
+	if len(fields) == 0 {
+		return 0, nil
+	}
+	return strconv.ParseInt(fields[0], 10, 64)
+}
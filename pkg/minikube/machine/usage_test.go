@@ -0,0 +1,146 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"fmt"
+	"os/exec"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+func TestParseCPUSamples(t *testing.T) {
+	tests := []struct {
+		name    string
+		out     string
+		want    float64
+		wantErr bool
+	}{
+		// 2 CPUs, 100 of 200 jiffies busy: one CPU's worth
+		{"half busy", "2\ncpu  1000 0 0 1000 0 0 0 0 0 0\ncpu  1100 0 0 1100 0 0 0 0 0 0\n", 100, false},
+		{"idle", "4\ncpu  1000 0 0 1000 0 0 0 0 0 0\ncpu  1000 0 0 1100 0 0 0 0 0 0\n", 0, false},
+		{"iowait is idle", "1\ncpu  1000 0 0 1000 0 0 0 0 0 0\ncpu  1050 0 0 1000 50 0 0 0 0 0\n", 50, false},
+		{"no elapsed time", "1\ncpu  1000 0 0 1000 0\ncpu  1000 0 0 1000 0\n", 0, false},
+		{"truncated", "2\ncpu  1000 0 0 1000 0 0 0 0 0 0\n", 0, true},
+		{"garbage", "2\nintr 1 2 3\ncpu  1 2 3 4 5\n", 0, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseCPUSamples(tc.out)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseCPUSamples() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("parseCPUSamples() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseMemUsage(t *testing.T) {
+	out := `              total        used        free      shared  buff/cache   available
+Mem:           1987         706         194           1        1086        1173
+Swap:             0           0           0
+`
+	used, total, err := parseMemUsage(out)
+	if err != nil {
+		t.Fatalf("parseMemUsage() error: %v", err)
+	}
+	if used != 814 || total != 1987 {
+		t.Errorf("parseMemUsage() = %d, %d, want 814, 1987", used, total)
+	}
+
+	if _, _, err := parseMemUsage("free: not found"); err == nil {
+		t.Errorf("parseMemUsage() expected an error for unexpected output")
+	}
+}
+
+func TestParseDiskUsage(t *testing.T) {
+	out := `Filesystem     1M-blocks  Used Available Use% Mounted on
+/dev/sda1          39643  3705     35922  10% /var
+`
+	used, total, err := parseDiskUsage(out)
+	if err != nil {
+		t.Fatalf("parseDiskUsage() error: %v", err)
+	}
+	if used != 3705 || total != 39643 {
+		t.Errorf("parseDiskUsage() = %d, %d, want 3705, 39643", used, total)
+	}
+}
+
+func TestParseDu(t *testing.T) {
+	tests := []struct {
+		out  string
+		want int64
+	}{
+		{"1432\t/var/lib/docker\n", 1432},
+		{"", 0},
+	}
+	for _, tc := range tests {
+		got, err := parseDu(tc.out)
+		if err != nil {
+			t.Fatalf("parseDu(%q) error: %v", tc.out, err)
+		}
+		if got != tc.want {
+			t.Errorf("parseDu(%q) = %d, want %d", tc.out, got, tc.want)
+		}
+	}
+}
+
+// partialRunner fails the commands of failing after they printed their output, as du does when files vanish under it
+type partialRunner struct {
+	*command.FakeCommandRunner
+	failing map[string]bool
+}
+
+func (r *partialRunner) RunCmd(cmd *exec.Cmd) (*command.RunResult, error) {
+	rr, err := r.FakeCommandRunner.RunCmd(cmd)
+	if err == nil && r.failing[rr.Command()] {
+		return rr, fmt.Errorf("%s: exit status 1", rr.Command())
+	}
+	return rr, err
+}
+
+func TestDirSizeMB(t *testing.T) {
+	cr := &partialRunner{FakeCommandRunner: command.NewFakeCommandRunner(), failing: map[string]bool{"sudo du -sm /var/lib/containerd": true}}
+	cr.SetCommandToOutput(map[string]string{
+		"sudo du -sm /var/lib/docker":      "1432\t/var/lib/docker\n",
+		"sudo du -sm /var/lib/containerd":  "2048\t/var/lib/containerd\n",
+		"sudo test -e /var/lib/unreadable": "",
+	})
+	tests := []struct {
+		dir     string
+		want    int64
+		wantErr bool
+	}{
+		{"/var/lib/docker", 1432, false},
+		{"/var/lib/containerd", 2048, false},
+		{"/var/lib/missing", 0, false},
+		{"/var/lib/unreadable", 0, true},
+		{"", 0, false},
+	}
+	for _, tc := range tests {
+		got, err := dirSizeMB(cr, tc.dir)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("dirSizeMB(%q) error = %v, want error: %v", tc.dir, err, tc.wantErr)
+		}
+		if got != tc.want {
+			t.Errorf("dirSizeMB(%q) = %d, want %d", tc.dir, got, tc.want)
+		}
+	}
+}
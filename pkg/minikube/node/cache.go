@@ -28,6 +28,7 @@ import (
 	"k8s.io/klog/v2"
 	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
 	"k8s.io/minikube/pkg/drivers/kic"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/download"
@@ -107,15 +108,22 @@ func doCacheBinaries(k8sVersion string) error {
 
 // beginDownloadKicBaseImage downloads the kic image
 func beginDownloadKicBaseImage(g *errgroup.Group, cc *config.ClusterConfig, downloadOnly bool) {
+	if cc.Driver == driver.Nerdctl {
+		beginLoadKicBaseImageWithNerdctl(g, cc)
+		return
+	}
 	if cc.Driver != "docker" {
 		// TODO: driver == "podman"
 		klog.Info("Driver isn't docker, skipping base image download")
 		return
 	}
-	if image.ExistsImageInDaemon(cc.KicBaseImage) {
+	// images built by minikube kicbase build have no repository digest, they are looked up by their ID
+	if oci.ImageExistsLocally(oci.Docker, cc.KicBaseImage) {
 		klog.Infof("%s exists in daemon, skipping pull", cc.KicBaseImage)
 		return
 	}
+	// the base images chosen by the user aren't replaced by the fallback images
+	custom := cc.KicBaseImage != kic.BaseImage
 
 	klog.Infof("Beginning downloading kic base image for %s with %s", cc.Driver, cc.KubernetesConfig.ContainerRuntime)
 	register.Reg.SetStep(register.PullingBaseImage)
@@ -134,16 +142,21 @@ func beginDownloadKicBaseImage(g *errgroup.Group, cc *config.ClusterConfig, down
 				cc.KicBaseImage = finalImg
 			}
 		}()
-		for _, img := range append([]string{baseImg}, kic.FallbackImages...) {
-			if err := image.LoadFromTarball(driver.Docker, img); err == nil {
-				klog.Infof("successfully loaded %s from cached tarball", img)
+		imgs := []string{baseImg}
+		if !custom {
+			imgs = append(imgs, kic.FallbackImages...)
+		}
+		var err error
+		for _, img := range imgs {
+			if err = image.LoadFromCache(driver.Docker, img); err == nil {
+				klog.Infof("successfully loaded %s from the image cache", img)
 				// strip the digest from the img before saving it in the config
-				// because loading an image from tarball to daemon doesn't load the digest
+				// because loading an image from the image cache to daemon doesn't load the digest
 				finalImg = img
 				return nil
 			}
 			klog.Infof("Downloading %s to local daemon", img)
-			err := image.WriteImageToDaemon(img)
+			err = image.WriteImageToDaemon(img)
 			if err == nil {
 				klog.Infof("successfully downloaded %s", img)
 				finalImg = img
@@ -151,20 +164,53 @@ func beginDownloadKicBaseImage(g *errgroup.Group, cc *config.ClusterConfig, down
 			}
 			if downloadOnly {
 				if err := image.SaveToDir([]string{img}, constants.ImageCacheDir); err == nil {
-					klog.Infof("successfully saved %s as a tarball", img)
+					klog.Infof("successfully saved %s to the image cache", img)
 					finalImg = img
 					return nil
 				}
 			}
 			klog.Infof("failed to download %s, will try fallback image if available: %v", img, err)
 		}
+		if custom {
+			return &errBaseImageMissing{img: baseImg, err: err}
+		}
 		return fmt.Errorf("failed to download kic base image or any fallback image")
 	})
 }
 
+// beginLoadKicBaseImageWithNerdctl loads the kic image from the image cache into containerd, where nerdctl pulls it otherwise
+func beginLoadKicBaseImageWithNerdctl(g *errgroup.Group, cc *config.ClusterConfig) {
+	if oci.ImageExistsLocally(oci.Nerdctl, cc.KicBaseImage) {
+		klog.Infof("%s exists in containerd, skipping load", cc.KicBaseImage)
+		return
+	}
+	g.Go(func() error {
+		if err := image.LoadFromCache(driver.Nerdctl, cc.KicBaseImage); err != nil {
+			klog.Infof("unable to load %s from the image cache, nerdctl will pull it: %v", cc.KicBaseImage, err)
+			return nil
+		}
+		klog.Infof("successfully loaded %s from the image cache", cc.KicBaseImage)
+		return nil
+	})
+}
+
+// errBaseImageMissing is the error of a base image chosen by the user which is neither local nor can be pulled
+type errBaseImageMissing struct {
+	img string
+	err error
+}
+
+func (e *errBaseImageMissing) Error() string {
+	return fmt.Sprintf("base image %s is neither in the local daemon nor can be pulled: %v", e.img, e.err)
+}
+
 // waitDownloadKicBaseImage blocks until the base image for KIC is downloaded.
 func waitDownloadKicBaseImage(g *errgroup.Group) {
 	if err := g.Wait(); err != nil {
+		var missing *errBaseImageMissing
+		if errors.As(err, &missing) {
+			exit.Message(reason.GuestBaseImageMissing, "The base image {{.image}} is missing: {{.error}}. Build it with 'minikube kicbase build', or pull it, before starting the cluster.", out.V{"image": missing.img, "error": missing.err})
+		}
 		if err != nil {
 			if errors.Is(err, image.ErrGithubNeedsLogin) {
 				klog.Warningf("Error downloading kic artifacts: %v", err)
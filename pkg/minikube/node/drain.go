@@ -0,0 +1,204 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/kubectl/pkg/drain"
+
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/out/register"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+// DrainOptions control how pods are evicted from a node
+type DrainOptions struct {
+	// Force also evicts pods not managed by a controller, which are left on the node otherwise,
+	// and lets stop and delete go on when draining fails
+	Force bool
+	// Timeout bounds the whole drain, zero waits forever
+	Timeout time.Duration
+}
+
+// logWriter forwards the messages of the drain helper to the log
+type logWriter struct{}
+
+func (logWriter) Write(p []byte) (int, error) {
+	klog.Info(strings.TrimSpace(string(p)))
+	return len(p), nil
+}
+
+// Drain cordons the node and evicts its pods, respecting their PodDisruptionBudgets
+func Drain(cc config.ClusterConfig, n config.Node, opts DrainOptions) error {
+	register.Reg.SetStep(register.Draining)
+	name := config.MachineName(cc, n)
+	out.Step(style.Waiting, "Draining node {{.name}} ...", out.V{"name": name})
+
+	client, err := kapi.Client(cc.Name)
+	if err != nil {
+		return errors.Wrap(err, "kubernetes client")
+	}
+	return drainNode(client, name, opts)
+}
+
+// Uncordon marks the node as schedulable again
+func Uncordon(cc config.ClusterConfig, n config.Node) error {
+	register.Reg.SetStep(register.Uncordoning)
+	name := config.MachineName(cc, n)
+
+	client, err := kapi.Client(cc.Name)
+	if err != nil {
+		return errors.Wrap(err, "kubernetes client")
+	}
+	return cordonNode(newDrainHelper(client, DrainOptions{}), name, false)
+}
+
+// drainWorker drains a worker node before it is stopped or deleted
+func drainWorker(cc config.ClusterConfig, n config.Node, opts DrainOptions) error {
+	if n.ControlPlane {
+		klog.Infof("not draining control plane node %s", n.Name)
+		return nil
+	}
+	if err := Drain(cc, n, opts); err != nil {
+		if !opts.Force {
+			return errors.Wrap(err, "drain")
+		}
+		out.WarningT("Unable to drain node {{.name}}, going on because of --force: {{.error}}", out.V{"name": n.Name, "error": err})
+	}
+	return nil
+}
+
+// drainBeforeDelete drains a worker node about to be deleted, unless it is unreachable: the pods of a stopped
+// or NotReady node can't be evicted gracefully, and are deleted along with the node anyway
+func drainBeforeDelete(api libmachine.API, cc config.ClusterConfig, n config.Node, opts DrainOptions) error {
+	if n.ControlPlane {
+		return nil
+	}
+	name := config.MachineName(cc, n)
+	if why := unreachable(api, cc, name); why != "" {
+		out.Step(style.Waiting, "Not draining node {{.name}}, as it is {{.reason}}", out.V{"name": name, "reason": why})
+		return nil
+	}
+	err := drainWorker(cc, n, opts)
+	if err == nil {
+		return nil
+	}
+	// the node may have gone down while draining
+	if why := unreachable(api, cc, name); why != "" {
+		out.WarningT("Unable to drain node {{.name}}, which is {{.reason}}, deleting it anyway: {{.error}}", out.V{"name": name, "reason": why, "error": err})
+		return nil
+	}
+	return err
+}
+
+// unreachable returns why the pods of a node can't be evicted, or an empty string if they can
+func unreachable(api libmachine.API, cc config.ClusterConfig, name string) string {
+	st, err := machine.Status(api, name)
+	if err != nil {
+		klog.Warningf("unable to get the status of %s: %v", name, err)
+	}
+	var client kubernetes.Interface
+	if c, err := kapi.Client(cc.Name); err == nil {
+		client = c
+	} else {
+		klog.Warningf("kubernetes client: %v", err)
+	}
+	return unreachableNode(st, client, name)
+}
+
+// unreachableNode returns why the pods of a node can't be evicted, given the state of its host and the API server
+func unreachableNode(hostState string, client kubernetes.Interface, name string) string {
+	if hostState != "" && hostState != state.Running.String() {
+		return strings.ToLower(hostState)
+	}
+	if client == nil {
+		return ""
+	}
+	node, err := client.CoreV1().Nodes().Get(name, metav1.GetOptions{})
+	if err != nil {
+		// the API server is unreachable rather than the node, draining fails anyway
+		klog.Infof("get node %s: %v", name, err)
+		return ""
+	}
+	if !nodeReady(node) {
+		return "NotReady"
+	}
+	return ""
+}
+
+func newDrainHelper(client kubernetes.Interface, opts DrainOptions) *drain.Helper {
+	return &drain.Helper{
+		Client:  client,
+		Force:   opts.Force,
+		Timeout: opts.Timeout,
+		// use the grace period of each pod
+		GracePeriodSeconds:  -1,
+		IgnoreAllDaemonSets: true,
+		// emptyDir volumes don't outlive the pods evicted from the node anyway
+		DeleteLocalData: true,
+		Out:             logWriter{},
+		ErrOut:          logWriter{},
+		OnPodDeletedOrEvicted: func(pod *core.Pod, usingEviction bool) {
+			out.Step(style.Check, "Evicted pod {{.namespace}}/{{.pod}}", out.V{"namespace": pod.Namespace, "pod": pod.Name})
+		},
+	}
+}
+
+// cordonNode marks the node as unschedulable, or schedulable again
+func cordonNode(h *drain.Helper, name string, unschedulable bool) error {
+	node, err := h.Client.CoreV1().Nodes().Get(name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "get node %s", name)
+	}
+	if err := drain.RunCordonOrUncordon(h, node, unschedulable); err != nil {
+		return errors.Wrapf(err, "cordon node %s", name)
+	}
+	return nil
+}
+
+// drainNode cordons the node and evicts all its pods but the DaemonSet ones
+func drainNode(client kubernetes.Interface, name string, opts DrainOptions) error {
+	h := newDrainHelper(client, opts)
+	if err := cordonNode(h, name, true); err != nil {
+		return err
+	}
+
+	list, errs := h.GetPodsForDeletion(name)
+	if errs != nil {
+		return errors.Wrapf(utilerrors.NewAggregate(errs), "pods of node %s", name)
+	}
+	if warnings := list.Warnings(); warnings != "" {
+		out.WarningT("{{.warnings}}", out.V{"warnings": warnings})
+	}
+	if err := h.DeleteOrEvictPods(list.Pods()); err != nil {
+		return errors.Wrapf(err, "evicting pods of node %s", name)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// testPod returns a pod of the node m02, owned by a controller of the given kind unless kind is empty
+func testPod(name string, kind string) *core.Pod {
+	pod := &core.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       core.PodSpec{NodeName: "m02"},
+	}
+	if kind != "" {
+		controller := true
+		pod.OwnerReferences = []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: kind, Name: name, Controller: &controller}}
+	}
+	return pod
+}
+
+// fakeClient returns a clientset supporting evictions, which delete the evicted pod
+func fakeClient(objects ...runtime.Object) *fake.Clientset {
+	client := fake.NewSimpleClientset(append([]runtime.Object{&core.Node{ObjectMeta: metav1.ObjectMeta{Name: "m02"}}}, objects...)...)
+	client.Resources = []*metav1.APIResourceList{
+		{GroupVersion: "policy/v1beta1"},
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{{Name: "pods/eviction", Kind: "Eviction"}}},
+	}
+	client.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		eviction := action.(k8stesting.CreateAction).GetObject().(metav1.Object)
+		return true, nil, client.Tracker().Delete(core.SchemeGroupVersion.WithResource("pods"), eviction.GetNamespace(), eviction.GetName())
+	})
+	return client
+}
+
+func TestDrainNode(t *testing.T) {
+	ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "proxy", Namespace: "default"}}
+	client := fakeClient(ds, testPod("web", "ReplicaSet"), testPod("db", "StatefulSet"), testPod("proxy", "DaemonSet"))
+	if err := drainNode(client, "m02", DrainOptions{Timeout: time.Minute}); err != nil {
+		t.Fatalf("drainNode: %v", err)
+	}
+
+	node, err := client.CoreV1().Nodes().Get("m02", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get node: %v", err)
+	}
+	if !node.Spec.Unschedulable {
+		t.Errorf("node m02 is schedulable after drain")
+	}
+	pods, err := client.CoreV1().Pods("default").List(metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("list pods: %v", err)
+	}
+	if len(pods.Items) != 1 || pods.Items[0].Name != "proxy" {
+		t.Errorf("pods after drain = %v, want only the DaemonSet pod", pods.Items)
+	}
+
+	if err := cordonNode(newDrainHelper(client, DrainOptions{}), "m02", false); err != nil {
+		t.Fatalf("uncordon: %v", err)
+	}
+	if node, _ := client.CoreV1().Nodes().Get("m02", metav1.GetOptions{}); node.Spec.Unschedulable {
+		t.Errorf("node m02 is unschedulable after uncordon")
+	}
+}
+
+func TestDrainNodeUnmanagedPod(t *testing.T) {
+	client := fakeClient(testPod("standalone", ""))
+	if err := drainNode(client, "m02", DrainOptions{Timeout: time.Minute}); err != nil {
+		t.Fatalf("drainNode: %v", err)
+	}
+	if _, err := client.CoreV1().Pods("default").Get("standalone", metav1.GetOptions{}); err != nil {
+		t.Errorf("drainNode evicted a pod not managed by a controller without force: %v", err)
+	}
+
+	if err := drainNode(client, "m02", DrainOptions{Force: true, Timeout: time.Minute}); err != nil {
+		t.Fatalf("drainNode with force: %v", err)
+	}
+	if _, err := client.CoreV1().Pods("default").Get("standalone", metav1.GetOptions{}); err == nil {
+		t.Errorf("pod standalone still exists after a forced drain")
+	}
+}
+
+func TestUnreachableNode(t *testing.T) {
+	ready := func(status core.ConditionStatus) *core.Node {
+		return &core.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "m02"},
+			Status:     core.NodeStatus{Conditions: []core.NodeCondition{{Type: core.NodeReady, Status: status}}},
+		}
+	}
+	tests := []struct {
+		description string
+		hostState   string
+		node        *core.Node
+		want        string
+	}{
+		{"running and ready", "Running", ready(core.ConditionTrue), ""},
+		{"stopped host", "Stopped", ready(core.ConditionTrue), "stopped"},
+		{"not ready", "Running", ready(core.ConditionFalse), "NotReady"},
+		{"unknown readiness", "Running", ready(core.ConditionUnknown), "NotReady"},
+		{"unknown host state", "", ready(core.ConditionTrue), ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			if got := unreachableNode(tc.hostState, fake.NewSimpleClientset(tc.node), "m02"); got != tc.want {
+				t.Errorf("unreachableNode(%q) = %q, want %q", tc.hostState, got, tc.want)
+			}
+		})
+	}
+
+	// an unreachable API server doesn't make the node unreachable
+	if got := unreachableNode("Running", fake.NewSimpleClientset(), "m02"); got != "" {
+		t.Errorf("unreachableNode() of a missing node = %q, want none", got)
+	}
+	if got := unreachableNode("Running", nil, "m02"); got != "" {
+		t.Errorf("unreachableNode() without a client = %q, want none", got)
+	}
+}
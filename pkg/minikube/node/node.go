@@ -18,16 +18,21 @@ package node
 
 import (
 	"fmt"
-	"os/exec"
 
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 
 	"k8s.io/klog/v2"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
 	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/out/register"
 )
 
 // TODO: Share these between cluster and node packages
@@ -78,8 +83,8 @@ func Add(cc *config.ClusterConfig, n config.Node, delOnFail bool) error {
 	return err
 }
 
-// Delete stops and deletes the given node from the given cluster
-func Delete(cc config.ClusterConfig, name string) (*config.Node, error) {
+// Delete drains, stops and deletes the given node from the given cluster. Stopped and NotReady nodes aren't drained.
+func Delete(cc config.ClusterConfig, name string, opts DrainOptions) (*config.Node, error) {
 	n, index, err := Retrieve(cc, name)
 	if err != nil {
 		return n, errors.Wrap(err, "retrieve")
@@ -91,24 +96,20 @@ func Delete(cc config.ClusterConfig, name string) (*config.Node, error) {
 		return n, err
 	}
 
-	// grab control plane to use kubeconfig
-	host, err := machine.LoadHost(api, cc.Name)
-	if err != nil {
-		return n, err
+	if cc.HA && config.IsPrimaryControlPlane(cc, *n) {
+		return n, errors.Errorf("%s is the primary control plane node of the cluster, it can't be deleted", m)
 	}
 
-	runner, err := machine.CommandRunner(host)
-	if err != nil {
+	if err := drainBeforeDelete(api, cc, *n, opts); err != nil {
 		return n, err
 	}
+	register.Reg.SetStep(register.Deleting)
 
-	// kubectl drain
-	kubectl := kapi.KubectlBinaryPath(cc.KubernetesConfig.KubernetesVersion)
-	cmd := exec.Command("sudo", "KUBECONFIG=/var/lib/minikube/kubeconfig", kubectl, "drain", m)
-	if _, err := runner.RunCmd(cmd); err != nil {
-		klog.Warningf("unable to scale coredns replicas to 1: %v", err)
-	} else {
-		klog.Infof("successfully scaled coredns replicas to 1")
+	if n.ControlPlane {
+		// kubeadm reset removes the etcd member of the node from the cluster
+		if err := resetHost(api, cc, m); err != nil {
+			klog.Warningf("unable to reset %s: %v", m, err)
+		}
 	}
 
 	// kubectl delete
@@ -122,6 +123,13 @@ func Delete(cc config.ClusterConfig, name string) (*config.Node, error) {
 		return n, err
 	}
 
+	// the hosts of the ssh driver outlive their nodes, so clean them up
+	if driver.IsSSH(cc.Driver) && !n.ControlPlane {
+		if err := resetHost(api, cc, m); err != nil {
+			klog.Warningf("unable to reset %s: %v", m, err)
+		}
+	}
+
 	err = machine.DeleteHost(api, m)
 	if err != nil {
 		return n, err
@@ -131,6 +139,57 @@ func Delete(cc config.ClusterConfig, name string) (*config.Node, error) {
 	return n, config.SaveProfile(viper.GetString(config.ProfileName), &cc)
 }
 
+// Stop drains the given worker node and stops its host
+func Stop(api libmachine.API, cc config.ClusterConfig, n config.Node, opts DrainOptions) error {
+	m := config.MachineName(cc, n)
+	st, err := machine.Status(api, m)
+	if err != nil {
+		return errors.Wrap(err, "status")
+	}
+	if st == state.Running.String() {
+		if cc.HA && n.ControlPlane {
+			if running := runningControlPlanes(api, cc); running-1 < config.Quorum(cc) {
+				out.WarningT("Stopping {{.name}} leaves {{.running}} of {{.total}} control plane nodes running: etcd loses its quorum and the cluster stops accepting changes", out.V{"name": m, "running": running - 1, "total": len(config.ControlPlanes(cc))})
+			}
+		}
+		if err := drainWorker(cc, n, opts); err != nil {
+			return err
+		}
+	}
+	return machine.StopHost(api, m)
+}
+
+// runningControlPlanes returns the number of control plane nodes whose host is running
+func runningControlPlanes(api libmachine.API, cc config.ClusterConfig) int {
+	running := 0
+	for _, cp := range config.ControlPlanes(cc) {
+		st, err := machine.Status(api, config.MachineName(cc, cp))
+		if err == nil && st == state.Running.String() {
+			running++
+		}
+	}
+	return running
+}
+
+// resetHost removes Kubernetes from the host of a machine with kubeadm reset
+func resetHost(api libmachine.API, cc config.ClusterConfig, machineName string) error {
+	h, err := machine.LoadHost(api, machineName)
+	if err != nil {
+		return errors.Wrap(err, "load host")
+	}
+
+	r, err := machine.CommandRunner(h)
+	if err != nil {
+		return errors.Wrap(err, "command runner")
+	}
+
+	bs, err := cluster.Bootstrapper(api, viper.GetString(cmdcfg.Bootstrapper), cc, r)
+	if err != nil {
+		return errors.Wrap(err, "bootstrapper")
+	}
+	return bs.DeleteCluster(cc.KubernetesConfig)
+}
+
 // Retrieve finds the node by name in the given cluster
 func Retrieve(cc config.ClusterConfig, name string) (*config.Node, int, error) {
 	if driver.BareMetal(cc.Driver) {
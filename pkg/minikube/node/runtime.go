@@ -0,0 +1,335 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/pkg/errors"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/cni"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/minikube/sysinit"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+	"k8s.io/minikube/pkg/util"
+)
+
+const (
+	// criSocketAnnotation is the annotation of the nodes with the socket of their runtime, which kubeadm reads
+	criSocketAnnotation = "kubeadm.alpha.kubernetes.io/cri-socket"
+	// runtimeSwitchDir holds the images of the old runtime while they are migrated to the new one
+	runtimeSwitchDir = vmpath.GuestPersistentDir + "/runtime-switch"
+	// runtimeSwitchTimeout bounds the wait for a node to be ready with its new runtime
+	runtimeSwitchTimeout = 4 * time.Minute
+)
+
+// switchNode switches the runtime of a node, replaced by the tests
+var switchNode = switchNodeRuntime
+
+// SwitchRuntime switches the running nodes of an existing cluster from the container runtime of from
+// to the one of to, migrating their images. If a node fails, the switched nodes are switched back.
+func SwitchRuntime(api libmachine.API, from config.ClusterConfig, to config.ClusterConfig) error {
+	var switched []config.Node
+	for _, n := range to.Nodes {
+		err := switchNode(api, from, to, n)
+		if err == nil {
+			switched = append(switched, n)
+			continue
+		}
+
+		out.WarningT("Switching the container runtime of {{.name}} failed, switching back to {{.runtime}}: {{.error}}", out.V{"name": config.MachineName(to, n), "runtime": from.KubernetesConfig.ContainerRuntime, "error": err})
+		switched = append(switched, n)
+		for i := len(switched) - 1; i >= 0; i-- {
+			if rerr := switchNode(api, to, from, switched[i]); rerr != nil {
+				out.WarningT("Unable to switch {{.name}} back to {{.runtime}}: {{.error}}", out.V{"name": config.MachineName(from, switched[i]), "runtime": from.KubernetesConfig.ContainerRuntime, "error": rerr})
+			}
+		}
+		return errors.Wrapf(err, "switching %s", config.MachineName(to, n))
+	}
+	return nil
+}
+
+// switchNodeRuntime drains a node, switches it to the runtime of to with the images of the runtime of from,
+// and waits for it to be ready before making it schedulable again
+func switchNodeRuntime(api libmachine.API, from config.ClusterConfig, to config.ClusterConfig, n config.Node) error {
+	name := config.MachineName(to, n)
+	h, err := machine.LoadHost(api, name)
+	if err != nil {
+		return errors.Wrap(err, "load host")
+	}
+	runner, err := machine.CommandRunner(h)
+	if err != nil {
+		return errors.Wrap(err, "command runner")
+	}
+	oldCR, err := newRuntime(from, h, runner, nil)
+	if err != nil {
+		return errors.Wrap(err, "old runtime")
+	}
+	hs, err := installRuntimeHandlers(runner, to)
+	if err != nil {
+		out.WarningT("Unable to install the runtime handlers, the pods selecting them won't start: {{.error}}", out.V{"error": err})
+	}
+	newCR, err := newRuntime(to, h, runner, hs)
+	if err != nil {
+		return errors.Wrap(err, "new runtime")
+	}
+
+	if err := Drain(to, n, DrainOptions{Force: true, Timeout: runtimeSwitchTimeout}); err != nil {
+		// the pods stop along with the old runtime anyway
+		out.WarningT("Unable to drain node {{.name}}, stopping its pods anyway: {{.error}}", out.V{"name": name, "error": err})
+	}
+
+	out.Step(style.Caching, "Saving the images of {{.name}} from {{.runtime}} ...", out.V{"name": name, "runtime": oldCR.Name()})
+	archives, err := saveImages(runner, oldCR)
+	defer func() {
+		if _, err := runner.RunCmd(exec.Command("sudo", "rm", "-rf", runtimeSwitchDir)); err != nil {
+			klog.Warningf("unable to remove %s: %v", runtimeSwitchDir, err)
+		}
+	}()
+	if err != nil {
+		return errors.Wrap(err, "saving images")
+	}
+
+	stopKubernetes(runner, oldCR)
+
+	out.Step(newCR.Style(), "Switching {{.name}} to {{.runtime}} ...", out.V{"name": name, "runtime": newCR.Name()})
+	if err := newCR.Enable(!driver.BareMetal(to.Driver), forceSystemd()); err != nil {
+		return errors.Wrap(err, "enable runtime")
+	}
+	if err := waitForCRISocket(runner, newCR.SocketPath(), 60, 1); err != nil {
+		return errors.Wrap(err, "runtime socket")
+	}
+	for img, archive := range archives {
+		if err := newCR.LoadImage(archive); err != nil {
+			// the image is pulled again when needed
+			klog.Warningf("unable to load %s: %v", img, err)
+		}
+	}
+
+	if err := restartKubelet(to, n, runner, newCR); err != nil {
+		return errors.Wrap(err, "kubelet")
+	}
+	if err := waitForRuntime(to, n, newCR); err != nil {
+		return errors.Wrap(err, "verify")
+	}
+
+	// the node isn't ready until the CNI of the new runtime runs, such as kindnet for containerd on docker
+	cpr := runner
+	if cp, err := config.PrimaryControlPlane(&to); err == nil && cp.Name != n.Name {
+		if cpr, err = controlPlaneRunner(api, to, cp); err != nil {
+			return errors.Wrap(err, "control plane")
+		}
+	}
+	if err := applyCNI(cpr, to); err != nil {
+		return errors.Wrap(err, "cni")
+	}
+	if _, err := waitForNode(to, n, nodeReady); err != nil {
+		return errors.Wrapf(err, "waiting for %s to be ready", name)
+	}
+	return Uncordon(to, n)
+}
+
+// controlPlaneRunner returns the command runner of the control plane of a cluster
+func controlPlaneRunner(api libmachine.API, cc config.ClusterConfig, cp config.Node) (command.Runner, error) {
+	h, err := machine.LoadHost(api, config.MachineName(cc, cp))
+	if err != nil {
+		return nil, errors.Wrap(err, "load host")
+	}
+	return machine.CommandRunner(h)
+}
+
+// applyCNI applies the CNI of a cluster through the runner of its control plane
+func applyCNI(cpr command.Runner, cc config.ClusterConfig) error {
+	cnm, err := cni.New(cc)
+	if err != nil {
+		return errors.Wrap(err, "cni")
+	}
+	klog.Infof("applying %s CNI for %s", cnm, cc.KubernetesConfig.ContainerRuntime)
+	return cnm.Apply(cpr)
+}
+
+// newRuntime returns the runtime of a cluster on the node of a host, with the runtime handlers installed on the node
+func newRuntime(cc config.ClusterConfig, h *host.Host, runner command.Runner, hs []cruntime.RuntimeHandler) (cruntime.Manager, error) {
+	kv, err := util.ParseKubernetesVersion(cc.KubernetesConfig.KubernetesVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse kubernetes version")
+	}
+	hostIP, err := cluster.HostIP(h, cc.Name)
+	if err != nil {
+		klog.Warningf("unable to get host IP, skipping the registry cache: %v", err)
+		hostIP = nil
+	}
+	co, err := runtimeConfig(cc, runner, kv, hostIP)
+	if err != nil {
+		return nil, errors.Wrap(err, "registries")
+	}
+	co.RuntimeHandlers = hs
+	return cruntime.New(co)
+}
+
+// saveImages saves the tagged images of a runtime into archives within the node, by image
+func saveImages(runner command.Runner, cr cruntime.Manager) (map[string]string, error) {
+	// writable by the runtimes which don't run as root
+	if _, err := runner.RunCmd(exec.Command("sudo", "mkdir", "-m", "0777", "-p", runtimeSwitchDir)); err != nil {
+		return nil, errors.Wrap(err, "mkdir")
+	}
+	archives := map[string]string{}
+	imgs, err := cr.ListImages()
+	if err != nil {
+		// the images are pulled again when needed
+		klog.Warningf("unable to list the images of %s: %v", cr.Name(), err)
+		return archives, nil
+	}
+	for i, img := range imgs {
+		archive := path.Join(runtimeSwitchDir, fmt.Sprintf("%d.tar", i))
+		if err := cr.SaveImage(img, archive); err != nil {
+			// the image is pulled again when needed
+			klog.Warningf("unable to save %s: %v", img, err)
+			continue
+		}
+		archives[img] = archive
+	}
+	return archives, nil
+}
+
+// stopKubernetes stops the kubelet and the containers of the runtime, which disabling the runtime may leave running
+func stopKubernetes(runner command.Runner, cr cruntime.Manager) {
+	if err := sysinit.New(runner).ForceStop("kubelet"); err != nil {
+		klog.Warningf("unable to stop kubelet: %v", err)
+	}
+	ids, err := cr.ListContainers(cruntime.ListOptions{State: cruntime.All})
+	if err != nil {
+		klog.Warningf("unable to list the containers of %s: %v", cr.Name(), err)
+		return
+	}
+	if len(ids) == 0 {
+		return
+	}
+	if err := cr.StopContainers(ids); err != nil {
+		klog.Warningf("unable to stop the containers of %s: %v", cr.Name(), err)
+	}
+}
+
+// restartKubelet regenerates the kubelet flags for the runtime, and restarts the kubelet
+func restartKubelet(cc config.ClusterConfig, n config.Node, runner command.Runner, cr cruntime.Manager) error {
+	kubeletCfg, err := bsutil.NewKubeletConfig(cc, n, cr)
+	if err != nil {
+		return errors.Wrap(err, "generating kubelet config")
+	}
+	files := []assets.CopyableFile{
+		assets.NewMemoryAssetTarget(kubeletCfg, bsutil.KubeletSystemdConfFile, "0644"),
+	}
+	if err := bsutil.CopyFiles(runner, files); err != nil {
+		return errors.Wrap(err, "copy")
+	}
+	return sysinit.New(runner).Restart("kubelet")
+}
+
+// waitForRuntime waits for the node to report running on the runtime, and updates the socket kubeadm knows it by
+// unless it is up to date. The socket is stale on the first start of a cluster whose runtime was switched while it was stopped.
+func waitForRuntime(cc config.ClusterConfig, n config.Node, cr cruntime.Manager) error {
+	name := config.MachineName(cc, n)
+	node, err := waitForNode(cc, n, func(node *core.Node) bool {
+		return nodeRunsOn(node, cc.KubernetesConfig.ContainerRuntime)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "waiting for %s to run on %s", name, cr.Name())
+	}
+
+	client, err := kapi.Client(cc.Name)
+	if err != nil {
+		return errors.Wrap(err, "kubernetes client")
+	}
+	return annotateCRISocket(client, node, cr.SocketPath())
+}
+
+// annotateCRISocket annotates a node with the socket of its runtime, unless it already is
+func annotateCRISocket(client kubernetes.Interface, node *core.Node, socket string) error {
+	if node.Annotations[criSocketAnnotation] == socket {
+		return nil
+	}
+	klog.Infof("updating the cri socket of %s from %q to %s", node.Name, node.Annotations[criSocketAnnotation], socket)
+	if node.Annotations == nil {
+		node.Annotations = map[string]string{}
+	}
+	node.Annotations[criSocketAnnotation] = socket
+	if _, err := client.CoreV1().Nodes().Update(node); err != nil {
+		return errors.Wrap(err, "annotate cri socket")
+	}
+	return nil
+}
+
+// waitForNode waits for a node to meet a condition, and returns it
+func waitForNode(cc config.ClusterConfig, n config.Node, cond func(*core.Node) bool) (*core.Node, error) {
+	name := bsutil.KubeNodeName(cc, n)
+	client, err := kapi.Client(cc.Name)
+	if err != nil {
+		return nil, errors.Wrap(err, "kubernetes client")
+	}
+
+	var node *core.Node
+	err = wait.PollImmediate(2*time.Second, runtimeSwitchTimeout, func() (bool, error) {
+		node, err = client.CoreV1().Nodes().Get(name, metav1.GetOptions{})
+		if err != nil {
+			klog.Infof("get node %s, will retry: %v", name, err)
+			return false, nil
+		}
+		return cond(node), nil
+	})
+	return node, err
+}
+
+// nodeRunsOn returns whether a node reports running on the container runtime, ready or not
+func nodeRunsOn(node *core.Node, containerRuntime string) bool {
+	// the runtime version is reported as <runtime>://<version>
+	name := containerRuntime
+	if name == "crio" {
+		name = "cri-o"
+	}
+	return strings.HasPrefix(node.Status.NodeInfo.ContainerRuntimeVersion, name+"://")
+}
+
+// nodeReady returns whether a node is ready
+func nodeReady(node *core.Node) bool {
+	for _, c := range node.Status.Conditions {
+		if c.Type == core.NodeReady {
+			return c.Status == core.ConditionTrue
+		}
+	}
+	return false
+}
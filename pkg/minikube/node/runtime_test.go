@@ -0,0 +1,173 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/docker/machine/libmachine"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestNodeRunsOn(t *testing.T) {
+	node := func(version string, ready core.ConditionStatus) *core.Node {
+		return &core.Node{
+			Status: core.NodeStatus{
+				NodeInfo:   core.NodeSystemInfo{ContainerRuntimeVersion: version},
+				Conditions: []core.NodeCondition{{Type: core.NodeReady, Status: ready}},
+			},
+		}
+	}
+	tests := []struct {
+		description string
+		node        *core.Node
+		runtime     string
+		want        bool
+	}{
+		{"ready on containerd", node("containerd://1.4.3", core.ConditionTrue), "containerd", true},
+		{"ready on cri-o", node("cri-o://1.19.1", core.ConditionTrue), "crio", true},
+		{"still on docker", node("docker://20.10.2", core.ConditionTrue), "containerd", false},
+		{"not ready yet", node("containerd://1.4.3", core.ConditionFalse), "containerd", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			if got := nodeRunsOn(tc.node, tc.runtime); got != tc.want {
+				t.Errorf("nodeRunsOn(%s) = %v, want %v", tc.runtime, got, tc.want)
+			}
+		})
+	}
+
+	if nodeReady(node("containerd://1.4.3", core.ConditionFalse)) {
+		t.Errorf("nodeReady() = true for a NotReady node")
+	}
+	if !nodeReady(node("containerd://1.4.3", core.ConditionTrue)) {
+		t.Errorf("nodeReady() = false for a Ready node")
+	}
+}
+
+func TestAnnotateCRISocket(t *testing.T) {
+	tests := []struct {
+		description string
+		annotations map[string]string
+		wantUpdate  bool
+	}{
+		{"switched while stopped", map[string]string{criSocketAnnotation: "/var/run/dockershim.sock"}, true},
+		{"not annotated", nil, true},
+		{"up to date", map[string]string{criSocketAnnotation: "/run/containerd/containerd.sock"}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			node := &core.Node{ObjectMeta: metav1.ObjectMeta{Name: "minikube", Annotations: tc.annotations}}
+			client := fake.NewSimpleClientset(node.DeepCopy())
+			if err := annotateCRISocket(client, node, "/run/containerd/containerd.sock"); err != nil {
+				t.Fatalf("annotateCRISocket() error = %v", err)
+			}
+
+			updates := 0
+			for _, a := range client.Actions() {
+				if a.GetVerb() == "update" {
+					updates++
+				}
+			}
+			if got := updates > 0; got != tc.wantUpdate {
+				t.Errorf("annotateCRISocket() updated the node %d times, want an update: %v", updates, tc.wantUpdate)
+			}
+			got, err := client.CoreV1().Nodes().Get("minikube", metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("get node: %v", err)
+			}
+			if socket := got.Annotations[criSocketAnnotation]; socket != "/run/containerd/containerd.sock" {
+				t.Errorf("cri socket = %q, want %q", socket, "/run/containerd/containerd.sock")
+			}
+		})
+	}
+}
+
+func TestSwitchRuntimeRollback(t *testing.T) {
+	defer func(f func(libmachine.API, config.ClusterConfig, config.ClusterConfig, config.Node) error) {
+		switchNode = f
+	}(switchNode)
+
+	nodes := []config.Node{{Name: "", ControlPlane: true}, {Name: "m02"}, {Name: "m03"}}
+	from := config.ClusterConfig{Name: "p", Nodes: nodes, KubernetesConfig: config.KubernetesConfig{ContainerRuntime: "docker"}}
+	to := config.ClusterConfig{Name: "p", Nodes: nodes, KubernetesConfig: config.KubernetesConfig{ContainerRuntime: "containerd"}}
+
+	var got []string
+	switchNode = func(_ libmachine.API, _ config.ClusterConfig, to config.ClusterConfig, n config.Node) error {
+		got = append(got, fmt.Sprintf("%s %s", config.MachineName(to, n), to.KubernetesConfig.ContainerRuntime))
+		if n.Name == "m02" && to.KubernetesConfig.ContainerRuntime == "containerd" {
+			return fmt.Errorf("node never became ready")
+		}
+		return nil
+	}
+
+	if err := SwitchRuntime(nil, from, to); err == nil {
+		t.Fatalf("SwitchRuntime() succeeded, want the error of m02")
+	}
+	// the failed node is switched back too, as it may be half switched, and m03 is left alone
+	want := []string{"p containerd", "p-m02 containerd", "p-m02 docker", "p docker"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("switched %v, want %v", got, want)
+	}
+}
+
+func TestApplyCNI(t *testing.T) {
+	kubectl := "sudo /var/lib/minikube/binaries/v1.20.2/kubectl apply --kubeconfig=/var/lib/minikube/kubeconfig -f /var/tmp/minikube/cni.yaml"
+	tests := []struct {
+		description string
+		driver      string
+		runtime     string
+		kindnet     bool
+	}{
+		{"kindnet for containerd on docker", "docker", "containerd", true},
+		{"kindnet for cri-o on podman", "podman", "crio", true},
+		{"none for docker on docker", "docker", "docker", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			cc := config.ClusterConfig{
+				Driver: tc.driver,
+				Nodes:  []config.Node{{ControlPlane: true, Worker: true}},
+				KubernetesConfig: config.KubernetesConfig{
+					KubernetesVersion: "v1.20.2",
+					ContainerRuntime:  tc.runtime,
+				},
+			}
+			runner := command.NewFakeCommandRunner()
+			runner.SetCommandToOutput(map[string]string{
+				"stat /opt/cni/bin/portmap": "",
+				kubectl:                     "",
+			})
+			if err := applyCNI(runner, cc); err != nil {
+				t.Fatalf("applyCNI() error = %v", err)
+			}
+			m, _ := runner.GetFileToContents(assets.MemorySource)
+			if applied := strings.Contains(m, "kindnet"); applied != tc.kindnet {
+				t.Errorf("applied kindnet = %v, want %v", applied, tc.kindnet)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/pkg/errors"
+	nodev1beta1 "k8s.io/api/node/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/kubernetes"
+
+	"k8s.io/minikube/pkg/addons"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+func init() {
+	// the gvisor addon installs gVisor as the runsc runtime handler
+	addons.EnableRuntimeHandler = EnableRuntimeHandler
+	addons.DisableRuntimeHandler = DisableRuntimeHandler
+}
+
+// EnableRuntimeHandler installs a runtime handler on the nodes of a cluster, registers it with their
+// container runtime, and creates its RuntimeClass. The handler is installed again by the next starts.
+func EnableRuntimeHandler(api libmachine.API, cc *config.ClusterConfig, name string) error {
+	h, err := cruntime.LookupRuntimeHandler(name)
+	if err != nil {
+		return err
+	}
+	if !cruntime.SupportsRuntimeHandlers(cc.KubernetesConfig.ContainerRuntime) {
+		return errors.Errorf("the %s container runtime doesn't support runtime handlers", cc.KubernetesConfig.ContainerRuntime)
+	}
+
+	enabled := false
+	for _, n := range cc.KubernetesConfig.RuntimeHandlers {
+		enabled = enabled || n == name
+	}
+	if !enabled {
+		cc.KubernetesConfig.RuntimeHandlers = append(cc.KubernetesConfig.RuntimeHandlers, name)
+	}
+
+	for _, n := range cc.Nodes {
+		mname := config.MachineName(*cc, n)
+		out.Step(style.Option, "Installing {{.handler}} on {{.name}} ...", out.V{"handler": h.Name, "name": mname})
+		if err := registerRuntimeHandlers(api, cc, n, name); err != nil {
+			return errors.Wrapf(err, "installing on %s", mname)
+		}
+	}
+
+	client, err := kapi.Client(cc.Name)
+	if err != nil {
+		return errors.Wrap(err, "kubernetes client")
+	}
+	return ensureRuntimeClass(client, h)
+}
+
+// DisableRuntimeHandler unregisters a runtime handler from the container runtime of the nodes of a cluster,
+// and deletes its RuntimeClass. Its binaries are left on the nodes.
+func DisableRuntimeHandler(api libmachine.API, cc *config.ClusterConfig, name string) error {
+	h, err := cruntime.LookupRuntimeHandler(name)
+	if err != nil {
+		return err
+	}
+	var hs []string
+	for _, n := range cc.KubernetesConfig.RuntimeHandlers {
+		if n != name {
+			hs = append(hs, n)
+		}
+	}
+	if len(hs) == len(cc.KubernetesConfig.RuntimeHandlers) {
+		return nil
+	}
+	cc.KubernetesConfig.RuntimeHandlers = hs
+
+	for _, n := range cc.Nodes {
+		mname := config.MachineName(*cc, n)
+		out.Step(style.Option, "Removing {{.handler}} from {{.name}} ...", out.V{"handler": h.Name, "name": mname})
+		if err := registerRuntimeHandlers(api, cc, n, ""); err != nil {
+			return errors.Wrapf(err, "removing from %s", mname)
+		}
+	}
+
+	client, err := kapi.Client(cc.Name)
+	if err != nil {
+		return errors.Wrap(err, "kubernetes client")
+	}
+	if err := client.NodeV1beta1().RuntimeClasses().Delete(h.ClassName(), nil); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "delete RuntimeClass %s", h.ClassName())
+	}
+	return nil
+}
+
+// registerRuntimeHandlers installs the runtime handlers of a cluster on a node, and registers the installed ones
+// with its container runtime. required is a handler which has to be installed, if any.
+func registerRuntimeHandlers(api libmachine.API, cc *config.ClusterConfig, n config.Node, required string) error {
+	mh, err := machine.LoadHost(api, config.MachineName(*cc, n))
+	if err != nil {
+		return errors.Wrap(err, "load host")
+	}
+	runner, err := machine.CommandRunner(mh)
+	if err != nil {
+		return errors.Wrap(err, "command runner")
+	}
+	hs, err := installRuntimeHandlers(runner, *cc)
+	if err != nil {
+		if required != "" && !containsRuntimeHandler(hs, required) {
+			return err
+		}
+		out.WarningT("Unable to install the runtime handlers, the pods selecting them won't start: {{.error}}", out.V{"error": err})
+	}
+	cr, err := newRuntime(*cc, mh, runner, hs)
+	if err != nil {
+		return errors.Wrap(err, "runtime")
+	}
+	// restarts the runtime with the handlers registered, the running containers are left alone
+	if err := cr.Enable(false, forceSystemd() || cruntime.ContainerdSystemdCgroup(runner)); err != nil {
+		return errors.Wrapf(err, "registering with %s", cr.Name())
+	}
+	return waitForCRISocket(runner, cr.SocketPath(), 60, 1)
+}
+
+// runtimeHandlers returns the runtime handlers of a cluster
+func runtimeHandlers(cc config.ClusterConfig) ([]cruntime.RuntimeHandler, error) {
+	if !cruntime.SupportsRuntimeHandlers(cc.KubernetesConfig.ContainerRuntime) {
+		return nil, nil
+	}
+	return cruntime.RuntimeHandlersOf(cc.KubernetesConfig.RuntimeHandlers)
+}
+
+// installRuntimeHandlers copies the binaries of the runtime handlers of a cluster into the node, downloading them
+// onto the host first, and returns the handlers whose binaries were all installed
+func installRuntimeHandlers(runner command.Runner, cc config.ClusterConfig) ([]cruntime.RuntimeHandler, error) {
+	hs, err := runtimeHandlers(cc)
+	if err != nil || len(hs) == 0 {
+		return nil, err
+	}
+	arch, err := nodeArch(runner)
+	if err != nil {
+		return nil, errors.Wrap(err, "node architecture")
+	}
+
+	var installed []cruntime.RuntimeHandler
+	var errs []error
+	for _, h := range hs {
+		if err := installRuntimeHandler(runner, h, arch); err != nil {
+			errs = append(errs, errors.Wrap(err, h.Name))
+			continue
+		}
+		installed = append(installed, h)
+	}
+	return installed, utilerrors.NewAggregate(errs)
+}
+
+// installRuntimeHandler copies the binaries of a runtime handler for an architecture into the node
+func installRuntimeHandler(runner command.Runner, h cruntime.RuntimeHandler, arch string) error {
+	for _, b := range h.Binaries {
+		// binaries cached from a local build have no download location
+		loc, checksum, lerr := h.Locations(b, arch)
+		src, err := download.RuntimeBinary(h.Name, h.Version, b, loc, checksum)
+		if err != nil {
+			if lerr != nil {
+				err = lerr
+			}
+			return errors.Wrap(err, b)
+		}
+		f, err := assets.NewFileAsset(src, cruntime.RuntimeHandlerBinDir, b, "0755")
+		if err != nil {
+			return errors.Wrap(err, b)
+		}
+		if err := runner.Copy(f); err != nil {
+			return errors.Wrapf(err, "copy %s", b)
+		}
+	}
+	return nil
+}
+
+// nodeArch returns the architecture of a node, as GOARCH names it
+func nodeArch(runner command.Runner) (string, error) {
+	rr, err := runner.RunCmd(exec.Command("uname", "-m"))
+	if err != nil {
+		return "", errors.Wrap(err, "uname")
+	}
+	m := strings.TrimSpace(rr.Stdout.String())
+	switch m {
+	case "x86_64":
+		return "amd64", nil
+	case "aarch64", "arm64":
+		return "arm64", nil
+	case "ppc64le", "s390x":
+		return m, nil
+	}
+	return "", fmt.Errorf("unsupported architecture %q", m)
+}
+
+// containsRuntimeHandler returns whether hs holds the runtime handler of a name
+func containsRuntimeHandler(hs []cruntime.RuntimeHandler, name string) bool {
+	for _, h := range hs {
+		if h.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ensureRuntimeClass creates the RuntimeClass selecting a runtime handler, unless it exists
+func ensureRuntimeClass(client kubernetes.Interface, h cruntime.RuntimeHandler) error {
+	rcs := client.NodeV1beta1().RuntimeClasses()
+	if _, err := rcs.Get(h.ClassName(), metav1.GetOptions{}); err == nil {
+		return nil
+	} else if !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "get RuntimeClass %s", h.ClassName())
+	}
+	rc := &nodev1beta1.RuntimeClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   h.ClassName(),
+			Labels: map[string]string{"app.kubernetes.io/managed-by": "minikube"},
+		},
+		Handler: h.Name,
+	}
+	if _, err := rcs.Create(rc); err != nil {
+		return errors.Wrapf(err, "create RuntimeClass %s", h.ClassName())
+	}
+	return nil
+}
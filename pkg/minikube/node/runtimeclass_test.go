@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	nodev1beta1 "k8s.io/api/node/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+func TestEnsureRuntimeClass(t *testing.T) {
+	// a RuntimeClass of the same name created by hand is left alone
+	existing := &nodev1beta1.RuntimeClass{ObjectMeta: metav1.ObjectMeta{Name: "crun"}, Handler: "crun-debug"}
+	client := fake.NewSimpleClientset(existing)
+
+	for _, name := range []string{"runsc", "crun", "runsc"} {
+		h, err := cruntime.LookupRuntimeHandler(name)
+		if err != nil {
+			t.Fatalf("LookupRuntimeHandler: %v", err)
+		}
+		if err := ensureRuntimeClass(client, h); err != nil {
+			t.Fatalf("ensureRuntimeClass(%s): %v", name, err)
+		}
+	}
+
+	for name, handler := range map[string]string{"gvisor": "runsc", "crun": "crun-debug"} {
+		rc, err := client.NodeV1beta1().RuntimeClasses().Get(name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("get RuntimeClass %s: %v", name, err)
+		}
+		if rc.Handler != handler {
+			t.Errorf("RuntimeClass %s handler = %q, want %q", name, rc.Handler, handler)
+		}
+	}
+}
+
+func TestRuntimeHandlers(t *testing.T) {
+	cc := config.ClusterConfig{KubernetesConfig: config.KubernetesConfig{ContainerRuntime: "docker", RuntimeHandlers: []string{"runsc"}}}
+	hs, err := runtimeHandlers(cc)
+	if err != nil || len(hs) != 0 {
+		t.Errorf("runtimeHandlers() with docker = %v, %v, want none", hs, err)
+	}
+
+	cc.KubernetesConfig.ContainerRuntime = "crio"
+	hs, err = runtimeHandlers(cc)
+	if err != nil || len(hs) != 1 || hs[0].Name != "runsc" {
+		t.Errorf("runtimeHandlers() with crio = %v, %v, want runsc", hs, err)
+	}
+
+	cc.KubernetesConfig.RuntimeHandlers = []string{"kata"}
+	if _, err := runtimeHandlers(cc); err == nil {
+		t.Errorf("runtimeHandlers() with an unknown handler succeeded")
+	}
+}
+
+func TestInstallRuntimeHandlers(t *testing.T) {
+	home, err := ioutil.TempDir("", "minikube")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(home)
+	defer os.Setenv(localpath.MinikubeHome, os.Getenv(localpath.MinikubeHome))
+	os.Setenv(localpath.MinikubeHome, home)
+
+	// crun is cached from a local build, runsc would have to be downloaded for the architecture of the node
+	bin := filepath.Join(home, "crun")
+	if err := ioutil.WriteFile(bin, []byte("#!/bin/sh"), 0755); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	crun := cruntime.RuntimeHandlers["crun"]
+	if _, err := download.CacheRuntimeBinary(crun.Name, crun.Version, crun.Binaries[0], bin); err != nil {
+		t.Fatalf("CacheRuntimeBinary: %v", err)
+	}
+
+	runner := command.NewFakeCommandRunner()
+	runner.SetCommandToOutput(map[string]string{"uname -m": "s390x\n"})
+	cc := config.ClusterConfig{KubernetesConfig: config.KubernetesConfig{ContainerRuntime: "containerd", RuntimeHandlers: []string{"runsc", "crun"}}}
+	hs, err := installRuntimeHandlers(runner, cc)
+	if err == nil || !strings.Contains(err.Error(), "s390x") {
+		t.Errorf("installRuntimeHandlers() error = %v, want runsc to be missing for s390x", err)
+	}
+	if len(hs) != 1 || hs[0].Name != "crun" {
+		t.Errorf("installRuntimeHandlers() = %v, want only crun", hs)
+	}
+}
+
+func TestNodeArch(t *testing.T) {
+	for uname, want := range map[string]string{"x86_64": "amd64", "aarch64": "arm64", "s390x": "s390x", "mips": ""} {
+		runner := command.NewFakeCommandRunner()
+		runner.SetCommandToOutput(map[string]string{"uname -m": uname + "\n"})
+		got, err := nodeArch(runner)
+		if (err != nil) != (want == "") || got != want {
+			t.Errorf("nodeArch(%s) = %q, %v, want %q", uname, got, err, want)
+		}
+	}
+}
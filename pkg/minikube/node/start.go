@@ -38,6 +38,7 @@ import (
 	"k8s.io/minikube/pkg/drivers/kic/oci"
 	"k8s.io/minikube/pkg/kapi"
 	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
 	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/cni"
@@ -56,6 +57,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/out/register"
 	"k8s.io/minikube/pkg/minikube/proxy"
 	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/registrycache"
 	"k8s.io/minikube/pkg/minikube/style"
 	"k8s.io/minikube/pkg/util"
 	"k8s.io/minikube/pkg/util/retry"
@@ -89,28 +91,51 @@ func Start(starter Starter, apiServer bool) (*kubeconfig.Settings, error) {
 		return nil, errors.Wrap(err, "Failed to parse Kubernetes version")
 	}
 
+	hostIP, err := cluster.HostIP(starter.Host, starter.Cfg.Name)
+	if err != nil {
+		klog.Errorf("Unable to get host IP: %v", err)
+		hostIP = nil
+	}
+
 	// configure the runtime (docker, containerd, crio)
-	cr := configureRuntimes(starter.Runner, *starter.Cfg, sv)
+	cr := configureRuntimes(starter.Runner, *starter.Cfg, sv, hostIP)
 	showVersionInfo(starter.Node.KubernetesVersion, cr)
 
 	// Add "host.minikube.internal" DNS alias (intentionally non-fatal)
-	hostIP, err := cluster.HostIP(starter.Host, starter.Cfg.Name)
-	if err != nil {
-		klog.Errorf("Unable to get host IP: %v", err)
-	} else if err := machine.AddHostAlias(starter.Runner, constants.HostAlias, hostIP); err != nil {
-		klog.Errorf("Unable to add host alias: %v", err)
+	if hostIP != nil {
+		if err := machine.AddHostAlias(starter.Runner, constants.HostAlias, hostIP); err != nil {
+			klog.Errorf("Unable to add host alias: %v", err)
+		}
 	}
 
 	var bs bootstrapper.Bootstrapper
 	var kcs *kubeconfig.Settings
 	if apiServer {
+		if starter.Cfg.HA && starter.Cfg.KubernetesConfig.APIServerHAVIP == "" {
+			vip, err := bsutil.VirtualIP(starter.Node.IP, starter.Cfg.Subnet, takenIPs(starter.Cfg.Name))
+			if err != nil {
+				return nil, errors.Wrap(err, "virtual IP")
+			}
+			starter.Cfg.KubernetesConfig.APIServerHAVIP = vip
+			if err := config.SaveProfile(starter.Cfg.Name, starter.Cfg); err != nil {
+				return nil, errors.Wrap(err, "saving virtual IP")
+			}
+		}
+		if starter.Cfg.HA {
+			out.Step(style.Connectivity, "Using virtual IP {{.ip}} for the control plane nodes", out.V{"ip": starter.Cfg.KubernetesConfig.APIServerHAVIP})
+			if starter.PreExists {
+				// etcd has no quorum until enough control plane nodes are up
+				startControlPlaneHosts(starter.MachineAPI, starter.Cfg)
+			}
+		}
+
 		// Must be written before bootstrap, otherwise health checks may flake due to stale IP
-		kcs = setupKubeconfig(starter.Host, starter.Cfg, starter.Node, starter.Cfg.Name)
+		kcs = SetupKubeconfig(starter.Host, starter.Cfg, starter.Node, starter.Cfg.Name)
 		if err != nil {
 			return nil, errors.Wrap(err, "Failed to setup kubeconfig")
 		}
 
-		// setup kubeadm (must come after setupKubeconfig)
+		// setup kubeadm (must come after SetupKubeconfig)
 		bs = setupKubeAdm(starter.MachineAPI, *starter.Cfg, *starter.Node, starter.Runner)
 		err = bs.StartCluster(*starter.Cfg)
 		if err != nil {
@@ -180,7 +205,7 @@ func Start(starter Starter, apiServer bool) (*kubeconfig.Settings, error) {
 			return nil, errors.Wrap(err, "getting control plane bootstrapper")
 		}
 
-		joinCmd, err := cpBs.GenerateToken(*starter.Cfg)
+		joinCmd, err := cpBs.GenerateToken(*starter.Cfg, *starter.Node)
 		if err != nil {
 			return nil, errors.Wrap(err, "generating join token")
 		}
@@ -204,6 +229,13 @@ func Start(starter Starter, apiServer bool) (*kubeconfig.Settings, error) {
 		return nil, errors.Wrapf(err, "wait %s for node", viper.GetDuration(waitTimeout))
 	}
 
+	if starter.PreExists {
+		// switching the runtime of a stopped cluster leaves the switch to this start, which kubeadm doesn't record
+		if err := waitForRuntime(*starter.Cfg, *starter.Node, cr); err != nil {
+			out.WarningT("Unable to update the container runtime socket of {{.name}}, kubeadm may not reach its runtime: {{.error}}", out.V{"name": config.MachineName(*starter.Cfg, *starter.Node), "error": err})
+		}
+	}
+
 	klog.Infof("waiting for startup goroutines ...")
 	wg.Wait()
 
@@ -211,6 +243,17 @@ func Start(starter Starter, apiServer bool) (*kubeconfig.Settings, error) {
 	return kcs, config.Write(viper.GetString(config.ProfileName), starter.Cfg)
 }
 
+// startControlPlaneHosts starts the hosts of the other control plane nodes of an HA cluster,
+// their kubelet brings the etcd members the primary control plane needs back
+func startControlPlaneHosts(api libmachine.API, cc *config.ClusterConfig) {
+	for _, n := range config.ControlPlanes(*cc)[1:] {
+		n := n
+		if _, _, err := machine.StartHost(api, cc, &n); err != nil {
+			out.WarningT("Unable to start control plane node {{.name}}: {{.error}}", out.V{"name": config.MachineName(*cc, n), "error": err})
+		}
+	}
+}
+
 // Provision provisions the machine/container for the node
 func Provision(cc *config.ClusterConfig, n *config.Node, apiServer bool, delOnFail bool) (command.Runner, bool, libmachine.API, *host.Host, error) {
 	register.Reg.SetStep(register.StartingNode)
@@ -241,23 +284,55 @@ func Provision(cc *config.ClusterConfig, n *config.Node, apiServer bool, delOnFa
 	return startMachine(cc, n, delOnFail)
 }
 
-// ConfigureRuntimes does what needs to happen to get a runtime going.
-func configureRuntimes(runner cruntime.CommandRunner, cc config.ClusterConfig, kv semver.Version) cruntime.Manager {
+// runtimeConfig returns the configuration of the runtime of a cluster on a node, pulling through the registry cache
+// when it is enabled. hostIP is the address of the host as seen from the node, nil if unknown.
+func runtimeConfig(cc config.ClusterConfig, runner cruntime.CommandRunner, kv semver.Version, hostIP net.IP) (cruntime.Config, error) {
 	co := cruntime.Config{
 		Type:              cc.KubernetesConfig.ContainerRuntime,
 		Runner:            runner,
 		ImageRepository:   cc.KubernetesConfig.ImageRepository,
 		KubernetesVersion: kv,
-		InsecureRegistry:  cc.InsecureRegistry,
 	}
-	cr, err := cruntime.New(co)
+	if driver.BareMetal(cc.Driver) {
+		// the registries of the runtime of the host are left alone
+		return co, nil
+	}
+	regs, err := cruntime.Registries(cc)
 	if err != nil {
-		exit.Error(reason.InternalRuntime, "Failed runtime", err)
+		return co, err
+	}
+	co.Registries = regs
+	if registrycache.Enabled() && hostIP != nil {
+		if err := registrycache.Start(hostIP); err != nil {
+			out.WarningT("Unable to start the registry cache, pulling from Docker Hub: {{.error}}", out.V{"error": err})
+		} else {
+			co.Registries = registrycache.Mirror(regs, hostIP)
+		}
 	}
+	return co, nil
+}
 
-	disableOthers := true
-	if driver.BareMetal(cc.Driver) {
-		disableOthers = false
+// ConfigureRuntimes does what needs to happen to get a runtime going.
+func configureRuntimes(runner cruntime.CommandRunner, cc config.ClusterConfig, kv semver.Version, hostIP net.IP) cruntime.Manager {
+	co, err := runtimeConfig(cc, runner, kv, hostIP)
+	if err != nil {
+		exit.Error(reason.RuntimeEnable, "Failed to configure registries", err)
+	}
+	disableOthers := !driver.BareMetal(cc.Driver)
+
+	if _, err := runtimeHandlers(cc); err != nil {
+		exit.Error(reason.RuntimeEnable, "Failed to configure runtime handlers", err)
+	}
+	// only the installed handlers are registered, so that the runtime can start without the others
+	hs, err := installRuntimeHandlers(runner, cc)
+	if err != nil {
+		out.WarningT("Unable to install the runtime handlers, the pods selecting them won't start: {{.error}}", out.V{"error": err})
+	}
+	co.RuntimeHandlers = hs
+
+	cr, err := cruntime.New(co)
+	if err != nil {
+		exit.Error(reason.InternalRuntime, "Failed runtime", err)
 	}
 
 	// Preload is overly invasive for bare metal, and caching is not meaningful.
@@ -354,7 +429,8 @@ func setupKubeAdm(mAPI libmachine.API, cfg config.ClusterConfig, n config.Node,
 	return bs
 }
 
-func setupKubeconfig(h *host.Host, cc *config.ClusterConfig, n *config.Node, clusterName string) *kubeconfig.Settings {
+// SetupKubeconfig returns the kubeconfig settings for a cluster control plane
+func SetupKubeconfig(h *host.Host, cc *config.ClusterConfig, n *config.Node, clusterName string) *kubeconfig.Settings {
 	addr, err := apiServerURL(*h, *cc, *n)
 	if err != nil {
 		exit.Message(reason.DrvCPEndpoint, fmt.Sprintf("failed to get API Server URL: %v", err), out.V{"profileArg": fmt.Sprintf("--profile=%s", clusterName)})
@@ -374,7 +450,7 @@ func setupKubeconfig(h *host.Host, cc *config.ClusterConfig, n *config.Node, clu
 		EmbedCerts:           cc.EmbedCerts,
 	}
 
-	kcs.SetPath(kubeconfig.PathFromEnv())
+	kcs.SetPath(kubeconfig.PathFromConfig(cc))
 	return kcs
 }
 
@@ -539,7 +615,22 @@ func trySSH(h *host.Host, ip string) error {
 
 // tryRegistry tries to connect to the image repository
 func tryRegistry(r command.Runner, driverName string, imageRepository string) {
-	// 2 second timeout. For best results, call tryRegistry in a non-blocking manner.
+	imageRepository = images.KubernetesRepo(imageRepository)
+	if err := CheckRegistryAccess(r, imageRepository); err != nil {
+		out.WarningT("This {{.type}} is having trouble accessing https://{{.repository}}", out.V{"repository": imageRepository, "type": driver.MachineType(driverName)})
+		out.ErrT(style.Tip, "To pull new external images, you may need to configure a proxy: https://minikube.sigs.k8s.io/docs/reference/networking/proxy/")
+	}
+}
+
+// ErrRegistryDNS is returned by CheckRegistryAccess when the registry host name cannot be resolved within the machine
+var ErrRegistryDNS = errors.New("unable to resolve registry host name")
+
+// curlCouldntResolveHost is the exit code curl returns when DNS resolution fails
+const curlCouldntResolveHost = 6
+
+// CheckRegistryAccess checks that the image repository can be reached from within the machine
+func CheckRegistryAccess(r command.Runner, imageRepository string) error {
+	// 2 second timeout. For best results, call CheckRegistryAccess in a non-blocking manner.
 	opts := []string{"-sS", "-m", "2"}
 
 	proxy := os.Getenv("HTTPS_PROXY")
@@ -547,16 +638,17 @@ func tryRegistry(r command.Runner, driverName string, imageRepository string) {
 		opts = append([]string{"-x", proxy}, opts...)
 	}
 
-	if imageRepository == "" {
-		imageRepository = images.DefaultKubernetesRepo
-	}
-
+	imageRepository = images.KubernetesRepo(imageRepository)
 	opts = append(opts, fmt.Sprintf("https://%s/", imageRepository))
-	if rr, err := r.RunCmd(exec.Command("curl", opts...)); err != nil {
+	rr, err := r.RunCmd(exec.Command("curl", opts...))
+	if err != nil {
 		klog.Warningf("%s failed: %v", rr.Args, err)
-		out.WarningT("This {{.type}} is having trouble accessing https://{{.repository}}", out.V{"repository": imageRepository, "type": driver.MachineType(driverName)})
-		out.ErrT(style.Tip, "To pull new external images, you may need to configure a proxy: https://minikube.sigs.k8s.io/docs/reference/networking/proxy/")
+		if rr.ExitCode == curlCouldntResolveHost {
+			return errors.Wrap(ErrRegistryDNS, imageRepository)
+		}
+		return errors.Wrapf(err, "accessing https://%s/", imageRepository)
 	}
+	return nil
 }
 
 // prepareNone prepares the user and host for the joy of the "none" driver
@@ -600,3 +692,24 @@ func rescaleCoreDNS(cc *config.ClusterConfig, runner command.Runner) {
 		klog.Infof("successfully scaled coredns replicas to 1")
 	}
 }
+
+// takenIPs returns the virtual IPs and node IPs of the other clusters, which may share the network of a cluster
+func takenIPs(name string) []string {
+	var ips []string
+	ps, _, err := config.ListProfiles()
+	if err != nil {
+		klog.Warningf("unable to list profiles: %v", err)
+	}
+	for _, p := range ps {
+		if p.Name == name || p.Config == nil {
+			continue
+		}
+		if vip := p.Config.KubernetesConfig.APIServerHAVIP; vip != "" {
+			ips = append(ips, vip)
+		}
+		for _, n := range p.Config.Nodes {
+			ips = append(ips, n.IP)
+		}
+	}
+	return ips
+}
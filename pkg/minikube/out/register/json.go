@@ -69,3 +69,8 @@ func PrintWarning(warning string) {
 	w := NewWarning(warning)
 	printAndRecordCloudEvent(w, w.data)
 }
+
+// MarshalStatusChange returns a StatusChange as a JSON encoded cloud event
+func MarshalStatusChange(s *StatusChange) ([]byte, error) {
+	return CloudEvent(s, s.data).MarshalJSON()
+}
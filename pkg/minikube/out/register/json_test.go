@@ -124,3 +124,21 @@ func TestWarning(t *testing.T) {
 		t.Fatalf("expected didn't match actual:\nExpected:\n%v\n\nActual:\n%v", expected, actual)
 	}
 }
+
+func TestMarshalStatusChange(t *testing.T) {
+	expected := `{"data":{"cluster":"minikube","component":"apiserver","current":"Paused","node":"minikube","previous":"Running","sequence":"7"},"datacontenttype":"application/json","id":"random-id","source":"https://minikube.sigs.k8s.io/","specversion":"1.0","type":"io.k8s.sigs.minikube.status"}`
+
+	GetUUID = func() string {
+		return "random-id"
+	}
+
+	bs, err := MarshalStatusChange(NewStatusChange(7, "minikube", "minikube", "apiserver", "Running", "Paused"))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	actual := string(bs)
+
+	if actual != expected {
+		t.Fatalf("expected didn't match actual:\nExpected:\n%v\n\nActual:\n%v", expected, actual)
+	}
+}
@@ -154,3 +154,25 @@ func NewErrorExitCode(err string, exitcode int, additionalData ...map[string]str
 func (s *Error) Type() string {
 	return "io.k8s.sigs.minikube.error"
 }
+
+// StatusChange will be used to notify clients that the state of a cluster component has changed
+type StatusChange struct {
+	data map[string]string
+}
+
+// Type returns the cloud events compatible type of this struct
+func (s *StatusChange) Type() string {
+	return "io.k8s.sigs.minikube.status"
+}
+
+// NewStatusChange returns a new StatusChange type. node is empty for cluster-wide components.
+func NewStatusChange(sequence int, cluster, node, component, previous, current string) *StatusChange {
+	return &StatusChange{data: map[string]string{
+		"sequence":  fmt.Sprintf("%d", sequence),
+		"cluster":   cluster,
+		"node":      node,
+		"component": component,
+		"previous":  previous,
+		"current":   current,
+	}}
+}
@@ -51,6 +51,9 @@ const (
 	Deleting  RegStep = "Deleting"
 	Pausing   RegStep = "Pausing"
 	Unpausing RegStep = "Unpausing"
+
+	Draining    RegStep = "Draining"
+	Uncordoning RegStep = "Uncordoning"
 )
 
 // RegStep is a type representing a distinct step of `minikube start`
@@ -97,6 +100,10 @@ func init() {
 			Pausing:   {Pausing, Done},
 			Unpausing: {Unpausing, Done},
 			Deleting:  {Deleting, Stopping, Deleting, Done},
+
+			// worker nodes are drained before they are stopped or deleted
+			Draining:    {Draining, Stopping, PowerOff, Deleting, Done},
+			Uncordoning: {Uncordoning, Done},
 		},
 	}
 }
@@ -0,0 +1,151 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preload
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/util"
+)
+
+// ThrowawayNode is a docker or podman container created only to generate a preloaded tarball
+type ThrowawayNode struct {
+	Driver *kic.Driver
+	Runner command.Runner
+}
+
+// NewThrowawayNode creates a node named name with the container runtime of cfg enabled
+func NewThrowawayNode(name string, ociBinary string, cfg Config) (*ThrowawayNode, error) {
+	d := kic.NewDriver(kic.Config{
+		ClusterName:       name,
+		KubernetesVersion: cfg.KubernetesVersion,
+		ContainerRuntime:  cfg.ContainerRuntime,
+		OCIBinary:         ociBinary,
+		MachineName:       name,
+		ImageDigest:       kic.BaseImage,
+		StorePath:         localpath.MiniPath(),
+		CPU:               2,
+		Memory:            4000,
+		APIServerPort:     8080,
+	})
+
+	baseDir := filepath.Dir(d.GetSSHKeyPath())
+	if err := os.MkdirAll(baseDir, 0755); err != nil {
+		return nil, errors.Wrap(err, "mkdir")
+	}
+	if err := d.Create(); err != nil {
+		return nil, errors.Wrap(err, "creating kic driver")
+	}
+	n := &ThrowawayNode{Driver: d, Runner: command.NewKICRunner(name, ociBinary)}
+
+	// will need to do this to enable the container run-time service
+	sv, err := util.ParseKubernetesVersion(cfg.KubernetesVersion)
+	if err != nil {
+		return n, errors.Wrap(err, "Failed to parse Kubernetes version")
+	}
+	cr, err := cruntime.New(cruntime.Config{
+		Type:              cfg.ContainerRuntime,
+		Runner:            n.Runner,
+		ImageRepository:   cfg.ImageRepository,
+		KubernetesVersion: sv, // this is just to satisfy cruntime and shouldnt matter what version.
+	})
+	if err != nil {
+		return n, errors.Wrap(err, "failed create new runtime")
+	}
+	if err := cr.Enable(true, false); err != nil {
+		return n, errors.Wrap(err, "enable container runtime")
+	}
+	return n, nil
+}
+
+// Delete removes the container of the node, and its machine directory
+func (n *ThrowawayNode) Delete() error {
+	if err := n.Driver.Remove(); err != nil {
+		return errors.Wrap(err, "removing kic driver")
+	}
+	return os.RemoveAll(filepath.Dir(n.Driver.GetSSHKeyPath()))
+}
+
+// CopyToHost copies the file at src within the node of the driver to dst on the host
+func CopyToHost(d drivers.Driver, src, dst string) error {
+	name := d.DriverName()
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return errors.Wrap(err, "mkdir")
+	}
+
+	if driver.BareMetal(name) {
+		return copyLocal(src, dst)
+	}
+
+	var cmd *exec.Cmd
+	if driver.IsKIC(name) {
+		cmd = oci.PrefixCmd(exec.Command(name, "cp", fmt.Sprintf("%s:%s", d.GetMachineName(), src), dst))
+	} else {
+		host, err := d.GetSSHHostname()
+		if err != nil {
+			return errors.Wrap(err, "ssh hostname")
+		}
+		port, err := d.GetSSHPort()
+		if err != nil {
+			return errors.Wrap(err, "ssh port")
+		}
+		cmd = exec.Command("scp",
+			"-o", "StrictHostKeyChecking=no",
+			"-o", "UserKnownHostsFile=/dev/null",
+			"-o", "IdentitiesOnly=yes",
+			"-i", d.GetSSHKeyPath(),
+			"-P", fmt.Sprint(port),
+			fmt.Sprintf("%s@%s:%s", d.GetSSHUsername(), host, src), dst)
+	}
+	klog.Infof("copying %s to the host: %v", src, cmd.Args)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "%v: %s", cmd.Args, output)
+	}
+	return nil
+}
+
+// copyLocal copies a file of a bare metal node, which is on the host already
+func copyLocal(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	w, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, in); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
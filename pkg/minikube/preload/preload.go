@@ -0,0 +1,212 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package preload generates preloaded images tarballs within a node
+package preload
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/sysinit"
+	"k8s.io/minikube/pkg/util/retry"
+)
+
+const (
+	// DockerStorageDriver is the docker storage driver that preloaded tarballs are built for
+	DockerStorageDriver = "overlay2"
+	// PodmanStorageDriver is the cri-o storage driver that preloaded tarballs are built for
+	PodmanStorageDriver = "overlay"
+)
+
+// Config describes the contents of a preloaded images tarball
+type Config struct {
+	KubernetesVersion string
+	ContainerRuntime  string
+	ImageRepository   string
+	// ExtraImages are pulled in addition to the images required by kubeadm
+	ExtraImages []string
+}
+
+// isCRIO returns whether the runtime is cri-o, which has two spellings
+func isCRIO(containerRuntime string) bool {
+	return containerRuntime == "cri-o" || containerRuntime == "crio"
+}
+
+// Images returns the images to include in a preloaded tarball
+func Images(cfg Config) ([]string, error) {
+	imgs, err := images.Kubeadm(cfg.ImageRepository, cfg.KubernetesVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "kubeadm images")
+	}
+	if cfg.ContainerRuntime != "docker" { // kic overlay image is only needed by containerd and cri-o https://github.com/kubernetes/minikube/issues/7428
+		imgs = append(imgs, images.KindNet(cfg.ImageRepository))
+	}
+	return append(imgs, cfg.ExtraImages...), nil
+}
+
+// UnexpectedImages returns the images of a runtime store which a preload of cfg doesn't include, and which
+// archiving the store would add to the preload anyway
+func UnexpectedImages(stored []string, cfg Config) ([]string, error) {
+	imgs, err := Images(cfg)
+	if err != nil {
+		return nil, err
+	}
+	want := map[string]bool{}
+	for _, img := range imgs {
+		want[normalizedImage(img)] = true
+	}
+	var unexpected []string
+	for _, img := range stored {
+		if !want[normalizedImage(img)] {
+			unexpected = append(unexpected, img)
+		}
+	}
+	sort.Strings(unexpected)
+	return unexpected, nil
+}
+
+// normalizedImage returns the fully qualified name of an image, which the CRI runtimes list with the docker.io prefix
+func normalizedImage(img string) string {
+	ref, err := name.ParseReference(img, name.WeakValidation)
+	if err != nil {
+		return img
+	}
+	return ref.Name()
+}
+
+// Generate pulls the images and transfers the binaries of cfg into the node, then archives them into a tarball at path within the node
+func Generate(runner command.Runner, cfg Config, path string) error {
+	if err := VerifyStorage(runner, cfg.ContainerRuntime); err != nil {
+		return errors.Wrap(err, "verifying storage")
+	}
+
+	imgs, err := Images(cfg)
+	if err != nil {
+		return err
+	}
+	for _, img := range imgs {
+		pull := func() error {
+			if _, err := runner.RunCmd(imagePullCommand(cfg.ContainerRuntime, img)); err != nil {
+				return errors.Wrapf(err, "pulling image %s", img)
+			}
+			return nil
+		}
+		// retry up to 5 times if network is bad
+		if err := retry.Expo(pull, time.Microsecond, time.Minute, 5); err != nil {
+			return errors.Wrapf(err, "pull image %s", img)
+		}
+	}
+
+	kcfg := config.KubernetesConfig{
+		KubernetesVersion: cfg.KubernetesVersion,
+	}
+	if err := bsutil.TransferBinaries(kcfg, runner, sysinit.New(runner)); err != nil {
+		return errors.Wrap(err, "transferring k8s binaries")
+	}
+
+	return createImageTarball(runner, cfg.ContainerRuntime, path)
+}
+
+// VerifyStorage checks that the storage driver of the runtime is the one preloaded tarballs are built for
+func VerifyStorage(runner command.Runner, containerRuntime string) error {
+	if containerRuntime == "docker" {
+		if err := verifyDockerStorage(runner); err != nil {
+			return errors.Wrap(err, "Docker storage type is incompatible")
+		}
+	}
+	if isCRIO(containerRuntime) {
+		if err := verifyPodmanStorage(runner); err != nil {
+			return errors.Wrap(err, "Podman storage type is incompatible")
+		}
+	}
+	return nil
+}
+
+func verifyDockerStorage(runner command.Runner) error {
+	rr, err := runner.RunCmd(exec.Command("docker", "info", "-f", "{{.Info.Driver}}"))
+	if err != nil {
+		return err
+	}
+	driver := strings.Trim(rr.Stdout.String(), " \n")
+	if driver != DockerStorageDriver {
+		return fmt.Errorf("docker storage driver %s does not match requested %s", driver, DockerStorageDriver)
+	}
+	return nil
+}
+
+func verifyPodmanStorage(runner command.Runner) error {
+	rr, err := runner.RunCmd(exec.Command("sudo", "podman", "info", "-f", "json"))
+	if err != nil {
+		return err
+	}
+	var info map[string]map[string]interface{}
+	if err := json.Unmarshal(rr.Stdout.Bytes(), &info); err != nil {
+		return err
+	}
+	driver := info["store"]["graphDriverName"]
+	if driver != PodmanStorageDriver {
+		return fmt.Errorf("podman storage driver %s does not match requested %s", driver, PodmanStorageDriver)
+	}
+	return nil
+}
+
+func imagePullCommand(containerRuntime, img string) *exec.Cmd {
+	if containerRuntime == "docker" {
+		return exec.Command("docker", "pull", img)
+	}
+	return exec.Command("sudo", "crictl", "pull", img)
+}
+
+// tarballDirs returns the directories under /var holding the binaries and the images of the runtime
+func tarballDirs(containerRuntime string) []string {
+	dirs := []string{
+		"./lib/minikube/binaries",
+	}
+	if containerRuntime == "docker" {
+		dirs = append(dirs, fmt.Sprintf("./lib/docker/%s", DockerStorageDriver), "./lib/docker/image")
+	}
+	if containerRuntime == "containerd" {
+		dirs = append(dirs, "./lib/containerd")
+	}
+	if isCRIO(containerRuntime) {
+		dirs = append(dirs, "./lib/containers")
+	}
+	return dirs
+}
+
+func createImageTarball(runner command.Runner, containerRuntime, path string) error {
+	args := []string{"sudo", "tar", "-I", "lz4", "-C", "/var", "-cf", path}
+	args = append(args, tarballDirs(containerRuntime)...)
+	if _, err := runner.RunCmd(exec.Command(args[0], args[1:]...)); err != nil {
+		return errors.Wrap(err, "tarball")
+	}
+	// readable by the ssh user copying it out of the node
+	if _, err := runner.RunCmd(exec.Command("sudo", "chmod", "0644", path)); err != nil {
+		return errors.Wrap(err, "chmod")
+	}
+	return nil
+}
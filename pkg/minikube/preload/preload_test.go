@@ -0,0 +1,106 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preload
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTarballDirs(t *testing.T) {
+	tests := []struct {
+		runtime string
+		want    []string
+	}{
+		{"docker", []string{"./lib/minikube/binaries", "./lib/docker/overlay2", "./lib/docker/image"}},
+		{"containerd", []string{"./lib/minikube/binaries", "./lib/containerd"}},
+		{"cri-o", []string{"./lib/minikube/binaries", "./lib/containers"}},
+		{"crio", []string{"./lib/minikube/binaries", "./lib/containers"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.runtime, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tarballDirs(tc.runtime)); diff != "" {
+				t.Errorf("tarballDirs(%s) mismatch (-want +got):\n%s", tc.runtime, diff)
+			}
+		})
+	}
+}
+
+func TestImages(t *testing.T) {
+	hasKindnet := func(imgs []string) bool {
+		for _, img := range imgs {
+			if strings.Contains(img, "kindnetd") {
+				return true
+			}
+		}
+		return false
+	}
+
+	imgs, err := Images(Config{KubernetesVersion: "v1.20.2", ContainerRuntime: "docker"})
+	if err != nil {
+		t.Fatalf("Images: %v", err)
+	}
+	if hasKindnet(imgs) {
+		t.Errorf("docker preload should not include kindnet: %v", imgs)
+	}
+
+	imgs, err = Images(Config{KubernetesVersion: "v1.20.2", ContainerRuntime: "containerd", ExtraImages: []string{"busybox:1.32"}})
+	if err != nil {
+		t.Fatalf("Images: %v", err)
+	}
+	if !hasKindnet(imgs) {
+		t.Errorf("containerd preload should include kindnet: %v", imgs)
+	}
+	if imgs[len(imgs)-1] != "busybox:1.32" {
+		t.Errorf("extra images should be included last: %v", imgs)
+	}
+
+	if _, err := Images(Config{KubernetesVersion: "latest"}); err == nil {
+		t.Errorf("expected an error for an invalid version")
+	}
+}
+
+func TestUnexpectedImages(t *testing.T) {
+	cfg := Config{KubernetesVersion: "v1.20.2", ContainerRuntime: "containerd", ExtraImages: []string{"busybox:1.32"}}
+	imgs, err := Images(cfg)
+	if err != nil {
+		t.Fatalf("Images: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		stored []string
+		want   []string
+	}{
+		{"preloaded images", imgs, nil},
+		{"docker.io prefix", []string{"docker.io/library/busybox:1.32", "docker.io/kindest/kindnetd:0.5.4"}, nil},
+		{"other images", append([]string{"nginx:latest", "docker.io/library/busybox:1.33"}, imgs...), []string{"docker.io/library/busybox:1.33", "nginx:latest"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := UnexpectedImages(tc.stored, cfg)
+			if err != nil {
+				t.Fatalf("UnexpectedImages: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("UnexpectedImages(%v) mismatch (-want +got):\n%s", tc.stored, diff)
+			}
+		})
+	}
+}
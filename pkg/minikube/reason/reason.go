@@ -189,6 +189,15 @@ var (
 			2. Run "minikube ssh -- docker system prune" if using the Docker container runtime`,
 		Issues: []int{9024},
 	}
+	RsrcInsufficientNerdctlStorage = Kind{
+		ID:       "RSRC_NERDCTL_STORAGE",
+		ExitCode: ExInsufficientStorage,
+		Advice: `Try one or more of the following to free up space on the device:
+
+			1. Run "sudo nerdctl system prune" to remove unused containers, networks and images of containerd (optionally with "-a")
+			2. Run "sudo nerdctl image prune -a" to remove the images no container uses
+			3. Run "minikube ssh -- sudo crictl rmi --prune" to remove the unused images of the nodes`,
+	}
 
 	RsrcInsufficientStorage = Kind{ID: "RSRC_INSUFFICIENT_STORAGE", ExitCode: ExInsufficientStorage, Style: style.UnmetRequirement}
 
@@ -203,8 +212,16 @@ var (
 		Issues:   []int{9165},
 	}
 
+	HostCertExpired = Kind{
+		ID:       "HOST_CERT_EXPIRED",
+		ExitCode: ExHostConfig,
+		Advice:   "Remove the expired certificate and its key, then run 'minikube start' to regenerate them",
+	}
 	HostCurrentUser         = Kind{ID: "HOST_CURRENT_USER", ExitCode: ExHostConfig}
 	HostDelCache            = Kind{ID: "HOST_DEL_CACHE", ExitCode: ExHostError}
+	HostCacheVerify         = Kind{ID: "HOST_CACHE_VERIFY", ExitCode: ExHostError}
+	HostDownloadCheck       = Kind{ID: "HOST_DOWNLOAD_CHECK", ExitCode: ExHostError}
+	HostKicbaseBuild        = Kind{ID: "HOST_KICBASE_BUILD", ExitCode: ExHostError}
 	HostKillMountProc       = Kind{ID: "HOST_KILL_MOUNT_PROC", ExitCode: ExHostError}
 	HostKubeconfigUnset     = Kind{ID: "HOST_KUBECNOFIG_UNSET", ExitCode: ExHostConfig}
 	HostKubeconfigUpdate    = Kind{ID: "HOST_KUBECONFIG_UPDATE", ExitCode: ExHostConfig}
@@ -213,8 +230,11 @@ var (
 	HostMountPid            = Kind{ID: "HOST_MOUNT_PID", ExitCode: ExHostError}
 	HostPathMissing         = Kind{ID: "HOST_PATH_MISSING", ExitCode: ExHostNotFound}
 	HostPathStat            = Kind{ID: "HOST_PATH_STAT", ExitCode: ExHostError}
+	HostPreloadBuild        = Kind{ID: "HOST_PRELOAD_BUILD", ExitCode: ExHostError}
 	HostPurge               = Kind{ID: "HOST_PURGE", ExitCode: ExHostError}
+	HostRegistryCache       = Kind{ID: "HOST_REGISTRY_CACHE", ExitCode: ExHostError}
 	HostSaveProfile         = Kind{ID: "HOST_SAVE_PROFILE", ExitCode: ExHostConfig}
+	HostStatusSocket        = Kind{ID: "HOST_STATUS_SOCKET", ExitCode: ExHostError}
 
 	ProviderNotFound    = Kind{ID: "PROVIDER_NOT_FOUND", ExitCode: ExProviderNotFound}
 	ProviderUnavailable = Kind{ID: "PROVIDER_UNAVAILABLE", ExitCode: ExProviderNotFound, Style: style.Shrug}
@@ -236,6 +256,7 @@ var (
 	DrvNeedsRoot          = Kind{ID: "DRV_NEEDS_ROOT", ExitCode: ExDriverPermission}
 	DrvNeedsAdministrator = Kind{ID: "DRV_NEEDS_ADMINISTRATOR", ExitCode: ExDriverPermission}
 
+	GuestBaseImageMissing = Kind{ID: "GUEST_BASE_IMAGE_MISSING", ExitCode: ExGuestNotFound}
 	GuestCacheLoad        = Kind{ID: "GUEST_CACHE_LOAD", ExitCode: ExGuestError}
 	GuestCert             = Kind{ID: "GUEST_CERT", ExitCode: ExGuestError}
 	GuestCpConfig         = Kind{ID: "GUEST_CP_CONFIG", ExitCode: ExGuestConfig}
@@ -246,18 +267,25 @@ var (
 	GuestMountConflict    = Kind{ID: "GUEST_MOUNT_CONFLICT", ExitCode: ExGuestConflict}
 	GuestNodeAdd          = Kind{ID: "GUEST_NODE_ADD", ExitCode: ExGuestError}
 	GuestNodeDelete       = Kind{ID: "GUEST_NODE_DELETE", ExitCode: ExGuestError}
+	GuestNodeDrain        = Kind{ID: "GUEST_NODE_DRAIN", ExitCode: ExGuestError}
 	GuestNodeProvision    = Kind{ID: "GUEST_NODE_PROVISION", ExitCode: ExGuestError}
 	GuestNodeRetrieve     = Kind{ID: "GUEST_NODE_RETRIEVE", ExitCode: ExGuestNotFound}
 	GuestNodeStart        = Kind{ID: "GUEST_NODE_START", ExitCode: ExGuestError}
 	GuestPause            = Kind{ID: "GUEST_PAUSE", ExitCode: ExGuestError}
 	GuestProfileDeletion  = Kind{ID: "GUEST_PROFILE_DELETION", ExitCode: ExGuestError}
 	GuestProvision        = Kind{ID: "GUEST_PROVISION", ExitCode: ExGuestError}
+	GuestResize           = Kind{ID: "GUEST_RESIZE", ExitCode: ExGuestError}
 	GuestStart            = Kind{ID: "GUEST_START", ExitCode: ExGuestError}
 	GuestStatus           = Kind{ID: "GUEST_STATUS", ExitCode: ExGuestError}
 	GuestStopTimeout      = Kind{ID: "GUEST_STOP_TIMEOUT", ExitCode: ExGuestTimeout}
 	GuestUnpause          = Kind{ID: "GUEST_UNPAUSE", ExitCode: ExGuestError}
 	GuestDrvMismatch      = Kind{ID: "GUEST_DRIVER_MISMATCH", ExitCode: ExGuestConflict, Style: style.Conflict}
 	GuestMissingConntrack = Kind{ID: "GUEST_MISSING_CONNTRACK", ExitCode: ExGuestUnsupported}
+	GuestClockSkew        = Kind{
+		ID:       "GUEST_CLOCK_SKEW",
+		ExitCode: ExGuestConfig,
+		Advice:   "Run 'minikube start' to synchronize the guest clock with the host",
+	}
 
 	IfHostIP    = Kind{ID: "IF_HOST_IP", ExitCode: ExLocalNetworkError}
 	IfMountIP   = Kind{ID: "IF_MOUNT_IP", ExitCode: ExLocalNetworkError}
@@ -276,6 +304,8 @@ var (
 	RuntimeEnable  = Kind{ID: "RUNTIME_ENABLE", ExitCode: ExRuntimeError}
 	RuntimeCache   = Kind{ID: "RUNTIME_CACHE", ExitCode: ExRuntimeError}
 	RuntimeRestart = Kind{ID: "RUNTIME_RESTART", ExitCode: ExRuntimeError}
+	RuntimeSwitch  = Kind{ID: "RUNTIME_SWITCH", ExitCode: ExRuntimeError}
+	RuntimeHandler = Kind{ID: "RUNTIME_HANDLER", ExitCode: ExRuntimeError}
 
 	SvcCheckTimeout = Kind{ID: "SVC_CHECK_TIMEOUT", ExitCode: ExSvcTimeout}
 	SvcTimeout      = Kind{ID: "SVC_TIMEOUT", ExitCode: ExSvcTimeout}
@@ -285,10 +315,12 @@ var (
 	SvcURLTimeout   = Kind{ID: "SVC_URL_TIMEOUT", ExitCode: ExSvcTimeout}
 	SvcNotFound     = Kind{ID: "SVC_NOT_FOUND", ExitCode: ExSvcNotFound}
 
-	EnvDriverConflict    = Kind{ID: "ENV_DRIVER_CONFLICT", ExitCode: ExDriverConflict}
-	EnvMultiConflict     = Kind{ID: "ENV_MULTINODE_CONFLICT", ExitCode: ExGuestConflict}
-	EnvDockerUnavailable = Kind{ID: "ENV_DOCKER_UNAVAILABLE", ExitCode: ExRuntimeUnavailable}
-	EnvPodmanUnavailable = Kind{ID: "ENV_PODMAN_UNAVAILABLE", ExitCode: ExRuntimeUnavailable}
+	EnvDriverConflict        = Kind{ID: "ENV_DRIVER_CONFLICT", ExitCode: ExDriverConflict}
+	EnvMultiConflict         = Kind{ID: "ENV_MULTINODE_CONFLICT", ExitCode: ExGuestConflict}
+	EnvDockerUnavailable     = Kind{ID: "ENV_DOCKER_UNAVAILABLE", ExitCode: ExRuntimeUnavailable}
+	EnvPodmanUnavailable     = Kind{ID: "ENV_PODMAN_UNAVAILABLE", ExitCode: ExRuntimeUnavailable}
+	EnvNerdctlUnavailable    = Kind{ID: "ENV_NERDCTL_UNAVAILABLE", ExitCode: ExRuntimeUnavailable}
+	EnvContainerdUnavailable = Kind{ID: "ENV_CONTAINERD_UNAVAILABLE", ExitCode: ExRuntimeUnavailable}
 
 	AddonUnsupported = Kind{ID: "SVC_ADDON_UNSUPPORTED", ExitCode: ExSvcUnsupported}
 	AddonNotEnabled  = Kind{ID: "SVC_ADDON_NOT_ENABLED", ExitCode: ExProgramConflict}
@@ -81,6 +81,11 @@ func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
 		KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
 		ContainerRuntime:  cc.KubernetesConfig.ContainerRuntime,
 		ExtraArgs:         extraArgs,
+		ExtraDisks:        cc.ExtraDisks,
+		ExtraDiskSize:     cc.ExtraDiskSize,
+		Subnet:            cc.Subnet,
+		StaticIP:          n.StaticIP,
+		ReservedIPs:       config.ReservedIPs(cc, n),
 		Network:           cc.Network,
 	}), nil
 }
@@ -129,6 +134,9 @@ func status() registry.State {
 		return suggestFix("info", -1, serr, fmt.Errorf("docker info error: %s", serr))
 	}
 
+	if s := checkRootless(si); s.NeedsImprovement {
+		return s
+	}
 	return checkNeedsImprovement()
 }
 
@@ -226,6 +234,22 @@ func checkOverlayMod() registry.State {
 	return registry.State{NeedsImprovement: true, Installed: true, Healthy: true, Fix: "enable the overlay Linux kernel module using 'modprobe overlay'"}
 }
 
+// checkRootless checks if a rootless docker daemon can limit the resources of containers, which requires cgroup v2
+func checkRootless(si oci.SysInfo) registry.State {
+	if !si.Rootless || runtime.GOOS != "linux" {
+		return registry.State{Installed: true, Healthy: true}
+	}
+	klog.Infof("docker is rootless, with cgroup v%s", si.CgroupVersion)
+
+	if si.CgroupVersion != "2" {
+		return registry.State{NeedsImprovement: true, Installed: true, Healthy: true, Fix: "Boot the host with cgroup v2 (systemd.unified_cgroup_hierarchy=1), as rootless docker can not limit the CPUs and memory of minikube on cgroup v1", Doc: docURL + "#rootless-docker"}
+	}
+	if missing := oci.DelegatedControllersMissing(oci.Docker); len(missing) > 0 {
+		return registry.State{NeedsImprovement: true, Installed: true, Healthy: true, Fix: fmt.Sprintf("Delegate the %s cgroup controllers to your user, as described in https://rootlesscontaine.rs/getting-started/common/cgroup2/", strings.Join(missing, ", ")), Doc: docURL + "#rootless-docker"}
+	}
+	return registry.State{Installed: true, Healthy: true}
+}
+
 // suggestFix matches a stderr with possible fix for the docker driver
 func suggestFix(src string, exitcode int, stderr string, err error) registry.State {
 	if strings.Contains(stderr, "permission denied") && runtime.GOOS == "linux" {
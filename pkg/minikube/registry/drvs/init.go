@@ -22,11 +22,22 @@ import (
 	_ "k8s.io/minikube/pkg/minikube/registry/drvs/hyperkit"
 	_ "k8s.io/minikube/pkg/minikube/registry/drvs/hyperv"
 	_ "k8s.io/minikube/pkg/minikube/registry/drvs/kvm2"
+	_ "k8s.io/minikube/pkg/minikube/registry/drvs/nerdctl"
 	_ "k8s.io/minikube/pkg/minikube/registry/drvs/none"
 	_ "k8s.io/minikube/pkg/minikube/registry/drvs/parallels"
 	_ "k8s.io/minikube/pkg/minikube/registry/drvs/podman"
+	_ "k8s.io/minikube/pkg/minikube/registry/drvs/qemu"
 	_ "k8s.io/minikube/pkg/minikube/registry/drvs/ssh"
 	_ "k8s.io/minikube/pkg/minikube/registry/drvs/virtualbox"
 	_ "k8s.io/minikube/pkg/minikube/registry/drvs/vmware"
 	_ "k8s.io/minikube/pkg/minikube/registry/drvs/vmwarefusion"
+
+	"k8s.io/minikube/pkg/minikube/registry"
+	"k8s.io/minikube/pkg/minikube/registry/drvs/plugin"
 )
+
+func init() {
+	// driver plugins are registered after the built-in drivers, so that they can't replace one, and only by name
+	// unless discovering all of them, which runs every plugin executable, is asked for
+	registry.SetDiscoverer(plugin.Lookup, plugin.Discover)
+}
@@ -56,38 +56,54 @@ func init() {
 type kvmDriver struct {
 	*drivers.BaseDriver
 
-	Memory         int
-	DiskSize       int
-	CPU            int
-	Network        string
-	PrivateNetwork string
-	ISO            string
-	Boot2DockerURL string
-	DiskPath       string
-	GPU            bool
-	Hidden         bool
-	ConnectionURI  string
+	Memory            int
+	DiskSize          int
+	CPU               int
+	Network           string
+	PrivateNetwork    string
+	ISO               string
+	Boot2DockerURL    string
+	DiskPath          string
+	GPU               bool
+	Hidden            bool
+	ConnectionURI     string
+	ExtraDisks        int
+	ExtraDiskSize     int
+	Subnet            string
+	StaticIP          string
+	ReserveVirtualIPs bool
 }
 
 func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
 	name := config.MachineName(cc, n)
+	// the private network shared by the clusters has a fixed subnet and leaves no room for virtual IPs,
+	// so each custom subnet and each HA cluster gets a network of its own
+	privateNetwork := "minikube-net"
+	if cc.Subnet != "" || cc.HA {
+		privateNetwork = fmt.Sprintf("mk-%s", cc.Name)
+	}
 	return kvmDriver{
 		BaseDriver: &drivers.BaseDriver{
 			MachineName: name,
 			StorePath:   localpath.MiniPath(),
 			SSHUser:     "docker",
 		},
-		Memory:         cc.Memory,
-		CPU:            cc.CPUs,
-		Network:        cc.KVMNetwork,
-		PrivateNetwork: "minikube-net",
-		Boot2DockerURL: download.LocalISOResource(cc.MinikubeISO),
-		DiskSize:       cc.DiskSize,
-		DiskPath:       filepath.Join(localpath.MiniPath(), "machines", name, fmt.Sprintf("%s.rawdisk", name)),
-		ISO:            filepath.Join(localpath.MiniPath(), "machines", name, "boot2docker.iso"),
-		GPU:            cc.KVMGPU,
-		Hidden:         cc.KVMHidden,
-		ConnectionURI:  cc.KVMQemuURI,
+		Memory:            cc.Memory,
+		CPU:               cc.CPUs,
+		Network:           cc.KVMNetwork,
+		PrivateNetwork:    privateNetwork,
+		Boot2DockerURL:    download.LocalISOResource(cc.MinikubeISO),
+		DiskSize:          cc.DiskSize,
+		DiskPath:          filepath.Join(localpath.MiniPath(), "machines", name, fmt.Sprintf("%s.rawdisk", name)),
+		ISO:               filepath.Join(localpath.MiniPath(), "machines", name, "boot2docker.iso"),
+		GPU:               cc.KVMGPU,
+		Hidden:            cc.KVMHidden,
+		ConnectionURI:     cc.KVMQemuURI,
+		ExtraDisks:        cc.ExtraDisks,
+		ExtraDiskSize:     cc.ExtraDiskSize,
+		Subnet:            cc.Subnet,
+		StaticIP:          n.StaticIP,
+		ReserveVirtualIPs: cc.HA,
 	}, nil
 }
 
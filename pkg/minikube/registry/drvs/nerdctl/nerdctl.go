@@ -0,0 +1,162 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nerdctl
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/docker/machine/libmachine/drivers"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/registry"
+)
+
+const docURL = "https://minikube.sigs.k8s.io/docs/drivers/nerdctl/"
+
+// minReqNerdctlVer is the minimum version of nerdctl which has the network and system info commands the driver uses
+var minReqNerdctlVer = semver.Version{Major: 0, Minor: 8, Patch: 0}
+
+func init() {
+	if err := registry.Register(registry.DriverDef{
+		Name:     driver.Nerdctl,
+		Config:   configure,
+		Init:     func() drivers.Driver { return kic.NewDriver(kic.Config{OCIBinary: oci.Nerdctl}) },
+		Status:   status,
+		Priority: registry.Experimental,
+	}); err != nil {
+		panic(fmt.Sprintf("register failed: %v", err))
+	}
+}
+
+func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
+	mounts := make([]oci.Mount, len(cc.ContainerVolumeMounts))
+	for i, spec := range cc.ContainerVolumeMounts {
+		var err error
+		mounts[i], err = oci.ParseMountString(spec)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	extraArgs := []string{}
+
+	for _, port := range cc.ExposedPorts {
+		extraArgs = append(extraArgs, "-p", port)
+	}
+
+	return kic.NewDriver(kic.Config{
+		ClusterName:       cc.Name,
+		MachineName:       config.MachineName(cc, n),
+		StorePath:         localpath.MiniPath(),
+		ImageDigest:       cc.KicBaseImage,
+		Mounts:            mounts,
+		CPU:               cc.CPUs,
+		Memory:            cc.Memory,
+		OCIBinary:         oci.Nerdctl,
+		APIServerPort:     cc.Nodes[0].Port,
+		KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
+		ContainerRuntime:  cc.KubernetesConfig.ContainerRuntime,
+		ExtraArgs:         extraArgs,
+		ExtraDisks:        cc.ExtraDisks,
+		ExtraDiskSize:     cc.ExtraDiskSize,
+		Subnet:            cc.Subnet,
+		StaticIP:          n.StaticIP,
+		ReservedIPs:       config.ReservedIPs(cc, n),
+		Network:           cc.Network,
+	}), nil
+}
+
+func status() registry.State {
+	if runtime.GOOS != "linux" {
+		return registry.State{Error: fmt.Errorf("nerdctl driver is not supported on %q systems", runtime.GOOS), Installed: false, Healthy: false, Fix: "Try the docker or podman driver", Doc: docURL}
+	}
+
+	nerdctl, err := exec.LookPath(oci.Nerdctl)
+	if err != nil {
+		return registry.State{Error: err, Installed: false, Healthy: false, Fix: "Install nerdctl", Doc: docURL}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 6*time.Second)
+	defer cancel()
+
+	// nerdctl version fails if it can't talk to containerd, so this also checks the daemon
+	// Run with sudo unless containerd is rootless
+	cmd := exec.CommandContext(ctx, "sudo", "-k", "-n", oci.Nerdctl, "version", "--format", "{{.Client.Version}}")
+	if oci.IsRootlessForced() {
+		cmd = exec.CommandContext(ctx, oci.Nerdctl, "version", "--format", "{{.Client.Version}}")
+	}
+	cmd.Env = append(os.Environ(), "LANG=C", "LC_ALL=C") // sudo is localized
+	o, err := cmd.Output()
+	output := strings.TrimSpace(string(o))
+	if err == nil {
+		klog.Infof("nerdctl version: %s", output)
+
+		v, err := semver.Make(strings.TrimPrefix(output, "v"))
+		if err != nil {
+			return registry.State{Error: err, Installed: true, Running: true, Healthy: false, Fix: "Cant verify minimum required version for nerdctl. See the nerdctl releases for installation.", Doc: "https://github.com/containerd/nerdctl/releases"}
+		}
+
+		if v.LT(minReqNerdctlVer) {
+			out.WarningT(`The minimum required version for nerdctl is "{{.minVersion}}". your version is "{{.currentVersion}}". minikube might not work. use at your own risk. To install latest version please see https://github.com/containerd/nerdctl/releases`,
+				out.V{"minVersion": minReqNerdctlVer.String(), "currentVersion": v.String()})
+		}
+		return registry.State{Installed: true, Healthy: true}
+	}
+
+	klog.Warningf("nerdctl returned error: %v", err)
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return registry.State{Error: err, Installed: true, Running: false, Healthy: false, Fix: "Restart the containerd service", Doc: docURL}
+	}
+
+	username := "$USER"
+	if u, err := user.Current(); err == nil {
+		username = u.Username
+	}
+
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		stderr := strings.TrimSpace(string(exitErr.Stderr))
+		newErr := fmt.Errorf(`%q %v: %s`, strings.Join(cmd.Args, " "), exitErr, stderr)
+
+		if strings.Contains(stderr, "a password is required") {
+			return registry.State{Error: newErr, Installed: true, Healthy: false, Fix: fmt.Sprintf("Add your user to the 'sudoers' file: '%s ALL=(ALL) NOPASSWD: %s'", username, nerdctl), Doc: docURL}
+		}
+
+		// Typical error when containerd is not running:
+		// "failed to dial \"/run/containerd/containerd.sock\": connection error: ... connect: no such file or directory"
+		if strings.Contains(stderr, "containerd.sock") {
+			return registry.State{Error: newErr, Installed: true, Running: false, Healthy: false, Fix: "Start the containerd service", Doc: docURL}
+		}
+
+		return registry.State{Error: newErr, Installed: true, Healthy: false, Doc: docURL}
+	}
+
+	return registry.State{Error: err, Installed: true, Healthy: false, Doc: docURL}
+}
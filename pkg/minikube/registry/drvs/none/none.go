@@ -27,6 +27,7 @@ import (
 	"k8s.io/minikube/pkg/drivers/none"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/kubeconfig"
 	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/registry"
 )
@@ -48,6 +49,7 @@ func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
 		MachineName:      config.MachineName(cc, n),
 		StorePath:        localpath.MiniPath(),
 		ContainerRuntime: cc.KubernetesConfig.ContainerRuntime,
+		KubeconfigPath:   kubeconfig.PathFromConfig(&cc),
 	}), nil
 }
 
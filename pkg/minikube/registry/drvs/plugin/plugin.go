@@ -0,0 +1,380 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package plugin discovers out-of-tree driver plugins and registers them as drivers.
+//
+// A plugin is an executable named minikube-driver-<name>, in $MINIKUBE_HOME/drivers or in the PATH.
+// Run as "minikube-driver-<name> metadata", it prints its Metadata as JSON, and run as
+// "minikube-driver-<name> status", its Status. Run without arguments, it serves the
+// libmachine driver RPC protocol, like the docker-machine-driver-<name> executables.
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine/drivers/plugin/localbinary"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/registry"
+)
+
+const (
+	// Prefix is the prefix of the names of plugin executables
+	Prefix = "minikube-driver-"
+	// ProtocolVersion is the version of the metadata handshake minikube understands
+	ProtocolVersion = 1
+
+	// rpcPrefix is the prefix libmachine looks up the executables of RPC drivers with
+	rpcPrefix = "docker-machine-driver-"
+	// handshakeTimeout is how long a plugin may take to answer the handshake
+	handshakeTimeout = 5 * time.Second
+)
+
+// validName matches driver and flag names
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// Metadata is what a plugin tells about itself
+type Metadata struct {
+	// ProtocolVersion is the version of the handshake the plugin implements
+	ProtocolVersion int `json:"protocolVersion"`
+	// Name of the driver, which has to match the name of the executable
+	Name  string   `json:"name"`
+	Alias []string `json:"alias,omitempty"`
+	// Priority is one of experimental, discouraged, deprecated or fallback
+	Priority string `json:"priority"`
+	// Resources maps the resources of the cluster (cpus, memory, diskSize and iso) to the keys of the driver config
+	Resources map[string]string `json:"resources,omitempty"`
+	// Options are the driver specific keys of the driver config, each set by a start flag
+	Options []Option `json:"options,omitempty"`
+}
+
+// Option is a driver config key set by a start flag
+type Option struct {
+	// Flag is the name of the start flag, without the "<driver>-" prefix minikube adds
+	Flag string `json:"flag"`
+	// Key is the key of the driver config
+	Key string `json:"key"`
+	// Type is one of string, int, bool or stringSlice
+	Type    string `json:"type"`
+	Default string `json:"default,omitempty"`
+	Usage   string `json:"usage,omitempty"`
+}
+
+// Status is the answer of a plugin to the status handshake
+type Status struct {
+	Installed        bool   `json:"installed"`
+	Healthy          bool   `json:"healthy"`
+	Running          bool   `json:"running"`
+	NeedsImprovement bool   `json:"needsImprovement"`
+	Error            string `json:"error,omitempty"`
+	Fix              string `json:"fix,omitempty"`
+	Doc              string `json:"doc,omitempty"`
+}
+
+// priorities are the priorities a plugin may claim, below the default one of the built-in drivers,
+// so that a plugin is never picked over them
+var priorities = map[string]registry.Priority{
+	"experimental": registry.Experimental,
+	"discouraged":  registry.Discouraged,
+	"deprecated":   registry.Deprecated,
+	"fallback":     registry.Fallback,
+}
+
+// Lookup registers the driver plugin of a name found in Dirs, if any, such as the one of minikube start --driver
+func Lookup(name string) {
+	if !enabled() || !validName.MatchString(name) {
+		return
+	}
+	path, ok := Find(Dirs())[name]
+	if !ok {
+		return
+	}
+	if register(name, path) {
+		// the named plugin is about to be used, which libmachine runs as an RPC driver
+		if err := linkRPCExecutable(name, path); err != nil {
+			klog.Warningf("driver plugin %s will not be found by libmachine: %v", path, err)
+		}
+	}
+}
+
+// Discover registers all the driver plugins found in Dirs, running each of them
+func Discover() {
+	if !enabled() {
+		return
+	}
+	for name, path := range Find(Dirs()) {
+		register(name, path)
+	}
+}
+
+// enabled returns whether plugins may be registered, which they can't when minikube runs as the RPC server of its own drivers
+func enabled() bool {
+	return os.Getenv(localbinary.PluginEnvKey) != localbinary.PluginEnvVal
+}
+
+// register registers the driver plugin at path, and returns whether it did
+func register(name string, path string) bool {
+	def, err := Load(name, path)
+	if err != nil {
+		klog.Warningf("ignoring driver plugin %s: %v", path, err)
+		return false
+	}
+	if err := registry.Register(def); err != nil {
+		klog.Warningf("ignoring driver plugin %s: %v", path, err)
+		return false
+	}
+	return true
+}
+
+// Dirs returns the directories searched for plugins, by decreasing precedence
+func Dirs() []string {
+	return append([]string{localpath.MakeMiniPath("drivers")}, filepath.SplitList(os.Getenv("PATH"))...)
+}
+
+// Find returns the paths of the plugin executables in dirs by driver name. The first one found wins.
+func Find(dirs []string) map[string]string {
+	found := map[string]string{}
+	for _, dir := range dirs {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, f := range files {
+			name := strings.TrimSuffix(f.Name(), ".exe")
+			if !strings.HasPrefix(name, Prefix) || f.IsDir() {
+				continue
+			}
+			if runtime.GOOS != "windows" && f.Mode().Perm()&0111 == 0 {
+				continue
+			}
+			name = strings.TrimPrefix(name, Prefix)
+			if _, ok := found[name]; !ok {
+				found[name] = filepath.Join(dir, f.Name())
+			}
+		}
+	}
+	return found
+}
+
+// Load runs the metadata handshake of the plugin at path, and returns its driver definition
+func Load(name string, path string) (registry.DriverDef, error) {
+	out, err := handshake(path, "metadata")
+	if err != nil {
+		return registry.DriverDef{}, err
+	}
+	var m Metadata
+	if err := json.Unmarshal(out, &m); err != nil {
+		return registry.DriverDef{}, errors.Wrapf(err, "parsing metadata %s", string(out))
+	}
+	if err := m.validate(name); err != nil {
+		return registry.DriverDef{}, err
+	}
+
+	flags := []registry.Flag{}
+	for _, o := range m.Options {
+		flags = append(flags, registry.Flag{Name: m.flagName(o), Default: o.Default, Usage: o.Usage})
+	}
+
+	return registry.DriverDef{
+		Name:  m.Name,
+		Alias: m.Alias,
+		Config: func(cc config.ClusterConfig, n config.Node) (interface{}, error) {
+			// the plugin may have been picked automatically, without being looked up by name
+			if err := linkRPCExecutable(name, path); err != nil {
+				return nil, errors.Wrap(err, "linking the driver plugin for libmachine")
+			}
+			return m.configure(cc, n)
+		},
+		Status:   func() registry.State { return status(path) },
+		Priority: priorities[m.Priority],
+		Flags:    flags,
+		Plugin:   path,
+	}, nil
+}
+
+// validate checks the metadata of the plugin named name
+func (m Metadata) validate(name string) error {
+	if m.ProtocolVersion != ProtocolVersion {
+		return fmt.Errorf("unsupported protocol version %d, minikube supports %d", m.ProtocolVersion, ProtocolVersion)
+	}
+	if m.Name != name {
+		return fmt.Errorf("plugin calls itself %q, expected %q", m.Name, name)
+	}
+	if !validName.MatchString(m.Name) {
+		return fmt.Errorf("invalid driver name %q", m.Name)
+	}
+	if _, ok := priorities[m.Priority]; !ok {
+		return fmt.Errorf("invalid priority %q", m.Priority)
+	}
+	for r := range m.Resources {
+		switch r {
+		case "cpus", "memory", "diskSize", "iso":
+		default:
+			return fmt.Errorf("unknown resource %q", r)
+		}
+	}
+	for _, o := range m.Options {
+		if !validName.MatchString(o.Flag) || o.Key == "" {
+			return fmt.Errorf("invalid option %+v", o)
+		}
+		if _, err := o.value(o.Default); err != nil {
+			return errors.Wrapf(err, "default of option %s", o.Flag)
+		}
+	}
+	return nil
+}
+
+// flagName returns the name of the start flag of an option, prefixed with the driver name so that it can't clash with other flags
+func (m Metadata) flagName(o Option) string {
+	return fmt.Sprintf("%s-%s", m.Name, o.Flag)
+}
+
+// value converts the string value of the flag of an option to its type in the driver config
+func (o Option) value(s string) (interface{}, error) {
+	switch o.Type {
+	case "", "string":
+		return s, nil
+	case "int":
+		if s == "" {
+			return 0, nil
+		}
+		return strconv.Atoi(s)
+	case "bool":
+		if s == "" {
+			return false, nil
+		}
+		return strconv.ParseBool(s)
+	case "stringSlice":
+		if s == "" {
+			return []string{}, nil
+		}
+		return strings.Split(s, ","), nil
+	}
+	return nil, fmt.Errorf("unknown type %q", o.Type)
+}
+
+// configure emits the driver config, which libmachine passes to the plugin as JSON
+func (m Metadata) configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
+	d := map[string]interface{}{
+		"MachineName": config.MachineName(cc, n),
+		"StorePath":   localpath.MiniPath(),
+	}
+	resources := map[string]interface{}{
+		"cpus":     cc.CPUs,
+		"memory":   cc.Memory,
+		"diskSize": cc.DiskSize,
+		"iso":      download.LocalISOResource(cc.MinikubeISO),
+	}
+	for r, key := range m.Resources {
+		d[key] = resources[r]
+	}
+	for _, o := range m.Options {
+		s, ok := cc.DriverOptions[m.flagName(o)]
+		if !ok {
+			s = o.Default
+		}
+		v, err := o.value(s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "--%s", m.flagName(o))
+		}
+		d[o.Key] = v
+	}
+	return d, nil
+}
+
+// status runs the status handshake of the plugin at path
+func status(path string) registry.State {
+	out, err := handshake(path, "status")
+	if err != nil {
+		return registry.State{Installed: true, Error: err, Fix: fmt.Sprintf("Check that %s works", path)}
+	}
+	var s Status
+	if err := json.Unmarshal(out, &s); err != nil {
+		return registry.State{Installed: true, Error: errors.Wrapf(err, "parsing status %s", string(out))}
+	}
+	st := registry.State{
+		Installed:        s.Installed,
+		Healthy:          s.Healthy,
+		Running:          s.Running,
+		NeedsImprovement: s.NeedsImprovement,
+		Fix:              s.Fix,
+		Doc:              s.Doc,
+	}
+	if s.Error != "" {
+		st.Error = errors.New(s.Error)
+	}
+	return st
+}
+
+// handshake runs the plugin at path with a handshake command, and returns what it printed
+func handshake(path string, command string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), handshakeTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, path, command)
+	out, err := cmd.Output()
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("%s %s timed out after %s", path, command, handshakeTimeout)
+		}
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("%s %s: %v: %s", path, command, err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, errors.Wrapf(err, "%s %s", path, command)
+	}
+	return out, nil
+}
+
+// linkRPCExecutable makes the plugin available as $MINIKUBE_HOME/bin/docker-machine-driver-<name>,
+// where the RPC client of libmachine finds it, as minikube puts that directory first in the PATH.
+func linkRPCExecutable(name string, path string) error {
+	dir := localpath.MakeMiniPath("bin")
+	link := filepath.Join(dir, rpcPrefix+name)
+	if runtime.GOOS == "windows" {
+		link += ".exe"
+	}
+	if fi, err := os.Lstat(link); err == nil {
+		if fi.Mode()&os.ModeSymlink == 0 {
+			return fmt.Errorf("%s already exists", link)
+		}
+		if target, err := os.Readlink(link); err == nil && target == path {
+			return nil
+		}
+		if err := os.Remove(link); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	klog.Infof("linking %s to %s", link, path)
+	return os.Symlink(path, link)
+}
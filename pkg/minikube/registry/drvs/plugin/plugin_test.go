@@ -0,0 +1,214 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/registry"
+)
+
+const testMetadata = `{
+  "protocolVersion": 1,
+  "name": "fake",
+  "priority": "experimental",
+  "resources": {"cpus": "CPU", "memory": "Memory"},
+  "options": [
+    {"flag": "endpoint", "key": "Endpoint", "default": "localhost", "usage": "API endpoint"},
+    {"flag": "replicas", "key": "Replicas", "type": "int", "default": "1"},
+    {"flag": "tags", "key": "Tags", "type": "stringSlice"}
+  ]
+}`
+
+// writePlugin writes a plugin executable answering the handshake with metadata and status into dir
+func writePlugin(t *testing.T, dir string, name string, metadata string, status string) string {
+	path := filepath.Join(dir, Prefix+name)
+	script := fmt.Sprintf("#!/bin/sh\ncase \"$1\" in\nmetadata) cat <<'EOF'\n%s\nEOF\n;;\nstatus) echo '%s';;\n*) exit 1;;\nesac\n", metadata, status)
+	if err := ioutil.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatalf("writing plugin: %v", err)
+	}
+	return path
+}
+
+func tempDir(t *testing.T) string {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin scripts need a shell")
+	}
+	dir, err := ioutil.TempDir("", "plugins")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+// minikubeHome points MINIKUBE_HOME to a temporary directory for the duration of a test, and returns the minikube directory
+func minikubeHome(t *testing.T) string {
+	old := os.Getenv(localpath.MinikubeHome)
+	os.Setenv(localpath.MinikubeHome, tempDir(t))
+	t.Cleanup(func() { os.Setenv(localpath.MinikubeHome, old) })
+	return localpath.MiniPath()
+}
+
+func TestFind(t *testing.T) {
+	first := tempDir(t)
+	second := tempDir(t)
+	want := writePlugin(t, first, "fake", testMetadata, "{}")
+	writePlugin(t, second, "fake", testMetadata, "{}")
+	other := writePlugin(t, second, "other", testMetadata, "{}")
+	if err := ioutil.WriteFile(filepath.Join(second, Prefix+"noexec"), nil, 0644); err != nil {
+		t.Fatalf("writing file: %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(second, "docker-machine-driver-fake"), nil, 0755); err != nil {
+		t.Fatalf("writing file: %v", err)
+	}
+
+	got := Find([]string{first, filepath.Join(first, "missing"), second})
+	if !reflect.DeepEqual(got, map[string]string{"fake": want, "other": other}) {
+		t.Errorf("Find() = %v, want fake at %s and other at %s", got, want, other)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	home := minikubeHome(t)
+	dir := tempDir(t)
+	path := writePlugin(t, dir, "fake", testMetadata, `{"installed": true, "healthy": true, "needsImprovement": true, "fix": "tune it"}`)
+
+	def, err := Load("fake", path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if def.Name != "fake" || def.Priority != registry.Experimental || def.Plugin != path {
+		t.Errorf("Load() = %+v, want an experimental plugin at %s", def, path)
+	}
+	wantFlags := []registry.Flag{
+		{Name: "fake-endpoint", Default: "localhost", Usage: "API endpoint"},
+		{Name: "fake-replicas", Default: "1"},
+		{Name: "fake-tags"},
+	}
+	if !reflect.DeepEqual(def.Flags, wantFlags) {
+		t.Errorf("Flags = %+v, want %+v", def.Flags, wantFlags)
+	}
+
+	st := def.Status()
+	if !st.Installed || !st.Healthy || !st.NeedsImprovement || st.Fix != "tune it" || st.Error != nil {
+		t.Errorf("Status() = %+v, want a healthy plugin needing improvement", st)
+	}
+
+	cc := config.ClusterConfig{
+		Name:          "p1",
+		CPUs:          4,
+		Memory:        4000,
+		DriverOptions: map[string]string{"fake-replicas": "3", "fake-tags": "a,b"},
+	}
+	got, err := def.Config(cc, config.Node{ControlPlane: true})
+	if err != nil {
+		t.Fatalf("Config: %v", err)
+	}
+	d := got.(map[string]interface{})
+	for key, want := range map[string]interface{}{
+		"MachineName": "p1",
+		"CPU":         4,
+		"Memory":      4000,
+		"Endpoint":    "localhost",
+		"Replicas":    3,
+		"Tags":        []string{"a", "b"},
+	} {
+		if !reflect.DeepEqual(d[key], want) {
+			t.Errorf("Config()[%s] = %v, want %v", key, d[key], want)
+		}
+	}
+
+	// libmachine runs the plugin once its driver config is generated
+	if target, err := os.Readlink(filepath.Join(home, "bin", "docker-machine-driver-fake")); err != nil || target != path {
+		t.Errorf("docker-machine-driver-fake links to %q (%v), want %s", target, err, path)
+	}
+
+	cc.DriverOptions["fake-replicas"] = "many"
+	if _, err := def.Config(cc, config.Node{ControlPlane: true}); err == nil {
+		t.Errorf("Config() accepted a non-integer --fake-replicas")
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	var tests = []struct {
+		description string
+		metadata    string
+	}{
+		{"unsupported protocol", `{"protocolVersion": 2, "name": "fake", "priority": "experimental"}`},
+		{"name mismatch", `{"protocolVersion": 1, "name": "other", "priority": "experimental"}`},
+		{"reserved priority", `{"protocolVersion": 1, "name": "fake", "priority": "preferred"}`},
+		{"default priority", `{"protocolVersion": 1, "name": "fake", "priority": "default"}`},
+		{"unknown resource", `{"protocolVersion": 1, "name": "fake", "priority": "fallback", "resources": {"gpus": "GPU"}}`},
+		{"bad option default", `{"protocolVersion": 1, "name": "fake", "priority": "fallback", "options": [{"flag": "n", "key": "N", "type": "int", "default": "x"}]}`},
+		{"not json", `fake driver v1.0`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			path := writePlugin(t, tempDir(t), "fake", tc.metadata, "{}")
+			if def, err := Load("fake", path); err == nil {
+				t.Errorf("Load() = %+v, want an error", def)
+			}
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	home := minikubeHome(t)
+	drivers := filepath.Join(home, "drivers")
+	if err := os.MkdirAll(drivers, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	path := writePlugin(t, drivers, "fake", testMetadata, "{}")
+	writePlugin(t, drivers, "listed", strings.Replace(testMetadata, `"fake"`, `"listed"`, 1), "{}")
+
+	// the plugin looked up by name is about to be used
+	Lookup("fake")
+	if target, err := os.Readlink(filepath.Join(home, "bin", "docker-machine-driver-fake")); err != nil || target != path {
+		t.Errorf("docker-machine-driver-fake links to %q (%v), want %s", target, err, path)
+	}
+
+	// discovering all the plugins registers them without writing anything
+	Discover()
+	if d := registry.Driver("listed"); d.Plugin == "" {
+		t.Errorf("Discover() didn't register the listed plugin")
+	}
+	if _, err := os.Lstat(filepath.Join(home, "bin", "docker-machine-driver-listed")); !os.IsNotExist(err) {
+		t.Errorf("Discover() linked the listed plugin: %v", err)
+	}
+}
+
+func TestStatusFailure(t *testing.T) {
+	path := writePlugin(t, tempDir(t), "fake", testMetadata, "not json")
+	if st := status(path); !st.Installed || st.Error == nil {
+		t.Errorf("status() = %+v, want an installed plugin with an error", st)
+	}
+
+	path = filepath.Join(tempDir(t), Prefix+"missing")
+	if st := status(path); st.Error == nil {
+		t.Errorf("status() = %+v, want an error", st)
+	}
+}
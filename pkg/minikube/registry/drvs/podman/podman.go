@@ -32,6 +32,7 @@ import (
 	"k8s.io/minikube/pkg/drivers/kic"
 	"k8s.io/minikube/pkg/drivers/kic/oci"
 	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/out"
@@ -41,6 +42,9 @@ import (
 // minReqPodmanVer is required the minimum version of podman to be installed for podman driver.
 var minReqPodmanVer = semver.Version{Major: 1, Minor: 7, Patch: 0}
 
+// minRootlessPodmanVer is the minimum version of podman which can run minikube rootless, with the systemd cgroup manager on cgroup v2
+var minRootlessPodmanVer = semver.Version{Major: 2, Minor: 1, Patch: 0}
+
 func init() {
 	priority := registry.Experimental
 	// Staged rollout for default:
@@ -79,7 +83,7 @@ func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
 		ClusterName:       cc.Name,
 		MachineName:       config.MachineName(cc, n),
 		StorePath:         localpath.MiniPath(),
-		ImageDigest:       strings.Split(cc.KicBaseImage, "@")[0], // for podman does not support docker images references with both a tag and digest.
+		ImageDigest:       cc.KicBaseImage,
 		Mounts:            mounts,
 		CPU:               cc.CPUs,
 		Memory:            cc.Memory,
@@ -88,6 +92,11 @@ func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
 		KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
 		ContainerRuntime:  cc.KubernetesConfig.ContainerRuntime,
 		ExtraArgs:         extraArgs,
+		ExtraDisks:        cc.ExtraDisks,
+		ExtraDiskSize:     cc.ExtraDiskSize,
+		Subnet:            cc.Subnet,
+		StaticIP:          n.StaticIP,
+		ReservedIPs:       config.ReservedIPs(cc, n),
 	}), nil
 }
 
@@ -107,9 +116,13 @@ func status() registry.State {
 
 	// Quickly returns an error code if service is not running
 	cmd := exec.CommandContext(ctx, oci.Podman, "version", "--format", "{{.Server.Version}}")
-	// Run with sudo on linux (local), otherwise podman-remote (as podman)
+	// Run with sudo on linux (local) unless podman is rootless, otherwise podman-remote (as podman)
 	if runtime.GOOS == "linux" {
-		cmd = exec.CommandContext(ctx, "sudo", "-k", "-n", oci.Podman, "version", "--format", "{{.Version}}")
+		if oci.IsRootlessForced() {
+			cmd = exec.CommandContext(ctx, oci.Podman, "version", "--format", "{{.Version}}")
+		} else {
+			cmd = exec.CommandContext(ctx, "sudo", "-k", "-n", oci.Podman, "version", "--format", "{{.Version}}")
+		}
 		cmd.Env = append(os.Environ(), "LANG=C", "LC_ALL=C") // sudo is localized
 	}
 	o, err := cmd.Output()
@@ -127,6 +140,9 @@ func status() registry.State {
 				out.V{"minVersion": minReqPodmanVer.String(), "currentVersion": v.String()})
 		}
 
+		if runtime.GOOS == "linux" && oci.IsRootlessForced() {
+			return checkRootless(v)
+		}
 		return registry.State{Installed: true, Healthy: true}
 	}
 
@@ -164,3 +180,26 @@ func status() registry.State {
 
 	return registry.State{Error: err, Installed: true, Healthy: false, Doc: docURL}
 }
+
+// checkRootless checks if a rootless podman can run minikube, which requires cgroup v2 with the cpu and memory controllers delegated to the user
+func checkRootless(v semver.Version) registry.State {
+	docURL := "https://minikube.sigs.k8s.io/docs/drivers/podman/#rootless-podman"
+	if v.LT(minRootlessPodmanVer) {
+		return registry.State{NeedsImprovement: true, Installed: true, Healthy: true, Fix: fmt.Sprintf("Upgrade podman to %s or later to run it rootless", minRootlessPodmanVer), Doc: docURL}
+	}
+
+	si, err := oci.CachedDaemonInfo(oci.Podman)
+	if err != nil {
+		return registry.State{Error: err, Installed: true, Healthy: false, Doc: docURL}
+	}
+	if !si.Rootless {
+		return registry.State{NeedsImprovement: true, Installed: true, Healthy: true, Fix: fmt.Sprintf("Unset %s or run 'minikube config set rootless false', as podman is not running rootless", constants.MinikubeRootlessEnv), Doc: docURL}
+	}
+	if si.CgroupVersion != "2" {
+		return registry.State{NeedsImprovement: true, Installed: true, Healthy: true, Fix: "Boot the host with cgroup v2 (systemd.unified_cgroup_hierarchy=1), as rootless podman can not limit the CPUs and memory of minikube on cgroup v1", Doc: docURL}
+	}
+	if missing := oci.DelegatedControllersMissing(oci.Podman); len(missing) > 0 {
+		return registry.State{NeedsImprovement: true, Installed: true, Healthy: true, Fix: fmt.Sprintf("Delegate the %s cgroup controllers to your user, as described in https://rootlesscontaine.rs/getting-started/common/cgroup2/", strings.Join(missing, ", ")), Doc: docURL}
+	}
+	return registry.State{Installed: true, Healthy: true}
+}
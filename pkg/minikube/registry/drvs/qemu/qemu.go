@@ -0,0 +1,102 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qemu
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine/drivers"
+
+	"k8s.io/minikube/pkg/drivers/qemu"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/registry"
+)
+
+const (
+	docURL = "https://minikube.sigs.k8s.io/docs/drivers/qemu/"
+)
+
+func init() {
+	if err := registry.Register(registry.DriverDef{
+		Name:     driver.QEMU,
+		Config:   configure,
+		Status:   status,
+		Priority: registry.Experimental,
+		Init:     func() drivers.Driver { return qemu.NewDriver("", "") },
+	}); err != nil {
+		panic(fmt.Sprintf("register failed: %v", err))
+	}
+}
+
+func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
+	d := qemu.NewDriver(config.MachineName(cc, n), localpath.MiniPath())
+	d.Boot2DockerURL = download.LocalISOResource(cc.MinikubeISO)
+	d.Memory = cc.Memory
+	d.CPU = cc.CPUs
+	d.DiskSize = cc.DiskSize
+	if cc.Network != "" {
+		d.Network = cc.Network
+	}
+	d.SocketVMnetClientPath = cc.SocketVMnetClientPath
+	d.SocketVMnetPath = cc.SocketVMnetPath
+	d.APIServerPort = cc.Nodes[0].Port
+	return d, nil
+}
+
+func status() registry.State {
+	if runtime.GOARCH != "amd64" {
+		return registry.State{Error: fmt.Errorf("qemu driver is not supported on %q systems yet", runtime.GOARCH), Installed: false, Healthy: false, Fix: "Try other drivers", Doc: docURL}
+	}
+
+	path, err := exec.LookPath(qemu.Binary())
+	if err != nil {
+		return registry.State{Error: err, Fix: fmt.Sprintf("Install %s", qemu.Binary()), Doc: docURL}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, path, "--version")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return registry.State{
+			Installed: true,
+			Error:     fmt.Errorf("%s failed:\n%s", strings.Join(cmd.Args, " "), strings.TrimSpace(string(out))),
+			Fix:       "Reinstall qemu",
+			Doc:       docURL,
+		}
+	}
+
+	if qemu.Accelerator() == "tcg" {
+		return registry.State{
+			Installed:        true,
+			Healthy:          true,
+			NeedsImprovement: true,
+			Fix:              "Make /dev/kvm accessible to your user (for instance by joining the kvm group), as qemu is falling back to slow software emulation",
+			Doc:              docURL,
+		}
+	}
+	return registry.State{Installed: true, Healthy: true}
+}
@@ -50,20 +50,20 @@ func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
 		ContainerRuntime: cc.KubernetesConfig.ContainerRuntime,
 	})
 
-	if cc.SSHIPAddress == "" {
+	if n.SSHIPAddress == "" {
 		return nil, errors.Errorf("please provide an IP address")
 	}
 
 	// We don't want the API server listening on loopback interface,
 	// even if we might use a tunneled VM port for the SSH service
-	if cc.SSHIPAddress == "127.0.0.1" || cc.SSHIPAddress == "localhost" {
+	if n.SSHIPAddress == "127.0.0.1" || n.SSHIPAddress == "localhost" {
 		return nil, errors.Errorf("please provide real IP address")
 	}
 
-	d.IPAddress = cc.SSHIPAddress
-	d.SSHUser = cc.SSHUser
-	d.SSHKey = cc.SSHKey
-	d.SSHPort = cc.SSHPort
+	d.IPAddress = n.SSHIPAddress
+	d.SSHUser = n.SSHUser
+	d.SSHKey = n.SSHKey
+	d.SSHPort = n.SSHPort
 
 	return d, nil
 }
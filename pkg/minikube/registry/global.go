@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"sync"
 
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/minikube/translate"
@@ -30,6 +31,8 @@ const (
 	Podman = "podman"
 	// Docker is Kubernetes in container using docker driver
 	Docker = "docker"
+	// Nerdctl is Kubernetes in container using nerdctl driver
+	Nerdctl = "nerdctl"
 	// Mock driver
 	Mock = "mock"
 	// None driver
@@ -38,7 +41,7 @@ const (
 
 // IsKIC checks if the driver is a Kubernetes in container
 func IsKIC(name string) bool {
-	return name == Docker || name == Podman
+	return name == Docker || name == Podman || name == Nerdctl
 }
 
 // IsMock checks if the driver is a mock
@@ -62,8 +65,45 @@ func BareMetal(name string) bool {
 var (
 	// globalRegistry is a globally accessible driver registry
 	globalRegistry = newRegistry()
+
+	// lookup registers the driver of a name which isn't built in, such as a driver plugin
+	lookup func(name string)
+	// discover registers all the drivers which aren't built in
+	discover     func()
+	discoverOnce sync.Once
+	// looked are the names lookup ran for
+	looked   = map[string]bool{}
+	lookedMu sync.Mutex
 )
 
+// SetDiscoverer sets how the drivers which aren't built in are registered, by name when a driver isn't built in,
+// and all of them when DiscoverAll is called. As they may run external programs, they only run once.
+func SetDiscoverer(lookupFn func(name string), discoverFn func()) {
+	lookup = lookupFn
+	discover = discoverFn
+}
+
+// DiscoverAll registers all the drivers which aren't built in, such as the driver plugins, which it runs,
+// so that they are listed and may be picked automatically. Unless called, they are only registered by name.
+func DiscoverAll() {
+	discoverOnce.Do(func() {
+		if discover != nil {
+			discover()
+		}
+	})
+}
+
+// lookupDriver registers the driver of a name which isn't built in, unless looked up already
+func lookupDriver(name string) {
+	lookedMu.Lock()
+	defer lookedMu.Unlock()
+	if lookup == nil || looked[name] {
+		return
+	}
+	looked[name] = true
+	lookup(name)
+}
+
 // DriverState is metadata relating to a driver and status
 type DriverState struct {
 	Name     string
@@ -93,6 +133,10 @@ func Register(driver DriverDef) error {
 
 // Driver gets a named driver from the global registry
 func Driver(name string) DriverDef {
+	if d := globalRegistry.Driver(name); !d.Empty() {
+		return d
+	}
+	lookupDriver(name)
 	return globalRegistry.Driver(name)
 }
 
@@ -132,7 +176,7 @@ func Available(vm bool) []DriverState {
 
 // Status returns the state of a driver within the global registry
 func Status(name string) State {
-	d := globalRegistry.Driver(name)
+	d := Driver(name)
 	if d.Empty() {
 		return State{}
 	}
@@ -17,6 +17,7 @@ limitations under the License.
 package registry
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -128,3 +129,52 @@ func TestGlobalStatus(t *testing.T) {
 		t.Errorf("status mismatch (-want +got):\n%s", diff)
 	}
 }
+
+func TestGlobalDiscoverer(t *testing.T) {
+	globalRegistry = newRegistry()
+	discoverOnce = sync.Once{}
+	looked = map[string]bool{}
+	defer SetDiscoverer(nil, nil)
+
+	lookups := map[string]int{}
+	discoveries := 0
+	SetDiscoverer(func(name string) {
+		lookups[name]++
+		if name == "plugin" {
+			if err := Register(DriverDef{Name: "plugin"}); err != nil {
+				t.Errorf("register returned error: %v", err)
+			}
+		}
+	}, func() {
+		discoveries++
+		if err := Register(DriverDef{Name: "other"}); err != nil {
+			t.Errorf("register returned error: %v", err)
+		}
+	})
+	if err := Register(DriverDef{Name: "foo"}); err != nil {
+		t.Errorf("register returned error: %v", err)
+	}
+
+	if got := len(List()); got != 1 || discoveries != 0 || len(lookups) != 0 {
+		t.Errorf("List() = %d drivers after %d discoveries and %v lookups, expected the built-in driver alone", got, discoveries, lookups)
+	}
+	if d := Driver("foo"); d.Empty() || len(lookups) != 0 {
+		t.Errorf("Driver(foo) = %v after %v lookups, expected the built-in driver without lookup", d, lookups)
+	}
+	if d := Driver("plugin"); d.Empty() || lookups["plugin"] != 1 {
+		t.Errorf("Driver(plugin) = %v after %v lookups, expected the looked up driver", d, lookups)
+	}
+	if d := Driver("missing"); !d.Empty() {
+		t.Errorf("Driver(missing) = %v, expected none", d)
+	}
+	Driver("missing")
+	if lookups["missing"] != 1 || discoveries != 0 {
+		t.Errorf("%v lookups and %d discoveries, expected a single lookup of missing and no discovery", lookups, discoveries)
+	}
+
+	DiscoverAll()
+	DiscoverAll()
+	if got := len(List()); got != 3 || discoveries != 1 {
+		t.Errorf("List() = %d drivers after %d discoveries, expected 3 drivers after 1", got, discoveries)
+	}
+}
@@ -104,6 +104,19 @@ type DriverDef struct {
 
 	// Priority returns the prioritization for selecting a driver by default.
 	Priority Priority
+
+	// Flags are the extra start flags of the driver, whose values are passed to Config through ClusterConfig.DriverOptions
+	Flags []Flag
+
+	// Plugin is the path of the out-of-tree executable providing the driver, empty if built-in to the minikube binary
+	Plugin string
+}
+
+// Flag is a start flag specific to a driver
+type Flag struct {
+	Name    string
+	Default string
+	Usage   string
 }
 
 // Empty returns true if the driver is nil
@@ -0,0 +1,495 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package registrycache manages the pull-through registry caches of Docker Hub and k8s.gcr.io shared by all profiles
+package registrycache
+
+import (
+	"fmt"
+	"net"
+	"os/exec"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/util"
+)
+
+const (
+	// EnabledKey is the config key to use the registry cache as mirror of Docker Hub and k8s.gcr.io in all profiles
+	EnabledKey = "registry-cache"
+	// SizeKey is the config key of the size limit of the registry cache, such as "20g"
+	SizeKey = "registry-cache-size"
+	// PortKey is the config key of the host port of the registry cache of Docker Hub, the next ports are used by the other registries
+	PortKey = "registry-cache-port"
+
+	// ContainerName is the name of the container running the registry cache of Docker Hub
+	ContainerName = "minikube-registry-cache"
+	// VolumeName is the volume the registry cache of Docker Hub keeps its blobs in, which survives minikube delete
+	VolumeName = "minikube-registry-cache"
+	// Image is the registry image run as pull-through cache
+	Image = "registry:2.7.1"
+	// DefaultPort is the default host port of the registry cache
+	DefaultPort = 5050
+	// DefaultSize is the default size limit of the registry cache, shared by the registries
+	DefaultSize = "20g"
+
+	// storageDir is where the registry keeps its data, in the container
+	storageDir = "/var/lib/registry"
+	// blobsDir is where the registry keeps the blobs, by digest
+	blobsDir = storageDir + "/docker/registry/v2/blobs"
+	// repositoriesDir is where the registry keeps the links of the repositories to the blobs
+	repositoriesDir = storageDir + "/docker/registry/v2/repositories"
+)
+
+// upstream is a registry the registry cache pulls through from. A registry container only proxies a single registry,
+// so each of them gets a container, a volume and a port of its own.
+type upstream struct {
+	// Host is the name container runtimes use for the registry
+	Host string
+	// RemoteURL is the URL the container pulls through from
+	RemoteURL string
+	// Container is the name of the container caching the registry
+	Container string
+	// Volume is the volume the container keeps its blobs in
+	Volume string
+	// PortOffset is the offset of the host port of the container from the port of the registry cache
+	PortOffset int
+}
+
+// upstreams are the registries mirrored by the registry cache
+var upstreams = []upstream{
+	{Host: cruntime.DockerHub, RemoteURL: "https://registry-1.docker.io", Container: ContainerName, Volume: VolumeName},
+	{Host: "k8s.gcr.io", RemoteURL: "https://k8s.gcr.io", Container: ContainerName + "-k8s-gcr-io", Volume: VolumeName + "-k8s-gcr-io", PortOffset: 1},
+}
+
+// port returns the host port of the container caching the registry
+func (u upstream) port() int {
+	return Port() + u.PortOffset
+}
+
+// Stats is the usage of the registry cache
+type Stats struct {
+	Running bool
+	// Addresses are the addresses of the registry cache on the host, by mirrored registry
+	Addresses    map[string]string
+	Blobs        int
+	Repositories int
+	Size         int64
+	Limit        int64
+}
+
+// blob is a blob of the registry cache
+type blob struct {
+	// Container is the container of the registry cache storing the blob
+	Container string
+	Digest    string
+	Size      int64
+	ModTime   int64
+}
+
+// Enabled returns whether the profiles use the registry cache
+func Enabled() bool {
+	return viper.GetBool(EnabledKey)
+}
+
+// Port returns the host port of the registry cache of Docker Hub
+func Port() int {
+	if p := viper.GetInt(PortKey); p > 0 {
+		return p
+	}
+	return DefaultPort
+}
+
+// Limit returns the size limit of the registry cache in bytes
+func Limit() (int64, error) {
+	s := viper.GetString(SizeKey)
+	if s == "" {
+		s = DefaultSize
+	}
+	mb, err := util.CalculateSizeInMB(s)
+	if err != nil {
+		return 0, errors.Wrapf(err, "parsing %s %q", SizeKey, s)
+	}
+	return util.ConvertMBToBytes(mb), nil
+}
+
+// ociBin returns the container engine of the host the registry cache runs in
+func ociBin() (string, error) {
+	for _, bin := range []string{oci.Docker, oci.Podman} {
+		if _, err := exec.LookPath(bin); err == nil {
+			return bin, nil
+		}
+	}
+	return "", errors.New("the registry cache needs docker or podman on the host")
+}
+
+// run runs a command of the container engine and returns its output
+func run(bin string, args ...string) (string, error) {
+	cmd := oci.PrefixCmd(exec.Command(bin, args...))
+	klog.Infof("Run: %v", cmd.Args)
+	b, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(b), errors.Wrapf(err, "%s: %s", strings.Join(cmd.Args, " "), b)
+	}
+	return string(b), nil
+}
+
+// running returns whether the registry cache container of u runs, and whether it exists
+func running(bin string, u upstream) (bool, bool) {
+	s, err := run(bin, "container", "inspect", "-f", "{{.State.Running}}", u.Container)
+	if err != nil {
+		return false, false
+	}
+	return strings.TrimSpace(s) == "true", true
+}
+
+// Start starts the registry cache of each registry if it isn't running, published on the loopback of the host and on hostIP,
+// the address of the host as seen from the nodes, and evicts the blobs over its size limit
+func Start(hostIP net.IP) error {
+	bin, err := ociBin()
+	if err != nil {
+		return err
+	}
+	local, err := localAddresses()
+	if err != nil {
+		return errors.Wrap(err, "host addresses")
+	}
+	for _, u := range upstreams {
+		if err := start(bin, u, hostIP, local); err != nil {
+			return errors.Wrapf(err, "registry cache of %s", u.Host)
+		}
+	}
+	limit, err := Limit()
+	if err != nil {
+		return err
+	}
+	_, err = prune(bin, limit)
+	return err
+}
+
+// start starts the registry cache container of u if it isn't running
+func start(bin string, u upstream, hostIP net.IP, local []net.IP) error {
+	up, exists := running(bin, u)
+	want := bindAddresses(nil, hostIP, local)
+	if exists {
+		bound, err := boundAddresses(bin, u)
+		if err != nil {
+			return err
+		}
+		if want = bindAddresses(bound, hostIP, local); !sameAddresses(bound, want) {
+			// the port bindings of a container are fixed, the blobs are kept in the volume
+			klog.Infof("publishing registry cache %s on %v instead of %v", u.Container, want, bound)
+			if _, err := run(bin, "rm", "-f", u.Container); err != nil {
+				return err
+			}
+			exists = false
+		}
+	}
+	if !exists {
+		klog.Infof("creating registry cache %s on port %d of %v", u.Container, u.port(), want)
+		args := []string{"run", "-d", "--restart=unless-stopped", "--name", u.Container}
+		for _, addr := range want {
+			args = append(args, "-p", fmt.Sprintf("%s:%d:5000", addr, u.port()))
+		}
+		args = append(args,
+			"-v", u.Volume+":"+storageDir,
+			"-e", "REGISTRY_PROXY_REMOTEURL="+u.RemoteURL,
+			Image)
+		_, err := run(bin, args...)
+		return err
+	}
+	if !up {
+		if _, err := run(bin, "start", u.Container); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// localAddresses returns the addresses of the interfaces of the host
+func localAddresses() ([]net.IP, error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, err
+	}
+	ips := []net.IP{}
+	for _, a := range addrs {
+		if n, ok := a.(*net.IPNet); ok {
+			ips = append(ips, n.IP)
+		}
+	}
+	return ips, nil
+}
+
+// bindAddresses returns the sorted host addresses to publish the registry cache on: the loopback, which the nodes of
+// the drivers forwarding to it reach, and hostIP along with the addresses it is already published on for other profiles,
+// as long as they are addresses of the host. The cache is never published on all the interfaces of the host.
+func bindAddresses(bound []string, hostIP net.IP, local []net.IP) []string {
+	candidates := []net.IP{hostIP}
+	for _, a := range bound {
+		candidates = append(candidates, net.ParseIP(a))
+	}
+	addrs := map[string]bool{"127.0.0.1": true}
+	for _, c := range candidates {
+		if c == nil || c.IsUnspecified() {
+			continue
+		}
+		for _, ip := range local {
+			if ip.Equal(c) {
+				addrs[c.String()] = true
+			}
+		}
+	}
+	result := []string{}
+	for a := range addrs {
+		result = append(result, a)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// boundAddresses returns the sorted host addresses the registry cache container of u is published on,
+// which are empty or unspecified when it is published on all the interfaces
+func boundAddresses(bin string, u upstream) ([]string, error) {
+	s, err := run(bin, "container", "inspect", "-f", "{{range $p, $b := .HostConfig.PortBindings}}{{range $b}}{{.HostIp}}|{{end}}{{end}}", u.Container)
+	if err != nil {
+		return nil, err
+	}
+	addrs := strings.Split(strings.TrimSuffix(strings.TrimSpace(s), "|"), "|")
+	sort.Strings(addrs)
+	return addrs, nil
+}
+
+// sameAddresses returns whether two sorted lists of addresses are equal
+func sameAddresses(a, b []string) bool {
+	return strings.Join(a, ",") == strings.Join(b, ",")
+}
+
+// Mirror returns the registries with the registry cache as first mirror of Docker Hub and k8s.gcr.io,
+// at the address of the host as seen from the nodes. Docker ignores the mirror of k8s.gcr.io, as it only supports mirrors of Docker Hub.
+func Mirror(regs []cruntime.Registry, hostIP net.IP) []cruntime.Registry {
+	result := append([]cruntime.Registry{}, regs...)
+	caches := []cruntime.Registry{}
+	for _, u := range upstreams {
+		addr := net.JoinHostPort(hostIP.String(), strconv.Itoa(u.port()))
+		mirrored := false
+		for i, r := range result {
+			if r.Host == u.Host {
+				result[i].Mirrors = append([]string{"http://" + addr}, r.Mirrors...)
+				mirrored = true
+			}
+		}
+		if !mirrored {
+			result = append(result, cruntime.Registry{Host: u.Host, Mirrors: []string{"http://" + addr}})
+		}
+		// the registry cache serves plain HTTP
+		caches = append(caches, cruntime.Registry{Host: addr, Insecure: true})
+	}
+	return append(result, caches...)
+}
+
+// listBlobs returns the blobs of the running registry cache containers
+func listBlobs(bin string) ([]blob, error) {
+	blobs := []blob{}
+	for _, u := range upstreams {
+		if up, _ := running(bin, u); !up {
+			continue
+		}
+		// there are no blobs until the first pull
+		s, err := run(bin, "exec", u.Container, "sh", "-c", fmt.Sprintf("find %s -name data -type f -exec stat -c '%%Y %%s %%n' {} + 2>/dev/null; true", blobsDir))
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range parseBlobs(s) {
+			b.Container = u.Container
+			blobs = append(blobs, b)
+		}
+	}
+	return blobs, nil
+}
+
+// parseBlobs parses "<mtime> <size> <path>" lines, where the path ends with /<digest hex>/data
+func parseBlobs(s string) []blob {
+	blobs := []blob{}
+	for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		mtime, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		digest := path.Base(path.Dir(fields[2]))
+		if len(digest) < 2 {
+			continue
+		}
+		blobs = append(blobs, blob{Digest: digest, Size: size, ModTime: mtime})
+	}
+	return blobs
+}
+
+// evict returns the least recently stored blobs to remove to fit in limit bytes
+func evict(blobs []blob, limit int64) []blob {
+	total := int64(0)
+	for _, b := range blobs {
+		total += b.Size
+	}
+	sorted := append([]blob{}, blobs...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].ModTime < sorted[j].ModTime })
+	victims := []blob{}
+	for _, b := range sorted {
+		if total <= limit {
+			break
+		}
+		victims = append(victims, b)
+		total -= b.Size
+	}
+	return victims
+}
+
+// prune removes the least recently stored blobs over limit bytes from the registry cache containers and the links to them,
+// and returns the bytes freed
+func prune(bin string, limit int64) (int64, error) {
+	blobs, err := listBlobs(bin)
+	if err != nil {
+		return 0, err
+	}
+	victims := evict(blobs, limit)
+	if len(victims) == 0 {
+		return 0, nil
+	}
+	freed := int64(0)
+	scripts := map[string][]string{}
+	for _, v := range victims {
+		freed += v.Size
+		scripts[v.Container] = append(scripts[v.Container],
+			fmt.Sprintf("rm -rf %s/sha256/%s/%s", blobsDir, v.Digest[:2], v.Digest),
+			fmt.Sprintf("grep -rlx sha256:%s %s | xargs -r rm -f", v.Digest, repositoriesDir))
+	}
+	klog.Infof("evicting %d blobs from the registry cache", len(victims))
+	for _, u := range upstreams {
+		script, ok := scripts[u.Container]
+		if !ok {
+			continue
+		}
+		if _, err := run(bin, "exec", u.Container, "sh", "-c", strings.Join(script, "; ")); err != nil {
+			return 0, err
+		}
+		// the registry keeps the descriptors of the blobs in memory
+		if _, err := run(bin, "restart", u.Container); err != nil {
+			return 0, err
+		}
+	}
+	return freed, nil
+}
+
+// Prune evicts the least recently stored blobs of the registry cache until it fits in its size limit,
+// or all of them, and returns the bytes freed
+func Prune(all bool) (int64, error) {
+	bin, err := ociBin()
+	if err != nil {
+		return 0, err
+	}
+	if !anyRunning(bin) {
+		return 0, errors.New("the registry cache isn't running")
+	}
+	limit := int64(0)
+	if !all {
+		if limit, err = Limit(); err != nil {
+			return 0, err
+		}
+	}
+	return prune(bin, limit)
+}
+
+// anyRunning returns whether any registry cache container runs
+func anyRunning(bin string) bool {
+	for _, u := range upstreams {
+		if up, _ := running(bin, u); up {
+			return true
+		}
+	}
+	return false
+}
+
+// GetStats returns the usage of the registry cache
+func GetStats() (Stats, error) {
+	limit, err := Limit()
+	if err != nil {
+		return Stats{}, err
+	}
+	st := Stats{Addresses: map[string]string{}, Limit: limit}
+	for _, u := range upstreams {
+		st.Addresses[u.Host] = fmt.Sprintf("localhost:%d", u.port())
+	}
+	bin, err := ociBin()
+	if err != nil {
+		return st, err
+	}
+	if st.Running = anyRunning(bin); !st.Running {
+		return st, nil
+	}
+	blobs, err := listBlobs(bin)
+	if err != nil {
+		return st, err
+	}
+	st.Blobs = len(blobs)
+	for _, b := range blobs {
+		st.Size += b.Size
+	}
+	for _, u := range upstreams {
+		if up, _ := running(bin, u); !up {
+			continue
+		}
+		s, err := run(bin, "exec", u.Container, "sh", "-c", fmt.Sprintf("find %s -name _manifests -type d 2>/dev/null; true", repositoriesDir))
+		if err == nil {
+			st.Repositories += len(strings.Fields(s))
+		}
+	}
+	return st, nil
+}
+
+// Delete removes the registry cache containers and their blobs
+func Delete() error {
+	bin, err := ociBin()
+	if err != nil {
+		// without a container engine, there is no registry cache to delete
+		return nil
+	}
+	for _, u := range upstreams {
+		if _, exists := running(bin, u); exists {
+			if _, err := run(bin, "rm", "-f", u.Container); err != nil {
+				return err
+			}
+		}
+		if err := oci.RemoveVolume(bin, u.Volume); err != nil && err != oci.ErrVolumeNotFound {
+			return err
+		}
+	}
+	return nil
+}
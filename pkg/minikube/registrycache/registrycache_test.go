@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registrycache
+
+import (
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+)
+
+func TestParseBlobs(t *testing.T) {
+	s := `1616000000 1024 /var/lib/registry/docker/registry/v2/blobs/sha256/ab/abcdef/data
+garbage
+1615000000 2048 /var/lib/registry/docker/registry/v2/blobs/sha256/12/123456/data
+`
+	want := []blob{
+		{Digest: "abcdef", Size: 1024, ModTime: 1616000000},
+		{Digest: "123456", Size: 2048, ModTime: 1615000000},
+	}
+	if diff := cmp.Diff(want, parseBlobs(s)); diff != "" {
+		t.Errorf("parseBlobs diff (-want +got):\n%s", diff)
+	}
+	if got := parseBlobs(""); len(got) != 0 {
+		t.Errorf("parseBlobs(\"\") = %v, want none", got)
+	}
+}
+
+func TestEvict(t *testing.T) {
+	blobs := []blob{
+		{Digest: "new", Size: 30, ModTime: 3},
+		{Digest: "old", Size: 30, ModTime: 1},
+		{Digest: "mid", Size: 30, ModTime: 2},
+	}
+	var tests = []struct {
+		limit int64
+		want  []string
+	}{
+		{100, []string{}},
+		{90, []string{}},
+		{60, []string{"old"}},
+		{45, []string{"old", "mid"}},
+		{0, []string{"old", "mid", "new"}},
+	}
+	for _, tc := range tests {
+		got := []string{}
+		for _, b := range evict(blobs, tc.limit) {
+			got = append(got, b.Digest)
+		}
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("evict(%d) diff (-want +got):\n%s", tc.limit, diff)
+		}
+	}
+}
+
+func TestMirror(t *testing.T) {
+	regs := []cruntime.Registry{
+		{Host: "10.96.0.0/12", Insecure: true},
+		{Host: cruntime.DockerHub, Mirrors: []string{"https://mirror.example.com"}},
+	}
+	want := []cruntime.Registry{
+		{Host: "10.96.0.0/12", Insecure: true},
+		{Host: cruntime.DockerHub, Mirrors: []string{"http://192.168.49.1:5050", "https://mirror.example.com"}},
+		{Host: "k8s.gcr.io", Mirrors: []string{"http://192.168.49.1:5051"}},
+		{Host: "192.168.49.1:5050", Insecure: true},
+		{Host: "192.168.49.1:5051", Insecure: true},
+	}
+	if diff := cmp.Diff(want, Mirror(regs, net.ParseIP("192.168.49.1"))); diff != "" {
+		t.Errorf("Mirror diff (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"https://mirror.example.com"}, regs[1].Mirrors); diff != "" {
+		t.Errorf("Mirror changed the registries it was passed (-want +got):\n%s", diff)
+	}
+
+	regs = []cruntime.Registry{
+		{Host: "k8s.gcr.io", Mirrors: []string{"https://gcr-mirror.example.com"}},
+	}
+	want = []cruntime.Registry{
+		{Host: "k8s.gcr.io", Mirrors: []string{"http://192.168.49.1:5051", "https://gcr-mirror.example.com"}},
+		{Host: cruntime.DockerHub, Mirrors: []string{"http://192.168.49.1:5050"}},
+		{Host: "192.168.49.1:5050", Insecure: true},
+		{Host: "192.168.49.1:5051", Insecure: true},
+	}
+	if diff := cmp.Diff(want, Mirror(regs, net.ParseIP("192.168.49.1"))); diff != "" {
+		t.Errorf("Mirror diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestBindAddresses(t *testing.T) {
+	local := []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("192.168.49.1"), net.ParseIP("192.168.58.1"), net.ParseIP("10.0.0.5")}
+	tests := []struct {
+		description string
+		bound       []string
+		hostIP      net.IP
+		want        []string
+	}{
+		{"docker network gateway", nil, net.ParseIP("192.168.49.1"), []string{"127.0.0.1", "192.168.49.1"}},
+		{"forwarded to the loopback", nil, net.ParseIP("10.0.2.2"), []string{"127.0.0.1"}},
+		{"no host address", nil, nil, []string{"127.0.0.1"}},
+		{"other profile", []string{"127.0.0.1", "192.168.49.1"}, net.ParseIP("192.168.58.1"), []string{"127.0.0.1", "192.168.49.1", "192.168.58.1"}},
+		{"all interfaces", []string{""}, net.ParseIP("192.168.49.1"), []string{"127.0.0.1", "192.168.49.1"}},
+		{"unspecified", []string{"0.0.0.0"}, nil, []string{"127.0.0.1"}},
+		{"removed network", []string{"127.0.0.1", "192.168.67.1"}, net.ParseIP("192.168.49.1"), []string{"127.0.0.1", "192.168.49.1"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			got := bindAddresses(tc.bound, tc.hostIP, local)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("bindAddresses(%v, %v) mismatch (-want +got):\n%s", tc.bound, tc.hostIP, diff)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ package tunnel
 
 import (
 	"fmt"
+	"net"
 	"strings"
 
 	"k8s.io/klog/v2"
@@ -67,6 +68,27 @@ func isValidToAddOrDelete(router router, r *Route) (bool, error) {
 	return false, nil
 }
 
+// OverlappingRoutes returns the lines of the routing table of the host, other than the default routes,
+// whose destination shares addresses with subnet
+func OverlappingRoutes(subnet *net.IPNet) ([]string, error) {
+	return overlappingRoutes(&osRouter{}, subnet)
+}
+
+func overlappingRoutes(router router, subnet *net.IPNet) ([]string, error) {
+	exists, conflict, overlaps, err := router.Inspect(&Route{DestCIDR: subnet, Gateway: net.IPv4zero})
+	if err != nil {
+		return nil, err
+	}
+	// a route to the same destination, such as the network of an interface, overlaps as well
+	if exists {
+		overlaps = append(overlaps, subnet.String())
+	}
+	if conflict != "" {
+		overlaps = append(overlaps, conflict)
+	}
+	return overlaps, nil
+}
+
 // a partial representation of the routing table on the host
 // tunnel only requires the destination CIDR, the gateway and the actual textual representation per line
 type routingTable []routingTableLine
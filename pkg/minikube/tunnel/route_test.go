@@ -141,3 +141,33 @@ func unsafeParseRoute(gatewayIP string, destCIDR string) *Route {
 	}
 	return expectedRoute
 }
+
+func TestOverlappingRoutes(t *testing.T) {
+	router := &fakeRouter{rt: routingTable{
+		{route: unsafeParseRoute("0.0.0.0", "192.168.49.0/24"), line: "192.168.49.0/24 dev br-1"},
+		{route: unsafeParseRoute("10.0.0.1", "172.16.0.0/12"), line: "172.16.0.0/12 via 10.0.0.1"},
+		{route: unsafeParseRoute("10.0.0.1", "192.168.50.0/24"), line: "192.168.50.0/24 via 10.0.0.1"},
+	}}
+
+	tcs := []struct {
+		subnet string
+		want   []string
+	}{
+		{"192.168.49.0/24", []string{"192.168.49.0/24"}},
+		{"192.168.49.128/25", []string{"192.168.49.0/24 dev br-1"}},
+		{"192.168.50.0/24", []string{"192.168.50.0/24 via 10.0.0.1"}},
+		{"192.168.0.0/16", []string{"192.168.49.0/24 dev br-1", "192.168.50.0/24 via 10.0.0.1"}},
+		{"172.17.0.0/16", []string{"172.16.0.0/12 via 10.0.0.1"}},
+		{"10.10.0.0/16", []string{}},
+	}
+	for _, tc := range tcs {
+		_, subnet, _ := net.ParseCIDR(tc.subnet)
+		got, err := overlappingRoutes(router, subnet)
+		if err != nil {
+			t.Fatalf("overlappingRoutes(%s): %v", tc.subnet, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("overlappingRoutes(%s) = %q, want %q", tc.subnet, got, tc.want)
+		}
+	}
+}
@@ -31,6 +31,4 @@ const (
 	GuestCertAuthDir = "/usr/share/ca-certificates"
 	// GuestCertStoreDir is where system SSL certificates are installed
 	GuestCertStoreDir = "/etc/ssl/certs"
-	// GuestGvisorDir is where gvisor bootstraps from
-	GuestGvisorDir = "/tmp/gvisor"
 )
@@ -0,0 +1,47 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// VirtualIPRange returns the first and last addresses of a subnet reserved for the virtual IPs of HA clusters:
+// its last quarter, which the DHCP range of the networks created by minikube for HA clusters leaves out
+func VirtualIPRange(subnet *net.IPNet) (net.IP, net.IP, error) {
+	base := subnet.IP.To4()
+	ones, bits := subnet.Mask.Size()
+	if base == nil || bits != 32 {
+		return nil, nil, fmt.Errorf("subnet %s is not an IPv4 subnet", subnet)
+	}
+	if ones > 29 {
+		return nil, nil, fmt.Errorf("subnet %s is too small for a virtual IP", subnet)
+	}
+
+	first := binary.BigEndian.Uint32(base.Mask(subnet.Mask))
+	size := uint32(1) << uint(bits-ones)
+	return uint32ToIP(first + size/4*3), uint32ToIP(first + size - 2), nil
+}
+
+// uint32ToIP returns the IPv4 address of n
+func uint32ToIP(n uint32) net.IP {
+	ip := make(net.IP, 4)
+	binary.BigEndian.PutUint32(ip, n)
+	return ip
+}
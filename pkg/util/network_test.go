@@ -0,0 +1,53 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"net"
+	"testing"
+)
+
+func TestVirtualIPRange(t *testing.T) {
+	var tests = []struct {
+		subnet string
+		first  string
+		last   string
+		err    bool
+	}{
+		{subnet: "192.168.39.0/24", first: "192.168.39.192", last: "192.168.39.254"},
+		{subnet: "10.10.0.0/16", first: "10.10.192.0", last: "10.10.255.254"},
+		{subnet: "172.16.5.64/29", first: "172.16.5.70", last: "172.16.5.70"},
+		{subnet: "172.16.5.64/30", err: true},
+		{subnet: "fd00::/64", err: true},
+	}
+	for _, tc := range tests {
+		_, subnet, err := net.ParseCIDR(tc.subnet)
+		if err != nil {
+			t.Fatalf("ParseCIDR(%q): %v", tc.subnet, err)
+		}
+		first, last, err := VirtualIPRange(subnet)
+		if (err != nil) != tc.err {
+			t.Fatalf("VirtualIPRange(%s) error = %v, want error %v", tc.subnet, err, tc.err)
+		}
+		if tc.err {
+			continue
+		}
+		if first.String() != tc.first || last.String() != tc.last {
+			t.Errorf("VirtualIPRange(%s) = %s-%s, want %s-%s", tc.subnet, first, last, tc.first, tc.last)
+		}
+	}
+}
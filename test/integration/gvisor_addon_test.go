@@ -22,6 +22,7 @@ import (
 	"context"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -38,7 +39,12 @@ func TestGvisorAddon(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), Minutes(60))
 	defer func() {
 		if t.Failed() {
-			rr, err := Run(t, exec.CommandContext(ctx, "kubectl", "--context", profile, "logs", "gvisor", "-n", "kube-system"))
+			rr, err := Run(t, exec.CommandContext(ctx, "kubectl", "--context", profile, "describe", "runtimeclass,pods", "-l", "run=nginx"))
+			if err != nil {
+				t.Logf("failed to get gvisor post-mortem logs: %v", err)
+			}
+			t.Logf("gvisor post-mortem: %s:\n%s\n", rr.Command(), rr.Output())
+			rr, err = Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "ssh", "sudo journalctl -u containerd --no-pager -n 200"))
 			if err != nil {
 				t.Logf("failed to get gvisor post-mortem logs: %v", err)
 			}
@@ -47,25 +53,23 @@ func TestGvisorAddon(t *testing.T) {
 		CleanupWithLogs(t, profile, cancel)
 	}()
 
-	startArgs := append([]string{"start", "-p", profile, "--memory=2200", "--container-runtime=containerd", "--docker-opt", "containerd=/var/run/containerd/containerd.sock"}, StartArgs()...)
+	startArgs := append([]string{"start", "-p", profile, "--memory=2200", "--container-runtime=containerd"}, StartArgs()...)
 	rr, err := Run(t, exec.CommandContext(ctx, Target(), startArgs...))
 	if err != nil {
 		t.Fatalf("failed to start minikube: args %q: %v", rr.Command(), err)
 	}
 
-	// If it exists, include a locally built gvisor image
-	rr, err = Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "cache", "add", "gcr.io/k8s-minikube/gvisor-addon:2"))
+	// the addon installs gVisor as the runsc runtime handler
+	rr, err = Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "addons", "enable", "gvisor"))
 	if err != nil {
-		t.Logf("%s failed: %v (won't test local image)", rr.Command(), err)
+		t.Fatalf("%s failed: %v", rr.Command(), err)
 	}
-
-	rr, err = Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "addons", "enable", "gvisor"))
+	rr, err = Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "runtime", "list"))
 	if err != nil {
 		t.Fatalf("%s failed: %v", rr.Command(), err)
 	}
-
-	if _, err := PodWait(ctx, t, profile, "kube-system", "kubernetes.io/minikube-addons=gvisor", Minutes(4)); err != nil {
-		t.Fatalf("failed waiting for 'gvisor controller' pod: %v", err)
+	if !strings.Contains(rr.Stdout.String(), "enabled") {
+		t.Errorf("expected runsc to be enabled, got: %s", rr.Stdout.String())
 	}
 
 	// Create an untrusted workload
@@ -78,27 +82,23 @@ func TestGvisorAddon(t *testing.T) {
 	if err != nil {
 		t.Fatalf("%s failed: %v", rr.Command(), err)
 	}
-
 	if _, err := PodWait(ctx, t, profile, "default", "run=nginx,untrusted=true", Minutes(4)); err != nil {
 		t.Errorf("failed waiting for nginx pod: %v", err)
 	}
 	if _, err := PodWait(ctx, t, profile, "default", "run=nginx,runtime=gvisor", Minutes(4)); err != nil {
-		t.Errorf("failed waitinf for gvisor pod: %v", err)
+		t.Errorf("failed waiting for gvisor pod: %v", err)
 	}
 
 	// Ensure that workloads survive a restart
 	rr, err = Run(t, exec.CommandContext(ctx, Target(), "stop", "-p", profile))
 	if err != nil {
-		t.Fatalf("faild stopping minikube. args %q : %v", rr.Command(), err)
+		t.Fatalf("failed stopping minikube. args %q : %v", rr.Command(), err)
 	}
 
 	rr, err = Run(t, exec.CommandContext(ctx, Target(), startArgs...))
 	if err != nil {
 		t.Fatalf("failed starting minikube after a stop. args %q, %v", rr.Command(), err)
 	}
-	if _, err := PodWait(ctx, t, profile, "kube-system", "kubernetes.io/minikube-addons=gvisor", Minutes(4)); err != nil {
-		t.Errorf("failed waiting for 'gvisor controller' pod : %v", err)
-	}
 	if _, err := PodWait(ctx, t, profile, "default", "run=nginx,untrusted=true", Minutes(4)); err != nil {
 		t.Errorf("failed waiting for 'nginx' pod : %v", err)
 	}
@@ -72,7 +72,7 @@ func TestKicExistingNetwork(t *testing.T) {
 	}
 	// create custom network
 	networkName := "existing-network"
-	if _, err := oci.CreateNetwork(oci.Docker, networkName); err != nil {
+	if _, err := oci.CreateNetwork(oci.Docker, networkName, ""); err != nil {
 		t.Fatalf("error creating network: %v", err)
 	}
 	defer func() {
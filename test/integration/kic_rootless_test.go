@@ -0,0 +1,61 @@
+// +build integration
+
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// TestKicRootless makes sure a cluster starts against a rootless docker or podman daemon
+func TestKicRootless(t *testing.T) {
+	if !RootlessDriver() {
+		t.Skip("only runs with a rootless docker or podman driver")
+	}
+
+	profile := UniqueProfileName("rootless")
+	ctx, cancel := context.WithTimeout(context.Background(), Minutes(10))
+	defer Cleanup(t, profile, cancel)
+
+	args := append([]string{"start", "-p", profile, "--memory=2048", "--wait=true", "--alsologtostderr"}, StartArgs()...)
+	rr, err := Run(t, exec.CommandContext(ctx, Target(), args...))
+	if err != nil {
+		t.Fatalf("failed to start minikube with args: %q : %v", rr.Command(), err)
+	}
+
+	// the root user of the node must be mapped to an unprivileged user of the host
+	rr, err = Run(t, exec.CommandContext(ctx, Target(), "-p", profile, "ssh", "cat /proc/self/uid_map"))
+	if err != nil {
+		t.Fatalf("failed to read the uid map. args %q: %v", rr.Command(), err)
+	}
+	fields := strings.Fields(rr.Stdout.String())
+	if len(fields) >= 2 && fields[0] == "0" && fields[1] == "0" {
+		t.Errorf("expected the node to run in a user namespace, but root is mapped to root: %s", rr.Stdout.String())
+	}
+
+	rr, err = Run(t, exec.CommandContext(ctx, "kubectl", "--context", profile, "get", "nodes", "-o", "jsonpath={.items[*].status.conditions[?(@.type==\"Ready\")].status}"))
+	if err != nil {
+		t.Fatalf("failed to get nodes. args %q: %v", rr.Command(), err)
+	}
+	if strings.TrimSpace(rr.Stdout.String()) != "True" {
+		t.Errorf("expected the node to be Ready, got %q", rr.Stdout.String())
+	}
+}
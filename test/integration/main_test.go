@@ -27,6 +27,7 @@ import (
 	"testing"
 	"time"
 
+	"k8s.io/minikube/pkg/drivers/kic/oci"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/driver"
 )
@@ -135,6 +136,15 @@ func KicDriver() bool {
 	return DockerDriver() || PodmanDriver()
 }
 
+// RootlessDriver returns whether or not this test is using a rootless docker or podman daemon
+func RootlessDriver() bool {
+	if PodmanDriver() {
+		// podman is run with sudo unless rootless is requested
+		return oci.IsRootlessForced()
+	}
+	return DockerDriver() && oci.IsRootless(oci.Docker)
+}
+
 // ContainerRuntime returns the name of a specific container runtime if it was specified
 func ContainerRuntime() string {
 	flag := "--container-runtime="